@@ -19,12 +19,22 @@ func Cmd() *cobra.Command {
 	cmd.AddCommand(updatePluginCmd)
 	cmd.AddCommand(deletePluginCmd)
 	cmd.AddCommand(downloadPluginCmd)
+	cmd.AddCommand(versionsPluginCmd)
+	cmd.AddCommand(rollbackPluginCmd)
+	cmd.AddCommand(addBinaryPluginCmd)
 	return cmd
 }
 
+var pluginVersion string
+
+func init() {
+	addPluginCmd.Flags().StringVarP(&pluginVersion, "version", "", "", "Plugin version, 1.0.0 by default")
+	updatePluginCmd.Flags().StringVarP(&pluginVersion, "version", "", "", "Plugin version, the next patch version by default")
+}
+
 var addPluginCmd = &cobra.Command{
 	Use:   "add",
-	Short: "cds plugin add <file>",
+	Short: "cds plugin add <file> [--version <version>]",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, err := sdk.IsAdmin(); !ok {
 			if err != nil {
@@ -38,7 +48,7 @@ var addPluginCmd = &cobra.Command{
 		}
 		var err error
 		for i := 0; i < 5; i++ {
-			_, err = sdk.UploadPlugin(args[0], false)
+			_, err = sdk.UploadPluginVersion(args[0], pluginVersion, false)
 			if err == nil {
 				break
 			}
@@ -52,7 +62,7 @@ var addPluginCmd = &cobra.Command{
 
 var updatePluginCmd = &cobra.Command{
 	Use:   "update",
-	Short: "cds plugin update <file>",
+	Short: "cds plugin update <file> [--version <version>]",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, err := sdk.IsAdmin(); !ok {
 			if err != nil {
@@ -66,7 +76,7 @@ var updatePluginCmd = &cobra.Command{
 		}
 		var err error
 		for i := 0; i < 5; i++ {
-			_, err = sdk.UploadPlugin(args[0], true)
+			_, err = sdk.UploadPluginVersion(args[0], pluginVersion, true)
 			if err == nil {
 				break
 			}
@@ -119,3 +129,69 @@ var downloadPluginCmd = &cobra.Command{
 		fmt.Printf("OK\n")
 	},
 }
+
+var versionsPluginCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "cds plugin versions <name>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+		versions, err := sdk.ListPluginVersions(args[0])
+		if err != nil {
+			sdk.Exit("Error: cannot list versions of plugin %s (%s)\n", args[0], err)
+		}
+		for _, v := range versions {
+			current := ""
+			if v.Current {
+				current = " (current)"
+			}
+			fmt.Printf("%s%s\n", v.Version, current)
+			for _, b := range v.Binaries {
+				fmt.Printf("  %s/%s %s\n", b.OS, b.Arch, b.SHA256sum)
+			}
+		}
+	},
+}
+
+var rollbackPluginCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "cds plugin rollback <name> <version>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if ok, err := sdk.IsAdmin(); !ok {
+			if err != nil {
+				fmt.Printf("Error : %v\n", err)
+			}
+			sdk.Exit("You are not allowed to run this command")
+		}
+
+		if len(args) != 2 {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+		if err := sdk.SetPluginCurrentVersion(args[0], args[1]); err != nil {
+			sdk.Exit("Error: cannot rollback plugin %s to %s (%s)\n", args[0], args[1], err)
+		}
+		fmt.Printf("OK\n")
+	},
+}
+
+var addBinaryPluginCmd = &cobra.Command{
+	Use:   "add-binary",
+	Short: "cds plugin add-binary <name> <version> <os> <arch> <file>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if ok, err := sdk.IsAdmin(); !ok {
+			if err != nil {
+				fmt.Printf("Error : %v\n", err)
+			}
+			sdk.Exit("You are not allowed to run this command")
+		}
+
+		if len(args) != 5 {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+		if _, err := sdk.UploadPluginBinary(args[0], args[1], args[2], args[3], args[4]); err != nil {
+			sdk.Exit("Error: cannot add binary %s to plugin %s %s (%s)\n", args[4], args[0], args[1], err)
+		}
+		fmt.Printf("OK\n")
+	},
+}
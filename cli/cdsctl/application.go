@@ -17,6 +17,7 @@ var (
 			cli.NewListCommand(applicationListCmd, applicationListRun, nil),
 			cli.NewGetCommand(applicationShowCmd, applicationShowRun, nil),
 			applicationKey,
+			applicationTests,
 		})
 )
 
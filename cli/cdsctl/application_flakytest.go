@@ -0,0 +1,94 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	applicationTestsCmd = cli.Command{
+		Name:  "tests",
+		Short: "Manage CDS application flaky tests",
+	}
+
+	applicationTests = cli.NewCommand(applicationTestsCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(applicationFlakyTestsCmd, applicationFlakyTestsRun, nil),
+			cli.NewListCommand(applicationQuarantineListCmd, applicationQuarantineListRun, nil),
+			cli.NewCommand(applicationQuarantineAddCmd, applicationQuarantineAddRun, nil),
+			cli.NewCommand(applicationQuarantineDeleteCmd, applicationQuarantineDeleteRun, nil),
+		})
+)
+
+var applicationFlakyTestsCmd = cli.Command{
+	Name:  "flaky",
+	Short: "List the flaky tests of an application",
+	Args: []cli.Arg{
+		{Name: "key"},
+		{Name: "appName"},
+	},
+	OptionnalArgs: []cli.Arg{
+		{Name: "branch"},
+	},
+}
+
+func applicationFlakyTestsRun(v cli.Values) (cli.ListResult, error) {
+	fs, err := client.ApplicationFlakyTestsList(v["key"], v["appName"], v["branch"])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(fs), nil
+}
+
+var applicationQuarantineListCmd = cli.Command{
+	Name:  "quarantined",
+	Short: "List the quarantined tests of an application",
+	Args: []cli.Arg{
+		{Name: "key"},
+		{Name: "appName"},
+	},
+}
+
+func applicationQuarantineListRun(v cli.Values) (cli.ListResult, error) {
+	qs, err := client.ApplicationTestQuarantineList(v["key"], v["appName"])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(qs), nil
+}
+
+var applicationQuarantineAddCmd = cli.Command{
+	Name:  "quarantine",
+	Short: "Quarantine a flaky test: its failures don't fail the job anymore",
+	Args: []cli.Arg{
+		{Name: "key"},
+		{Name: "appName"},
+		{Name: "testSuite"},
+		{Name: "testName"},
+	},
+}
+
+func applicationQuarantineAddRun(v cli.Values) error {
+	q := &sdk.TestQuarantine{
+		TestSuite: v["testSuite"],
+		TestName:  v["testName"],
+	}
+	return client.ApplicationTestQuarantineAdd(v["key"], v["appName"], q)
+}
+
+var applicationQuarantineDeleteCmd = cli.Command{
+	Name:  "release",
+	Short: "Release a test from the quarantine",
+	Args: []cli.Arg{
+		{Name: "key"},
+		{Name: "appName"},
+		{Name: "testSuite"},
+		{Name: "testName"},
+	},
+}
+
+func applicationQuarantineDeleteRun(v cli.Values) error {
+	return client.ApplicationTestQuarantineDelete(v["key"], v["appName"], v["testSuite"], v["testName"])
+}
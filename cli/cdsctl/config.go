@@ -18,6 +18,7 @@ type config struct {
 	Host                  string
 	user                  string
 	token                 string
+	accessToken           string
 	InsecureSkipVerifyTLS bool
 }
 
@@ -28,6 +29,7 @@ func loadConfig(configFile string) (*cdsclient.Config, error) {
 	c.Host = os.Getenv("CDS_API")
 	c.user = os.Getenv("CDS_USER")
 	c.token = os.Getenv("CDS_TOKEN")
+	c.accessToken = os.Getenv("CDS_ACCESS_TOKEN")
 	c.InsecureSkipVerifyTLS, _ = strconv.ParseBool(os.Getenv("CDS_INSECURE"))
 
 	if c.Host != "" && c.user != "" {
@@ -81,16 +83,21 @@ func loadConfig(configFile string) (*cdsclient.Config, error) {
 	}
 
 	conf := &cdsclient.Config{
-		Host:    c.Host,
-		User:    c.user,
-		Token:   c.token,
-		Verbose: verbose,
+		Host:        c.Host,
+		User:        c.user,
+		Token:       c.token,
+		AccessToken: c.accessToken,
+		Verbose:     verbose,
 	}
 
 	return conf, nil
 }
 
 func loadClient(c *cdsclient.Config) (cdsclient.Interface, error) {
+	//Access tokens do not need the secret stored in the keychain
+	if c.AccessToken != "" {
+		return cdsclient.New(*c), nil
+	}
 	user, secret, err := keychain.GetSecret(c.Host)
 	if err != nil {
 		return nil, err
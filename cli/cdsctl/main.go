@@ -54,6 +54,7 @@ func main() {
 
 		client, err = loadClient(config)
 		cli.ExitOnError(err)
+		cfg = config
 
 		//Manage warnings
 		/*		if !internal.NoWarnings && cmd != user.Cmd {
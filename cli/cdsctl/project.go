@@ -19,6 +19,8 @@ var (
 		[]*cobra.Command{
 			cli.NewListCommand(projectListCmd, projectListRun, nil),
 			cli.NewGetCommand(projectShowCmd, projectShowRun, nil),
+			cli.NewCommand(projectExportCmd, projectExportRun, nil),
+			cli.NewCommand(projectImportCmd, projectImportRun, nil),
 			projectKey,
 		})
 )
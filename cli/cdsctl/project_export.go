@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+
+	"github.com/howeyc/gopass"
+
+	"github.com/ovh/cds/cli"
+)
+
+var projectPassphraseFlag = cli.Flag{
+	Name:  "passphrase",
+	Usage: "Passphrase protecting the secrets of the archive, asked on command line if not set",
+}
+
+var projectExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export a whole CDS project as an archive, its secrets are encrypted with a passphrase",
+	Args: []cli.Arg{
+		{Name: "key"},
+	},
+	Flags: []cli.Flag{
+		projectPassphraseFlag,
+		{
+			Name:  "output",
+			Usage: "Path of the archive, <key>.tar.gz if not set",
+		},
+	},
+}
+
+func projectExportRun(v cli.Values) error {
+	passphrase, err := projectPassphrase(v)
+	if err != nil {
+		return err
+	}
+
+	output := v.GetString("output")
+	if output == "" {
+		output = v["key"] + ".tar.gz"
+	}
+
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := client.ProjectExport(v["key"], passphrase, f); err != nil {
+		os.Remove(output)
+		return err
+	}
+
+	fmt.Printf("Project %s exported to %s\n", v["key"], output)
+	return nil
+}
+
+var projectImportCmd = cli.Command{
+	Name:  "import",
+	Short: "Import a CDS project archive, only the missing entities are created",
+	Args: []cli.Arg{
+		{Name: "key"},
+		{Name: "file"},
+	},
+	Flags: []cli.Flag{
+		projectPassphraseFlag,
+		{
+			Name:  "dry-run",
+			Usage: "Only display what would be imported and how the existing entities differ from the archive",
+			Kind:  reflect.Bool,
+		},
+	},
+}
+
+func projectImportRun(v cli.Values) error {
+	archive, err := ioutil.ReadFile(v["file"])
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := projectPassphrase(v)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := client.ProjectImport(v["key"], archive, passphrase, v.GetBool("dry-run"))
+	for _, m := range msgs {
+		fmt.Println(m)
+	}
+	return err
+}
+
+//projectPassphrase takes the passphrase from flags or asks for it on command line
+func projectPassphrase(v cli.Values) (string, error) {
+	if passphrase := v.GetString("passphrase"); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fmt.Printf("Passphrase: ")
+	b, err := gopass.GetPasswd()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -20,6 +20,7 @@ var (
 			cli.NewGetCommand(userShowCmd, userShowRun, nil),
 			cli.NewCommand(userResetCmd, userResetRun, nil),
 			cli.NewCommand(userConfirmCmd, userConfirmRun, nil),
+			userToken,
 		})
 )
 
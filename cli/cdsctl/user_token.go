@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	userTokenCmd = cli.Command{
+		Name:  "token",
+		Short: "Manage CDS personal access tokens and service account tokens",
+	}
+
+	userToken = cli.NewCommand(userTokenCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(userTokenListCmd, userTokenListRun, nil),
+			cli.NewCommand(userTokenCreateCmd, userTokenCreateRun, nil),
+			cli.NewCommand(userTokenRevokeCmd, userTokenRevokeRun, nil),
+		})
+)
+
+var userTokenGroupFlag = cli.Flag{
+	Name:  "group",
+	Usage: "Manage the service account tokens of this group instead of your personal access tokens",
+	Kind:  reflect.String,
+}
+
+func userTokenUsername() (string, error) {
+	if cfg == nil || cfg.User == "" {
+		return "", fmt.Errorf("unable to find your username, you should try to login first")
+	}
+	return cfg.User, nil
+}
+
+var userTokenListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your access tokens",
+	Flags: []cli.Flag{userTokenGroupFlag},
+}
+
+func userTokenListRun(v cli.Values) (cli.ListResult, error) {
+	var tokens []sdk.AccessToken
+	var err error
+	if g := v.GetString("group"); g != "" {
+		tokens, err = client.GroupAccessTokenList(g)
+	} else {
+		username, errU := userTokenUsername()
+		if errU != nil {
+			return nil, errU
+		}
+		tokens, err = client.UserAccessTokenList(username)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(tokens), nil
+}
+
+var userTokenCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create an access token. Scopes are a comma separated list of read, run and admin",
+	Args: []cli.Arg{
+		{Name: "name"},
+		{Name: "scopes"},
+	},
+	Flags: []cli.Flag{
+		userTokenGroupFlag,
+		{
+			Name:  "expire-in-days",
+			Usage: "Number of days before the token expires, the token never expires if not set",
+			Kind:  reflect.String,
+			IsValid: func(s string) bool {
+				_, err := strconv.Atoi(s)
+				return s == "" || err == nil
+			},
+		},
+	},
+}
+
+func userTokenCreateRun(v cli.Values) error {
+	t := &sdk.AccessToken{Name: v["name"]}
+	for _, s := range strings.Split(v["scopes"], ",") {
+		scope, ok := sdk.AccessTokenScopeFromString(strings.TrimSpace(s))
+		if !ok {
+			return fmt.Errorf("invalid scope %s, scopes are %v", s, sdk.AccessTokenScopes)
+		}
+		t.Scopes = append(t.Scopes, scope)
+	}
+	if d := v.GetString("expire-in-days"); d != "" {
+		days, err := strconv.Atoi(d)
+		if err != nil {
+			return err
+		}
+		expireAt := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+		t.ExpireAt = &expireAt
+	}
+
+	if g := v.GetString("group"); g != "" {
+		if err := client.GroupAccessTokenCreate(g, t); err != nil {
+			return err
+		}
+	} else {
+		username, err := userTokenUsername()
+		if err != nil {
+			return err
+		}
+		if err := client.UserAccessTokenCreate(username, t); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Access token %d created, it will not be displayed again:\n%s\n", t.ID, t.Token)
+	return nil
+}
+
+var userTokenRevokeCmd = cli.Command{
+	Name:  "revoke",
+	Short: "Revoke an access token",
+	Args: []cli.Arg{
+		{Name: "id", IsValid: isInt},
+	},
+	Flags: []cli.Flag{userTokenGroupFlag},
+}
+
+func userTokenRevokeRun(v cli.Values) error {
+	id, _ := strconv.ParseInt(v["id"], 10, 64)
+	if g := v.GetString("group"); g != "" {
+		return client.GroupAccessTokenRevoke(g, id)
+	}
+	username, err := userTokenUsername()
+	if err != nil {
+		return err
+	}
+	return client.UserAccessTokenRevoke(username, id)
+}
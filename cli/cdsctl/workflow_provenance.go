@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	workflowProvenanceCmd = cli.Command{
+		Name:  "provenance",
+		Short: "Manage provenance attestations of CDS workflow runs",
+	}
+
+	workflowProvenance = cli.NewCommand(workflowProvenanceCmd, nil,
+		[]*cobra.Command{
+			cli.NewCommand(workflowProvenanceDownloadCmd, workflowProvenanceDownloadRun, nil),
+			cli.NewGetCommand(workflowProvenanceVerifyCmd, workflowProvenanceVerifyRun, nil),
+		})
+)
+
+var workflowProvenanceArgs = []cli.Arg{
+	{Name: "project-key"},
+	{Name: "name"},
+	{Name: "number", IsValid: isInt},
+	{Name: "node-run-id", IsValid: isInt},
+}
+
+func isInt(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
+func workflowProvenanceGet(v cli.Values) (*sdk.ProvenanceAttestation, error) {
+	number, _ := strconv.ParseInt(v["number"], 10, 64)
+	nodeRunID, _ := strconv.ParseInt(v["node-run-id"], 10, 64)
+	return client.WorkflowNodeRunProvenance(v["project-key"], v["name"], number, nodeRunID)
+}
+
+var workflowProvenanceDownloadCmd = cli.Command{
+	Name:  "download",
+	Short: "Download the signed provenance attestation of a workflow node run",
+	Args:  workflowProvenanceArgs,
+}
+
+func workflowProvenanceDownloadRun(v cli.Values) error {
+	att, err := workflowProvenanceGet(v)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(att, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+var workflowProvenanceVerifyCmd = cli.Command{
+	Name:  "verify",
+	Short: "Verify the signature of the provenance attestation of a workflow node run with the project public key",
+	Args:  workflowProvenanceArgs,
+}
+
+func workflowProvenanceVerifyRun(v cli.Values) (interface{}, error) {
+	att, err := workflowProvenanceGet(v)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := client.ProjectKeysList(v["project-key"])
+	if err != nil {
+		return nil, err
+	}
+
+	res := sdk.ProvenanceVerification{KeyName: att.KeyName, KeyID: att.KeyID}
+	for _, k := range keys {
+		if k.Name != att.KeyName || k.Type != sdk.KeyTypePgp {
+			continue
+		}
+		if err := att.Verify(k.Public); err != nil {
+			res.Error = err.Error()
+			return res, nil
+		}
+		res.Verified = true
+		return res, nil
+	}
+	res.Error = fmt.Sprintf("key %s not found on project %s", att.KeyName, v["project-key"])
+	return res, nil
+}
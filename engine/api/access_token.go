@@ -0,0 +1,160 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+// checkAccessTokenRequest checks the name and the scopes of an access token to create
+func checkAccessTokenRequest(t *sdk.AccessToken) error {
+	if t.Name == "" {
+		return sdk.WrapError(sdk.ErrWrongRequest, "checkAccessTokenRequest> Name is mandatory")
+	}
+	if len(t.Scopes) == 0 {
+		return sdk.WrapError(sdk.ErrWrongRequest, "checkAccessTokenRequest> At least one scope is mandatory")
+	}
+	for _, s := range t.Scopes {
+		if _, ok := sdk.AccessTokenScopeFromString(string(s)); !ok {
+			return sdk.WrapError(sdk.ErrWrongRequest, "checkAccessTokenRequest> Unknown scope %s", s)
+		}
+	}
+	return nil
+}
+
+// loadUserForAccessToken loads the user of the route, only the user himself and admins can manage its tokens.
+// The tokens of a group can't manage the tokens of any user
+func loadUserForAccessToken(r *http.Request, db gorp.SqlExecutor, c *businesscontext.Ctx) (*sdk.User, error) {
+	if c.AccessToken != nil && c.AccessToken.GroupID != 0 {
+		return nil, sdk.ErrForbidden
+	}
+	username := mux.Vars(r)["username"]
+	u, err := user.LoadUserWithoutAuth(db, username)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadUserForAccessToken> Cannot load user %s", username)
+	}
+	if !c.User.Admin && u.ID != c.User.ID {
+		return nil, sdk.ErrForbidden
+	}
+	return u, nil
+}
+
+func getUserAccessTokensHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	u, err := loadUserForAccessToken(r, db, c)
+	if err != nil {
+		return err
+	}
+	tokens, err := token.LoadAccessTokensByUser(db, u.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getUserAccessTokensHandler> Cannot load access tokens")
+	}
+	return WriteJSON(w, r, tokens, http.StatusOK)
+}
+
+func postUserAccessTokenHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	u, err := loadUserForAccessToken(r, db, c)
+	if err != nil {
+		return err
+	}
+
+	t := &sdk.AccessToken{}
+	if err := UnmarshalBody(r, t); err != nil {
+		return err
+	}
+	if err := checkAccessTokenRequest(t); err != nil {
+		return err
+	}
+	t.UserID = u.ID
+	t.GroupID = 0
+	t.Revoked = false
+
+	if err := token.InsertAccessToken(db, t); err != nil {
+		return sdk.WrapError(err, "postUserAccessTokenHandler> Cannot insert access token")
+	}
+	return WriteJSON(w, r, t, http.StatusCreated)
+}
+
+func deleteUserAccessTokenHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	u, err := loadUserForAccessToken(r, db, c)
+	if err != nil {
+		return err
+	}
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return err
+	}
+
+	t, err := token.LoadAccessTokenByID(db, id)
+	if err != nil {
+		return sdk.WrapError(err, "deleteUserAccessTokenHandler> Cannot load access token %d", id)
+	}
+	if t.UserID != u.ID {
+		return sdk.ErrNotFound
+	}
+	return token.RevokeAccessToken(db, t.ID)
+}
+
+func getGroupAccessTokensHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	groupName := mux.Vars(r)["permGroupName"]
+	g, err := group.LoadGroup(db, groupName)
+	if err != nil {
+		return sdk.WrapError(err, "getGroupAccessTokensHandler> Cannot load group %s", groupName)
+	}
+	tokens, err := token.LoadAccessTokensByGroup(db, g.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getGroupAccessTokensHandler> Cannot load access tokens")
+	}
+	return WriteJSON(w, r, tokens, http.StatusOK)
+}
+
+func postGroupAccessTokenHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	groupName := mux.Vars(r)["permGroupName"]
+	g, err := group.LoadGroup(db, groupName)
+	if err != nil {
+		return sdk.WrapError(err, "postGroupAccessTokenHandler> Cannot load group %s", groupName)
+	}
+
+	t := &sdk.AccessToken{}
+	if err := UnmarshalBody(r, t); err != nil {
+		return err
+	}
+	if err := checkAccessTokenRequest(t); err != nil {
+		return err
+	}
+	t.UserID = 0
+	t.GroupID = g.ID
+	t.Revoked = false
+
+	if err := token.InsertAccessToken(db, t); err != nil {
+		return sdk.WrapError(err, "postGroupAccessTokenHandler> Cannot insert access token")
+	}
+	return WriteJSON(w, r, t, http.StatusCreated)
+}
+
+func deleteGroupAccessTokenHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	groupName := mux.Vars(r)["permGroupName"]
+	g, err := group.LoadGroup(db, groupName)
+	if err != nil {
+		return sdk.WrapError(err, "deleteGroupAccessTokenHandler> Cannot load group %s", groupName)
+	}
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return err
+	}
+
+	t, err := token.LoadAccessTokenByID(db, id)
+	if err != nil {
+		return sdk.WrapError(err, "deleteGroupAccessTokenHandler> Cannot load access token %d", id)
+	}
+	if t.GroupID != g.ID {
+		return sdk.ErrNotFound
+	}
+	return token.RevokeAccessToken(db, t.ID)
+}
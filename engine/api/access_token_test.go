@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/sdk"
+)
+
+// Test_groupAccessTokenNamedAfterAdmin checks that a group access token named after an admin can't act on behalf of the admin
+func Test_groupAccessTokenNamedAfterAdmin(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	router = newRouter(auth.TestLocalAuth(t), mux.NewRouter(), "/Test_groupAccessTokenNamedAfterAdmin")
+	router.init()
+
+	admin, _ := assets.InsertAdminUser(db)
+	u, pass := assets.InsertLambdaUser(db)
+	g := &sdk.Group{Name: sdk.RandomString(10)}
+	test.NoError(t, group.InsertGroup(db, g))
+	test.NoError(t, group.InsertUserInGroup(db, g.ID, u.ID, true))
+
+	//The admin of the group creates a token of the group named after the CDS admin
+	uri := router.getRoute("POST", postGroupAccessTokenHandler, map[string]string{"permGroupName": g.Name})
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.AccessToken{
+		Name:   admin.Username,
+		Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeAdmin},
+	})
+	rec := httptest.NewRecorder()
+	router.mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	groupToken := &sdk.AccessToken{}
+	test.NoError(t, json.Unmarshal(rec.Body.Bytes(), groupToken))
+
+	call := func(method, uri string, i interface{}) int {
+		//The request is authenticated only by the token of the group
+		req := assets.NewAuthentifiedRequest(t, u, pass, method, uri, i)
+		req.Header.Del(sdk.RequestedWithHeader)
+		req.Header.Del(sdk.SessionTokenHeader)
+		req.Header.Del("Authorization")
+		req.Header.Set(sdk.AccessTokenHeader, groupToken.Token)
+		rec := httptest.NewRecorder()
+		router.mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	//The token of the group can't manage the tokens of the admin nor read or update the admin
+	vars := map[string]string{"username": admin.Username}
+	uri = router.getRoute("POST", postUserAccessTokenHandler, vars)
+	test.NotEmpty(t, uri)
+	assert.Equal(t, http.StatusForbidden, call("POST", uri, sdk.AccessToken{
+		Name:   "escalation",
+		Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeAdmin},
+	}))
+	assert.Equal(t, http.StatusForbidden, call("GET", router.getRoute("GET", getUserAccessTokensHandler, vars), nil))
+	assert.Equal(t, http.StatusForbidden, call("GET", router.getRoute("GET", GetUserHandler, vars), nil))
+	assert.Equal(t, http.StatusForbidden, call("PUT", router.getRoute("PUT", UpdateUserHandler, vars), admin))
+
+	tokens, err := token.LoadAccessTokensByUser(db, admin.ID)
+	test.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	//Neither the tokens of the user who created it
+	vars = map[string]string{"username": u.Username}
+	assert.Equal(t, http.StatusForbidden, call("POST", router.getRoute("POST", postUserAccessTokenHandler, vars), sdk.AccessToken{
+		Name:   "escalation",
+		Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeAdmin},
+	}))
+}
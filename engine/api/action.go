@@ -23,6 +23,18 @@ func getActionsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	return WriteJSON(w, r, acts, http.StatusOK)
 }
 
+// pipelineUsingAction is a pipeline using an action, returned by getPipelinesUsingActionHandler
+type pipelineUsingAction struct {
+	ActionID   int    `json:"action_id"`
+	ActionType string `json:"type"`
+	ActionName string `json:"action_name"`
+	PipName    string `json:"pipeline_name"`
+	AppName    string `json:"application_name"`
+	ProjName   string `json:"project_name"`
+	ProjKey    string `json:"key"`
+	StageID    int64  `json:"stage_id"`
+}
+
 func getPipelinesUsingActionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	// Get action name in URL
 	vars := mux.Vars(r)
@@ -51,17 +63,6 @@ func getPipelinesUsingActionHandler(w http.ResponseWriter, r *http.Request, db *
 	}
 	defer rows.Close()
 
-	type pipelineUsingAction struct {
-		ActionID   int    `json:"action_id"`
-		ActionType string `json:"type"`
-		ActionName string `json:"action_name"`
-		PipName    string `json:"pipeline_name"`
-		AppName    string `json:"application_name"`
-		ProjName   string `json:"project_name"`
-		ProjKey    string `json:"key"`
-		StageID    int64  `json:"stage_id"`
-	}
-
 	response := []pipelineUsingAction{}
 
 	for rows.Next() {
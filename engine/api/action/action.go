@@ -84,7 +84,7 @@ func InsertAction(tx gorp.SqlExecutor, a *sdk.Action, public bool) error {
 		for _, cr := range c.Requirements {
 			found := false
 			for _, pr := range a.Requirements {
-				if pr.Type == cr.Type && pr.Value == cr.Value {
+				if sameRequirement(pr, cr) {
 					found = true
 					break
 				}
@@ -233,7 +233,7 @@ func loadActionDependencies(db gorp.SqlExecutor, a *sdk.Action) error {
 		for _, cr := range c.Requirements {
 			found := false
 			for _, pr := range a.Requirements {
-				if pr.Type == cr.Type && pr.Value == cr.Value {
+				if sameRequirement(pr, cr) {
 					found = true
 					break
 				}
@@ -300,7 +300,7 @@ func UpdateActionDB(db gorp.SqlExecutor, a *sdk.Action, userID int64) error {
 		for _, cr := range c.Requirements {
 			found := false
 			for _, pr := range a.Requirements {
-				if pr.Type == cr.Type && pr.Value == cr.Value {
+				if sameRequirement(pr, cr) {
 					found = true
 					break
 				}
@@ -40,6 +40,25 @@ Parse given file to extract Unit Test results.`
 		return err
 	}
 
+	// ----------------------------------- Coverage ---------------------------
+	coverage := sdk.NewAction(sdk.CoverageAction)
+	coverage.Type = sdk.BuiltinAction
+	coverage.Description = `CDS Builtin Action.
+Parse given files to extract the line and branch coverage of the tests.
+The coverage and its delta with the previous run are available in the trigger conditions:
+{{.cds.coverage.lines}}, {{.cds.coverage.lines.delta}}, {{.cds.coverage.lines.base_delta}} for pull requests.`
+	coverage.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: `Path to the coverage reports, a glob pattern is allowed.`,
+		Type:        sdk.StringParameter})
+	coverage.Parameter(sdk.Parameter{
+		Name:        "format",
+		Description: `Format of the reports: cobertura, lcov or go. Detected if empty.`,
+		Type:        sdk.StringParameter})
+	if err := checkBuiltinAction(db, coverage); err != nil {
+		return err
+	}
+
 	// ----------------------------------- Git clone    -----------------------
 	gitclone := sdk.NewAction(sdk.GitCloneAction)
 	gitclone.Type = sdk.BuiltinAction
@@ -94,3 +94,17 @@ func UpdateAllRequirements(db gorp.SqlExecutor, oldValue, newValue, reqType stri
 
 	return actions, nil
 }
+
+// sameRequirement returns true if two requirements are the same.
+// Plugin requirements on the same plugin are the same whatever the required version, the version pinned by the job wins
+func sameRequirement(a, b sdk.Requirement) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	if a.Type == sdk.PluginRequirement {
+		nameA, _ := sdk.PluginRequirementVersion(a.Value)
+		nameB, _ := sdk.PluginRequirementVersion(b.Value)
+		return nameA == nameB
+	}
+	return a.Value == b.Value
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
 	"github.com/ovh/cds/sdk/log"
 	"github.com/ovh/cds/sdk/plugin"
 )
@@ -20,15 +22,12 @@ import (
 func Get(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
 	//FIXME: run this in a jail with apparmor
 	log.Debug("actionplugin.Get> Getting info from '%s' (%s)", name, path)
-	client := plugin.NewClient(context.Background(), name, path, "ID", "http://127.0.0.1:8081", true)
-	defer func() {
-		log.Debug("actionplugin.Get> kill rpc-server")
-		client.Kill()
-	}()
-	log.Debug("actionplugin.Get> Client '%s'", name)
-	_plugin, err := client.Instance()
+	ap, params, err := getGRPCManifest(path)
+	if err == grpcplugin.ErrNotGRPCPlugin {
+		ap, params, err = getRPCManifest(name, path)
+	}
 	if err != nil {
-		return nil, nil, sdk.WrapError(err, "actionplugin.Get> ")
+		return nil, nil, err
 	}
 
 	fi, err := os.Open(path)
@@ -49,20 +48,60 @@ func Get(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
 	hashInBytes := hash.Sum(nil)[:16]
 	md5sumStr := hex.EncodeToString(hashInBytes)
 
-	ap := sdk.ActionPlugin{
-		Filename:    name,
+	ap.Filename = name
+	ap.Path = path
+	ap.Size = stat.Size()
+	ap.Perm = uint32(stat.Mode().Perm())
+	ap.MD5sum = md5sumStr
+
+	return ap, params, nil
+}
+
+//getGRPCManifest reads the manifest of a plugin implementing the GRPC protocol
+func getGRPCManifest(path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
+	client, err := grpcplugin.Start(context.Background(), path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Kill()
+
+	m, err := client.GetManifest(context.Background())
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "actionplugin.getGRPCManifest> ")
+	}
+
+	params := plugin.NewParameters()
+	for _, p := range m.Parameters {
+		params.Add(p.Name, plugin.ParameterType(p.Type), p.Description, p.Value)
+	}
+	ap := &sdk.ActionPlugin{
+		Name:        m.Name,
+		Author:      m.Author,
+		Description: m.Description,
+	}
+	return ap, &params, nil
+}
+
+//getRPCManifest reads the manifest of a plugin implementing the net/rpc protocol
+func getRPCManifest(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
+	client := plugin.NewClient(context.Background(), name, path, "ID", "http://127.0.0.1:8081", true)
+	defer func() {
+		log.Debug("actionplugin.Get> kill rpc-server")
+		client.Kill()
+	}()
+	log.Debug("actionplugin.Get> Client '%s'", name)
+	_plugin, err := client.Instance()
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "actionplugin.Get> ")
+	}
+
+	ap := &sdk.ActionPlugin{
 		Name:        _plugin.Name(),
 		Author:      _plugin.Author(),
 		Description: _plugin.Description(),
-		Path:        path,
-		Size:        stat.Size(),
-		Perm:        uint32(stat.Mode().Perm()),
-		MD5sum:      md5sumStr,
 	}
-
 	params := _plugin.Parameters()
-
-	return &ap, &params, nil
+	return ap, &params, nil
 }
 
 func actionPluginToAction(ap *sdk.ActionPlugin, params *plugin.Parameters) (*sdk.Action, error) {
@@ -117,8 +156,8 @@ func actionPluginToAction(ap *sdk.ActionPlugin, params *plugin.Parameters) (*sdk
 	return a, nil
 }
 
-//Insert create action in database
-func Insert(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters) (*sdk.Action, error) {
+//Insert create action in database with the first version of the plugin
+func Insert(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters, bin *sdk.ActionPluginBinary) (*sdk.Action, error) {
 	a, err := actionPluginToAction(ap, params)
 	if err != nil {
 		return nil, err
@@ -129,15 +168,20 @@ func Insert(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters
 		return nil, err
 	}
 
-	query := `INSERT INTO plugin (name, size, perm, md5sum, object_path) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	if err = db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath).Scan(&ap.ID); err != nil {
+	query := `INSERT INTO plugin (name, size, perm, md5sum, object_path, current_version) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	if err = db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath, ap.Version).Scan(&ap.ID); err != nil {
+		return nil, err
+	}
+
+	if err := insertVersion(db, ap.ID, ap.Version, a.Parameters, bin); err != nil {
 		return nil, err
 	}
 	return a, nil
 }
 
-//Update action in database
-func Update(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters, userID int64) (*sdk.Action, error) {
+//Update action in database with a new version of the plugin, which becomes the current version.
+//Previous versions are kept, a job can still pin them
+func Update(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters, bin *sdk.ActionPluginBinary, userID int64) (*sdk.Action, error) {
 	a, err := actionPluginToAction(ap, params)
 	if err != nil {
 		return nil, err
@@ -154,19 +198,18 @@ func Update(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters
 		return nil, err
 	}
 
-	query := "DELETE FROM plugin WHERE name = $1"
-	if _, err := db.Exec(query, a.Name); err != nil {
+	query := `UPDATE plugin SET size = $2, perm = $3, md5sum = $4, object_path = $5, current_version = $6 WHERE name = $1 RETURNING id`
+	if err = db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath, ap.Version).Scan(&ap.ID); err != nil {
 		return nil, err
 	}
 
-	query = `INSERT INTO plugin (name, size, perm, md5sum, object_path) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	if err = db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath).Scan(&ap.ID); err != nil {
+	if err := insertVersion(db, ap.ID, ap.Version, a.Parameters, bin); err != nil {
 		return nil, err
 	}
 	return a, nil
 }
 
-//Delete action in database
+//Delete action in database with all the versions of the plugin
 func Delete(db *gorp.DbMap, name string, userID int64) error {
 	tx, err := db.Begin()
 	if err != nil {
@@ -180,6 +223,21 @@ func Delete(db *gorp.DbMap, name string, userID int64) error {
 		return err
 	}
 
+	versions, err := LoadVersions(tx, a.Name)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		for _, b := range v.Binaries {
+			if b.SHA256sum == "" {
+				continue
+			}
+			if err := objectstore.ReleaseBlob(tx, b.SHA256sum); err != nil {
+				return err
+			}
+		}
+	}
+
 	query := "DELETE FROM plugin WHERE name = $1"
 	if _, err := tx.Exec(query, a.Name); err != nil {
 		return err
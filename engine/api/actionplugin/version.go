@@ -0,0 +1,218 @@
+package actionplugin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/blang/semver"
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/sdk"
+)
+
+//FirstVersion is the version of a plugin uploaded without version
+const FirstVersion = "1.0.0"
+
+//CheckVersion checks that a version follows semantic versioning
+func CheckVersion(version string) error {
+	if _, err := semver.Parse(version); err != nil {
+		return sdk.WrapError(sdk.ErrPluginVersionInvalid, "CheckVersion> %s: %s", version, err)
+	}
+	return nil
+}
+
+//NextVersion returns the version following the highest version of a plugin: its next patch
+func NextVersion(versions []sdk.ActionPluginVersion) string {
+	if len(versions) == 0 {
+		return FirstVersion
+	}
+	v, err := semver.Parse(versions[0].Version)
+	if err != nil {
+		return FirstVersion
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch+1)
+}
+
+//insertVersion inserts a version of a plugin with its first binary
+func insertVersion(db gorp.SqlExecutor, pluginID int64, version string, params []sdk.Parameter, bin *sdk.ActionPluginBinary) error {
+	btes, err := json.Marshal(params)
+	if err != nil {
+		return sdk.WrapError(err, "insertVersion> Unable to marshal parameters")
+	}
+
+	query := `INSERT INTO plugin_version (plugin_id, version, parameters) VALUES ($1, $2, $3) RETURNING id`
+	if err := db.QueryRow(query, pluginID, version, string(btes)).Scan(&bin.PluginVersionID); err != nil {
+		return sdk.WrapError(err, "insertVersion> Unable to insert version %s of plugin %d", version, pluginID)
+	}
+	bin.Version = version
+	return insertBinary(db, bin)
+}
+
+func insertBinary(db gorp.SqlExecutor, bin *sdk.ActionPluginBinary) error {
+	query := `INSERT INTO plugin_binary (plugin_version_id, os, arch, size, perm, sha256sum, object_path) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	if err := db.QueryRow(query, bin.PluginVersionID, bin.OS, bin.Arch, bin.Size, bin.Perm, bin.SHA256sum, bin.ObjectPath).Scan(&bin.ID); err != nil {
+		return sdk.WrapError(err, "insertBinary> Unable to insert binary %s/%s of plugin version %d", bin.OS, bin.Arch, bin.PluginVersionID)
+	}
+	return nil
+}
+
+//InsertBinary adds the binary for another OS or architecture to an existing version of a plugin
+func InsertBinary(db gorp.SqlExecutor, name, version string, bin *sdk.ActionPluginBinary) error {
+	v, err := LoadVersion(db, name, version)
+	if err != nil {
+		return err
+	}
+	for _, b := range v.Binaries {
+		if b.OS == bin.OS && b.Arch == bin.Arch {
+			return sdk.WrapError(sdk.ErrConflict, "InsertBinary> Version %s of plugin %s already has a binary for %s/%s", version, name, bin.OS, bin.Arch)
+		}
+	}
+	bin.PluginVersionID = v.ID
+	bin.PluginName = name
+	bin.Version = version
+	return insertBinary(db, bin)
+}
+
+//LoadVersions loads all the versions of a plugin with their binaries, highest version first
+func LoadVersions(db gorp.SqlExecutor, name string) ([]sdk.ActionPluginVersion, error) {
+	query := `SELECT plugin_version.id, plugin_version.version, plugin_version.version = plugin.current_version, plugin_version.parameters, plugin_version.created
+	FROM plugin_version JOIN plugin ON plugin.id = plugin_version.plugin_id WHERE plugin.name = $1`
+	rows, err := db.Query(query, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadVersions> Unable to load versions of plugin %s", name)
+	}
+	defer rows.Close()
+
+	versions := []sdk.ActionPluginVersion{}
+	index := map[int64]int{}
+	for rows.Next() {
+		v := sdk.ActionPluginVersion{PluginName: name, Binaries: []sdk.ActionPluginBinary{}}
+		var current sql.NullBool
+		var params sql.NullString
+		if err := rows.Scan(&v.ID, &v.Version, &current, &params, &v.Created); err != nil {
+			return nil, sdk.WrapError(err, "LoadVersions> Unable to scan version of plugin %s", name)
+		}
+		v.Current = current.Bool
+		if params.Valid {
+			if err := json.Unmarshal([]byte(params.String), &v.Parameters); err != nil {
+				return nil, sdk.WrapError(err, "LoadVersions> Unable to unmarshal parameters of version %s of plugin %s", v.Version, name)
+			}
+		}
+		index[v.ID] = len(versions)
+		versions = append(versions, v)
+	}
+	rows.Close()
+
+	query = `SELECT plugin_binary.id, plugin_binary.plugin_version_id, plugin_binary.os, plugin_binary.arch, plugin_binary.size, plugin_binary.perm, plugin_binary.sha256sum, plugin_binary.object_path
+	FROM plugin_binary JOIN plugin_version ON plugin_version.id = plugin_binary.plugin_version_id JOIN plugin ON plugin.id = plugin_version.plugin_id
+	WHERE plugin.name = $1 ORDER BY plugin_binary.os, plugin_binary.arch`
+	binRows, err := db.Query(query, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadVersions> Unable to load binaries of plugin %s", name)
+	}
+	defer binRows.Close()
+
+	for binRows.Next() {
+		b := sdk.ActionPluginBinary{PluginName: name}
+		var size sql.NullInt64
+		var perm sql.NullInt64
+		var sha256sum, objectPath sql.NullString
+		if err := binRows.Scan(&b.ID, &b.PluginVersionID, &b.OS, &b.Arch, &size, &perm, &sha256sum, &objectPath); err != nil {
+			return nil, sdk.WrapError(err, "LoadVersions> Unable to scan binary of plugin %s", name)
+		}
+		b.Size = size.Int64
+		b.Perm = uint32(perm.Int64)
+		b.SHA256sum = sha256sum.String
+		b.ObjectPath = objectPath.String
+		i, ok := index[b.PluginVersionID]
+		if !ok {
+			continue
+		}
+		b.Version = versions[i].Version
+		versions[i].Binaries = append(versions[i].Binaries, b)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		vi, erri := semver.Parse(versions[i].Version)
+		vj, errj := semver.Parse(versions[j].Version)
+		if erri != nil || errj != nil {
+			return versions[i].Version > versions[j].Version
+		}
+		return vi.GT(vj)
+	})
+	return versions, nil
+}
+
+//LoadVersion loads a version of a plugin with its binaries
+func LoadVersion(db gorp.SqlExecutor, name, version string) (*sdk.ActionPluginVersion, error) {
+	versions, err := LoadVersions(db, name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		if versions[i].Version == version {
+			return &versions[i], nil
+		}
+	}
+	return nil, sdk.WrapError(sdk.ErrPluginVersionNotFound, "LoadVersion> Version %s of plugin %s not found", version, name)
+}
+
+//ResolveBinary returns the binary of a plugin for an OS and an architecture, in the highest version matching the constraint.
+//Without constraint, the current version of the plugin is used
+func ResolveBinary(db gorp.SqlExecutor, name, constraint, os, arch string) (*sdk.ActionPluginBinary, error) {
+	versions, err := LoadVersions(db, name)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []string{}
+	binaries := map[string]*sdk.ActionPluginBinary{}
+	for i := range versions {
+		b := versions[i].Binary(os, arch)
+		if b == nil {
+			continue
+		}
+		if constraint == "" && versions[i].Current {
+			return b, nil
+		}
+		candidates = append(candidates, versions[i].Version)
+		binaries[versions[i].Version] = b
+	}
+	if constraint == "" {
+		return nil, sdk.WrapError(sdk.ErrPluginVersionNotFound, "ResolveBinary> No binary of the current version of plugin %s for %s/%s", name, os, arch)
+	}
+
+	version, err := sdk.LatestPluginVersion(candidates, constraint)
+	if err != nil {
+		return nil, sdk.WrapError(err, "ResolveBinary> No binary of plugin %s matching %s for %s/%s", name, constraint, os, arch)
+	}
+	return binaries[version], nil
+}
+
+//SetCurrentVersion makes a version the current version of a plugin, used by the jobs which don't pin a version.
+//The parameters of the plugin action are restored from this version
+func SetCurrentVersion(db gorp.SqlExecutor, name, version string, userID int64) (*sdk.Action, error) {
+	v, err := LoadVersion(db, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := action.LoadPublicAction(db, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "SetCurrentVersion> Unable to load action %s", name)
+	}
+	if v.Parameters != nil {
+		a.Parameters = v.Parameters
+		if err := action.UpdateActionDB(db, a, userID); err != nil {
+			return nil, sdk.WrapError(err, "SetCurrentVersion> Unable to update action %s", name)
+		}
+	}
+
+	if _, err := db.Exec("UPDATE plugin SET current_version = $2 WHERE name = $1", name, version); err != nil {
+		return nil, sdk.WrapError(err, "SetCurrentVersion> Unable to set current version of plugin %s", name)
+	}
+	return a, nil
+}
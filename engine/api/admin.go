@@ -2,11 +2,15 @@ package main
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/businesscontext"
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -33,3 +37,40 @@ func deleteAdminMaintenanceHandler(w http.ResponseWriter, r *http.Request, db *g
 	cache.Delete("maintenance")
 	return nil
 }
+
+// getAdminAuditHandler searches the audit log with the query parameters username, method, url, from, to (RFC3339), offset and limit
+func getAdminAuditHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	f := audit.Filter{
+		Username: r.FormValue("username"),
+		Method:   r.FormValue("method"),
+		URL:      r.FormValue("url"),
+	}
+
+	var err error
+	if from := r.FormValue("from"); from != "" {
+		if f.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getAdminAuditHandler> Invalid from date %s: %s", from, err)
+		}
+	}
+	if to := r.FormValue("to"); to != "" {
+		if f.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getAdminAuditHandler> Invalid to date %s: %s", to, err)
+		}
+	}
+	if offset := r.FormValue("offset"); offset != "" {
+		if f.Offset, err = strconv.Atoi(offset); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getAdminAuditHandler> Invalid offset %s", offset)
+		}
+	}
+	if limit := r.FormValue("limit"); limit != "" {
+		if f.Limit, err = strconv.Atoi(limit); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getAdminAuditHandler> Invalid limit %s", limit)
+		}
+	}
+
+	logs, err := audit.Load(db, f)
+	if err != nil {
+		return sdk.WrapError(err, "getAdminAuditHandler> Unable to load audit log")
+	}
+	return WriteJSON(w, r, logs, http.StatusOK)
+}
@@ -14,7 +14,7 @@ import (
 // LoadGroupByApplication loads all the groups on the given application
 func LoadGroupByApplication(db gorp.SqlExecutor, app *sdk.Application) error {
 	app.ApplicationGroups = []sdk.GroupPermission{}
-	query := `SELECT "group".id, "group".name, application_group.role FROM "group"
+	query := `SELECT "group".id, "group".name, application_group.role, COALESCE(application_group.role_name, '') FROM "group"
 	 		  JOIN application_group ON application_group.group_id = "group".id
 	 		  WHERE application_group.application_id = $1 ORDER BY "group".name ASC`
 	rows, errq := db.Query(query, app.ID)
@@ -26,12 +26,14 @@ func LoadGroupByApplication(db gorp.SqlExecutor, app *sdk.Application) error {
 	for rows.Next() {
 		var group sdk.Group
 		var perm int
-		if err := rows.Scan(&group.ID, &group.Name, &perm); err != nil {
+		var role string
+		if err := rows.Scan(&group.ID, &group.Name, &perm, &role); err != nil {
 			return err
 		}
 		app.ApplicationGroups = append(app.ApplicationGroups, sdk.GroupPermission{
 			Group:      group,
 			Permission: perm,
+			Role:       role,
 		})
 	}
 	return nil
@@ -43,7 +45,7 @@ func LoadPermissions(db gorp.SqlExecutor, group *sdk.Group) error {
 		  SELECT project.projectKey,
 	                 application.name,
 	                 application.id,
-					 application_group.role, application.last_modified
+					 application_group.role, application.last_modified, COALESCE(application_group.role_name, '')
 	      FROM application
 	      JOIN application_group ON application_group.application_id = application.id
 	 	  JOIN project ON application.project_id = project.id
@@ -58,13 +60,15 @@ func LoadPermissions(db gorp.SqlExecutor, group *sdk.Group) error {
 	for rows.Next() {
 		var application sdk.Application
 		var perm int
-		err = rows.Scan(&application.ProjectKey, &application.Name, &application.ID, &perm, &application.LastModified)
+		var role string
+		err = rows.Scan(&application.ProjectKey, &application.Name, &application.ID, &perm, &application.LastModified, &role)
 		if err != nil {
 			return sdk.WrapError(err, "LoadPermission %s (%d)", group.Name, group.ID)
 		}
 		group.ApplicationGroups = append(group.ApplicationGroups, sdk.ApplicationGroup{
 			Application: application,
 			Permission:  perm,
+			Role:        role,
 		})
 	}
 	return nil
@@ -12,7 +12,7 @@ import (
 )
 
 // TriggerPipeline linked to received hook
-func TriggerPipeline(tx gorp.SqlExecutor, h sdk.Hook, branch string, hash string, author string, p *sdk.Pipeline, projectData *sdk.Project) (*sdk.PipelineBuild, error) {
+func TriggerPipeline(tx gorp.SqlExecutor, h sdk.Hook, branch string, hash string, author string, p *sdk.Pipeline, projectData *sdk.Project, extraArgs ...sdk.Parameter) (*sdk.PipelineBuild, error) {
 
 	// Create pipeline args
 	var args []sdk.Parameter
@@ -36,6 +36,9 @@ func TriggerPipeline(tx gorp.SqlExecutor, h sdk.Hook, branch string, hash string
 		Name:  "git.project",
 		Value: h.Project,
 	})
+	for _, e := range extraArgs {
+		sdk.AddParameter(&args, e.Name, e.Type, e.Value)
+	}
 
 	// Load pipeline Argument
 	parameters, err := pipeline.GetAllParametersInPipeline(tx, p.ID)
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/flakytest"
+	"github.com/ovh/cds/sdk"
+)
+
+func getApplicationFlakyTestsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	appName := vars["permApplicationName"]
+
+	app, err := application.LoadByName(db, projectKey, appName, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getApplicationFlakyTestsHandler> Cannot load application %s", appName)
+	}
+
+	flaky, err := flakytest.LoadFlakyTests(db, app.ID, r.FormValue("branch"))
+	if err != nil {
+		return sdk.WrapError(err, "getApplicationFlakyTestsHandler> Cannot load flaky tests")
+	}
+	return WriteJSON(w, r, flaky, http.StatusOK)
+}
+
+func getApplicationTestHistoryHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	appName := vars["permApplicationName"]
+
+	testSuite := r.FormValue("suite")
+	testName := r.FormValue("name")
+	if testSuite == "" || testName == "" {
+		return sdk.WrapError(sdk.ErrWrongRequest, "getApplicationTestHistoryHandler> suite and name are mandatory")
+	}
+
+	limit := defaultLimit
+	if limitS := r.FormValue("limit"); limitS != "" {
+		var errAtoi error
+		limit, errAtoi = strconv.Atoi(limitS)
+		if errAtoi != nil || limit <= 0 || limit > rangeMax {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getApplicationTestHistoryHandler> Invalid limit %s", limitS)
+		}
+	}
+
+	app, err := application.LoadByName(db, projectKey, appName, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getApplicationTestHistoryHandler> Cannot load application %s", appName)
+	}
+
+	history, err := flakytest.LoadHistory(db, app.ID, r.FormValue("branch"), testSuite, testName, limit)
+	if err != nil {
+		return sdk.WrapError(err, "getApplicationTestHistoryHandler> Cannot load tests history")
+	}
+	return WriteJSON(w, r, history, http.StatusOK)
+}
+
+func getApplicationTestQuarantineHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	appName := vars["permApplicationName"]
+
+	app, err := application.LoadByName(db, projectKey, appName, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getApplicationTestQuarantineHandler> Cannot load application %s", appName)
+	}
+
+	quarantine, err := flakytest.LoadQuarantine(db, app.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getApplicationTestQuarantineHandler> Cannot load quarantined tests")
+	}
+	return WriteJSON(w, r, quarantine, http.StatusOK)
+}
+
+func postApplicationTestQuarantineHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	appName := vars["permApplicationName"]
+
+	var q sdk.TestQuarantine
+	if err := UnmarshalBody(r, &q); err != nil {
+		return sdk.WrapError(err, "postApplicationTestQuarantineHandler> Cannot unmarshal request")
+	}
+
+	app, err := application.LoadByName(db, projectKey, appName, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "postApplicationTestQuarantineHandler> Cannot load application %s", appName)
+	}
+
+	q.ApplicationID = app.ID
+	q.Author = c.User.Username
+	if err := flakytest.AddQuarantine(db, &q); err != nil {
+		return sdk.WrapError(err, "postApplicationTestQuarantineHandler> Cannot quarantine test")
+	}
+	return WriteJSON(w, r, q, http.StatusCreated)
+}
+
+func deleteApplicationTestQuarantineHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	appName := vars["permApplicationName"]
+
+	app, err := application.LoadByName(db, projectKey, appName, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "deleteApplicationTestQuarantineHandler> Cannot load application %s", appName)
+	}
+
+	if err := flakytest.DeleteQuarantine(db, app.ID, r.FormValue("suite"), r.FormValue("name")); err != nil {
+		return sdk.WrapError(err, "deleteApplicationTestQuarantineHandler> Cannot release test from quarantine")
+	}
+	return nil
+}
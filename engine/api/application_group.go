@@ -15,6 +15,19 @@ import (
 	"github.com/ovh/cds/sdk"
 )
 
+func getGroupsInApplicationHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	appName := vars["permApplicationName"]
+
+	app, err := application.LoadByName(db, key, appName, c.User, application.LoadOptions.WithGroups)
+	if err != nil {
+		return sdk.WrapError(err, "getGroupsInApplicationHandler> Cannot load application %s", appName)
+	}
+
+	return WriteJSON(w, r, app.ApplicationGroups, http.StatusOK)
+}
+
 func updateGroupRoleOnApplicationHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	// Get project name in URL
 	vars := mux.Vars(r)
@@ -26,6 +39,9 @@ func updateGroupRoleOnApplicationHandler(w http.ResponseWriter, r *http.Request,
 	if err := UnmarshalBody(r, &groupApplication); err != nil {
 		return err
 	}
+	if err := checkGroupRole(&groupApplication); err != nil {
+		return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler> Invalid role")
+	}
 
 	app, errload := application.LoadByName(db, key, appName, c.User)
 	if errload != nil {
@@ -57,6 +73,9 @@ func updateGroupRoleOnApplicationHandler(w http.ResponseWriter, r *http.Request,
 	if err := group.UpdateGroupRoleInApplication(tx, key, appName, groupName, groupApplication.Permission); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler: Cannot update permission for group %s in application %s", groupName, appName)
 	}
+	if err := group.UpdateGroupRoleNameInApplication(tx, key, appName, groupName, groupApplication.Role); err != nil {
+		return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler: Cannot set role of group %s in application %s", groupName, appName)
+	}
 
 	if err := application.UpdateLastModified(tx, app, c.User); err != nil {
 		return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot update last modified date")
@@ -95,6 +114,11 @@ func updateGroupsInApplicationHandler(w http.ResponseWriter, r *http.Request, db
 	if len(groupsPermission) == 0 {
 		return sdk.WrapError(sdk.ErrGroupNeedWrite, "updateGroupsInApplicationHandler: Cannot remove all groups for application %s", appName)
 	}
+	for i := range groupsPermission {
+		if err := checkGroupRole(&groupsPermission[i]); err != nil {
+			return sdk.WrapError(err, "updateGroupsInApplicationHandler> Invalid role")
+		}
+	}
 
 	found := false
 	for _, gp := range groupsPermission {
@@ -125,6 +149,11 @@ func updateGroupsInApplicationHandler(w http.ResponseWriter, r *http.Request, db
 	if err := application.AddGroup(tx, proj, app, c.User, groupsPermission...); err != nil {
 		return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot add groups in application %s", app.Name)
 	}
+	for _, gp := range groupsPermission {
+		if err := group.UpdateGroupRoleNameInApplication(tx, key, app.Name, gp.Group.Name, gp.Role); err != nil {
+			return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot set role of group %s in application %s", gp.Group.Name, app.Name)
+		}
+	}
 
 	if err := application.UpdateLastModified(tx, app, c.User); err != nil {
 		return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot update last modified date")
@@ -152,6 +181,9 @@ func addGroupInApplicationHandler(w http.ResponseWriter, r *http.Request, db *go
 	if err := UnmarshalBody(r, &groupPermission); err != nil {
 		return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot unmarshal request")
 	}
+	if err := checkGroupRole(&groupPermission); err != nil {
+		return sdk.WrapError(err, "addGroupInApplicationHandler> Invalid role")
+	}
 
 	proj, err := project.Load(db, key, c.User)
 	if err != nil {
@@ -177,6 +209,9 @@ func addGroupInApplicationHandler(w http.ResponseWriter, r *http.Request, db *go
 	if err := application.AddGroup(tx, proj, app, c.User, groupPermission); err != nil {
 		return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot add group %s in application %s", g.Name, app.Name)
 	}
+	if err := group.UpdateGroupRoleNameInApplication(tx, key, app.Name, g.Name, groupPermission.Role); err != nil {
+		return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot set role of group %s in application %s", g.Name, app.Name)
+	}
 
 	if err := application.UpdateLastModified(tx, app, c.User); err != nil {
 		return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot update application last modified date")
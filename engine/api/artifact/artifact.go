@@ -205,17 +205,39 @@ func insertArtifact(db gorp.SqlExecutor, pipelineID, applicationID int64, enviro
 	return nil
 }
 
-// SaveWorkflowFile Insert file in db and write it in data directory
-func SaveWorkflowFile(art *sdk.WorkflowNodeRunArtifact, content io.ReadCloser) error {
-	objectPath, err := objectstore.StoreArtifact(art, content)
+// SaveWorkflowFile writes the file in the objectstore. Files are deduplicated by their SHA-256 digest,
+// which is set on the artifact
+func SaveWorkflowFile(db gorp.SqlExecutor, art *sdk.WorkflowNodeRunArtifact, content io.ReadSeeker) error {
+	digest, objectPath, err := objectstore.StoreBlob(db, content)
 	if err != nil {
 		return sdk.WrapError(err, "SaveWorkflowFile> Cannot store artifact")
 	}
 	log.Debug("objectpath=%s\n", objectPath)
+	art.SHA256sum = digest
 	art.ObjectPath = objectPath
 	return nil
 }
 
+// DeleteWorkflowFile removes the file of the artifact from the objectstore. Deduplicated files are
+// only removed when no more artifact references them
+func DeleteWorkflowFile(db gorp.SqlExecutor, art *sdk.WorkflowNodeRunArtifact) error {
+	if art.SHA256sum != "" {
+		return objectstore.ReleaseBlob(db, art.SHA256sum)
+	}
+	if err := objectstore.DeleteArtifact(art); err != nil && !strings.Contains(err.Error(), "404") {
+		return err
+	}
+	return nil
+}
+
+// StreamWorkflowFile streams the file of a workflow artifact
+func StreamWorkflowFile(w io.Writer, art *sdk.WorkflowNodeRunArtifact) error {
+	if art.SHA256sum != "" {
+		return StreamFile(w, &objectstore.Blob{SHA256sum: art.SHA256sum})
+	}
+	return StreamFile(w, art)
+}
+
 // SaveFile Insert file in db and write it in data directory
 func SaveFile(db *gorp.DbMap, p *sdk.Pipeline, a *sdk.Application, art sdk.Artifact, content io.ReadCloser, e *sdk.Environment) error {
 	tx, errB := db.Begin()
@@ -0,0 +1,12 @@
+package artifact
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbArtifactRetention sdk.ArtifactRetention
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbArtifactRetention{}, "project_artifact_retention", false, "project_id"))
+}
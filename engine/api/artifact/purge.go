@@ -0,0 +1,286 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//purgeCandidate is an artifact with the information needed to apply retention rules
+type purgeCandidate struct {
+	artifact sdk.PurgedArtifact
+	//group identifies the runs artifacts are compared with: same application, pipeline and environment, or same workflow
+	group string
+	//workflowRunID, workflowNodeRunID and sha256sum are only set for workflow artifacts, they are needed to delete the stored object
+	workflowRunID     int64
+	workflowNodeRunID int64
+	sha256sum         string
+	tagged            bool
+}
+
+//Purger is the artifact purger main goroutine
+func Purger(c context.Context, DBFunc func() *gorp.DbMap) {
+	tick := time.NewTicker(1 * time.Hour).C
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting artifact.Purger: %v", c.Err())
+				return
+			}
+		case <-tick:
+			if _, err := PurgeRun(DBFunc()); err != nil {
+				log.Warning("artifact.Purger> Error : %s", err)
+				continue
+			}
+		}
+	}
+}
+
+//PurgeRun is the core function of the purger goroutine, it applies the retention rules of all projects.
+//The retention rules are locked during the purge, so that only one API instance purges the artifacts at a time
+func PurgeRun(db *gorp.DbMap) ([]sdk.ArtifactPurgeReport, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, sdk.WrapError(err, "artifact.PurgeRun> Unable to start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := lockRetentions(tx); err != nil {
+		log.Debug("artifact.PurgeRun> Artifacts are already being purged: %s", err)
+		return nil, nil
+	}
+
+	log.Debug("artifact.PurgeRun> Purging artifacts...")
+
+	rs, err := LoadAllRetentions(tx)
+	if err != nil {
+		return nil, sdk.WrapError(err, "artifact.PurgeRun> Unable to load retentions")
+	}
+
+	reports := []sdk.ArtifactPurgeReport{}
+	for _, r := range rs {
+		if !r.IsEnabled() {
+			continue
+		}
+		report, err := Purge(db, r, false)
+		if err != nil {
+			log.Warning("artifact.PurgeRun> Unable to purge artifacts of project %d: %s", r.ProjectID, err)
+			continue
+		}
+		if len(report.Artifacts) > 0 {
+			log.Info("artifact.PurgeRun> %d artifacts (%d bytes) purged on project %s", len(report.Artifacts), report.Size, report.ProjectKey)
+		}
+		reports = append(reports, *report)
+	}
+
+	return reports, nil
+}
+
+//Purge applies the retention rules on the artifacts of a project. With dryRun, nothing is deleted
+//and the report lists the artifacts which would be removed
+func Purge(db *gorp.DbMap, r sdk.ArtifactRetention, dryRun bool) (*sdk.ArtifactPurgeReport, error) {
+	key, err := db.SelectStr("SELECT projectkey FROM project WHERE id = $1", r.ProjectID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Purge> Unable to load project %d", r.ProjectID)
+	}
+
+	report := &sdk.ArtifactPurgeReport{
+		ProjectKey: key,
+		DryRun:     dryRun,
+		Retention:  r,
+		Artifacts:  []sdk.PurgedArtifact{},
+	}
+	if !r.IsEnabled() {
+		return report, nil
+	}
+
+	pipCandidates, err := loadPipelineArtifactCandidates(db, r.ProjectID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Purge> Unable to load pipeline artifacts of project %s", key)
+	}
+	wfCandidates, err := loadWorkflowArtifactCandidates(db, r.ProjectID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Purge> Unable to load workflow artifacts of project %s", key)
+	}
+
+	for _, c := range selectArtifactsToPurge(r, append(pipCandidates, wfCandidates...), time.Now()) {
+		if !dryRun {
+			if err := deletePurgedArtifact(db, c); err != nil {
+				log.Warning("Purge> Unable to delete %s artifact %d: %s", c.artifact.Type, c.artifact.ID, err)
+				continue
+			}
+		}
+		report.Artifacts = append(report.Artifacts, c.artifact)
+		report.Size += c.artifact.Size
+	}
+
+	return report, nil
+}
+
+//selectArtifactsToPurge returns the candidates which are not kept by the retention rules
+func selectArtifactsToPurge(r sdk.ArtifactRetention, candidates []purgeCandidate, now time.Time) []purgeCandidate {
+	//Compute the rank of each run in its group and branch, the most recent run is ranked 1
+	runs := map[string][]int64{}
+	for _, c := range candidates {
+		k := c.group + "/" + c.artifact.Branch
+		found := false
+		for _, n := range runs[k] {
+			if n == c.artifact.Number {
+				found = true
+				break
+			}
+		}
+		if !found {
+			runs[k] = append(runs[k], c.artifact.Number)
+		}
+	}
+	ranks := map[string]int{}
+	for k, ns := range runs {
+		sort.Slice(ns, func(i, j int) bool { return ns[i] > ns[j] })
+		for i, n := range ns {
+			ranks[fmt.Sprintf("%s/%d", k, n)] = i + 1
+		}
+	}
+
+	res := []purgeCandidate{}
+	for _, c := range candidates {
+		if r.KeepTagged && c.tagged {
+			continue
+		}
+
+		rank := ranks[fmt.Sprintf("%s/%s/%d", c.group, c.artifact.Branch, c.artifact.Number)]
+		switch {
+		case r.KeepLast > 0 && rank <= r.KeepLast:
+			//The last runs are kept whatever their age
+			continue
+		case r.MaxAge > 0 && now.Sub(c.artifact.Created) > time.Duration(r.MaxAge)*24*time.Hour:
+			c.artifact.Reason = fmt.Sprintf("older than %d days", r.MaxAge)
+		case r.KeepLast > 0 && r.MaxAge == 0:
+			c.artifact.Reason = fmt.Sprintf("not in the %d last runs of branch %s", r.KeepLast, c.artifact.Branch)
+		default:
+			continue
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+func loadPipelineArtifactCandidates(db gorp.SqlExecutor, projectID int64) ([]purgeCandidate, error) {
+	query := `SELECT artifact.id, artifact.name, artifact.tag, COALESCE(artifact.size, 0), COALESCE(artifact.created, NOW()), artifact.build_number,
+			artifact.application_id, artifact.pipeline_id, artifact.environment_id,
+			application.name, pipeline.name, environment.name,
+			COALESCE(pipeline_build.vcs_changes_branch, ''), COALESCE(pipeline_build.args, '')
+		FROM artifact
+		JOIN pipeline ON pipeline.id = artifact.pipeline_id
+		JOIN application ON application.id = artifact.application_id
+		JOIN environment ON environment.id = artifact.environment_id
+		LEFT JOIN pipeline_build ON pipeline_build.pipeline_id = artifact.pipeline_id
+			AND pipeline_build.application_id = artifact.application_id
+			AND pipeline_build.environment_id = artifact.environment_id
+			AND pipeline_build.build_number = artifact.build_number
+		WHERE pipeline.project_id = $1`
+
+	rows, err := db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cs := []purgeCandidate{}
+	for rows.Next() {
+		c := purgeCandidate{artifact: sdk.PurgedArtifact{Type: sdk.PipelineArtifactType}}
+		var appID, pipID, envID int64
+		var args string
+		if err := rows.Scan(&c.artifact.ID, &c.artifact.Name, &c.artifact.Tag, &c.artifact.Size, &c.artifact.Created, &c.artifact.Number,
+			&appID, &pipID, &envID, &c.artifact.Application, &c.artifact.Pipeline, &c.artifact.Environment,
+			&c.artifact.Branch, &args); err != nil {
+			return nil, err
+		}
+		c.group = fmt.Sprintf("%d-%d-%d", appID, pipID, envID)
+		if args != "" {
+			var params []sdk.Parameter
+			if err := json.Unmarshal([]byte(args), &params); err != nil {
+				log.Warning("loadPipelineArtifactCandidates> Unable to read parameters of build %d: %s", c.artifact.Number, err)
+			}
+			c.tagged = sdk.ParameterValue(params, "git.tag") != ""
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+func loadWorkflowArtifactCandidates(db gorp.SqlExecutor, projectID int64) ([]purgeCandidate, error) {
+	query := `SELECT workflow_node_run_artifacts.id, workflow_node_run_artifacts.name, workflow_node_run_artifacts.tag,
+			COALESCE(workflow_node_run_artifacts.size, 0), COALESCE(workflow_node_run_artifacts.created, NOW()),
+			workflow_node_run_artifacts.workflow_run_id, workflow_node_run_artifacts.workflow_node_run_id,
+			COALESCE(workflow_node_run_artifacts.sha256sum, ''),
+			workflow_run.num, workflow.id, workflow.name,
+			COALESCE((SELECT value FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = 'git.branch'), ''),
+			COALESCE((SELECT value FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = 'git.tag'), '')
+		FROM workflow_node_run_artifacts
+		JOIN workflow_run ON workflow_run.id = workflow_node_run_artifacts.workflow_run_id
+		JOIN workflow ON workflow.id = workflow_run.workflow_id
+		WHERE workflow_run.project_id = $1`
+
+	rows, err := db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cs := []purgeCandidate{}
+	for rows.Next() {
+		c := purgeCandidate{artifact: sdk.PurgedArtifact{Type: sdk.WorkflowArtifactType}}
+		var workflowID int64
+		var gitTag string
+		if err := rows.Scan(&c.artifact.ID, &c.artifact.Name, &c.artifact.Tag, &c.artifact.Size, &c.artifact.Created,
+			&c.workflowRunID, &c.workflowNodeRunID, &c.sha256sum, &c.artifact.Number, &workflowID, &c.artifact.Workflow,
+			&c.artifact.Branch, &gitTag); err != nil {
+			return nil, err
+		}
+		c.group = fmt.Sprintf("%d", workflowID)
+		c.tagged = gitTag != ""
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+//deletePurgedArtifact removes the artifact from the objectstore and the database
+func deletePurgedArtifact(db *gorp.DbMap, c purgeCandidate) error {
+	if c.artifact.Type == sdk.PipelineArtifactType {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := DeleteArtifact(tx, c.artifact.ID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	art := sdk.WorkflowNodeRunArtifact{
+		ID:                c.artifact.ID,
+		WorkflowID:        c.workflowRunID,
+		WorkflowNodeRunID: c.workflowNodeRunID,
+		Name:              c.artifact.Name,
+		Tag:               c.artifact.Tag,
+		SHA256sum:         c.sha256sum,
+	}
+	if err := DeleteWorkflowFile(db, &art); err != nil {
+		return sdk.WrapError(err, "deletePurgedArtifact> Cannot delete artifact in store")
+	}
+	if _, err := db.Exec("DELETE FROM workflow_node_run_artifacts WHERE id = $1", c.artifact.ID); err != nil {
+		return sdk.WrapError(err, "deletePurgedArtifact> Cannot delete artifact in DB")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package artifact
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_selectArtifactsToPurge(t *testing.T) {
+	now := time.Now()
+	candidate := func(id int64, group, branch string, number int64, age int, tagged bool) purgeCandidate {
+		return purgeCandidate{
+			artifact: sdk.PurgedArtifact{
+				ID:      id,
+				Branch:  branch,
+				Number:  number,
+				Created: now.Add(-time.Duration(age) * 24 * time.Hour),
+			},
+			group:  group,
+			tagged: tagged,
+		}
+	}
+
+	candidates := []purgeCandidate{
+		candidate(1, "app-pip", "master", 1, 40, false),
+		candidate(2, "app-pip", "master", 2, 20, true),
+		candidate(3, "app-pip", "master", 3, 10, false),
+		candidate(4, "app-pip", "master", 3, 10, false),
+		candidate(5, "app-pip", "master", 4, 1, false),
+		candidate(6, "app-pip", "feat", 2, 20, false),
+		candidate(7, "workflow", "master", 1, 40, false),
+	}
+
+	ids := func(cs []purgeCandidate) []int64 {
+		res := []int64{}
+		for _, c := range cs {
+			res = append(res, c.artifact.ID)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name string
+		rule sdk.ArtifactRetention
+		want []int64
+	}{
+		{
+			name: "keep last runs per branch",
+			rule: sdk.ArtifactRetention{KeepLast: 2},
+			want: []int64{1, 2},
+		},
+		{
+			name: "keep last runs per branch and tagged runs",
+			rule: sdk.ArtifactRetention{KeepLast: 2, KeepTagged: true},
+			want: []int64{1},
+		},
+		{
+			name: "expire after some days",
+			rule: sdk.ArtifactRetention{MaxAge: 15, KeepTagged: true},
+			want: []int64{1, 6, 7},
+		},
+		{
+			name: "keep last runs per branch whatever their age",
+			rule: sdk.ArtifactRetention{KeepLast: 1, MaxAge: 15},
+			want: []int64{1, 2},
+		},
+		{
+			name: "disabled rules",
+			rule: sdk.ArtifactRetention{KeepTagged: true},
+			want: []int64{},
+		},
+	}
+	for _, tt := range tests {
+		got := selectArtifactsToPurge(tt.rule, candidates, now)
+		assert.Equal(t, tt.want, ids(got), tt.name)
+	}
+}
@@ -0,0 +1,61 @@
+package artifact
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+//LoadRetention loads the artifact retention rules of a project. If the project has no rules, disabled rules are returned
+func LoadRetention(db gorp.SqlExecutor, projectID int64) (*sdk.ArtifactRetention, error) {
+	var r dbArtifactRetention
+	if err := db.SelectOne(&r, "SELECT * FROM project_artifact_retention WHERE project_id = $1", projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return &sdk.ArtifactRetention{ProjectID: projectID, KeepTagged: true}, nil
+		}
+		return nil, sdk.WrapError(err, "LoadRetention> Unable to load artifact retention of project %d", projectID)
+	}
+	res := sdk.ArtifactRetention(r)
+	return &res, nil
+}
+
+//LoadAllRetentions loads the artifact retention rules of all projects
+func LoadAllRetentions(db gorp.SqlExecutor) ([]sdk.ArtifactRetention, error) {
+	var rs []dbArtifactRetention
+	if _, err := db.Select(&rs, "SELECT * FROM project_artifact_retention ORDER BY project_id"); err != nil {
+		return nil, sdk.WrapError(err, "LoadAllRetentions> Unable to load artifact retentions")
+	}
+	res := make([]sdk.ArtifactRetention, len(rs))
+	for i := range rs {
+		res[i] = sdk.ArtifactRetention(rs[i])
+	}
+	return res, nil
+}
+
+//UpsertRetention inserts or updates the artifact retention rules of a project
+func UpsertRetention(db gorp.SqlExecutor, r *sdk.ArtifactRetention) error {
+	if r.KeepLast < 0 || r.MaxAge < 0 {
+		return sdk.ErrWrongRequest
+	}
+
+	dbr := dbArtifactRetention(*r)
+	n, err := db.Update(&dbr)
+	if err != nil {
+		return sdk.WrapError(err, "UpsertRetention> Unable to update artifact retention of project %d", r.ProjectID)
+	}
+	if n == 0 {
+		if err := db.Insert(&dbr); err != nil {
+			return sdk.WrapError(err, "UpsertRetention> Unable to insert artifact retention of project %d", r.ProjectID)
+		}
+	}
+	return nil
+}
+
+//lockRetentions locks the retention rules until the end of the transaction, they can still be read but not updated.
+//It fails right away if the retention rules are already locked
+func lockRetentions(db gorp.SqlExecutor) error {
+	_, err := db.Exec("LOCK TABLE project_artifact_retention IN SHARE ROW EXCLUSIVE MODE NOWAIT")
+	return err
+}
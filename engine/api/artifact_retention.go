@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+)
+
+func getArtifactRetentionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	p, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getArtifactRetentionHandler> Cannot load project")
+	}
+
+	rule, errR := artifact.LoadRetention(db, p.ID)
+	if errR != nil {
+		return sdk.WrapError(errR, "getArtifactRetentionHandler> Cannot load artifact retention")
+	}
+
+	return WriteJSON(w, r, rule, http.StatusOK)
+}
+
+func putArtifactRetentionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	var rule sdk.ArtifactRetention
+	if err := UnmarshalBody(r, &rule); err != nil {
+		return err
+	}
+
+	p, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "putArtifactRetentionHandler> Cannot load project")
+	}
+	rule.ProjectID = p.ID
+
+	if err := artifact.UpsertRetention(db, &rule); err != nil {
+		return sdk.WrapError(err, "putArtifactRetentionHandler> Cannot save artifact retention")
+	}
+
+	return WriteJSON(w, r, rule, http.StatusOK)
+}
+
+func getArtifactPurgeReportHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	p, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getArtifactPurgeReportHandler> Cannot load project")
+	}
+
+	rule, errR := artifact.LoadRetention(db, p.ID)
+	if errR != nil {
+		return sdk.WrapError(errR, "getArtifactPurgeReportHandler> Cannot load artifact retention")
+	}
+
+	report, errReport := artifact.Purge(db, *rule, true)
+	if errReport != nil {
+		return sdk.WrapError(errReport, "getArtifactPurgeReportHandler> Cannot compute purge report")
+	}
+
+	return WriteJSON(w, r, report, http.StatusOK)
+}
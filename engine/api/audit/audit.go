@@ -0,0 +1,268 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Filter is used to search the audit log. Empty fields are ignored
+type Filter struct {
+	Username string
+	Method   string
+	URL      string
+	From     time.Time
+	To       time.Time
+	Offset   int
+	Limit    int
+}
+
+// Insert stores an audit log entry, the diff is computed from the states before and after the call
+func Insert(db gorp.SqlExecutor, l *sdk.AuditLog) error {
+	if l.Diff == nil {
+		l.Diff = Diff(l.Before, l.After)
+	}
+	diff, err := json.Marshal(l.Diff)
+	if err != nil {
+		return sdk.WrapError(err, "audit.Insert> Unable to marshal diff")
+	}
+	if l.Created.IsZero() {
+		l.Created = time.Now()
+	}
+
+	query := `INSERT INTO audit_log (created, user_id, username, remote_ip, method, route, url, status, data_before, data_after, diff)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	if err := db.QueryRow(query, l.Created, l.UserID, l.Username, l.RemoteIP, l.Method, l.Route, l.URL, l.Status,
+		nullJSON(l.Before), nullJSON(l.After), string(diff)).Scan(&l.ID); err != nil {
+		return sdk.WrapError(err, "audit.Insert> Unable to insert audit log")
+	}
+	return nil
+}
+
+func nullJSON(b json.RawMessage) sql.NullString {
+	if len(b) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(b), Valid: true}
+}
+
+// Load searches the audit log, most recent entries first
+func Load(db gorp.SqlExecutor, f Filter) ([]sdk.AuditLog, error) {
+	clauses := []string{}
+	args := []interface{}{}
+	addClause := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if f.Username != "" {
+		addClause("username = $%d", f.Username)
+	}
+	if f.Method != "" {
+		addClause("method = $%d", strings.ToUpper(f.Method))
+	}
+	if f.URL != "" {
+		addClause("url LIKE $%d", "%"+f.URL+"%")
+	}
+	if !f.From.IsZero() {
+		addClause("created >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		addClause("created <= $%d", f.To)
+	}
+	if f.Limit <= 0 || f.Limit > 500 {
+		f.Limit = 500
+	}
+
+	query := `SELECT id, created, user_id, username, remote_ip, method, route, url, status, data_before, data_after, diff FROM audit_log`
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY created DESC, id DESC OFFSET %d LIMIT %d", f.Offset, f.Limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "audit.Load> Unable to load audit log")
+	}
+	defer rows.Close()
+
+	logs := []sdk.AuditLog{}
+	for rows.Next() {
+		var l sdk.AuditLog
+		var userID sql.NullInt64
+		var before, after, diff sql.NullString
+		if err := rows.Scan(&l.ID, &l.Created, &userID, &l.Username, &l.RemoteIP, &l.Method, &l.Route, &l.URL, &l.Status, &before, &after, &diff); err != nil {
+			return nil, sdk.WrapError(err, "audit.Load> Unable to scan audit log")
+		}
+		l.UserID = userID.Int64
+		if before.Valid {
+			l.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			l.After = json.RawMessage(after.String)
+		}
+		if diff.Valid {
+			if err := json.Unmarshal([]byte(diff.String), &l.Diff); err != nil {
+				log.Warning("audit.Load> Unable to unmarshal diff of audit log %d: %s", l.ID, err)
+			}
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+// Purge deletes the audit log entries older than the retention
+func Purge(db gorp.SqlExecutor, retention time.Duration) (int64, error) {
+	res, err := db.Exec("DELETE FROM audit_log WHERE created < $1", time.Now().Add(-retention))
+	if err != nil {
+		return 0, sdk.WrapError(err, "audit.Purge> Unable to purge audit log")
+	}
+	return res.RowsAffected()
+}
+
+// Purger deletes periodically the audit log entries older than the retention. Nothing is deleted with a retention of 0
+func Purger(c context.Context, DBFunc func() *gorp.DbMap, retention time.Duration) {
+	if retention <= 0 {
+		log.Info("audit.Purger> No audit log retention, the audit log is kept forever")
+		return
+	}
+
+	tick := time.NewTicker(1 * time.Hour)
+	defer tick.Stop()
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting audit.Purger: %v", c.Err())
+			}
+			return
+		case <-tick.C:
+			n, err := Purge(DBFunc(), retention)
+			if err != nil {
+				log.Warning("audit.Purger> %s", err)
+				continue
+			}
+			log.Debug("audit.Purger> %d audit log entries deleted", n)
+		}
+	}
+}
+
+// Diff returns the fields which differ between two JSON documents
+func Diff(before, after json.RawMessage) []sdk.AuditDiff {
+	b := flatten(before)
+	a := flatten(after)
+
+	fields := map[string]bool{}
+	for k := range b {
+		fields[k] = true
+	}
+	for k := range a {
+		fields[k] = true
+	}
+
+	diff := []sdk.AuditDiff{}
+	for k := range fields {
+		if b[k] != a[k] {
+			diff = append(diff, sdk.AuditDiff{Field: k, Before: b[k], After: a[k]})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Field < diff[j].Field })
+	return diff
+}
+
+// flatten returns the values of a JSON document indexed by their path
+func flatten(doc json.RawMessage) map[string]string {
+	res := map[string]string{}
+	if len(doc) == 0 {
+		return res
+	}
+	var i interface{}
+	if err := json.Unmarshal(doc, &i); err != nil {
+		return res
+	}
+	flattenValue(res, "", i)
+	return res
+}
+
+func flattenValue(res map[string]string, path string, i interface{}) {
+	switch v := i.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			flattenValue(res, p, e)
+		}
+	case []interface{}:
+		for k, e := range v {
+			flattenValue(res, fmt.Sprintf("%s[%d]", path, k), e)
+		}
+	case nil:
+	default:
+		res[path] = fmt.Sprintf("%v", v)
+	}
+}
+
+// secretFields are the JSON fields whose values are never stored in the audit log
+var secretFields = []string{"password", "private", "secret", "token"}
+
+// Sanitize replaces the values of the secret fields of a JSON document by a placeholder
+func Sanitize(doc json.RawMessage) json.RawMessage {
+	if len(doc) == 0 {
+		return doc
+	}
+	var i interface{}
+	if err := json.Unmarshal(doc, &i); err != nil {
+		return nil
+	}
+	b, err := json.Marshal(sanitizeValue(i))
+	if err != nil {
+		return nil
+	}
+	return json.RawMessage(b)
+}
+
+func sanitizeValue(i interface{}) interface{} {
+	switch v := i.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if isSecretField(k) {
+				if e != nil && e != "" {
+					v[k] = sdk.PasswordPlaceholder
+				}
+				continue
+			}
+			v[k] = sanitizeValue(e)
+		}
+		//Variables and parameters hold their secret values in a value field
+		if t, ok := v["type"].(string); ok && sdk.NeedPlaceholder(t) {
+			if _, ok := v["value"]; ok {
+				v["value"] = sdk.PasswordPlaceholder
+			}
+		}
+	case []interface{}:
+		for k, e := range v {
+			v[k] = sanitizeValue(e)
+		}
+	}
+	return i
+}
+
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	for _, f := range secretFields {
+		if strings.Contains(name, f) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestDiff(t *testing.T) {
+	before := json.RawMessage(`{"name":"foo","keys":[{"name":"k1"},{"name":"k2"}],"description":"bar"}`)
+	after := json.RawMessage(`{"name":"foo","keys":[{"name":"k1"}],"description":"baz"}`)
+
+	diff := Diff(before, after)
+	assert.Equal(t, []sdk.AuditDiff{
+		{Field: "description", Before: "bar", After: "baz"},
+		{Field: "keys[1].name", Before: "k2", After: ""},
+	}, diff)
+
+	assert.Len(t, Diff(nil, nil), 0)
+	assert.Len(t, Diff(nil, after), 3)
+}
+
+func TestSanitize(t *testing.T) {
+	doc := json.RawMessage(`{"name":"foo","password":"secret","variables":[{"name":"a","type":"password","value":"b"},{"name":"c","type":"string","value":"d"}]}`)
+
+	var res map[string]interface{}
+	assert.NoError(t, json.Unmarshal(Sanitize(doc), &res))
+	assert.Equal(t, "foo", res["name"])
+	assert.Equal(t, sdk.PasswordPlaceholder, res["password"])
+
+	variables := res["variables"].([]interface{})
+	assert.Equal(t, sdk.PasswordPlaceholder, variables[0].(map[string]interface{})["value"])
+	assert.Equal(t, "d", variables[1].(map[string]interface{})["value"])
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/hatchery"
 	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/token"
 	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
@@ -162,6 +163,39 @@ func getUserPersistentSession(db gorp.SqlExecutor, store sessionstore.Store, hea
 	return true
 }
 
+// CheckAccessToken checks personal access tokens and service account tokens
+func CheckAccessToken(db gorp.SqlExecutor, headers http.Header, c *ctx.Ctx) error {
+	t, err := token.LoadAccessToken(db, headers.Get(sdk.AccessTokenHeader))
+	if err != nil {
+		return err
+	}
+	if !t.IsValid() {
+		return fmt.Errorf("access token %d is revoked or expired", t.ID)
+	}
+
+	if t.UserID != 0 {
+		u, err := user.LoadUserWithoutAuthByID(db, t.UserID)
+		if err != nil {
+			return fmt.Errorf("cannot load user of access token %d: %s", t.ID, err)
+		}
+		//Admin privileges are granted only with the admin scope
+		if !t.HasScope(sdk.AccessTokenScopeAdmin) {
+			u.Admin = false
+		}
+		c.User = u
+	} else {
+		//Service account tokens act on behalf of their group. The name of the token is not a username,
+		//so that the token can't be taken for a user with the same name
+		c.User = &sdk.User{Fullname: t.Name}
+	}
+	c.AccessToken = t
+
+	if err := token.UpdateAccessTokenLastUsed(db, t.ID); err != nil {
+		log.Warning("CheckAccessToken> %s", err)
+	}
+	return nil
+}
+
 //GetWorker returns the worker instance from its id
 func GetWorker(db gorp.SqlExecutor, workerID string) (*sdk.Worker, error) {
 	// Load worker
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	ctx "github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestCheckAccessToken(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	read := &sdk.AccessToken{Name: "read", UserID: u.ID, Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeRead}, ExpireAt: &future}
+	test.NoError(t, token.InsertAccessToken(db, read))
+	expired := &sdk.AccessToken{Name: "expired", UserID: u.ID, Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeAdmin}, ExpireAt: &past}
+	test.NoError(t, token.InsertAccessToken(db, expired))
+	revoked := &sdk.AccessToken{Name: "revoked", UserID: u.ID, Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeAdmin}}
+	test.NoError(t, token.InsertAccessToken(db, revoked))
+	test.NoError(t, token.RevokeAccessToken(db, revoked.ID))
+
+	check := func(tk string) (*ctx.Ctx, error) {
+		h := http.Header{}
+		h.Set(sdk.AccessTokenHeader, tk)
+		c := &ctx.Ctx{}
+		return c, CheckAccessToken(db, h, c)
+	}
+
+	//A valid token authenticates its owner, without admin privileges unless it has the admin scope
+	c, err := check(read.Token)
+	test.NoError(t, err)
+	assert.Equal(t, u.Username, c.User.Username)
+	assert.False(t, c.User.Admin)
+	assert.Equal(t, read.ID, c.AccessToken.ID)
+
+	_, err = check(expired.Token)
+	assert.Error(t, err)
+
+	_, err = check(revoked.Token)
+	assert.Error(t, err)
+
+	_, err = check("unknown")
+	assert.Error(t, err)
+
+	//A token of a group named after a user doesn't authenticate this user
+	g := &sdk.Group{Name: sdk.RandomString(10)}
+	test.NoError(t, group.InsertGroup(db, g))
+	service := &sdk.AccessToken{Name: u.Username, GroupID: g.ID, Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeAdmin}}
+	test.NoError(t, token.InsertAccessToken(db, service))
+	c, err = check(service.Token)
+	test.NoError(t, err)
+	assert.Empty(t, c.User.Username)
+	assert.Zero(t, c.User.ID)
+	assert.False(t, c.User.Admin)
+	assert.Equal(t, g.ID, c.AccessToken.GroupID)
+}
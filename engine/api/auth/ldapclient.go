@@ -318,6 +318,11 @@ func (c *LDAPClient) AuthentifyUser(db gorp.SqlExecutor, u *sdk.User, password s
 
 //CheckAuthHeader returns the func to heck http headers.
 func (c *LDAPClient) CheckAuthHeader(db *gorp.DbMap, headers http.Header, ctx *businesscontext.Ctx) error {
+	//Check if its an access token
+	if headers.Get(sdk.AccessTokenHeader) != "" {
+		return CheckAccessToken(db, headers, ctx)
+	}
+
 	//Check if its coming from CLI
 	if headers.Get(sdk.RequestedWithHeader) == sdk.RequestedWithValue {
 		if getUserPersistentSession(db, c.Store(), headers, ctx) {
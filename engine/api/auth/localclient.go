@@ -49,6 +49,11 @@ func (c *LocalClient) AuthentifyUser(db gorp.SqlExecutor, u *sdk.User, password
 
 //CheckAuthHeader checks http headers.
 func (c *LocalClient) CheckAuthHeader(db *gorp.DbMap, headers http.Header, ctx *businesscontext.Ctx) error {
+	//Check if its an access token
+	if headers.Get(sdk.AccessTokenHeader) != "" {
+		return CheckAccessToken(db, headers, ctx)
+	}
+
 	//Check if its coming from CLI
 	if headers.Get(sdk.RequestedWithHeader) == sdk.RequestedWithValue {
 		if getUserPersistentSession(db, c.Store(), headers, ctx) {
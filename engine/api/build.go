@@ -14,9 +14,11 @@ import (
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/businesscontext"
 	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/flakytest"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/stats"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
@@ -379,6 +381,12 @@ func takePipelineBuildJobHandler(w http.ResponseWriter, r *http.Request, db *gor
 		return sdk.WrapError(errSecret, "takePipelineBuildJobHandler> Cannot load action build secrets")
 	}
 
+	//Pull requests from forks must not have access to project secrets
+	if repositoriesmanager.WithholdSecrets(pbJob.Parameters) {
+		log.Info("takePipelineBuildJobHandler> Withholding secrets of pipeline build job %d triggered by a pull request from a fork", pbJob.ID)
+		pbji.Secrets = nil
+	}
+
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "takePipelineBuildJobHandler> Cannot commit transaction")
 	}
@@ -717,6 +725,20 @@ func addBuildTestResultsHandler(w http.ResponseWriter, r *http.Request, db *gorp
 		return sdk.WrapError(err, "addBuildVariableHandler> cannot unmarshal request")
 	}
 
+	// Record the tests history of the application to detect flaky tests
+	flaky, err := flakytest.Record(db, a.ID, pb.Trigger.VCSChangesBranch, pb.Trigger.VCSChangesHash, pb.BuildNumber, new)
+	if err != nil {
+		return sdk.WrapError(err, "addBuildTestResultsHandler> Cannot record tests history")
+	}
+	for _, f := range flaky {
+		log.Info("addBuildTestResultsHandler> %s/%s is flaky on application %s branch %s (%d flips)", f.TestSuite, f.TestName, a.Name, f.Branch, f.Flips)
+	}
+
+	quarantine, err := flakytest.LoadQuarantine(db, a.ID)
+	if err != nil {
+		return sdk.WrapError(err, "addBuildTestResultsHandler> Cannot load quarantined tests")
+	}
+
 	// Load existing and merge
 	tests, err := pipeline.LoadTestResults(db, pb.ID)
 	if err != nil {
@@ -752,7 +774,9 @@ func addBuildTestResultsHandler(w http.ResponseWriter, r *http.Request, db *gorp
 	}
 
 	stats.TestEvent(db, p.ProjectID, a.ID, tests)
-	return nil
+
+	//The worker ignores the failures of the quarantined tests
+	return WriteJSON(w, r, quarantine, http.StatusOK)
 }
 
 func getBuildTestResultsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
@@ -6,8 +6,9 @@ import (
 
 // Ctx gather information about http call origin
 type Ctx struct {
-	Agent    string
-	User     *sdk.User
-	Worker   *sdk.Worker
-	Hatchery *sdk.Hatchery
+	Agent       string
+	User        *sdk.User
+	Worker      *sdk.Worker
+	Hatchery    *sdk.Hatchery
+	AccessToken *sdk.AccessToken
 }
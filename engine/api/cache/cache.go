@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ovh/cds/sdk/log"
 )
@@ -37,6 +38,7 @@ type Store interface {
 	Publish(queueName string, value interface{})
 	Subscribe(queueName string) PubSub
 	GetMessageFromSubscription(c context.Context, pb PubSub) (string, error)
+	TakeToken(key string, rate float64, burst int) (bool, time.Duration)
 }
 
 //Initialize the global cache in memory, or redis
@@ -161,3 +163,11 @@ func GetMessageFromSubscription(c context.Context, pb PubSub) (string, error) {
 	}
 	return s.GetMessageFromSubscription(c, pb)
 }
+
+// TakeToken takes a token from a rate limit bucket, it returns false and the duration to wait if the bucket is empty
+func TakeToken(key string, rate float64, burst int) (bool, time.Duration) {
+	if s == nil {
+		return true, 0
+	}
+	return s.TakeToken(key, rate, burst)
+}
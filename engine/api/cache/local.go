@@ -20,6 +20,9 @@ type LocalStore struct {
 	Data   map[string][]byte
 	Queues map[string]*list.List
 	TTL    int
+
+	buckets   map[string]*localBucket
+	nextSweep int64
 }
 
 //Get a key from local store
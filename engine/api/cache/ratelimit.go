@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+//tokenBucket is the state of a rate limit: the bucket is refilled with rate tokens per second, up to burst tokens
+type tokenBucket struct {
+	Tokens float64 `json:"tokens"`
+	Last   int64   `json:"last"`
+}
+
+//take takes a token from the bucket at now (in milliseconds). It returns false and the duration to wait
+//for the next token if the bucket is empty
+func (b *tokenBucket) take(now int64, rate float64, burst int) (bool, time.Duration) {
+	if b.Last == 0 {
+		b.Tokens = float64(burst)
+	} else if now > b.Last {
+		b.Tokens = math.Min(float64(burst), b.Tokens+float64(now-b.Last)/1000*rate)
+	}
+	b.Last = now
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return true, 0
+	}
+	wait := math.Ceil((1 - b.Tokens) / rate * 1000)
+	return false, time.Duration(wait) * time.Millisecond
+}
+
+//bucketTTL is the duration after which an unused bucket is full again
+func bucketTTL(rate float64, burst int) int {
+	return int(math.Ceil(float64(burst)/rate)) + 1
+}
+
+//bucketsSweepInterval is the interval, in milliseconds, between two removals of the expired buckets of a LocalStore
+const bucketsSweepInterval = 60 * 1000
+
+//localBucket is a bucket of a LocalStore with its expiration date in milliseconds
+type localBucket struct {
+	tokenBucket
+	expire int64
+}
+
+//TakeToken takes a token from the bucket key, refilled with rate tokens per second up to burst tokens.
+//It returns false and the duration to wait for the next token if the bucket is empty
+func (s *LocalStore) TakeToken(key string, rate float64, burst int) (bool, time.Duration) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	s.sweepBuckets(now)
+
+	if s.buckets == nil {
+		s.buckets = map[string]*localBucket{}
+	}
+	b := s.buckets[key]
+	if b == nil || b.expire <= now {
+		b = &localBucket{}
+		s.buckets[key] = b
+	}
+	ok, wait := b.take(now, rate, burst)
+	b.expire = now + int64(bucketTTL(rate, burst))*1000
+	return ok, wait
+}
+
+//sweepBuckets removes the expired buckets, at most once per sweep interval. The mutex must be held
+func (s *LocalStore) sweepBuckets(now int64) {
+	if now < s.nextSweep {
+		return
+	}
+	s.nextSweep = now + bucketsSweepInterval
+	for k, b := range s.buckets {
+		if b.expire <= now {
+			delete(s.buckets, k)
+		}
+	}
+}
+
+//takeTokenScript is the token bucket algorithm, run atomically by redis so that all the API instances share the buckets
+const takeTokenScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local b = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(b[1])
+local last = tonumber(b[2])
+if tokens == nil or last == nil then
+	tokens = burst
+elseif now > last then
+	tokens = math.min(burst, tokens + (now - last) / 1000 * rate)
+end
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "last", tostring(now))
+redis.call("EXPIRE", KEYS[1], tonumber(ARGV[4]))
+return wait
+`
+
+//TakeToken takes a token from the bucket key, refilled with rate tokens per second up to burst tokens.
+//It returns false and the duration to wait for the next token if the bucket is empty
+func (s *RedisStore) TakeToken(key string, rate float64, burst int) (bool, time.Duration) {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return true, 0
+	}
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	res, err := s.Client.Eval(takeTokenScript, []string{key},
+		strconv.FormatFloat(rate, 'f', -1, 64), burst, now, bucketTTL(rate, burst)).Result()
+	if err != nil {
+		//The requests are not limited if redis is not available
+		log.Warning("redis> Error taking token %s : %s", key, err)
+		return true, 0
+	}
+	wait, _ := res.(int64)
+	if wait > 0 {
+		return false, time.Duration(wait) * time.Millisecond
+	}
+	return true, 0
+}
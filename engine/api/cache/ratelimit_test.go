@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	b := tokenBucket{}
+	// 2 tokens per second, up to 3 tokens
+	for i := 0; i < 3; i++ {
+		ok, _ := b.take(1000, 2, 3)
+		assert.True(t, ok)
+	}
+	ok, wait := b.take(1000, 2, 3)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	ok, _ = b.take(1500, 2, 3)
+	assert.True(t, ok)
+
+	// The bucket is never refilled over the burst
+	ok, _ = b.take(60000, 2, 3)
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, b.Tokens)
+}
+
+func TestLocalStoreTakeToken(t *testing.T) {
+	s := &LocalStore{
+		Mutex:  &sync.Mutex{},
+		Data:   map[string][]byte{},
+		Queues: map[string]*list.List{},
+	}
+	ok, _ := s.TakeToken("ratelimit:user:foo", 1, 1)
+	assert.True(t, ok)
+	ok, wait := s.TakeToken("ratelimit:user:foo", 1, 1)
+	assert.False(t, ok)
+	assert.True(t, wait > 0 && wait <= time.Second)
+
+	ok, _ = s.TakeToken("ratelimit:user:bar", 1, 1)
+	assert.True(t, ok)
+	assert.Len(t, s.buckets, 2)
+
+	//The expired buckets are removed by the next sweep
+	for _, b := range s.buckets {
+		b.expire = 1
+	}
+	s.nextSweep = 0
+	ok, _ = s.TakeToken("ratelimit:user:foo", 1, 1)
+	assert.True(t, ok)
+	assert.Len(t, s.buckets, 1)
+}
@@ -41,11 +41,20 @@ var statusCmd = &cobra.Command{
 	Run:   statusCmdFunc,
 }
 
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify schema",
+	Long:  "Compares the tables and columns of the database with the gorp mappings of the API.",
+	Run:   verifyCmdFunc,
+}
+
 var (
-	sqlMigrateDir       string
-	sqlMigrateDryRun    bool
-	sqlMigrateLimitUp   int
-	sqlMigrateLimitDown int
+	sqlMigrateDir         string
+	sqlMigrateDryRun      bool
+	sqlMigrateOnline      bool
+	sqlMigrateLockTimeout time.Duration
+	sqlMigrateLimitUp     int
+	sqlMigrateLimitDown   int
 )
 
 func setFlags(cmd *cobra.Command) {
@@ -65,12 +74,16 @@ func init() {
 	setFlags(upgradeCmd)
 	setFlags(downgradeCmd)
 	setFlags(statusCmd)
+	setFlags(verifyCmd)
 	DBCmd.AddCommand(upgradeCmd)
 	DBCmd.AddCommand(downgradeCmd)
 	DBCmd.AddCommand(statusCmd)
+	DBCmd.AddCommand(verifyCmd)
 
 	upgradeCmd.Flags().BoolVarP(&sqlMigrateDryRun, "dry-run", "", false, "Dry run upgrade")
 	upgradeCmd.Flags().IntVarP(&sqlMigrateLimitUp, "limit", "", 0, "Max number of migrations to apply (0 = unlimited)")
+	upgradeCmd.Flags().BoolVarP(&sqlMigrateOnline, "online", "", false, "Apply only the online migrations, while the API is running: stops at the first offline migration")
+	upgradeCmd.Flags().DurationVarP(&sqlMigrateLockTimeout, "lock-timeout", "", 5*time.Second, "Lock timeout of the online migrations (0 = no timeout)")
 
 	downgradeCmd.Flags().BoolVarP(&sqlMigrateDryRun, "dry-run", "", false, "Dry run downgrade")
 	downgradeCmd.Flags().IntVarP(&sqlMigrateLimitDown, "limit", "", 1, "Max number of migrations to apply (0 = unlimited)")
@@ -80,16 +93,17 @@ type statusRow struct {
 	ID        string
 	Migrated  bool
 	AppliedAt time.Time
+	Mode      string
 }
 
 func upgradeCmdFunc(cmd *cobra.Command, args []string) {
-	if err := ApplyMigrations(migrate.Up, sqlMigrateDryRun, sqlMigrateLimitUp); err != nil {
+	if err := ApplyMigrations(migrate.Up, sqlMigrateDryRun, sqlMigrateOnline, sqlMigrateLimitUp); err != nil {
 		sdk.Exit("Error: %s\n", err)
 	}
 }
 
 func downgradeCmdFunc(cmd *cobra.Command, args []string) {
-	if err := ApplyMigrations(migrate.Down, sqlMigrateDryRun, sqlMigrateLimitDown); err != nil {
+	if err := ApplyMigrations(migrate.Down, sqlMigrateDryRun, false, sqlMigrateLimitDown); err != nil {
 		sdk.Exit("Error: %s\n", err)
 	}
 }
@@ -115,7 +129,7 @@ func statusCmdFunc(cmd *cobra.Command, args []string) {
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Migration", "Applied"})
+	table.SetHeader([]string{"Migration", "Mode", "Applied"})
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
 	table.SetColWidth(60)
@@ -123,9 +137,14 @@ func statusCmdFunc(cmd *cobra.Command, args []string) {
 	rows := make(map[string]*statusRow)
 
 	for _, m := range migrations {
+		o, err := loadMigrationOptions(sqlMigrateDir, m.Id)
+		if err != nil {
+			sdk.Exit("Error: %s\n", err)
+		}
 		rows[m.Id] = &statusRow{
 			ID:       m.Id,
 			Migrated: false,
+			Mode:     o.mode(),
 		}
 	}
 
@@ -142,11 +161,13 @@ func statusCmdFunc(cmd *cobra.Command, args []string) {
 		if rows[m.Id].Migrated {
 			table.Append([]string{
 				m.Id,
+				rows[m.Id].Mode,
 				rows[m.Id].AppliedAt.String(),
 			})
 		} else {
 			table.Append([]string{
 				m.Id,
+				rows[m.Id].Mode,
 				"no",
 			})
 		}
@@ -155,8 +176,9 @@ func statusCmdFunc(cmd *cobra.Command, args []string) {
 	table.Render()
 }
 
-//ApplyMigrations applies migration (or not depending on dryrun flag)
-func ApplyMigrations(dir migrate.MigrationDirection, dryrun bool, limit int) error {
+//ApplyMigrations applies migration (or not depending on dryrun flag). If online is true, only the online migrations
+//are applied, while the API is running
+func ApplyMigrations(dir migrate.MigrationDirection, dryrun, online bool, limit int) error {
 	db, err := Init(dbUser, dbPassword, dbName, dbHost, dbPort, dbSSLMode, dbTimeout, dbMaxConn)
 	if err != nil {
 		sdk.Exit("Error: %s\n", err)
@@ -173,7 +195,15 @@ func ApplyMigrations(dir migrate.MigrationDirection, dryrun bool, limit int) err
 		}
 
 		for _, m := range migrations {
-			printMigration(m, dir)
+			o, err := loadMigrationOptions(sqlMigrateDir, m.Id)
+			if err != nil {
+				return err
+			}
+			if online && !o.online {
+				fmt.Printf("==> Would stop at offline migration %s\n", m.Id)
+				break
+			}
+			printMigration(m, dir, o)
 		}
 		return nil
 	}
@@ -189,9 +219,10 @@ func ApplyMigrations(dir migrate.MigrationDirection, dryrun bool, limit int) err
 
 	defer unlockMigrate(db, hostname)
 
-	n, err := migrate.ExecMax(db, "postgres", source, dir, limit)
+	n, err := executeMigrations(db, source, dir, limit, online, sqlMigrateLockTimeout)
 	if err != nil {
-		return fmt.Errorf("Migration failed: %s", err)
+		fmt.Printf("Applied %d migrations before failure\n", n)
+		return err
 	}
 
 	if n == 1 {
@@ -203,14 +234,20 @@ func ApplyMigrations(dir migrate.MigrationDirection, dryrun bool, limit int) err
 	return nil
 }
 
-func printMigration(m *migrate.PlannedMigration, dir migrate.MigrationDirection) {
+func printMigration(m *migrate.PlannedMigration, dir migrate.MigrationDirection, o migrationOptions) {
 	if dir == migrate.Up {
-		fmt.Printf("==> Would apply migration %s (up)\n", m.Id)
+		fmt.Printf("==> Would apply migration %s (up, %s)\n", m.Id, o.mode())
+		for _, q := range o.prechecks {
+			fmt.Printf("-- precheck: %s\n", q)
+		}
 		for _, q := range m.Up {
 			fmt.Println(q)
 		}
+		for _, q := range o.postchecks {
+			fmt.Printf("-- postcheck: %s\n", q)
+		}
 	} else if dir == migrate.Down {
-		fmt.Printf("==> Would apply migration %s (down)\n", m.Id)
+		fmt.Printf("==> Would apply migration %s (down, %s)\n", m.Id, o.mode())
 		for _, q := range m.Down {
 			fmt.Println(q)
 		}
@@ -22,8 +22,10 @@ func (g gorpLogger) Printf(format string, v ...interface{}) {
 }
 
 var (
-	lastDB    *sql.DB
-	lastDBMap *gorp.DbMap
+	lastDB           *sql.DB
+	lastDBMap        *gorp.DbMap
+	lastReplicaDB    *sql.DB
+	lastReplicaDBMap *gorp.DbMap
 )
 
 //DBMap returns a propor intialized gorp.DBMap pointer
@@ -31,6 +33,9 @@ func DBMap(db *sql.DB) *gorp.DbMap {
 	if db == lastDB && lastDBMap != nil && db == lastDBMap.Db {
 		return lastDBMap
 	}
+	if db != nil && db == lastReplicaDB && lastReplicaDBMap != nil && db == lastReplicaDBMap.Db {
+		return lastReplicaDBMap
+	}
 
 	dbmap := &gorp.DbMap{Db: db, Dialect: gorp.PostgresDialect{}, TypeConverter: new(TypeConverter)}
 
@@ -42,8 +47,14 @@ func DBMap(db *sql.DB) *gorp.DbMap {
 		dbmap.AddTableWithName(m.Target, m.Name).SetKeys(m.AutoIncrement, m.Keys...)
 	}
 
-	lastDB = db
-	lastDBMap = dbmap
+	//The replica has its own DbMap, not to rebuild them when the requests alternate between the databases
+	if isReplica(db) {
+		lastReplicaDB = db
+		lastReplicaDBMap = dbmap
+	} else {
+		lastDB = db
+		lastDBMap = dbmap
+	}
 
 	return dbmap
 }
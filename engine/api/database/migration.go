@@ -0,0 +1,237 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rubenv/sql-migrate"
+)
+
+//migrationDirectivePrefix prefixes the CDS directives of the migrations, written before "-- +migrate Up":
+//	-- +cds online                   the migration can be applied while the API is running
+//	-- +cds precheck <SQL query>     the query must return true before applying the migration
+//	-- +cds postcheck <SQL query>    the query must return true after applying the migration
+//The migrations are offline by default: they must be applied with the API stopped
+const migrationDirectivePrefix = "-- +cds "
+
+//migrationOptions are the CDS directives of a migration
+type migrationOptions struct {
+	online     bool
+	prechecks  []string
+	postchecks []string
+}
+
+//mode returns "online" or "offline"
+func (o migrationOptions) mode() string {
+	if o.online {
+		return "online"
+	}
+	return "offline"
+}
+
+//parseMigrationOptions reads the CDS directives of a migration
+func parseMigrationOptions(r io.Reader) (migrationOptions, error) {
+	var o migrationOptions
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, migrationDirectivePrefix) {
+			continue
+		}
+		directive := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, migrationDirectivePrefix)), " ", 2)
+		switch directive[0] {
+		case "online":
+			o.online = true
+		case "precheck", "postcheck":
+			if len(directive) < 2 || strings.TrimSpace(directive[1]) == "" {
+				return o, fmt.Errorf("missing query in directive %s", line)
+			}
+			if directive[0] == "precheck" {
+				o.prechecks = append(o.prechecks, strings.TrimSpace(directive[1]))
+			} else {
+				o.postchecks = append(o.postchecks, strings.TrimSpace(directive[1]))
+			}
+		default:
+			return o, fmt.Errorf("unknown directive %s", line)
+		}
+	}
+	return o, scanner.Err()
+}
+
+//loadMigrationOptions reads the CDS directives of a migration file of the migration directory
+func loadMigrationOptions(dir, id string) (migrationOptions, error) {
+	f, err := os.Open(filepath.Join(dir, id))
+	if err != nil {
+		return migrationOptions{}, err
+	}
+	defer f.Close()
+
+	o, err := parseMigrationOptions(f)
+	if err != nil {
+		return o, fmt.Errorf("Migration %s: %s", id, err)
+	}
+	return o, nil
+}
+
+//concurrentlyRegexp matches the statements which can't run in a transaction, such as CREATE INDEX CONCURRENTLY
+var concurrentlyRegexp = regexp.MustCompile(`(?i)\bCONCURRENTLY\b`)
+
+//needsNoTransaction returns true if one of the queries can't run in a transaction
+func needsNoTransaction(queries []string) bool {
+	for _, q := range queries {
+		if concurrentlyRegexp.MatchString(q) {
+			return true
+		}
+	}
+	return false
+}
+
+//runCheck runs a check query, which must return true
+func runCheck(db *sql.DB, query string) error {
+	var ok bool
+	if err := db.QueryRow(query).Scan(&ok); err != nil {
+		return fmt.Errorf("check %s failed: %s", query, err)
+	}
+	if !ok {
+		return fmt.Errorf("check %s returned false", query)
+	}
+	return nil
+}
+
+//checkValidIndexes fails if an index is invalid. A failed CREATE INDEX CONCURRENTLY leaves an invalid index behind,
+//which would be silently kept by a retry using IF NOT EXISTS
+func checkValidIndexes(db *sql.DB) error {
+	rows, err := db.Query(`SELECT c.relname FROM pg_index i
+	JOIN pg_class c ON c.oid = i.indexrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE NOT i.indisvalid AND n.nspname = current_schema()`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var invalid []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		invalid = append(invalid, name)
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid indexes %s: drop them before applying the migration again", strings.Join(invalid, ", "))
+	}
+	return rows.Err()
+}
+
+//applyMigration applies a planned migration and records it. The online migrations are applied with a lock timeout,
+//so that they fail instead of blocking the requests of the API behind their locks
+func applyMigration(db *sql.DB, dbMap *gorp.DbMap, m *migrate.PlannedMigration, dir migrate.MigrationDirection, o migrationOptions, lockTimeout time.Duration) error {
+	record := func(e gorp.SqlExecutor) error {
+		if dir == migrate.Up {
+			return e.Insert(&migrate.MigrationRecord{Id: m.Id, AppliedAt: time.Now()})
+		}
+		_, err := e.Delete(&migrate.MigrationRecord{Id: m.Id})
+		return err
+	}
+
+	if needsNoTransaction(m.Queries) {
+		//The statements run on a dedicated connection without statement timeout: a concurrent index build is
+		//longer than the timeout of the API connections
+		ctx := context.Background()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if _, err := conn.ExecContext(ctx, "SET statement_timeout = 0"); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "RESET statement_timeout")
+
+		for _, q := range m.Queries {
+			if _, err := conn.ExecContext(ctx, q); err != nil {
+				return err
+			}
+		}
+		if err := checkValidIndexes(db); err != nil {
+			return err
+		}
+		return record(dbMap)
+	}
+
+	tx, err := dbMap.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if o.online && lockTimeout > 0 {
+		if _, err := tx.Exec(fmt.Sprintf("SET LOCAL lock_timeout = %d", lockTimeout/time.Millisecond)); err != nil {
+			return err
+		}
+	}
+	for _, q := range m.Queries {
+		if _, err := tx.Exec(q); err != nil {
+			return err
+		}
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+//executeMigrations applies the planned migrations one by one, with their checks. If online is true, it stops at
+//the first offline migration
+func executeMigrations(db *sql.DB, source migrate.FileMigrationSource, dir migrate.MigrationDirection, limit int, online bool, lockTimeout time.Duration) (int, error) {
+	migrations, dbMap, err := migrate.PlanMigration(db, "postgres", source, dir, limit)
+	if err != nil {
+		return 0, fmt.Errorf("Cannot plan migration: %s", err)
+	}
+
+	applied := 0
+	for _, m := range migrations {
+		o, err := loadMigrationOptions(source.Dir, m.Id)
+		if err != nil {
+			return applied, err
+		}
+		if online && !o.online {
+			return applied, fmt.Errorf("Migration %s is offline: stop the API and upgrade without --online", m.Id)
+		}
+
+		if dir == migrate.Up {
+			for _, q := range o.prechecks {
+				if err := runCheck(db, q); err != nil {
+					return applied, fmt.Errorf("Migration %s: %s", m.Id, err)
+				}
+			}
+		}
+
+		start := time.Now()
+		fmt.Printf("Applying migration %s (%s)\n", m.Id, o.mode())
+		if err := applyMigration(db, dbMap, m, dir, o, lockTimeout); err != nil {
+			return applied, fmt.Errorf("Migration %s failed: %s", m.Id, err)
+		}
+		applied++
+		fmt.Printf("Applied migration %s in %v\n", m.Id, time.Since(start))
+
+		if dir == migrate.Up {
+			for _, q := range o.postchecks {
+				if err := runCheck(db, q); err != nil {
+					return applied, fmt.Errorf("Migration %s: %s", m.Id, err)
+				}
+			}
+		}
+	}
+	return applied, nil
+}
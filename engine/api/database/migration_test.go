@@ -0,0 +1,50 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseMigrationOptions(t *testing.T) {
+	o, err := parseMigrationOptions(strings.NewReader(`-- +cds online
+-- +cds precheck SELECT to_regclass('workflow_node_run') IS NOT NULL
+-- +cds postcheck SELECT COUNT(1) = 1 FROM pg_indexes WHERE indexname = 'idx_workflow_node_run_status'
+
+-- +migrate Up
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_workflow_node_run_status ON workflow_node_run(status);
+
+-- +migrate Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_workflow_node_run_status;
+`))
+	assert.NoError(t, err)
+	assert.True(t, o.online)
+	assert.Equal(t, "online", o.mode())
+	assert.Equal(t, []string{"SELECT to_regclass('workflow_node_run') IS NOT NULL"}, o.prechecks)
+	assert.Equal(t, []string{"SELECT COUNT(1) = 1 FROM pg_indexes WHERE indexname = 'idx_workflow_node_run_status'"}, o.postchecks)
+
+	o, err = parseMigrationOptions(strings.NewReader("-- +migrate Up\nALTER TABLE application ADD COLUMN foo TEXT;\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "offline", o.mode())
+
+	_, err = parseMigrationOptions(strings.NewReader("-- +cds precheck\n"))
+	assert.Error(t, err)
+
+	_, err = parseMigrationOptions(strings.NewReader("-- +cds onlin\n"))
+	assert.Error(t, err)
+}
+
+func Test_loadMigrationOptions(t *testing.T) {
+	o, err := loadMigrationOptions("../../sql", "047_workflow_run_indexes.sql")
+	assert.NoError(t, err)
+	assert.True(t, o.online)
+	assert.Len(t, o.prechecks, 1)
+	assert.Len(t, o.postchecks, 1)
+}
+
+func Test_needsNoTransaction(t *testing.T) {
+	assert.True(t, needsNoTransaction([]string{"CREATE INDEX concurrently idx_foo ON foo(bar);"}))
+	assert.True(t, needsNoTransaction([]string{"SELECT 1;", "DROP INDEX CONCURRENTLY IF EXISTS idx_foo;"}))
+	assert.False(t, needsNoTransaction([]string{"CREATE INDEX idx_foo ON foo(bar);"}))
+}
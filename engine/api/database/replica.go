@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+var (
+	replica         *sql.DB
+	replicaMaxLag   time.Duration
+	replicaLagQuery string
+	replicaMutex    = &sync.RWMutex{}
+	replicaLag      time.Duration
+	replicaErr      error
+
+	//errReplicaPrivileges is returned when the replica user cannot read pg_stat_wal_receiver
+	errReplicaPrivileges = fmt.Errorf("insufficient privileges to read pg_stat_wal_receiver, the replica user needs the pg_read_all_stats role (superuser with PostgreSQL 9.6)")
+)
+
+//replicaLagQuery96 and replicaLagQuery10 return whether the database is a replica, its replication lag in seconds
+//and whether the user can read the state of the WAL receiver. The lag is 0 when everything received has been replayed
+//and the WAL receiver still gets messages from the primary: the replay timestamp doesn't move when there is no write
+//on the primary. Without any message for wal_receiver_timeout, the replica is stalled and the lag is the age of the
+//last replayed transaction. The details of pg_stat_wal_receiver are only visible to the superusers, and to the members
+//of pg_read_all_stats since PostgreSQL 10: other users only see the pid of the WAL receiver
+const (
+	replicaLagQuery96 = `SELECT pg_is_in_recovery(), CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_xlog_receive_location() = pg_last_xlog_replay_location() AND EXISTS (
+			SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming' AND (
+				current_setting('wal_receiver_timeout') = '0'
+				OR last_msg_receipt_time > now() - current_setting('wal_receiver_timeout')::interval
+			)
+		) THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END, NOT EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE pid IS NOT NULL AND status IS NULL)`
+	replicaLagQuery10 = `SELECT pg_is_in_recovery(), CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() AND EXISTS (
+			SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming' AND (
+				current_setting('wal_receiver_timeout') = '0'
+				OR last_msg_receipt_time > now() - current_setting('wal_receiver_timeout')::interval
+			)
+		) THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END, NOT EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE pid IS NOT NULL AND status IS NULL)`
+)
+
+// InitReplica initializes the connection to a read replica of the database. The user and the password default to
+// the ones of the primary database. The user must be a member of pg_read_all_stats (a superuser with PostgreSQL 9.6)
+// to check the replication lag. The replica is used by the read only handlers as long as its replication lag is under
+// maxLag
+func InitReplica(user, password, name, host, port, sslmode string, timeout, maxconn int, maxLag time.Duration) error {
+	replicaMutex.Lock()
+	defer replicaMutex.Unlock()
+
+	if replica != nil {
+		if err := replica.Close(); err != nil {
+			log.Error("Cannot close connection to replica DB : %s", err)
+		}
+		replica = nil
+	}
+
+	if user == "" {
+		user = dbUser
+	}
+	if password == "" {
+		password = dbPassword
+	}
+	if host == "" || port == "" || name == "" {
+		return fmt.Errorf("Missing replica database infos")
+	}
+
+	if timeout < 200 || timeout > 15000 {
+		timeout = 3000
+	}
+	if maxconn <= 0 {
+		maxconn = dbMaxConn
+	}
+
+	dsn := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=%s connect_timeout=10 statement_timeout=%d", user, password, name, host, port, sslmode, timeout)
+	r, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return err
+	}
+
+	var version int
+	if err := r.QueryRow("SELECT current_setting('server_version_num')::int").Scan(&version); err != nil {
+		r.Close()
+		return err
+	}
+	replicaLagQuery = replicaLagQuery96
+	if version >= 100000 {
+		replicaLagQuery = replicaLagQuery10
+	}
+
+	r.SetMaxOpenConns(maxconn)
+	r.SetMaxIdleConns(int(maxconn / 2))
+
+	replica = r
+	replicaMaxLag = maxLag
+	replicaLag, replicaErr = checkReplicaLag(r, replicaLagQuery)
+	if replicaErr != nil {
+		log.Warning("Database> replica is not usable: %s", replicaErr)
+	}
+	return nil
+}
+
+//checkReplicaLag returns the replication lag of the replica. Without the privileges to read the state of the WAL
+//receiver, an idle replica can't be told from a stalled one and the lag can't be trusted
+func checkReplicaLag(r *sql.DB, query string) (time.Duration, error) {
+	var inRecovery, privileged bool
+	var lag float64
+	if err := r.QueryRow(query).Scan(&inRecovery, &lag, &privileged); err != nil {
+		return 0, err
+	}
+	if !inRecovery {
+		return 0, fmt.Errorf("database is not a replica")
+	}
+	if !privileged {
+		return 0, errReplicaPrivileges
+	}
+	return time.Duration(lag * float64(time.Second)), nil
+}
+
+// ReplicaLagChecker runs in a goroutine and refreshes the replication lag of the replica. Until the next check, the
+// read only handlers use the primary database if the replica is down or late
+func ReplicaLagChecker(c context.Context, delay time.Duration) {
+	tick := time.NewTicker(delay).C
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting ReplicaLagChecker: %v", c.Err())
+				return
+			}
+		case <-tick:
+			replicaMutex.RLock()
+			r, query := replica, replicaLagQuery
+			replicaMutex.RUnlock()
+			if r == nil {
+				continue
+			}
+
+			lag, err := checkReplicaLag(r, query)
+			if err != nil {
+				log.Warning("ReplicaLagChecker> Cannot check replica: %s", err)
+			} else if lag > replicaMaxLag {
+				log.Warning("ReplicaLagChecker> Replica is %v late, using primary database", lag)
+			}
+
+			replicaMutex.Lock()
+			replicaLag, replicaErr = lag, err
+			replicaMutex.Unlock()
+		}
+	}
+}
+
+// ReadDB returns the replica if it is up to date, the primary database otherwise
+func ReadDB() *sql.DB {
+	replicaMutex.RLock()
+	r := replica
+	usable := replica != nil && replicaErr == nil && replicaLag <= replicaMaxLag
+	replicaMutex.RUnlock()
+	if !usable {
+		return DB()
+	}
+	return r
+}
+
+//isReplica returns true if d is the replica
+func isReplica(d *sql.DB) bool {
+	replicaMutex.RLock()
+	defer replicaMutex.RUnlock()
+	return d != nil && d == replica
+}
+
+// GetReadDBMap returns a gorp.DbMap pointer on the database to use for read only work
+func GetReadDBMap() *gorp.DbMap {
+	return DBMap(ReadDB())
+}
+
+// ReplicaStatus returns the replica status in a printable string
+func ReplicaStatus() string {
+	replicaMutex.RLock()
+	defer replicaMutex.RUnlock()
+
+	switch {
+	case replica == nil:
+		return "Database Replica: disabled"
+	case replicaErr != nil:
+		return fmt.Sprintf("Database Replica: KO (%s), using primary", replicaErr)
+	case replicaLag > replicaMaxLag:
+		return fmt.Sprintf("Database Replica: KO (lag %v > %v), using primary", replicaLag, replicaMaxLag)
+	}
+	return fmt.Sprintf("Database Replica: OK (lag %v, %d conns)", replicaLag, replica.Stats().OpenConnections)
+}
+
+// CloseReplica closes the replica, releasing any open resources.
+func CloseReplica() error {
+	replicaMutex.Lock()
+	defer replicaMutex.Unlock()
+	if replica != nil {
+		return replica.Close()
+	}
+	return nil
+}
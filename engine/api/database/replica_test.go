@@ -0,0 +1,42 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDB(t *testing.T) {
+	defer func() {
+		replica, replicaLag, replicaErr, replicaMaxLag = nil, 0, nil, 0
+	}()
+
+	//Without replica, the primary database is used
+	assert.Equal(t, DB(), ReadDB())
+	assert.Equal(t, "Database Replica: disabled", ReplicaStatus())
+
+	replica = &sql.DB{}
+	replicaMaxLag = 10 * time.Second
+	replicaLag = 2 * time.Second
+	assert.True(t, replica == ReadDB())
+	assert.True(t, isReplica(replica))
+
+	//The primary database is used when the replica is late or down
+	replicaLag = 30 * time.Second
+	assert.False(t, replica == ReadDB())
+	assert.Equal(t, "Database Replica: KO (lag 30s > 10s), using primary", ReplicaStatus())
+
+	replicaLag = 0
+	replicaErr = fmt.Errorf("database is not a replica")
+	assert.False(t, replica == ReadDB())
+	assert.Equal(t, "Database Replica: KO (database is not a replica), using primary", ReplicaStatus())
+
+	//Without the privileges to check the lag, the replica is not used
+	replicaErr = errReplicaPrivileges
+	assert.False(t, replica == ReadDB())
+	assert.Contains(t, ReplicaStatus(), "Database Replica: KO (insufficient privileges")
+	assert.Contains(t, ReplicaStatus(), "pg_read_all_stats")
+}
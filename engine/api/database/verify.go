@@ -0,0 +1,315 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+//SchemaError is a difference between the database schema and a gorp mapping
+type SchemaError struct {
+	Table   string
+	Column  string
+	Message string
+}
+
+//dbColumn is a column of the database schema
+type dbColumn struct {
+	dataType   string
+	nullable   bool
+	hasDefault bool
+}
+
+//dbTable is a table of the database schema
+type dbTable struct {
+	columns    map[string]dbColumn
+	primaryKey []string
+}
+
+//loadSchema loads the tables of the current schema of the database
+func loadSchema(db *sql.DB) (map[string]*dbTable, error) {
+	tables := map[string]*dbTable{}
+	table := func(name string) *dbTable {
+		if tables[name] == nil {
+			tables[name] = &dbTable{columns: map[string]dbColumn{}}
+		}
+		return tables[name]
+	}
+
+	rows, err := db.Query(`SELECT table_name, column_name, data_type, is_nullable = 'YES', column_default IS NOT NULL
+	FROM information_schema.columns
+	WHERE table_schema = current_schema()`)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot load columns: %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t, c string
+		var col dbColumn
+		if err := rows.Scan(&t, &c, &col.dataType, &col.nullable, &col.hasDefault); err != nil {
+			return nil, fmt.Errorf("Cannot scan columns: %s", err)
+		}
+		table(t).columns[c] = col
+	}
+
+	pkRows, err := db.Query(`SELECT tc.table_name, kcu.column_name
+	FROM information_schema.table_constraints tc
+	JOIN information_schema.key_column_usage kcu ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema
+	WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = current_schema()
+	ORDER BY tc.table_name, kcu.ordinal_position`)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot load primary keys: %s", err)
+	}
+	defer pkRows.Close()
+	for pkRows.Next() {
+		var t, c string
+		if err := pkRows.Scan(&t, &c); err != nil {
+			return nil, fmt.Errorf("Cannot scan primary keys: %s", err)
+		}
+		table(t).primaryKey = append(table(t).primaryKey, c)
+	}
+	return tables, nil
+}
+
+//mappedColumn is a column mapped by gorp on a struct field
+type mappedColumn struct {
+	name  string
+	field string
+	gtype reflect.Type
+}
+
+//mappedColumns returns the columns mapped by gorp on the fields of t, following the rules of gorp: the db tag names
+//the column, "-" ignores the field and the embedded structs are flattened
+func mappedColumns(t reflect.Type) []mappedColumn {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	cols := []mappedColumn{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for _, sub := range mappedColumns(f.Type) {
+				found := false
+				for _, c := range cols {
+					if c.field == sub.field {
+						found = true
+						break
+					}
+				}
+				if !found {
+					cols = append(cols, sub)
+				}
+			}
+			continue
+		}
+		name := strings.TrimSpace(strings.Split(f.Tag.Get("db"), ",")[0])
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		cols = append(cols, mappedColumn{name: name, field: f.Name, gtype: f.Type})
+	}
+	return cols
+}
+
+var (
+	textTypes      = []string{"text", "character varying", "character", "uuid", "json", "jsonb", "USER-DEFINED"}
+	integerTypes   = []string{"smallint", "integer", "bigint", "numeric"}
+	floatTypes     = []string{"real", "double precision", "numeric"}
+	booleanTypes   = []string{"boolean"}
+	timeTypes      = []string{"timestamp with time zone", "timestamp without time zone", "date"}
+	byteTypes      = []string{"bytea", "text", "json", "jsonb"}
+	valuerType     = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType    = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	compatibleSQLs = map[reflect.Type][]string{
+		reflect.TypeOf(time.Time{}):           timeTypes,
+		reflect.TypeOf(timestamp.Timestamp{}): timeTypes,
+		reflect.TypeOf(sql.NullString{}):      textTypes,
+		reflect.TypeOf(sql.NullInt64{}):       integerTypes,
+		reflect.TypeOf(sql.NullFloat64{}):     floatTypes,
+		reflect.TypeOf(sql.NullBool{}):        booleanTypes,
+	}
+)
+
+//compatibleTypes returns the database types compatible with a Go type, nil if it can't be checked
+func compatibleTypes(t reflect.Type) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if types, ok := compatibleSQLs[t]; ok {
+		return types
+	}
+	//The types serializing themselves can be stored in any column
+	if t.Implements(valuerType) || reflect.PtrTo(t).Implements(valuerType) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return textTypes
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return integerTypes
+	case reflect.Float32, reflect.Float64:
+		return floatTypes
+	case reflect.Bool:
+		return booleanTypes
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return byteTypes
+		}
+	}
+	return nil
+}
+
+//acceptsNull returns true if a NULL can be read into a field of type t: pointers, sql.Null* and the other types
+//scanning themselves, byte slices and interfaces
+func acceptsNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+	}
+	return reflect.PtrTo(t).Implements(scannerType)
+}
+
+//verifySchema compares the tables of the database with the gorp mappings. It reports the missing tables and columns,
+//the columns with incompatible types, the nullable columns mapped on fields which can't be NULL, the unmapped columns which would make the inserts fail and the primary keys
+//differing from the mapping keys
+func verifySchema(mappings []gorpmapping.TableMapping, tables map[string]*dbTable) []SchemaError {
+	//A table can be mapped by several structs, some of them mapping only a part of the columns
+	mapped := map[string]map[string]bool{}
+	for _, m := range mappings {
+		if mapped[m.Name] == nil {
+			mapped[m.Name] = map[string]bool{}
+		}
+		for _, c := range mappedColumns(reflect.TypeOf(m.Target)) {
+			mapped[m.Name][c.name] = true
+		}
+	}
+
+	errs := []SchemaError{}
+	checked := map[string]bool{}
+	for _, m := range mappings {
+		t, ok := tables[m.Name]
+		if !ok {
+			errs = append(errs, SchemaError{Table: m.Name, Message: "table does not exist"})
+			continue
+		}
+
+		cols := mappedColumns(reflect.TypeOf(m.Target))
+		fields := map[string]string{}
+		for _, c := range cols {
+			fields[c.field] = c.name
+
+			dbc, ok := t.columns[c.name]
+			if !ok {
+				errs = append(errs, SchemaError{Table: m.Name, Column: c.name, Message: "column does not exist"})
+				continue
+			}
+			if types := compatibleTypes(c.gtype); types != nil && !inArray(dbc.dataType, types) {
+				errs = append(errs, SchemaError{Table: m.Name, Column: c.name, Message: fmt.Sprintf("type %s is not compatible with %s", dbc.dataType, c.gtype)})
+			}
+			if dbc.nullable && !acceptsNull(c.gtype) {
+				errs = append(errs, SchemaError{Table: m.Name, Column: c.name, Message: fmt.Sprintf("column is nullable but %s can't be NULL: the selects will fail on NULL values", c.gtype)})
+			}
+		}
+
+		if !checked[m.Name] {
+			checked[m.Name] = true
+			names := make([]string, 0, len(t.columns))
+			for name := range t.columns {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				dbc := t.columns[name]
+				if !mapped[m.Name][name] && !dbc.nullable && !dbc.hasDefault {
+					errs = append(errs, SchemaError{Table: m.Name, Column: name, Message: "column is not mapped, not null and without default: the inserts will fail"})
+				}
+			}
+		}
+
+		//The keys are given by field or column names
+		keys := make([]string, len(m.Keys))
+		for i, k := range m.Keys {
+			keys[i] = k
+			if c, ok := fields[k]; ok {
+				keys[i] = c
+			}
+		}
+		if len(keys) > 0 && !sameColumns(keys, t.primaryKey) {
+			errs = append(errs, SchemaError{Table: m.Name, Message: fmt.Sprintf("primary key (%s) differs from the mapping keys (%s)", strings.Join(t.primaryKey, ", "), strings.Join(keys, ", "))})
+		}
+	}
+	return errs
+}
+
+//inArray returns true if s is in a
+func inArray(s string, a []string) bool {
+	for _, e := range a {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+//sameColumns returns true if a and b contain the same columns, in any order
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, c := range a {
+		if !inArray(c, b) {
+			return false
+		}
+	}
+	return true
+}
+
+func verifyCmdFunc(cmd *cobra.Command, args []string) {
+	db, err := Init(dbUser, dbPassword, dbName, dbHost, dbPort, dbSSLMode, dbTimeout, dbMaxConn)
+	if err != nil {
+		sdk.Exit("Error: %s\n", err)
+	}
+
+	tables, err := loadSchema(db)
+	if err != nil {
+		sdk.Exit("Error: %s\n", err)
+	}
+
+	errs := verifySchema(gorpmapping.Mapping, tables)
+	if len(errs) == 0 {
+		fmt.Printf("The database schema matches the %d gorp mappings\n", len(gorpmapping.Mapping))
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Table", "Column", "Error"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.SetColWidth(80)
+	for _, e := range errs {
+		table.Append([]string{e.Table, e.Column, e.Message})
+	}
+	table.Render()
+	sdk.Exit("%d differences between the database schema and the gorp mappings\n", len(errs))
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+)
+
+type verifyTestBase struct {
+	ID      int64     `db:"id"`
+	Created time.Time `db:"created"`
+}
+
+type verifyTestTable struct {
+	verifyTestBase
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	Transient   string         `db:"-"`
+}
+
+func Test_mappedColumns(t *testing.T) {
+	cols := mappedColumns(reflect.TypeOf(&verifyTestTable{}))
+	names := []string{}
+	for _, c := range cols {
+		names = append(names, c.name)
+	}
+	assert.Equal(t, []string{"id", "created", "name", "description"}, names)
+}
+
+func Test_verifySchema(t *testing.T) {
+	mappings := []gorpmapping.TableMapping{
+		gorpmapping.New(verifyTestTable{}, "verify_test", true, "id"),
+		gorpmapping.New(verifyTestBase{}, "verify_missing", true, "id"),
+	}
+
+	tables := map[string]*dbTable{
+		"verify_test": {
+			columns: map[string]dbColumn{
+				"id":          {dataType: "bigint"},
+				"created":     {dataType: "timestamp with time zone"},
+				"name":        {dataType: "text"},
+				"description": {dataType: "text", nullable: true},
+			},
+			primaryKey: []string{"id"},
+		},
+	}
+	assert.Equal(t, []SchemaError{{Table: "verify_missing", Message: "table does not exist"}}, verifySchema(mappings, tables))
+
+	tables["verify_test"].columns["created"] = dbColumn{dataType: "timestamp with time zone", nullable: true}
+	tables["verify_test"].columns["name"] = dbColumn{dataType: "integer"}
+	tables["verify_test"].columns["project_id"] = dbColumn{dataType: "bigint"}
+	tables["verify_test"].columns["other_id"] = dbColumn{dataType: "bigint", nullable: true}
+	delete(tables["verify_test"].columns, "description")
+	tables["verify_test"].primaryKey = []string{"id", "name"}
+
+	errs := verifySchema(mappings[:1], tables)
+	if assert.Len(t, errs, 5) {
+		assert.Equal(t, "created", errs[0].Column)
+		assert.Contains(t, errs[0].Message, "nullable")
+		assert.Equal(t, "name", errs[1].Column)
+		assert.Contains(t, errs[1].Message, "not compatible")
+		assert.Equal(t, "description", errs[2].Column)
+		assert.Equal(t, "column does not exist", errs[2].Message)
+		assert.Equal(t, "project_id", errs[3].Column)
+		assert.Contains(t, errs[3].Message, "not mapped")
+		assert.Contains(t, errs[4].Message, "primary key")
+	}
+}
+
+type verifyTestScanner struct{}
+
+func (s *verifyTestScanner) Scan(interface{}) error { return nil }
+
+func Test_acceptsNull(t *testing.T) {
+	var s string
+	for _, v := range []interface{}{&s, sql.NullString{}, sql.NullInt64{}, []byte{}, verifyTestScanner{}} {
+		assert.True(t, acceptsNull(reflect.TypeOf(v)), "%T", v)
+	}
+	for _, v := range []interface{}{s, int64(0), true, time.Time{}, []string{}} {
+		assert.False(t, acceptsNull(reflect.TypeOf(v)), "%T", v)
+	}
+}
@@ -335,7 +335,7 @@ func UpdateLastModified(db gorp.SqlExecutor, u *sdk.User, env *sdk.Environment)
 }
 
 func loadGroupByEnvironment(db gorp.SqlExecutor, environment *sdk.Environment) error {
-	query := `SELECT "group".id, "group".name, environment_group.role FROM "group"
+	query := `SELECT "group".id, "group".name, environment_group.role, COALESCE(environment_group.role_name, '') FROM "group"
 	 		  JOIN environment_group ON environment_group.group_id = "group".id
 	 		  WHERE environment_group.environment_id = $1 ORDER BY "group".name ASC`
 
@@ -348,13 +348,15 @@ func loadGroupByEnvironment(db gorp.SqlExecutor, environment *sdk.Environment) e
 	for rows.Next() {
 		var group sdk.Group
 		var perm int
-		err = rows.Scan(&group.ID, &group.Name, &perm)
+		var role string
+		err = rows.Scan(&group.ID, &group.Name, &perm, &role)
 		if err != nil {
 			return err
 		}
 		environment.EnvironmentGroups = append(environment.EnvironmentGroups, sdk.GroupPermission{
 			Group:      group,
 			Permission: perm,
+			Role:       role,
 		})
 	}
 	return nil
@@ -365,7 +367,8 @@ func LoadEnvironmentByGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 	query := `SELECT project.projectKey,
 			 environment.id,
 	                 environment.name,
-	                 environment_group.role
+	                 environment_group.role,
+	                 COALESCE(environment_group.role_name, '')
 	          FROM environment
 	          JOIN environment_group ON environment_group.environment_id = environment.id
 	 	  JOIN project ON environment.project_id = project.id
@@ -380,13 +383,15 @@ func LoadEnvironmentByGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 	for rows.Next() {
 		var environment sdk.Environment
 		var perm int
-		err = rows.Scan(&environment.ProjectKey, &environment.ID, &environment.Name, &perm)
+		var role string
+		err = rows.Scan(&environment.ProjectKey, &environment.ID, &environment.Name, &perm, &role)
 		if err != nil {
 			return err
 		}
 		group.EnvironmentGroups = append(group.EnvironmentGroups, sdk.EnvironmentGroup{
 			Environment: environment,
 			Permission:  perm,
+			Role:        role,
 		})
 	}
 	return nil
@@ -15,6 +15,19 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+func getGroupsInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	envName := vars["permEnvironmentName"]
+
+	env, err := environment.LoadEnvironmentByName(db, key, envName)
+	if err != nil {
+		return sdk.WrapError(err, "getGroupsInEnvironmentHandler> Cannot load environment %s", envName)
+	}
+
+	return WriteJSON(w, r, env.EnvironmentGroups, http.StatusOK)
+}
+
 func updateGroupRoleOnEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	// Get project name in URL
 	vars := mux.Vars(r)
@@ -26,6 +39,9 @@ func updateGroupRoleOnEnvironmentHandler(w http.ResponseWriter, r *http.Request,
 	if err := UnmarshalBody(r, &groupEnvironment); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler> Cannot read body")
 	}
+	if err := checkGroupRole(&groupEnvironment); err != nil {
+		return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler> Invalid role")
+	}
 
 	g, errG := group.LoadGroup(db, groupName)
 	if errG != nil {
@@ -63,6 +79,9 @@ func updateGroupRoleOnEnvironmentHandler(w http.ResponseWriter, r *http.Request,
 	if err := group.UpdateGroupRoleInEnvironment(tx, key, envName, groupName, groupEnvironment.Permission); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler: Cannot update permission for group %s in environment %s", groupName, envName)
 	}
+	if err := group.UpdateGroupRoleNameInEnvironment(tx, key, envName, groupName, groupEnvironment.Role); err != nil {
+		return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler: Cannot set role of group %s in environment %s", groupName, envName)
+	}
 
 	if err := environment.UpdateLastModified(tx, c.User, env); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler: Cannot update environment last modified date")
@@ -94,6 +113,11 @@ func addGroupsInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *g
 	if err := UnmarshalBody(r, &groupPermission); err != nil {
 		return sdk.WrapError(err, "addGroupsInEnvironmentHandler> Cannot read body")
 	}
+	for i := range groupPermission {
+		if err := checkGroupRole(&groupPermission[i]); err != nil {
+			return sdk.WrapError(err, "addGroupsInEnvironmentHandler> Invalid role")
+		}
+	}
 
 	env, err := environment.LoadEnvironmentByName(db, key, envName)
 	if err != nil {
@@ -124,6 +148,9 @@ func addGroupsInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *g
 		if err := group.InsertGroupInEnvironment(tx, env.ID, g.ID, gp.Permission); err != nil {
 			return sdk.WrapError(err, "addGroupsInEnvironmentHandler: Cannot add group %s in environment %s", g.Name, env.Name)
 		}
+		if err := group.UpdateGroupRoleNameInEnvironment(tx, key, env.Name, g.Name, gp.Role); err != nil {
+			return sdk.WrapError(err, "addGroupsInEnvironmentHandler: Cannot set role of group %s in environment %s", g.Name, env.Name)
+		}
 	}
 
 	// Update last modified on environment
@@ -166,6 +193,9 @@ func addGroupInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *go
 	if err := UnmarshalBody(r, &groupPermission); err != nil {
 		return err
 	}
+	if err := checkGroupRole(&groupPermission); err != nil {
+		return sdk.WrapError(err, "addGroupInEnvironmentHandler> Invalid role")
+	}
 
 	env, err := environment.LoadEnvironmentByName(db, key, envName)
 	if err != nil {
@@ -194,6 +224,9 @@ func addGroupInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *go
 		log.Warning("addGroupInEnvironmentHandler: Cannot add group %s in environment %s:  %s\n", g.Name, env.Name, err)
 		return err
 	}
+	if err := group.UpdateGroupRoleNameInEnvironment(db, key, env.Name, g.Name, groupPermission.Role); err != nil {
+		return sdk.WrapError(err, "addGroupInEnvironmentHandler: Cannot set role of group %s in environment %s", g.Name, env.Name)
+	}
 
 	return nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
 	"github.com/ovh/cds/sdk"
@@ -45,6 +46,10 @@ func TestAddGroupsInEnvironmentHandler(t *testing.T) {
 	_, _, errG2 := group.AddGroup(db, grp2)
 	test.NoError(t, errG2)
 
+	grp3 := &sdk.Group{Name: sdk.RandomString(10)}
+	_, _, errG3 := group.AddGroup(db, grp3)
+	test.NoError(t, errG3)
+
 	//5. Prepare request
 	gps := []sdk.GroupPermission{
 		{
@@ -55,13 +60,17 @@ func TestAddGroupsInEnvironmentHandler(t *testing.T) {
 			Permission: 4,
 			Group:      *grp2,
 		},
+		{
+			Role:  sdk.RoleDeployer,
+			Group: *grp3,
+		},
 	}
 
 	jsonBody, _ := json.Marshal(gps)
 	body := bytes.NewBuffer(jsonBody)
 
 	vars := map[string]string{
-		"key": proj.Key,
+		"key":                 proj.Key,
 		"permEnvironmentName": envProd.Name,
 	}
 
@@ -83,6 +92,7 @@ func TestAddGroupsInEnvironmentHandler(t *testing.T) {
 
 	grp1Found := false
 	grp2Found := false
+	grp3Found := false
 
 	for _, gp := range envUpdated.EnvironmentGroups {
 		if gp.Group.Name == grp1.Name {
@@ -93,10 +103,16 @@ func TestAddGroupsInEnvironmentHandler(t *testing.T) {
 			grp2Found = true
 			assert.Equal(t, 4, gp.Permission)
 		}
+		if gp.Group.Name == grp3.Name {
+			grp3Found = true
+			assert.Equal(t, sdk.RoleDeployer, gp.Role)
+			assert.Equal(t, permission.RolePermission(sdk.RoleDeployer), gp.Permission)
+		}
 	}
 
 	assert.True(t, grp1Found)
 	assert.True(t, grp2Found)
+	assert.True(t, grp3Found)
 }
 
 func TestUpdateGroupRoleOnEnvironmentHandler(t *testing.T) {
@@ -139,7 +155,7 @@ func TestUpdateGroupRoleOnEnvironmentHandler(t *testing.T) {
 	body := bytes.NewBuffer(jsonBody)
 
 	vars := map[string]string{
-		"key": proj.Key,
+		"key":                 proj.Key,
 		"permEnvironmentName": envProd.Name,
 		"group":               grp1.Name,
 	}
@@ -31,9 +31,123 @@ func Publish(payload interface{}) {
 	cache.Enqueue("events_repositoriesmanager", event)
 }
 
-// PublishJobRun sends an event
+// PublishJobRun sends a workflow node job run event
 func PublishJobRun(n *sdk.WorkflowNodeRun, j *sdk.WorkflowNodeJobRun) {
-	//TODO PublishJobRun sends an event
+	e := sdk.EventWorkflowNodeJobRun{
+		ID:                j.ID,
+		WorkflowNodeRunID: j.WorkflowNodeRunID,
+		WorkflowRunID:     n.WorkflowRunID,
+		Number:            n.Number,
+		SubNumber:         n.SubNumber,
+		JobName:           j.Job.Action.Name,
+		Status:            sdk.StatusFromString(j.Status),
+		Queued:            j.Queued.Unix(),
+		Start:             j.Start.Unix(),
+		Done:              j.Done.Unix(),
+		ModelName:         j.Model,
+		WorkerName:        j.Job.WorkerName,
+	}
+
+	Publish(e)
+}
+
+// PublishWorkflowNodeRun sends a workflow node run event
+func PublishWorkflowNodeRun(wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun) {
+	e := sdk.EventWorkflowNodeRun{
+		ID:             n.ID,
+		WorkflowRunID:  n.WorkflowRunID,
+		WorkflowNodeID: n.WorkflowNodeID,
+		Number:         n.Number,
+		SubNumber:      n.SubNumber,
+		Status:         sdk.StatusFromString(n.Status),
+		Start:          n.Start.Unix(),
+		Done:           n.Done.Unix(),
+		ProjectKey:     wr.Workflow.ProjectKey,
+		WorkflowName:   wr.Workflow.Name,
+	}
+
+	if node := wr.Workflow.GetNode(n.WorkflowNodeID); node != nil {
+		e.PipelineName = node.Pipeline.Name
+		if node.Context != nil {
+			if node.Context.Application != nil {
+				e.ApplicationName = node.Context.Application.Name
+				e.RepositoryFullname = node.Context.Application.RepositoryFullname
+				if node.Context.Application.RepositoriesManager != nil {
+					e.RepositoryManagerName = node.Context.Application.RepositoriesManager.Name
+				}
+			}
+			if node.Context.Environment != nil {
+				e.EnvironmentName = node.Context.Environment.Name
+			}
+		}
+	}
+
+	if n.Tests != nil {
+		e.TestsTotal = n.Tests.Total
+		e.TestsOK = n.Tests.TotalOK
+		e.TestsKO = n.Tests.TotalKO
+		e.TestsSkipped = n.Tests.TotalSkipped
+	}
+
+	for _, p := range n.BuildParameters {
+		switch p.Name {
+		case "git.branch":
+			e.BranchName = p.Value
+		case "git.hash":
+			e.Hash = p.Value
+		case "cds.coverage.lines":
+			e.CoverageLines = p.Value
+		case "cds.coverage.lines.delta":
+			if e.CoverageLinesDelta == "" {
+				e.CoverageLinesDelta = p.Value
+			}
+		case "cds.coverage.lines.base_delta":
+			//On a pull request, the delta with the base branch is more relevant
+			e.CoverageLinesDelta = p.Value
+		}
+	}
+
+	Publish(e)
+}
+
+// PublishWorkflowRun sends a workflow run event
+func PublishWorkflowRun(wr *sdk.WorkflowRun) {
+	e := sdk.EventWorkflowRun{
+		ID:           wr.ID,
+		Number:       wr.Number,
+		Status:       workflowRunStatus(wr),
+		Start:        wr.Start.Unix(),
+		LastModified: wr.LastModified.Unix(),
+		ProjectKey:   wr.Workflow.ProjectKey,
+		WorkflowName: wr.Workflow.Name,
+		Tags:         wr.Tags,
+	}
+
+	Publish(e)
+}
+
+// workflowRunStatus computes the global status of a workflow run from its node runs
+func workflowRunStatus(wr *sdk.WorkflowRun) sdk.Status {
+	status := sdk.StatusSuccess
+	for _, nodeRuns := range wr.WorkflowNodeRuns {
+		if len(nodeRuns) == 0 {
+			continue
+		}
+		// Only the last node run (highest subnumber) is relevant
+		last := nodeRuns[0]
+		for _, nr := range nodeRuns {
+			if nr.SubNumber > last.SubNumber {
+				last = nr
+			}
+		}
+		switch last.Status {
+		case sdk.StatusWaiting.String(), sdk.StatusWaitingApproval.String(), sdk.StatusBuilding.String():
+			return sdk.StatusBuilding
+		case sdk.StatusFail.String():
+			status = sdk.StatusFail
+		}
+	}
+	return status
 }
 
 // PublishActionBuild sends a actionBuild event
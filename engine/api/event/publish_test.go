@@ -0,0 +1,50 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWorkflowRunStatus(t *testing.T) {
+	run := func(nodes map[int64][]sdk.WorkflowNodeRun) *sdk.WorkflowRun {
+		return &sdk.WorkflowRun{WorkflowNodeRuns: nodes}
+	}
+
+	assert.Equal(t, sdk.StatusSuccess, workflowRunStatus(run(nil)))
+
+	assert.Equal(t, sdk.StatusSuccess, workflowRunStatus(run(map[int64][]sdk.WorkflowNodeRun{
+		1: {{Status: sdk.StatusSuccess.String()}},
+		2: {{Status: sdk.StatusSuccess.String()}},
+	})))
+
+	//A node still running makes the whole run building, even if another one has failed
+	assert.Equal(t, sdk.StatusBuilding, workflowRunStatus(run(map[int64][]sdk.WorkflowNodeRun{
+		1: {{Status: sdk.StatusFail.String()}},
+		2: {{Status: sdk.StatusBuilding.String()}},
+	})))
+	assert.Equal(t, sdk.StatusBuilding, workflowRunStatus(run(map[int64][]sdk.WorkflowNodeRun{
+		1: {{Status: sdk.StatusWaitingApproval.String()}},
+	})))
+
+	assert.Equal(t, sdk.StatusFail, workflowRunStatus(run(map[int64][]sdk.WorkflowNodeRun{
+		1: {{Status: sdk.StatusSuccess.String()}},
+		2: {{Status: sdk.StatusFail.String()}},
+	})))
+
+	//Only the last run of a node counts
+	assert.Equal(t, sdk.StatusSuccess, workflowRunStatus(run(map[int64][]sdk.WorkflowNodeRun{
+		1: {
+			{SubNumber: 1, Status: sdk.StatusSuccess.String()},
+			{SubNumber: 0, Status: sdk.StatusFail.String()},
+		},
+	})))
+	assert.Equal(t, sdk.StatusFail, workflowRunStatus(run(map[int64][]sdk.WorkflowNodeRun{
+		1: {
+			{SubNumber: 0, Status: sdk.StatusSuccess.String()},
+			{SubNumber: 1, Status: sdk.StatusFail.String()},
+		},
+	})))
+}
@@ -0,0 +1,272 @@
+package flakytest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/ovh/venom"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// HistoryRetention is the duration the results of the test cases are kept
+const HistoryRetention = 30 * 24 * time.Hour
+
+//insertBatchSize is the number of results inserted by statement, far under the 65535 parameters of a PostgreSQL query
+const insertBatchSize = 500
+
+type testKey struct {
+	suite, name string
+}
+
+//Results returns the results of the test cases of a run, the skipped test cases are ignored
+func Results(appID int64, branch, hash string, num int64, tests venom.Tests) []sdk.TestCaseResult {
+	now := time.Now()
+	res := []sdk.TestCaseResult{}
+	for i, ts := range tests.TestSuites {
+		suite := ts.Name
+		if suite == "" {
+			suite = fmt.Sprintf("TestSuite.%d", i)
+		}
+		for k, tc := range ts.TestCases {
+			status := sdk.TestCaseStatus(tc)
+			if status == "" {
+				continue
+			}
+			name := tc.Name
+			if name == "" {
+				name = fmt.Sprintf("TestCase.%d", k)
+			}
+			res = append(res, sdk.TestCaseResult{
+				ApplicationID: appID,
+				Branch:        branch,
+				Hash:          hash,
+				Num:           num,
+				TestSuite:     suite,
+				TestName:      name,
+				Status:        status,
+				Created:       now,
+			})
+		}
+	}
+	return res
+}
+
+//detectFlips returns the test cases whose status differs from their last status on the same commit.
+//last is updated with the results
+func detectFlips(last map[testKey]string, results []sdk.TestCaseResult) []testKey {
+	flips := []testKey{}
+	for _, r := range results {
+		k := testKey{r.TestSuite, r.TestName}
+		if s, ok := last[k]; ok && s != r.Status {
+			flips = append(flips, k)
+		}
+		last[k] = r.Status
+	}
+	return flips
+}
+
+//Record inserts the results of the test cases of a run of an application on a branch.
+//The test cases whose status flipped without code change, on the same commit, are flagged as flaky
+func Record(db gorp.SqlExecutor, appID int64, branch, hash string, num int64, tests venom.Tests) ([]sdk.FlakyTest, error) {
+	results := Results(appID, branch, hash, num, tests)
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	var flips []testKey
+	if hash != "" {
+		rows, err := db.Query(`SELECT DISTINCT ON (test_suite, test_name) test_suite, test_name, status
+		FROM application_test_history
+		WHERE application_id = $1 AND branch = $2 AND hash = $3
+		ORDER BY test_suite, test_name, id DESC`, appID, branch, hash)
+		if err != nil {
+			return nil, sdk.WrapError(err, "Record> Unable to load the tests history of application %d", appID)
+		}
+		last := map[testKey]string{}
+		for rows.Next() {
+			var k testKey
+			var status string
+			if err := rows.Scan(&k.suite, &k.name, &status); err != nil {
+				rows.Close()
+				return nil, sdk.WrapError(err, "Record> Unable to scan the tests history of application %d", appID)
+			}
+			last[k] = status
+		}
+		rows.Close()
+		flips = detectFlips(last, results)
+	}
+
+	if err := insertResults(db, results); err != nil {
+		return nil, err
+	}
+
+	flaky := make([]sdk.FlakyTest, 0, len(flips))
+	for _, k := range flips {
+		f := sdk.FlakyTest{}
+		if err := db.SelectOne(&f, `INSERT INTO application_flaky_test (application_id, branch, test_suite, test_name, flips, last_hash, last_flip)
+		VALUES ($1, $2, $3, $4, 1, $5, $6)
+		ON CONFLICT (application_id, branch, test_suite, test_name) DO UPDATE SET flips = application_flaky_test.flips + 1, last_hash = $5, last_flip = $6
+		RETURNING *`, appID, branch, k.suite, k.name, hash, time.Now()); err != nil {
+			return nil, sdk.WrapError(err, "Record> Unable to flag %s/%s as flaky", k.suite, k.name)
+		}
+		flaky = append(flaky, f)
+	}
+	return flaky, nil
+}
+
+//insertResults inserts the results of the test cases by batches of insertBatchSize rows
+func insertResults(db gorp.SqlExecutor, results []sdk.TestCaseResult) error {
+	for start := 0; start < len(results); start += insertBatchSize {
+		end := start + insertBatchSize
+		if end > len(results) {
+			end = len(results)
+		}
+		query, args := insertResultsQuery(results[start:end])
+		if _, err := db.Exec(query, args...); err != nil {
+			return sdk.WrapError(err, "insertResults> Unable to insert %d test case results", end-start)
+		}
+	}
+	return nil
+}
+
+//insertResultsQuery returns a multi-row insert of the results
+func insertResultsQuery(results []sdk.TestCaseResult) (string, []interface{}) {
+	values := make([]string, len(results))
+	args := make([]interface{}, 0, len(results)*8)
+	for i, r := range results {
+		n := len(args)
+		values[i] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8)
+		args = append(args, r.ApplicationID, r.Branch, r.Hash, r.Num, r.TestSuite, r.TestName, r.Status, r.Created)
+	}
+	return "INSERT INTO application_test_history (application_id, branch, hash, num, test_suite, test_name, status, created) VALUES " + strings.Join(values, ", "), args
+}
+
+//Purge deletes the results of the test cases older than HistoryRetention
+func Purge(db gorp.SqlExecutor) (int64, error) {
+	res, err := db.Exec("DELETE FROM application_test_history WHERE created < $1", time.Now().Add(-HistoryRetention))
+	if err != nil {
+		return 0, sdk.WrapError(err, "flakytest.Purge> Unable to purge the tests history")
+	}
+	return res.RowsAffected()
+}
+
+//Purger deletes periodically the results of the test cases older than HistoryRetention
+func Purger(c context.Context, DBFunc func() *gorp.DbMap) {
+	tick := time.NewTicker(1 * time.Hour)
+	defer tick.Stop()
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting flakytest.Purger: %v", c.Err())
+			}
+			return
+		case <-tick.C:
+			db := DBFunc()
+			if db == nil {
+				continue
+			}
+			n, err := Purge(db)
+			if err != nil {
+				log.Warning("flakytest.Purger> %s", err)
+				continue
+			}
+			log.Debug("flakytest.Purger> %d test case results deleted", n)
+		}
+	}
+}
+
+//LoadFlakyTests loads the flaky tests of an application, on all branches if branch is empty
+func LoadFlakyTests(db gorp.SqlExecutor, appID int64, branch string) ([]sdk.FlakyTest, error) {
+	query := "SELECT * FROM application_flaky_test WHERE application_id = $1"
+	args := []interface{}{appID}
+	if branch != "" {
+		query += " AND branch = $2"
+		args = append(args, branch)
+	}
+	query += " ORDER BY flips DESC, last_flip DESC"
+
+	var fs []dbFlakyTest
+	if _, err := db.Select(&fs, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "LoadFlakyTests> Unable to load flaky tests of application %d", appID)
+	}
+
+	quarantine, err := LoadQuarantine(db, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]sdk.FlakyTest, len(fs))
+	for i := range fs {
+		res[i] = sdk.FlakyTest(fs[i])
+		res[i].Quarantined = sdk.IsTestQuarantined(quarantine, res[i].TestSuite, res[i].TestName)
+	}
+	return res, nil
+}
+
+//LoadHistory loads the last results of a test case of an application on a branch, the most recent first
+func LoadHistory(db gorp.SqlExecutor, appID int64, branch, testSuite, testName string, limit int) ([]sdk.TestCaseResult, error) {
+	var rs []dbTestCaseResult
+	if _, err := db.Select(&rs, `SELECT * FROM application_test_history
+	WHERE application_id = $1 AND branch = $2 AND test_suite = $3 AND test_name = $4
+	ORDER BY id DESC LIMIT $5`, appID, branch, testSuite, testName, limit); err != nil {
+		return nil, sdk.WrapError(err, "LoadHistory> Unable to load the history of %s/%s", testSuite, testName)
+	}
+	res := make([]sdk.TestCaseResult, len(rs))
+	for i := range rs {
+		res[i] = sdk.TestCaseResult(rs[i])
+	}
+	return res, nil
+}
+
+//LoadQuarantine loads the quarantined tests of an application
+func LoadQuarantine(db gorp.SqlExecutor, appID int64) ([]sdk.TestQuarantine, error) {
+	var qs []dbTestQuarantine
+	if _, err := db.Select(&qs, "SELECT * FROM application_test_quarantine WHERE application_id = $1 ORDER BY test_suite, test_name", appID); err != nil {
+		return nil, sdk.WrapError(err, "LoadQuarantine> Unable to load quarantined tests of application %d", appID)
+	}
+	res := make([]sdk.TestQuarantine, len(qs))
+	for i := range qs {
+		res[i] = sdk.TestQuarantine(qs[i])
+	}
+	return res, nil
+}
+
+//AddQuarantine quarantines a test of an application: its failures don't fail the job anymore
+func AddQuarantine(db gorp.SqlExecutor, q *sdk.TestQuarantine) error {
+	if q.TestSuite == "" || q.TestName == "" {
+		return sdk.ErrWrongRequest
+	}
+
+	n, err := db.SelectInt("SELECT COUNT(1) FROM application_test_quarantine WHERE application_id = $1 AND test_suite = $2 AND test_name = $3", q.ApplicationID, q.TestSuite, q.TestName)
+	if err != nil {
+		return sdk.WrapError(err, "AddQuarantine> Unable to check quarantine of %s/%s", q.TestSuite, q.TestName)
+	}
+	if n > 0 {
+		return sdk.ErrAlreadyExist
+	}
+
+	q.Created = time.Now()
+	dbq := dbTestQuarantine(*q)
+	if err := db.Insert(&dbq); err != nil {
+		return sdk.WrapError(err, "AddQuarantine> Unable to quarantine %s/%s", q.TestSuite, q.TestName)
+	}
+	return nil
+}
+
+//DeleteQuarantine releases a test of an application from the quarantine
+func DeleteQuarantine(db gorp.SqlExecutor, appID int64, testSuite, testName string) error {
+	res, err := db.Exec("DELETE FROM application_test_quarantine WHERE application_id = $1 AND test_suite = $2 AND test_name = $3", appID, testSuite, testName)
+	if err != nil {
+		return sdk.WrapError(err, "DeleteQuarantine> Unable to release %s/%s from quarantine", testSuite, testName)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package flakytest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestResults(t *testing.T) {
+	tests := venom.Tests{
+		TestSuites: []venom.TestSuite{
+			{
+				Name: "api",
+				TestCases: []venom.TestCase{
+					{Name: "TestOK"},
+					{Name: "TestKO", Failures: []venom.Failure{{Message: "boom"}}},
+					{Name: "TestError", Errors: []venom.Failure{{Message: "panic"}}},
+					{Name: "TestSkipped", Skipped: 1},
+				},
+			},
+			{
+				TestCases: []venom.TestCase{{}},
+			},
+		},
+	}
+
+	res := Results(1, "master", "abcdef", 12, tests)
+	assert.Len(t, res, 4)
+	assert.Equal(t, sdk.TestCaseStatusSuccess, res[0].Status)
+	assert.Equal(t, sdk.TestCaseStatusFail, res[1].Status)
+	assert.Equal(t, sdk.TestCaseStatusFail, res[2].Status)
+	assert.Equal(t, "TestSuite.1", res[3].TestSuite)
+	assert.Equal(t, "TestCase.0", res[3].TestName)
+	assert.Equal(t, "abcdef", res[3].Hash)
+}
+
+func TestDetectFlips(t *testing.T) {
+	last := map[testKey]string{
+		{"api", "TestOK"}: sdk.TestCaseStatusSuccess,
+		{"api", "TestKO"}: sdk.TestCaseStatusSuccess,
+	}
+	results := []sdk.TestCaseResult{
+		{TestSuite: "api", TestName: "TestOK", Status: sdk.TestCaseStatusSuccess},
+		{TestSuite: "api", TestName: "TestKO", Status: sdk.TestCaseStatusFail},
+		{TestSuite: "api", TestName: "TestNew", Status: sdk.TestCaseStatusFail},
+		// retried in the same run
+		{TestSuite: "api", TestName: "TestNew", Status: sdk.TestCaseStatusSuccess},
+	}
+
+	flips := detectFlips(last, results)
+	assert.Equal(t, []testKey{{"api", "TestKO"}, {"api", "TestNew"}}, flips)
+	assert.Equal(t, sdk.TestCaseStatusSuccess, last[testKey{"api", "TestNew"}])
+}
+
+func TestInsertResultsQuery(t *testing.T) {
+	now := time.Now()
+	query, args := insertResultsQuery([]sdk.TestCaseResult{
+		{ApplicationID: 1, Branch: "master", Hash: "abc", Num: 3, TestSuite: "api", TestName: "TestA", Status: sdk.TestCaseStatusSuccess, Created: now},
+		{ApplicationID: 1, Branch: "master", Hash: "abc", Num: 3, TestSuite: "api", TestName: "TestB", Status: sdk.TestCaseStatusFail, Created: now},
+	})
+	assert.Equal(t, "INSERT INTO application_test_history (application_id, branch, hash, num, test_suite, test_name, status, created) VALUES "+
+		"($1, $2, $3, $4, $5, $6, $7, $8), ($9, $10, $11, $12, $13, $14, $15, $16)", query)
+	assert.Equal(t, []interface{}{
+		int64(1), "master", "abc", int64(3), "api", "TestA", sdk.TestCaseStatusSuccess, now,
+		int64(1), "master", "abc", int64(3), "api", "TestB", sdk.TestCaseStatusFail, now,
+	}, args)
+}
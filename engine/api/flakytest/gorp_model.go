@@ -0,0 +1,18 @@
+package flakytest
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbTestCaseResult sdk.TestCaseResult
+type dbFlakyTest sdk.FlakyTest
+type dbTestQuarantine sdk.TestQuarantine
+
+func init() {
+	gorpmapping.Register(
+		gorpmapping.New(dbTestCaseResult{}, "application_test_history", true, "id"),
+		gorpmapping.New(dbFlakyTest{}, "application_flaky_test", false, "application_id", "branch", "test_suite", "test_name"),
+		gorpmapping.New(dbTestQuarantine{}, "application_test_quarantine", false, "application_id", "test_suite", "test_name"),
+	)
+}
@@ -325,7 +325,7 @@ func InsertGroup(db gorp.SqlExecutor, g *sdk.Group) error {
 
 // LoadGroupByProject retrieves all groups related to project
 func LoadGroupByProject(db gorp.SqlExecutor, project *sdk.Project) error {
-	query := `SELECT "group".id,"group".name,project_group.role FROM "group"
+	query := `SELECT "group".id,"group".name,project_group.role, COALESCE(project_group.role_name, '') FROM "group"
 	 		  JOIN project_group ON project_group.group_id = "group".id
 	 		  WHERE project_group.project_id = $1 ORDER BY "group".name ASC`
 
@@ -338,12 +338,14 @@ func LoadGroupByProject(db gorp.SqlExecutor, project *sdk.Project) error {
 	for rows.Next() {
 		var group sdk.Group
 		var perm int
-		if err := rows.Scan(&group.ID, &group.Name, &perm); err != nil {
+		var role string
+		if err := rows.Scan(&group.ID, &group.Name, &perm, &role); err != nil {
 			return err
 		}
 		project.ProjectGroups = append(project.ProjectGroups, sdk.GroupPermission{
 			Group:      group,
 			Permission: perm,
+			Role:       role,
 		})
 	}
 	return nil
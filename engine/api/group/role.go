@@ -0,0 +1,49 @@
+package group
+
+import (
+	"github.com/go-gorp/gorp"
+)
+
+//roleName returns the value stored for a role name: without role name, the numeric permission is used
+func roleName(role string) interface{} {
+	if role == "" {
+		return nil
+	}
+	return role
+}
+
+// UpdateGroupRoleNameInProject set the named role of a group on the given project
+func UpdateGroupRoleNameInProject(db gorp.SqlExecutor, projectID, groupID int64, role string) error {
+	query := `UPDATE project_group SET role_name=$1 WHERE project_id=$2 AND group_id=$3`
+	_, err := db.Exec(query, roleName(role), projectID, groupID)
+	return err
+}
+
+// UpdateGroupRoleNameInPipeline set the named role of a group on the given pipeline
+func UpdateGroupRoleNameInPipeline(db gorp.SqlExecutor, pipelineID, groupID int64, role string) error {
+	query := `UPDATE pipeline_group SET role_name=$1 WHERE pipeline_id=$2 AND group_id=$3`
+	_, err := db.Exec(query, roleName(role), pipelineID, groupID)
+	return err
+}
+
+// UpdateGroupRoleNameInApplication set the named role of a group on the given application
+func UpdateGroupRoleNameInApplication(db gorp.SqlExecutor, key, appName, groupName string, role string) error {
+	query := `UPDATE application_group
+	          SET role_name=$1
+	          FROM application, project, "group"
+	          WHERE application.id = application_id AND application.project_id = project.id AND "group".id = group_id
+	          AND application.name = $2 AND  project.projectKey = $3 AND "group".name = $4 `
+	_, err := db.Exec(query, roleName(role), appName, key, groupName)
+	return err
+}
+
+// UpdateGroupRoleNameInEnvironment set the named role of a group on the given environment
+func UpdateGroupRoleNameInEnvironment(db gorp.SqlExecutor, key, envName, groupName string, role string) error {
+	query := `UPDATE environment_group
+	          SET role_name=$1
+	          FROM environment, project, "group"
+	          WHERE environment.id = environment_id AND environment.project_id = project.id AND "group".id = group_id
+	          AND environment.name = $2 AND  project.projectKey = $3 AND "group".name = $4 `
+	_, err := db.Exec(query, roleName(role), envName, key, groupName)
+	return err
+}
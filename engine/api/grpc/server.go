@@ -13,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // Init initialize all GRPC services
@@ -63,7 +64,13 @@ func streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.Str
 	m := metadata.Pairs(string(keyWorkerID), w.ID, string(keyWorkerName), w.Name)
 	stream.SendHeader(m)
 
-	return handler(srv, stream)
+	_, span := tracing.Start(traceContext(c), info.FullMethod)
+	span.SetAttribute("worker", w.Name)
+	defer span.Finish()
+
+	err = handler(srv, stream)
+	span.SetError(err)
+	return err
 }
 
 func unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
@@ -74,7 +81,22 @@ func unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServ
 	}
 	ctx = context.WithValue(ctx, keyWorkerID, w.ID)
 	ctx = context.WithValue(ctx, keyWorkerName, w.Name)
-	return handler(ctx, req)
+
+	ctx, span := tracing.Start(traceContext(ctx), info.FullMethod)
+	span.SetAttribute("worker", w.Name)
+	defer span.Finish()
+
+	resp, err = handler(ctx, req)
+	span.SetError(err)
+	return resp, err
+}
+
+// traceContext returns a context carrying the trace context sent by the worker in the metadata
+func traceContext(ctx context.Context) context.Context {
+	if md, ok := metadata.FromContext(ctx); ok && len(md[tracing.Header]) > 0 {
+		return tracing.ContextWithTraceParent(ctx, md[tracing.Header][0])
+	}
+	return ctx
 }
 
 func authorize(ctx context.Context) (*sdk.Worker, error) {
@@ -17,6 +17,9 @@ import (
 	"github.com/ovh/cds/engine/api/hook"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/repositoriesmanager/repogitea"
+	"github.com/ovh/cds/engine/api/repositoriesmanager/repogitlab"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -47,6 +50,42 @@ func receiveHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *busi
 		UID:        r.FormValue("uid"),
 	}
 
+	//Gitlab does not substitute variables in hook URL, details are in the payload
+	if glEvent := r.Header.Get(repogitlab.HookEventHeader); glEvent != "" {
+		if !repogitlab.CheckHookToken(r.Header.Get(repogitlab.HookTokenHeader)) {
+			return sdk.WrapError(sdk.ErrForbidden, "receiveHook> Invalid token for gitlab event %s", glEvent)
+		}
+		if err := fillGitlabHook(&rh, glEvent); err != nil {
+			return err
+		}
+		if rh.Branch == "" {
+			log.Debug("receiveHook> Ignoring gitlab event %s", glEvent)
+			return nil
+		}
+	}
+
+	//Gitea and Gogs do not substitute variables in hook URL either
+	giteaEvent := r.Header.Get(repogitea.HookEventHeader)
+	if giteaEvent == "" {
+		giteaEvent = r.Header.Get(repogitea.GogsHookEventHeader)
+	}
+	if giteaEvent != "" {
+		signature := r.Header.Get(repogitea.HookSignatureHeader)
+		if signature == "" {
+			signature = r.Header.Get(repogitea.GogsHookSignatureHeader)
+		}
+		if !repogitea.CheckHookSignature(data, signature) {
+			return sdk.WrapError(sdk.ErrForbidden, "receiveHook> Invalid signature for gitea event %s", giteaEvent)
+		}
+		if err := fillGiteaHook(&rh, giteaEvent); err != nil {
+			return err
+		}
+		if rh.Branch == "" {
+			log.Debug("receiveHook> Ignoring gitea event %s", giteaEvent)
+			return nil
+		}
+	}
+
 	if db == nil {
 		hook.Recovery(rh, fmt.Errorf("database not available"))
 		return err
@@ -62,6 +101,74 @@ func receiveHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *busi
 	return nil
 }
 
+func fillGitlabHook(rh *hook.ReceivedHook, glEvent string) error {
+	rh.Branch, rh.Hash, rh.Author, rh.Message = "", "", "", ""
+	switch glEvent {
+	case repogitlab.PushHookEvent:
+		e, deleted, err := repogitlab.ParsePushHook(rh.Data)
+		if err != nil {
+			return err
+		}
+		rh.Branch = e.Branch.ID
+		rh.Hash = e.Commit.Hash
+		rh.Author = e.Commit.Author.Name
+		rh.Message = "UPDATE"
+		if deleted {
+			rh.Message = "DELETE"
+		}
+	case repogitlab.MergeRequestHookEvent:
+		e, err := repogitlab.ParseMergeRequestHook(rh.Data)
+		if err != nil {
+			return err
+		}
+		if !repositoriesmanager.TriggerPullRequest(*e) {
+			return nil
+		}
+		rh.Branch = e.Head.Branch.ID
+		rh.Hash = e.Head.Commit.Hash
+		rh.Author = e.User.Name
+		rh.Message = "UPDATE"
+		rh.PullRequest = e
+	}
+	return nil
+}
+
+func fillGiteaHook(rh *hook.ReceivedHook, giteaEvent string) error {
+	rh.Branch, rh.Hash, rh.Author, rh.Message = "", "", "", ""
+	switch giteaEvent {
+	case repogitea.PushHookEvent:
+		e, err := repogitea.ParsePushHook(rh.Data)
+		if err != nil {
+			return err
+		}
+		rh.Branch = e.Branch.ID
+		rh.Hash = e.Commit.Hash
+		rh.Author = e.Commit.Author.Name
+		rh.Message = "UPDATE"
+	case repogitea.DeleteHookEvent:
+		e, err := repogitea.ParseDeleteHook(rh.Data)
+		if err != nil || e == nil {
+			return err
+		}
+		rh.Branch = e.Branch.ID
+		rh.Message = "DELETE"
+	case repogitea.PullRequestHookEvent:
+		e, err := repogitea.ParsePullRequestHook(rh.Data)
+		if err != nil {
+			return err
+		}
+		if !repositoriesmanager.TriggerPullRequest(*e) {
+			return nil
+		}
+		rh.Branch = e.Head.Branch.ID
+		rh.Hash = e.Head.Commit.Hash
+		rh.Author = e.User.Name
+		rh.Message = "UPDATE"
+		rh.PullRequest = e
+	}
+	return nil
+}
+
 func addHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	vars := mux.Vars(r)
 	projectKey := vars["key"]
@@ -300,7 +407,12 @@ func processHook(db *gorp.DbMap, h hook.ReceivedHook) error {
 		}
 		projectData.Variable = projectsVar
 
-		pb, err := application.TriggerPipeline(tx, hooks[i], h.Branch, h.Hash, h.Author, p, projectData)
+		var prArgs []sdk.Parameter
+		if h.PullRequest != nil {
+			prArgs = sdk.PullRequestParameters(*h.PullRequest)
+		}
+
+		pb, err := application.TriggerPipeline(tx, hooks[i], h.Branch, h.Hash, h.Author, p, projectData, prArgs...)
 		if err != nil {
 			log.Warning("processHook> cannot trigger pipeline %d: %s\n", hooks[i].Pipeline.ID, err)
 			return err
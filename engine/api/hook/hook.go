@@ -26,15 +26,16 @@ func Init(url string) {
 
 //ReceivedHook is a temporary struct to manage received hook
 type ReceivedHook struct {
-	URL        url.URL
-	Data       []byte
-	ProjectKey string
-	Repository string
-	Branch     string
-	Hash       string
-	Author     string
-	Message    string
-	UID        string
+	URL         url.URL
+	Data        []byte
+	ProjectKey  string
+	Repository  string
+	Branch      string
+	Hash        string
+	Author      string
+	Message     string
+	UID         string
+	PullRequest *sdk.VCSPullRequestEvent
 }
 
 // HookLink format in stash/bitbucket
@@ -7,6 +7,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"strings"
 
 	"golang.org/x/crypto/openpgp"
 	"golang.org/x/crypto/openpgp/armor"
@@ -96,3 +97,20 @@ func GeneratePGPKeyPair(keyname string) (string, string, string, error) {
 
 	return key.PrimaryKey.KeyIdShortString(), bufPublic.String(), bufPrivate.String(), nil
 }
+
+// SignPGP returns an armored detached signature of data, made with an armored PGP private key
+func SignPGP(privateKey string, data []byte) (string, error) {
+	entities, errR := openpgp.ReadArmoredKeyRing(strings.NewReader(privateKey))
+	if errR != nil {
+		return "", sdk.WrapError(errR, "SignPGP> Cannot read private key")
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return "", fmt.Errorf("SignPGP> Cannot find private key")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := openpgp.ArmoredDetachSign(buf, entities[0], bytes.NewReader(data), nil); err != nil {
+		return "", sdk.WrapError(err, "SignPGP> Cannot sign data")
+	}
+	return buf.String(), nil
+}
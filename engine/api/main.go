@@ -15,11 +15,14 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/flakytest"
 	"github.com/ovh/cds/engine/api/grpc"
 	"github.com/ovh/cds/engine/api/hatchery"
 	"github.com/ovh/cds/engine/api/hook"
@@ -39,6 +42,7 @@ import (
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 var startupTime time.Time
@@ -80,6 +84,7 @@ var mainCmd = &cobra.Command{
 			case <-c:
 				log.Warning("Cleanup SQL connections")
 				database.Close()
+				database.CloseReplica()
 				cancel()
 				event.Publish(sdk.EventEngine{Message: "shutdown"})
 				event.Close()
@@ -148,6 +153,25 @@ var mainCmd = &cobra.Command{
 			os.Exit(3)
 		}
 
+		//Intialize database replica
+		if viper.GetString(viperDBReplicaHost) != "" {
+			if err := database.InitReplica(
+				viper.GetString(viperDBReplicaUser),
+				viper.GetString(viperDBReplicaPassword),
+				viper.GetString(viperDBName),
+				viper.GetString(viperDBReplicaHost),
+				viper.GetString(viperDBReplicaPort),
+				viper.GetString(viperDBSSLMode),
+				viper.GetInt(viperDBTimeout),
+				viper.GetInt(viperDBReplicaMaxConn),
+				time.Duration(viper.GetInt(viperDBReplicaMaxLag))*time.Second,
+			); err != nil {
+				log.Warning("⚠ Cannot connect to database replica, using primary database: %s", err)
+			} else {
+				go database.ReplicaLagChecker(ctx, 5*time.Second)
+			}
+		}
+
 		defaultValues := sdk.DefaultValues{
 			DefaultGroupName: viper.GetString(viperAuthDefaultGroup),
 			SharedInfraToken: viper.GetString(viperAuthSharedInfraToken),
@@ -162,6 +186,7 @@ var mainCmd = &cobra.Command{
 
 		cache.Initialize(viper.GetString(viperCacheMode), viper.GetString(viperCacheRedisHost), viper.GetString(viperCacheRedisPassword), viper.GetInt(viperCacheTTL))
 		InitLastUpdateBroker(ctx, database.GetDBMap)
+		initRateLimits()
 
 		router = &Router{
 			mux: mux.NewRouter(),
@@ -173,15 +198,28 @@ var mainCmd = &cobra.Command{
 
 		//Intialize repositories manager
 		rmInitOpts := repositoriesmanager.InitializeOpts{
-			KeysDirectory:          viper.GetString(viperKeysDirectory),
-			UIBaseURL:              baseURL,
-			APIBaseURL:             viper.GetString(viperURLAPI),
-			DisableGithubSetStatus: viper.GetBool(viperVCSRepoGithubStatusDisabled),
-			DisableGithubStatusURL: viper.GetBool(viperVCSRepoGithubStatusURLDisabled),
-			DisableStashSetStatus:  viper.GetBool(viperVCSRepoBitbucketStatusDisabled),
-			GithubSecret:           viper.GetString(viperVCSRepoGithubSecret),
-			StashPrivateKey:        viper.GetString(viperVCSRepoBitbucketPrivateKey),
-			StashConsumerKey:       viper.GetString(viperVCSRepoBitbucketConsumerKey),
+			KeysDirectory:                  viper.GetString(viperKeysDirectory),
+			UIBaseURL:                      baseURL,
+			APIBaseURL:                     viper.GetString(viperURLAPI),
+			DisableGithubSetStatus:         viper.GetBool(viperVCSRepoGithubStatusDisabled),
+			DisableGithubStatusURL:         viper.GetBool(viperVCSRepoGithubStatusURLDisabled),
+			DisableStashSetStatus:          viper.GetBool(viperVCSRepoBitbucketStatusDisabled),
+			DisableGitlabSetStatus:         viper.GetBool(viperVCSRepoGitlabStatusDisabled),
+			DisableGitlabStatusURL:         viper.GetBool(viperVCSRepoGitlabStatusURLDisabled),
+			DisableGiteaSetStatus:          viper.GetBool(viperVCSRepoGiteaStatusDisabled),
+			DisableGiteaStatusURL:          viper.GetBool(viperVCSRepoGiteaStatusURLDisabled),
+			EnableGithubPullRequestComment: viper.GetBool(viperVCSRepoGithubPRComments),
+			EnableStashPullRequestComment:  viper.GetBool(viperVCSRepoBitbucketPRComments),
+			EnableGitlabPullRequestComment: viper.GetBool(viperVCSRepoGitlabPRComments),
+			EnableGiteaPullRequestComment:  viper.GetBool(viperVCSRepoGiteaPRComments),
+			GithubSecret:                   viper.GetString(viperVCSRepoGithubSecret),
+			GitlabSecret:                   viper.GetString(viperVCSRepoGitlabSecret),
+			GitlabHookSecret:               viper.GetString(viperVCSRepoGitlabHookSecret),
+			GiteaSecret:                    viper.GetString(viperVCSRepoGiteaSecret),
+			GiteaHookSecret:                viper.GetString(viperVCSRepoGiteaHookSecret),
+			ForkPullRequestPolicy:          viper.GetString(viperVCSPullRequestForkPolicy),
+			StashPrivateKey:                viper.GetString(viperVCSRepoBitbucketPrivateKey),
+			StashConsumerKey:               viper.GetString(viperVCSRepoBitbucketConsumerKey),
 		}
 		if err := repositoriesmanager.Initialize(rmInitOpts); err != nil {
 			log.Warning("Error initializing repositories manager connections: %s", err)
@@ -241,11 +279,29 @@ var mainCmd = &cobra.Command{
 			log.Warning("⚠ Error while initializing workers routine: %s", err)
 		}
 
+		initMetrics(database.GetDBMap)
+
+		if err := tracing.Init(tracing.Config{
+			Exporter:    viper.GetString(viperTracingExporter),
+			File:        viper.GetString(viperTracingFile),
+			Endpoint:    viper.GetString(viperTracingEndpoint),
+			ServiceName: "cds-api",
+		}); err != nil {
+			log.Warning("⚠ Error while initializing tracing: %s", err)
+		}
+		defer tracing.Close()
+
 		go queue.Pipelines(ctx, database.GetDBMap)
 		go workflow.Scheduler(ctx, database.GetDBMap)
+		go workflow.ApprovalTimeoutChecker(ctx, database.GetDBMap)
 		go pipeline.AWOLPipelineKiller(ctx, database.GetDBMap)
 		go hatchery.Heartbeat(ctx, database.GetDBMap)
 		go auditCleanerRoutine(ctx, database.GetDBMap)
+		go audit.Purger(ctx, database.GetDBMap, time.Duration(viper.GetInt(viperAuditRetention))*24*time.Hour)
+		go flakytest.Purger(ctx, database.GetDBMap)
+		if err := setAuditTrustedProxies(viper.GetString(viperAuditTrustedProxies)); err != nil {
+			log.Fatalf("Cannot initialize audit: %s", err)
+		}
 
 		go repositoriesmanager.ReceiveEvents(ctx, database.GetDBMap)
 
@@ -267,6 +323,13 @@ var mainCmd = &cobra.Command{
 			log.Warning("⚠ Cron Scheduler is disabled")
 		}
 
+		if !viper.GetBool(viperArtifactPurgeDisabled) {
+			go artifact.Purger(ctx, database.GetDBMap)
+		} else {
+			log.Warning("⚠ Artifacts purge is disabled")
+		}
+		go objectstore.BlobPurger(ctx, database.GetDBMap)
+
 		s := &http.Server{
 			Addr:           ":" + viper.GetString(viperServerHTTPPort),
 			Handler:        router.mux,
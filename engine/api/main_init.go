@@ -35,6 +35,12 @@ const (
 	viperDBMaxConn                      = "db.maxconn"
 	viperDBTimeout                      = "db.timeout"
 	viperDBSecret                       = "db.secret"
+	viperDBReplicaHost                  = "db.replica.host"
+	viperDBReplicaPort                  = "db.replica.port"
+	viperDBReplicaUser                  = "db.replica.user"
+	viperDBReplicaPassword              = "db.replica.password"
+	viperDBReplicaMaxConn               = "db.replica.maxconn"
+	viperDBReplicaMaxLag                = "db.replica.maxlag"
 	viperCacheMode                      = "cache.mode"
 	viperCacheTTL                       = "cache.ttl"
 	viperCacheRedisHost                 = "cache.redis.host"
@@ -65,6 +71,15 @@ const (
 	viperArtifactOSTenant               = "artifact.openstack.tenant"
 	viperArtifactOSRegion               = "artifact.openstack.region"
 	viperArtifactOSContainerPrefix      = "artifact.openstack.containerprefix"
+	viperArtifactPurgeDisabled          = "artifact.purge.disabled"
+	viperAuditRetention                 = "audit.retention"
+	viperAuditTrustedProxies            = "audit.trustedproxies"
+	viperRateLimitEnabled               = "ratelimit.enabled"
+	viperRateLimitDefault               = "ratelimit.default"
+	viperRateLimitRoutes                = "ratelimit.routes"
+	viperTracingExporter                = "tracing.exporter"
+	viperTracingFile                    = "tracing.file"
+	viperTracingEndpoint                = "tracing.endpoint"
 	viperEventsKafkaEnabled             = "events.kafka.enabled"
 	viperEventsKafkaBroker              = "events.kafka.broker"
 	viperEventsKafkaTopic               = "events.kafka.topic"
@@ -72,12 +87,25 @@ const (
 	viperEventsKafkaPassword            = "events.kafka.password"
 	viperSchedulersDisabled             = "schedulers.disabled"
 	viperVCSPollingDisabled             = "vcs.polling.disabled"
+	viperVCSPullRequestForkPolicy       = "vcs.pullrequest.fork_policy"
 	viperVCSRepoGithubStatusDisabled    = "vcs.repositories.github.statuses_disabled"
 	viperVCSRepoGithubStatusURLDisabled = "vcs.repositories.github.statuses_url_disabled"
 	viperVCSRepoGithubSecret            = "vcs.repositories.github.clientsecret"
+	viperVCSRepoGithubPRComments        = "vcs.repositories.github.pullrequest_comments"
 	viperVCSRepoBitbucketStatusDisabled = "vcs.repositories.bitbucket.statuses_disabled"
 	viperVCSRepoBitbucketConsumerKey    = "vcs.repositories.bitbucket.consumerkey"
 	viperVCSRepoBitbucketPrivateKey     = "vcs.repositories.bitbucket.privatekey"
+	viperVCSRepoBitbucketPRComments     = "vcs.repositories.bitbucket.pullrequest_comments"
+	viperVCSRepoGitlabStatusDisabled    = "vcs.repositories.gitlab.statuses_disabled"
+	viperVCSRepoGitlabStatusURLDisabled = "vcs.repositories.gitlab.statuses_url_disabled"
+	viperVCSRepoGitlabSecret            = "vcs.repositories.gitlab.clientsecret"
+	viperVCSRepoGitlabHookSecret        = "vcs.repositories.gitlab.hooksecret"
+	viperVCSRepoGitlabPRComments        = "vcs.repositories.gitlab.pullrequest_comments"
+	viperVCSRepoGiteaStatusDisabled     = "vcs.repositories.gitea.statuses_disabled"
+	viperVCSRepoGiteaStatusURLDisabled  = "vcs.repositories.gitea.statuses_url_disabled"
+	viperVCSRepoGiteaSecret             = "vcs.repositories.gitea.clientsecret"
+	viperVCSRepoGiteaHookSecret         = "vcs.repositories.gitea.hooksecret"
+	viperVCSRepoGiteaPRComments         = "vcs.repositories.gitea.pullrequest_comments"
 	vaultConfKey                        = "/secret/cds/conf"
 )
 
@@ -222,6 +250,12 @@ const tmpl = `###################################
 # CDS_DB_MAXCONN
 # CDS_DB_TIMEOUT
 # CDS_DB_SECRET
+# CDS_DB_REPLICA_HOST
+# CDS_DB_REPLICA_PORT
+# CDS_DB_REPLICA_USER
+# CDS_DB_REPLICA_PASSWORD
+# CDS_DB_REPLICA_MAXCONN
+# CDS_DB_REPLICA_MAXLAG
 # CDS_CACHE_MODE
 # CDS_CACHE_TTL
 # CDS_CACHE_REDIS_HOST
@@ -252,6 +286,7 @@ const tmpl = `###################################
 # CDS_ARTIFACT_OPENSTACK_TENANT
 # CDS_ARTIFACT_OPENSTACK_REGION
 # CDS_ARTIFACT_OPENSTACK_CONTAINERPREFIX
+# CDS_ARTIFACT_PURGE_DISABLED
 # CDS_EVENTS_KAFKA_ENABLED
 # CDS_EVENTS_KAFKA_BROKER
 # CDS_EVENTS_KAFKA_TOPIC
@@ -259,12 +294,23 @@ const tmpl = `###################################
 # CDS_EVENTS_KAFKA_PASSWORD
 # CDS_SCHEDULERS_DISABLED
 # CDS_VCS_POLLING_DISABLED
+# CDS_VCS_PULLREQUEST_FORK_POLICY
 # CDS_VCS_REPOSITORIES_GITHUB_STATUSES_DISABLED
 # CDS_VCS_REPOSITORIES_GITHUB_STATUSES_URL_DISABLED
 # CDS_VCS_REPOSITORIES_GITHUB_CLIENTSECRET
+# CDS_VCS_REPOSITORIES_GITHUB_PULLREQUEST_COMMENTS
 # CDS_VCS_REPOSITORIES_BITBUCKET_STATUSES_DISABLED
 # CDS_VCS_REPOSITORIES_BITBUCKET_CONSUMERKEY
 # CDS_VCS_REPOSITORIES_BITBUCKET_PRIVATEKEY
+# CDS_VCS_REPOSITORIES_BITBUCKET_PULLREQUEST_COMMENTS
+# CDS_VCS_REPOSITORIES_GITLAB_STATUSES_DISABLED
+# CDS_VCS_REPOSITORIES_GITLAB_STATUSES_URL_DISABLED
+# CDS_VCS_REPOSITORIES_GITLAB_CLIENTSECRET
+# CDS_VCS_REPOSITORIES_GITLAB_PULLREQUEST_COMMENTS
+# CDS_VCS_REPOSITORIES_GITEA_STATUSES_DISABLED
+# CDS_VCS_REPOSITORIES_GITEA_STATUSES_URL_DISABLED
+# CDS_VCS_REPOSITORIES_GITEA_CLIENTSECRET
+# CDS_VCS_REPOSITORIES_GITEA_PULLREQUEST_COMMENTS
 
 
 #####################
@@ -319,6 +365,18 @@ sslmode = "disable"
 maxconn = 20
 timeout = 3000
 
+    # Optional streaming replica of the database. The heavy read only handlers run on it, as long as its replication lag is under maxlag
+    # The replica is disabled if host is empty. User and password default to the ones of the primary database
+    # The user needs the pg_read_all_stats role (superuser with PostgreSQL 9.6) to tell an idle replica from a stalled one
+    # Without it, the replica is reported with insufficient privileges in the status and the primary database is used
+    [db.replica]
+    host = ""
+    port = 5432
+    user = ""
+    password = ""
+    maxconn = 20
+    maxlag = 10 # Maximum replication lag in seconds, the primary database is used when the replica is later
+
 
 ######################
 # CDS Cache Settings #
@@ -391,6 +449,48 @@ mode = "local"
     region = "<OS_REGION_NAME>"
     containerprefix = "" # Use if your want to prefix containers
 
+    [artifact.purge]
+    disabled = false # Set to true to disable the purge of artifacts according to projects retention rules
+
+######################
+# CDS Audit Settings #
+######################
+# Every POST, PUT and DELETE call to the API is recorded in the audit log
+[audit]
+retention = 365 # Number of days the audit log is kept. Set to 0 to keep it forever
+trustedproxies = "" # Comma separated IPs or CIDRs of the reverse proxies allowed to set the X-Forwarded-For header, e.g. "10.0.0.0/8"
+
+###########################
+# CDS Rate Limit Settings #
+###########################
+# Token bucket rate limits by user, worker and hatchery. The buckets are stored in the cache, use the redis cache with several API instances
+# The rejected requests get a 429 status and a Retry-After header
+[ratelimit]
+enabled = false
+    # Rate limit of the routes without a specific rate limit
+    [ratelimit.default]
+    rate = 50 # Requests per second
+    burst = 200 # Maximum number of requests at once
+
+    # Rate limits by route group, the group of a route is the first element of its path: queue, project, worker, hatchery...
+    [ratelimit.routes]
+        [ratelimit.routes.queue]
+        rate = 20
+        burst = 100
+
+        [ratelimit.routes.hatchery]
+        rate = 5
+        burst = 20
+
+########################
+# CDS Tracing Settings #
+########################
+# Traces follow the workflow jobs from the API to the hatcheries and the workers
+[tracing]
+exporter = "" # stdout, file or otlp (OpenTelemetry collector). Tracing is disabled if empty
+file = "/tmp/cds-api-traces.json" # File the spans are written to with the file exporter
+endpoint = "http://localhost:4318" # URL of the OpenTelemetry collector with the otlp exporter
+
 #######################
 # CDS Events Settings #
 #######################
@@ -416,14 +516,33 @@ disabled = false #This is mainly for dev purpose, you should not have to change
     [vcs.polling]
     disabled = false #This is mainly for dev purpose, you should not have to change it
 
+    [vcs.pullrequest]
+    fork_policy = "withhold-secrets" # Policy for pull requests from forks: withhold-secrets (build without secrets nor private keys), skip (no build) or trust
+
     [vcs.repositories]
 
     [vcs.repositories.github]
     statuses_disabled = false # Set to true if you don't want CDS to push statuses on Github API
     statuses_url_disabled = false # Set to true if you don't want CDS to push CDS URL in statuses on Github API
+    pullrequest_comments = false # Set to true if you want CDS to comment pull requests with workflow results
     clientsecret = ""
 
     [vcs.repositories.bitbucket]
     statuses_disabled = false
+    pullrequest_comments = false # Set to true if you want CDS to comment pull requests with workflow results
     privatekey = ""
+
+    [vcs.repositories.gitlab]
+    statuses_disabled = false # Set to true if you don't want CDS to push statuses on Gitlab API
+    statuses_url_disabled = false # Set to true if you don't want CDS to push CDS URL in statuses on Gitlab API
+    pullrequest_comments = false # Set to true if you want CDS to comment merge requests with workflow results
+    clientsecret = ""
+    hooksecret = "" # Secret token of the hooks created by CDS on Gitlab. Required: the Gitlab hooks received without it are rejected
+
+    [vcs.repositories.gitea]
+    statuses_disabled = false # Set to true if you don't want CDS to push statuses on Gitea API
+    statuses_url_disabled = false # Set to true if you don't want CDS to push CDS URL in statuses on Gitea API
+    pullrequest_comments = false # Set to true if you want CDS to comment pull requests with workflow results
+    clientsecret = "" # Only needed for OAuth2 authentication, users can also use personal access tokens
+    hooksecret = "" # Secret signing the payloads of the hooks created by CDS on Gitea and Gogs. Required: the hooks received without a valid signature are rejected
 `
@@ -5,6 +5,8 @@ import (
 	"path"
 
 	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/sdk"
 )
 
 func (router *Router) init() {
@@ -20,11 +22,16 @@ func (router *Router) init() {
 
 	// Admin
 	router.Handle("/admin/warning", DELETE(adminTruncateWarningsHandler, NeedAdmin(true)))
+	router.Handle("/admin/audit", GET(getAdminAuditHandler, NeedAdmin(true)))
 	router.Handle("/admin/maintenance", POST(postAdminMaintenanceHandler, NeedAdmin(true)), GET(getAdminMaintenanceHandler, NeedAdmin(true)), DELETE(deleteAdminMaintenanceHandler, NeedAdmin(true)))
 
 	// Action plugin
 	router.Handle("/plugin", POST(addPluginHandler, NeedAdmin(true)), PUT(updatePluginHandler, NeedAdmin(true)))
 	router.Handle("/plugin/{name}", DELETE(deletePluginHandler, NeedAdmin(true)))
+	router.Handle("/plugin/{name}/version", GET(getPluginVersionsHandler))
+	router.Handle("/plugin/{name}/version/{version}/binary", POST(addPluginBinaryHandler, NeedAdmin(true)))
+	router.Handle("/plugin/{name}/current", PUT(putPluginCurrentVersionHandler, NeedAdmin(true)))
+	router.Handle("/plugin/{name}/resolve", GET(resolvePluginHandler))
 	router.Handle("/plugin/download/{name}", GET(downloadPluginHandler))
 
 	// Download file
@@ -36,11 +43,13 @@ func (router *Router) init() {
 	// Group
 	router.Handle("/group", GET(getGroups), POST(addGroupHandler))
 	router.Handle("/group/public", GET(getPublicGroups))
-	router.Handle("/group/{permGroupName}", GET(getGroupHandler), PUT(updateGroupHandler), DELETE(deleteGroupHandler))
+	router.Handle("/group/{permGroupName}", GET(getGroupHandler), PUT(updateGroupHandler, AuditSnapshot()), DELETE(deleteGroupHandler, AuditSnapshot()))
 	router.Handle("/group/{permGroupName}/user", POST(addUserInGroup))
 	router.Handle("/group/{permGroupName}/user/{user}", DELETE(removeUserFromGroupHandler))
 	router.Handle("/group/{permGroupName}/user/{user}/admin", POST(setUserGroupAdminHandler), DELETE(removeUserGroupAdminHandler))
 	router.Handle("/group/{permGroupName}/token/{expiration}", POST(generateTokenHandler))
+	router.Handle("/group/{permGroupName}/accesstoken", GET(getGroupAccessTokensHandler), POST(postGroupAccessTokenHandler))
+	router.Handle("/group/{permGroupName}/accesstoken/{id}", DELETE(deleteGroupAccessTokenHandler))
 
 	// Hatchery
 	router.Handle("/hatchery", POST(registerHatchery, Auth(false)))
@@ -53,36 +62,42 @@ func (router *Router) init() {
 	router.Handle("/mon/status", GET(statusHandler, Auth(false)))
 	router.Handle("/mon/smtp/ping", GET(smtpPingHandler, Auth(true)))
 	router.Handle("/mon/version", GET(getVersionHandler, Auth(false)))
-	router.Handle("/mon/stats", GET(getStats, Auth(false)))
+	router.Handle("/mon/stats", GET(getStats, Auth(false), ReadOnly()))
+	router.Handle("/mon/metrics", GET(getMetricsHandler, Auth(false)))
+	router.Handle("/mon/openapi", GET(getOpenAPIHandler, Auth(false)))
 	router.Handle("/mon/building", GET(getBuildingPipelines))
 	router.Handle("/mon/building/{hash}", GET(getPipelineBuildingCommit))
 	router.Handle("/mon/warning", GET(getUserWarnings))
-	router.Handle("/mon/lastupdates", GET(getUserLastUpdates))
+	router.Handle("/mon/lastupdates", GET(getUserLastUpdates, ReadOnly()))
 
 	// Project
 	router.Handle("/project", GET(getProjectsHandler), POST(addProjectHandler))
 	router.Handle("/project/{permProjectKey}", GET(getProjectHandler), PUT(updateProjectHandler), DELETE(deleteProjectHandler))
-	router.Handle("/project/{permProjectKey}/group", POST(addGroupInProject), PUT(updateGroupsInProject, DEPRECATED))
-	router.Handle("/project/{permProjectKey}/group/{group}", PUT(updateGroupRoleOnProjectHandler), DELETE(deleteGroupFromProjectHandler))
-	router.Handle("/project/{permProjectKey}/variable", GET(getVariablesInProjectHandler), PUT(updateVariablesInProjectHandler, DEPRECATED))
+	router.Handle("/project/{permProjectKey}/group", GET(getGroupsInProjectHandler), POST(addGroupInProject, AuditSnapshot()), PUT(updateGroupsInProject, DEPRECATED, AuditSnapshot()))
+	router.Handle("/project/{permProjectKey}/group/{group}", PUT(updateGroupRoleOnProjectHandler, AuditSnapshot()), DELETE(deleteGroupFromProjectHandler, AuditSnapshot()))
+	router.Handle("/project/{permProjectKey}/variable", GET(getVariablesInProjectHandler), PUT(updateVariablesInProjectHandler, DEPRECATED, NeedCapability(sdk.CapabilityManageVariables)))
 	router.Handle("/project/{key}/variable/audit", GET(getVariablesAuditInProjectnHandler))
 	router.Handle("/project/{key}/variable/audit/{auditID}", PUT(restoreProjectVariableAuditHandler, DEPRECATED))
-	router.Handle("/project/{permProjectKey}/variable/{name}", GET(getVariableInProjectHandler, DEPRECATED), POST(addVariableInProjectHandler), PUT(updateVariableInProjectHandler), DELETE(deleteVariableFromProjectHandler))
+	router.Handle("/project/{permProjectKey}/variable/{name}", GET(getVariableInProjectHandler, DEPRECATED), POST(addVariableInProjectHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()), PUT(updateVariableInProjectHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()), DELETE(deleteVariableFromProjectHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()))
 	router.Handle("/project/{permProjectKey}/variable/{name}/audit", GET(getVariableAuditInProjectHandler))
 	router.Handle("/project/{permProjectKey}/applications", GET(getApplicationsHandler), POST(addApplicationHandler))
 	router.Handle("/project/{permProjectKey}/notifications", GET(getProjectNotificationsHandler))
-	router.Handle("/project/{permProjectKey}/keys", GET(getKeysInProjectHandler), POST(addKeyInProjectHandler))
-	router.Handle("/project/{permProjectKey}/keys/{name}", DELETE(deleteKeyInProjectHandler))
+	router.Handle("/project/{permProjectKey}/keys", GET(getKeysInProjectHandler), POST(addKeyInProjectHandler, NeedCapability(sdk.CapabilityManageKeys)))
+	router.Handle("/project/{permProjectKey}/keys/{name}", DELETE(deleteKeyInProjectHandler, NeedCapability(sdk.CapabilityManageKeys), AuditSnapshot()))
+	router.Handle("/project/{permProjectKey}/export", POST(exportProjectHandler, NeedCapability(sdk.CapabilityManageKeys)))
+	router.Handle("/project/{key}/import", POST(importProjectHandler))
+	router.Handle("/project/{permProjectKey}/artifact/retention", GET(getArtifactRetentionHandler), PUT(putArtifactRetentionHandler))
+	router.Handle("/project/{permProjectKey}/artifact/retention/report", GET(getArtifactPurgeReportHandler))
 
 	// Application
 	router.Handle("/project/{key}/application/{permApplicationName}", GET(getApplicationHandler), PUT(updateApplicationHandler), DELETE(deleteApplicationHandler))
-	router.Handle("/project/{key}/application/{permApplicationName}/keys", GET(getKeysInApplicationHandler), POST(addKeyInApplicationHandler))
-	router.Handle("/project/{key}/application/{permApplicationName}/keys/{name}", DELETE(deleteKeyInApplicationHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/keys", GET(getKeysInApplicationHandler), POST(addKeyInApplicationHandler, NeedCapability(sdk.CapabilityManageKeys)))
+	router.Handle("/project/{key}/application/{permApplicationName}/keys/{name}", DELETE(deleteKeyInApplicationHandler, NeedCapability(sdk.CapabilityManageKeys), AuditSnapshot()))
 	router.Handle("/project/{key}/application/{permApplicationName}/branches", GET(getApplicationBranchHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/version", GET(getApplicationBranchVersionHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/clone", POST(cloneApplicationHandler))
-	router.Handle("/project/{key}/application/{permApplicationName}/group", POST(addGroupInApplicationHandler), PUT(updateGroupsInApplicationHandler, DEPRECATED))
-	router.Handle("/project/{key}/application/{permApplicationName}/group/{group}", PUT(updateGroupRoleOnApplicationHandler), DELETE(deleteGroupFromApplicationHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/group", GET(getGroupsInApplicationHandler), POST(addGroupInApplicationHandler, AuditSnapshot()), PUT(updateGroupsInApplicationHandler, DEPRECATED, AuditSnapshot()))
+	router.Handle("/project/{key}/application/{permApplicationName}/group/{group}", PUT(updateGroupRoleOnApplicationHandler, AuditSnapshot()), DELETE(deleteGroupFromApplicationHandler, AuditSnapshot()))
 	router.Handle("/project/{key}/application/{permApplicationName}/history/branch", GET(getPipelineBuildBranchHistoryHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/history/env/deploy", GET(getApplicationDeployHistoryHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/notifications", POST(addNotificationsHandler))
@@ -92,12 +107,15 @@ func (router *Router) init() {
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/notification", GET(getUserNotificationApplicationPipelineHandler), PUT(updateUserNotificationApplicationPipelineHandler), DELETE(deleteUserNotificationApplicationPipelineHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/scheduler", GET(getSchedulerApplicationPipelineHandler), POST(addSchedulerApplicationPipelineHandler), PUT(updateSchedulerApplicationPipelineHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/scheduler/{id}", DELETE(deleteSchedulerApplicationPipelineHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/tests/flaky", GET(getApplicationFlakyTestsHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/tests/history", GET(getApplicationTestHistoryHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/tests/quarantine", GET(getApplicationTestQuarantineHandler), POST(postApplicationTestQuarantineHandler), DELETE(deleteApplicationTestQuarantineHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/tree", GET(getApplicationTreeHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/tree/status", GET(getApplicationTreeStatusHandler))
-	router.Handle("/project/{key}/application/{permApplicationName}/variable", GET(getVariablesInApplicationHandler), PUT(updateVariablesInApplicationHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/variable", GET(getVariablesInApplicationHandler), PUT(updateVariablesInApplicationHandler, NeedCapability(sdk.CapabilityManageVariables)))
 	router.Handle("/project/{key}/application/{permApplicationName}/variable/audit", GET(getVariablesAuditInApplicationHandler))
-	router.Handle("/project/{key}/application/{permApplicationName}/variable/audit/{auditID}", PUT(restoreAuditHandler, DEPRECATED))
-	router.Handle("/project/{key}/application/{permApplicationName}/variable/{name}", GET(getVariableInApplicationHandler), POST(addVariableInApplicationHandler), PUT(updateVariableInApplicationHandler), DELETE(deleteVariableFromApplicationHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/variable/audit/{auditID}", PUT(restoreAuditHandler, DEPRECATED, NeedCapability(sdk.CapabilityManageVariables)))
+	router.Handle("/project/{key}/application/{permApplicationName}/variable/{name}", GET(getVariableInApplicationHandler), POST(addVariableInApplicationHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()), PUT(updateVariableInApplicationHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()), DELETE(deleteVariableFromApplicationHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()))
 	router.Handle("/project/{key}/application/{permApplicationName}/variable/{name}/audit", GET(getVariableAuditInApplicationHandler))
 
 	// Pipeline
@@ -120,8 +138,8 @@ func (router *Router) init() {
 	router.Handle("/project/{permProjectKey}/pipeline", GET(getPipelinesHandler), POST(addPipeline))
 	router.Handle("/project/{permProjectKey}/import/pipeline", POST(importPipelineHandler))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/application", GET(getApplicationUsingPipelineHandler))
-	router.Handle("/project/{key}/pipeline/{permPipelineKey}/group", POST(addGroupInPipelineHandler), PUT(updateGroupsOnPipelineHandler, DEPRECATED))
-	router.Handle("/project/{key}/pipeline/{permPipelineKey}/group/{group}", PUT(updateGroupRoleOnPipelineHandler), DELETE(deleteGroupFromPipelineHandler))
+	router.Handle("/project/{key}/pipeline/{permPipelineKey}/group", GET(getGroupsInPipelineHandler), POST(addGroupInPipelineHandler, AuditSnapshot()), PUT(updateGroupsOnPipelineHandler, DEPRECATED, AuditSnapshot()))
+	router.Handle("/project/{key}/pipeline/{permPipelineKey}/group/{group}", PUT(updateGroupRoleOnPipelineHandler, AuditSnapshot()), DELETE(deleteGroupFromPipelineHandler, AuditSnapshot()))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/parameter", GET(getParametersInPipelineHandler), PUT(updateParametersInPipelineHandler, DEPRECATED))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/parameter/{name}", POST(addParameterInPipelineHandler), PUT(updateParameterInPipelineHandler), DELETE(deleteParameterFromPipelineHandler))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}", GET(getPipelineHandler), PUT(updatePipelineHandler), DELETE(deletePipeline))
@@ -135,7 +153,7 @@ func (router *Router) init() {
 	router.Handle("/project/{permProjectKey}/workflows", POST(postWorkflowHandler), GET(getWorkflowsHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}", GET(getWorkflowHandler), PUT(putWorkflowHandler), DELETE(deleteWorkflowHandler))
 	// Workflows run
-	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs", GET(getWorkflowRunsHandler), POST(postWorkflowRunHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs", GET(getWorkflowRunsHandler, ReadOnly()), POST(postWorkflowRunHandler, NeedAccessTokenScope(sdk.AccessTokenScopeRun)))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/latest", GET(getLatestWorkflowRunHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/tags", GET(getWorkflowRunTagsHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}", GET(getWorkflowRunHandler))
@@ -143,7 +161,12 @@ func (router *Router) init() {
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}", GET(getWorkflowNodeRunHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/job/{runJobId}/step/{stepOrder}", GET(getWorkflowNodeRunJobStepHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/artifacts", GET(getWorkflowNodeRunArtifactsHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/provenance", GET(getWorkflowNodeRunProvenanceHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/coverage", GET(getWorkflowNodeRunCoverageHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/provenance/verify", GET(getWorkflowNodeRunProvenanceVerifyHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/approval", GET(getWorkflowNodeRunApprovalsHandler), POST(postWorkflowNodeRunApprovalHandler, NeedCapability(sdk.CapabilityApproveDeployments)))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/artifact/{artifactId}", GET(getDownloadArtifactHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/coverage", GET(getWorkflowCoverageTrendHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/node/{nodeID}/triggers/condition", GET(getWorkflowTriggerConditionHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/join/{joinID}/triggers/condition", GET(getWorkflowTriggerJoinConditionHandler))
 
@@ -164,16 +187,16 @@ func (router *Router) init() {
 	router.Handle("/project/{permProjectKey}/environment/import", POST(importNewEnvironmentHandler))
 	router.Handle("/project/{permProjectKey}/environment/import/{permEnvironmentName}", POST(importIntoEnvironmentHandler))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}", GET(getEnvironmentHandler), PUT(updateEnvironmentHandler), DELETE(deleteEnvironmentHandler))
-	router.Handle("/project/{key}/environment/{permEnvironmentName}/keys", GET(getKeysInEnvironmentHandler), POST(addKeyInEnvironmentHandler))
-	router.Handle("/project/{key}/environment/{permEnvironmentName}/keys/{name}", DELETE(deleteKeyInEnvironmentHandler))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/keys", GET(getKeysInEnvironmentHandler), POST(addKeyInEnvironmentHandler, NeedCapability(sdk.CapabilityManageKeys)))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/keys/{name}", DELETE(deleteKeyInEnvironmentHandler, NeedCapability(sdk.CapabilityManageKeys), AuditSnapshot()))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/clone/{cloneName}", POST(cloneEnvironmentHandler))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/audit", GET(getEnvironmentsAuditHandler, DEPRECATED))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/audit/{auditID}", PUT(restoreEnvironmentAuditHandler, DEPRECATED))
-	router.Handle("/project/{key}/environment/{permEnvironmentName}/group", POST(addGroupInEnvironmentHandler))
-	router.Handle("/project/{key}/environment/{permEnvironmentName}/groups", POST(addGroupsInEnvironmentHandler))
-	router.Handle("/project/{key}/environment/{permEnvironmentName}/group/{group}", PUT(updateGroupRoleOnEnvironmentHandler), DELETE(deleteGroupFromEnvironmentHandler))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/group", GET(getGroupsInEnvironmentHandler), POST(addGroupInEnvironmentHandler, AuditSnapshot()))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/groups", POST(addGroupsInEnvironmentHandler, AuditSnapshot()))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/group/{group}", PUT(updateGroupRoleOnEnvironmentHandler, AuditSnapshot()), DELETE(deleteGroupFromEnvironmentHandler, AuditSnapshot()))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/variable", GET(getVariablesInEnvironmentHandler))
-	router.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}", GET(getVariableInEnvironmentHandler), POST(addVariableInEnvironmentHandler), PUT(updateVariableInEnvironmentHandler), DELETE(deleteVariableFromEnvironmentHandler))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}", GET(getVariableInEnvironmentHandler), POST(addVariableInEnvironmentHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()), PUT(updateVariableInEnvironmentHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()), DELETE(deleteVariableFromEnvironmentHandler, NeedCapability(sdk.CapabilityManageVariables), AuditSnapshot()))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}/audit", GET(getVariableAuditInEnvironmentHandler))
 
 	// Artifacts
@@ -212,11 +235,13 @@ func (router *Router) init() {
 	router.Handle("/queue/workflows/{permID}/result", POSTEXECUTE(postWorkflowJobResultHandler, NeedWorker()))
 	router.Handle("/queue/workflows/{permID}/log", POSTEXECUTE(postWorkflowJobLogsHandler, NeedWorker()))
 	router.Handle("/queue/workflows/{permID}/test", POSTEXECUTE(postWorkflowJobTestsResultsHandler, NeedWorker()))
+	router.Handle("/queue/workflows/{permID}/coverage", POSTEXECUTE(postWorkflowJobCoverageHandler, NeedWorker()))
 	router.Handle("/queue/workflows/{permID}/variable", POSTEXECUTE(postWorkflowJobVariableHandler, NeedWorker()))
 	router.Handle("/queue/workflows/{permID}/step", POSTEXECUTE(postWorkflowJobStepStatusHandler, NeedWorker()))
 	router.Handle("/queue/workflows/{permID}/artifact/{tag}", POSTEXECUTE(postWorkflowJobArtifactHandler, NeedWorker()))
 
 	router.Handle("/variable/type", GET(getVariableTypeHandler))
+	router.Handle("/role", GET(getRolesHandler))
 	router.Handle("/parameter/type", GET(getParameterTypeHandler))
 	router.Handle("/pipeline/type", GET(getPipelineTypeHandler))
 	router.Handle("/notification/type", GET(getUserNotificationTypeHandler))
@@ -263,6 +288,8 @@ func (router *Router) init() {
 	router.Handle("/user/import", POST(importUsersHandler, NeedAdmin(true)))
 	router.Handle("/user/{username}", GET(GetUserHandler, NeedUsernameOrAdmin(true)), PUT(UpdateUserHandler, NeedUsernameOrAdmin(true)), DELETE(DeleteUserHandler, NeedUsernameOrAdmin(true)))
 	router.Handle("/user/{username}/groups", GET(getUserGroupsHandler, NeedUsernameOrAdmin(true)))
+	router.Handle("/user/{username}/accesstoken", GET(getUserAccessTokensHandler, NeedUsernameOrAdmin(true)), POST(postUserAccessTokenHandler, NeedUsernameOrAdmin(true)))
+	router.Handle("/user/{username}/accesstoken/{id}", DELETE(deleteUserAccessTokenHandler, NeedUsernameOrAdmin(true)))
 	router.Handle("/user/{username}/confirm/{token}", GET(ConfirmUser, Auth(false)))
 	router.Handle("/user/{username}/reset", POST(ResetUser, Auth(false)))
 	router.Handle("/auth/mode", GET(AuthModeHandler, Auth(false)))
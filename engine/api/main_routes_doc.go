@@ -0,0 +1,426 @@
+package main
+
+import (
+	"github.com/ovh/venom"
+
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/sdk"
+)
+
+// routesDoc documents the routes in the OpenAPI specification, by method and route. Every route registered in
+// main_routes.go must be documented here
+var routesDoc = map[string]HandlerDoc{
+	"POST /login": {Summary: "Log in a user and open a session", Request: sdk.UserLoginRequest{}, Response: sdk.UserAPIResponse{}},
+
+	// Action
+	"GET /action":                     {Summary: "List the actions", Response: []sdk.Action{}},
+	"POST /action/import":             {Summary: "Import an action", Request: sdk.Action{}, Response: sdk.Action{}},
+	"GET /action/requirement":         {Summary: "List the requirements of all the actions", Response: []sdk.Requirement{}},
+	"GET /action/{permActionName}":    {Summary: "Get an action", Response: sdk.Action{}},
+	"POST /action/{permActionName}":   {Summary: "Add an action", Request: sdk.Action{}, Response: sdk.Action{}},
+	"PUT /action/{permActionName}":    {Summary: "Update an action", Request: sdk.Action{}, Response: sdk.Action{}},
+	"DELETE /action/{permActionName}": {Summary: "Delete an action"},
+	"GET /action/{actionName}/using":  {Summary: "List the pipelines using an action", Response: []pipelineUsingAction{}},
+	"GET /action/{actionID}/audit":    {Summary: "Get the audit of an action", Response: []sdk.ActionAudit{}},
+
+	// Admin
+	"DELETE /admin/warning":     {Summary: "Delete all the warnings"},
+	"GET /admin/audit":          {Summary: "List the audit logs", Response: []sdk.AuditLog{}},
+	"POST /admin/maintenance":   {Summary: "Enable the maintenance mode"},
+	"GET /admin/maintenance":    {Summary: "Get the maintenance mode", Response: new(bool)},
+	"DELETE /admin/maintenance": {Summary: "Disable the maintenance mode"},
+
+	// Action plugin
+	"POST /plugin":               {Summary: "Add an action plugin", Response: sdk.Action{}},
+	"PUT /plugin":                {Summary: "Update an action plugin", Response: sdk.Action{}},
+	"DELETE /plugin/{name}":      {Summary: "Delete an action plugin"},
+	"GET /plugin/{name}/version": {Summary: "List the versions of an action plugin", Response: []sdk.ActionPluginVersion{}},
+	"POST /plugin/{name}/version/{version}/binary": {Summary: "Upload a binary of an action plugin version", Response: sdk.ActionPluginBinary{}},
+	"PUT /plugin/{name}/current":                   {Summary: "Set the current version of an action plugin", Request: sdk.ActionPluginVersion{}, Response: sdk.Action{}},
+	"GET /plugin/{name}/resolve":                   {Summary: "Resolve the binary of an action plugin for the worker platform", Response: sdk.ActionPluginBinary{}},
+	"GET /plugin/download/{name}":                  {Summary: "Download the binary of an action plugin"},
+
+	// Group
+	"GET /group":                                      {Summary: "List the groups", Response: []sdk.Group{}},
+	"POST /group":                                     {Summary: "Add a group", Request: sdk.Group{}},
+	"GET /group/public":                               {Summary: "List the public groups", Response: []sdk.Group{}},
+	"GET /group/{permGroupName}":                      {Summary: "Get a group", Response: sdk.Group{}},
+	"PUT /group/{permGroupName}":                      {Summary: "Update a group", Request: sdk.Group{}},
+	"DELETE /group/{permGroupName}":                   {Summary: "Delete a group"},
+	"POST /group/{permGroupName}/user":                {Summary: "Add users in a group", Request: []string{}},
+	"DELETE /group/{permGroupName}/user/{user}":       {Summary: "Remove a user from a group"},
+	"POST /group/{permGroupName}/user/{user}/admin":   {Summary: "Set a user administrator of a group"},
+	"DELETE /group/{permGroupName}/user/{user}/admin": {Summary: "Remove a user from the administrators of a group"},
+	"POST /group/{permGroupName}/token/{expiration}":  {Summary: "Generate a worker token for a group", Response: map[string]string{}},
+	"GET /group/{permGroupName}/accesstoken":          {Summary: "List the access tokens of a group", Response: []sdk.AccessToken{}},
+	"POST /group/{permGroupName}/accesstoken":         {Summary: "Add an access token to a group", Request: sdk.AccessToken{}, Response: sdk.AccessToken{}},
+	"DELETE /group/{permGroupName}/accesstoken/{id}":  {Summary: "Delete an access token of a group"},
+
+	// Hatchery
+	"POST /hatchery":     {Summary: "Register a hatchery", Request: sdk.Hatchery{}, Response: sdk.Hatchery{}},
+	"PUT /hatchery/{id}": {Summary: "Refresh a hatchery"},
+
+	// Hooks
+	"POST /hook": {Summary: "Receive a hook from a repository manager"},
+
+	// Overall health
+	"GET /mon/status":    {Summary: "Get the status of the API", Response: []string{}},
+	"GET /mon/smtp/ping": {Summary: "Check the SMTP server", Response: map[string]string{}},
+	"GET /mon/version": {
+		Summary: "Get the version of the API",
+		Response: struct {
+			Version string `json:"version"`
+		}{},
+	},
+	"GET /mon/stats":           {Summary: "Get the statistics of the builds", Response: sdk.Stats{}},
+	"GET /mon/metrics":         {Summary: "Get the Prometheus metrics"},
+	"GET /mon/openapi":         {Summary: "Get the OpenAPI specification of the API", Response: openAPISpec{}},
+	"GET /mon/building":        {Summary: "List the building pipelines", Response: []sdk.PipelineBuild{}},
+	"GET /mon/building/{hash}": {Summary: "List the building pipelines of a commit", Response: []sdk.PipelineBuild{}},
+	"GET /mon/warning":         {Summary: "List the warnings of the user", Response: []sdk.Warning{}},
+	"GET /mon/lastupdates":     {Summary: "List the last updates of the user", Response: []sdk.ProjectLastUpdates{}},
+
+	// Project
+	"GET /project":                                            {Summary: "List the projects", Response: []sdk.Project{}},
+	"POST /project":                                           {Summary: "Add a project", Request: sdk.Project{}, Response: sdk.Project{}},
+	"GET /project/{permProjectKey}":                           {Summary: "Get a project", Response: sdk.Project{}},
+	"PUT /project/{permProjectKey}":                           {Summary: "Update a project", Request: sdk.Project{}, Response: sdk.Project{}},
+	"DELETE /project/{permProjectKey}":                        {Summary: "Delete a project"},
+	"POST /project/{permProjectKey}/group":                    {Summary: "Add a group in a project", Request: sdk.GroupPermission{}, Response: []sdk.GroupPermission{}},
+	"PUT /project/{permProjectKey}/group":                     {Summary: "Update the groups of a project", Request: []sdk.GroupPermission{}},
+	"PUT /project/{permProjectKey}/group/{group}":             {Summary: "Update the role of a group on a project", Request: sdk.GroupPermission{}, Response: sdk.GroupPermission{}},
+	"DELETE /project/{permProjectKey}/group/{group}":          {Summary: "Remove a group from a project"},
+	"GET /project/{permProjectKey}/variable":                  {Summary: "List the variables of a project", Response: []sdk.Variable{}},
+	"PUT /project/{permProjectKey}/variable":                  {Summary: "Update the variables of a project", Request: []sdk.Variable{}},
+	"GET /project/{key}/variable/audit":                       {Summary: "List the audit of the variables of a project", Response: []sdk.VariableAudit{}},
+	"PUT /project/{key}/variable/audit/{auditID}":             {Summary: "Restore the variables of a project from an audit"},
+	"GET /project/{permProjectKey}/variable/{name}":           {Summary: "Get a variable of a project", Response: sdk.Variable{}},
+	"POST /project/{permProjectKey}/variable/{name}":          {Summary: "Add a variable in a project", Request: sdk.Variable{}, Response: sdk.Project{}},
+	"PUT /project/{permProjectKey}/variable/{name}":           {Summary: "Update a variable of a project", Request: sdk.Variable{}, Response: sdk.Variable{}},
+	"DELETE /project/{permProjectKey}/variable/{name}":        {Summary: "Delete a variable of a project"},
+	"GET /project/{permProjectKey}/variable/{name}/audit":     {Summary: "Get the audit of a variable of a project", Response: []sdk.ProjectVariableAudit{}},
+	"GET /project/{permProjectKey}/applications":              {Summary: "List the applications of a project", Response: []sdk.Application{}},
+	"POST /project/{permProjectKey}/applications":             {Summary: "Add an application in a project", Request: sdk.Application{}},
+	"GET /project/{permProjectKey}/notifications":             {Summary: "List the notifications of a project", Response: []sdk.UserNotification{}},
+	"GET /project/{permProjectKey}/keys":                      {Summary: "List the keys of a project", Response: []sdk.ProjectKey{}},
+	"POST /project/{permProjectKey}/keys":                     {Summary: "Add a key in a project", Request: sdk.ProjectKey{}, Response: sdk.ProjectKey{}},
+	"DELETE /project/{permProjectKey}/keys/{name}":            {Summary: "Delete a key of a project"},
+	"POST /project/{permProjectKey}/export":                   {Summary: "Export a whole project as an archive, its secrets are encrypted with the passphrase header"},
+	"POST /project/{key}/import":                              {Summary: "Import a project archive, only the missing entities are created", Response: []string{}},
+	"GET /project/{permProjectKey}/artifact/retention":        {Summary: "Get the artifact retention policy of a project", Response: sdk.ArtifactRetention{}},
+	"PUT /project/{permProjectKey}/artifact/retention":        {Summary: "Update the artifact retention policy of a project", Request: sdk.ArtifactRetention{}, Response: sdk.ArtifactRetention{}},
+	"GET /project/{permProjectKey}/artifact/retention/report": {Summary: "Get the report of the last artifact purge of a project", Response: sdk.ArtifactPurgeReport{}},
+
+	// Application
+	"GET /project/{key}/application/{permApplicationName}":                                              {Summary: "Get an application", Response: sdk.Application{}},
+	"PUT /project/{key}/application/{permApplicationName}":                                              {Summary: "Update an application", Request: sdk.Application{}, Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}":                                           {Summary: "Delete an application"},
+	"GET /project/{key}/application/{permApplicationName}/keys":                                         {Summary: "List the keys of an application", Response: []sdk.ApplicationKey{}},
+	"POST /project/{key}/application/{permApplicationName}/keys":                                        {Summary: "Add a key in an application", Request: sdk.ApplicationKey{}, Response: sdk.ApplicationKey{}},
+	"DELETE /project/{key}/application/{permApplicationName}/keys/{name}":                               {Summary: "Delete a key of an application"},
+	"GET /project/{key}/application/{permApplicationName}/branches":                                     {Summary: "List the branches of an application", Response: []sdk.VCSBranch{}},
+	"GET /project/{key}/application/{permApplicationName}/version":                                      {Summary: "List the versions of a branch of an application", Response: []int{}},
+	"POST /project/{key}/application/{permApplicationName}/clone":                                       {Summary: "Clone an application", Request: sdk.Application{}, Response: sdk.Application{}},
+	"POST /project/{key}/application/{permApplicationName}/group":                                       {Summary: "Add a group in an application", Request: sdk.GroupPermission{}, Response: sdk.Application{}},
+	"PUT /project/{key}/application/{permApplicationName}/group":                                        {Summary: "Update the groups of an application", Request: []sdk.GroupPermission{}, Response: sdk.Application{}},
+	"PUT /project/{key}/application/{permApplicationName}/group/{group}":                                {Summary: "Update the role of a group on an application", Request: sdk.GroupPermission{}, Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}/group/{group}":                             {Summary: "Remove a group from an application", Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/history/branch":                               {Summary: "Get the history of the builds of an application by branch", Response: []sdk.PipelineBuild{}},
+	"GET /project/{key}/application/{permApplicationName}/history/env/deploy":                           {Summary: "Get the history of the deployments of an application by environment", Response: []sdk.PipelineBuild{}},
+	"POST /project/{key}/application/{permApplicationName}/notifications":                               {Summary: "Add notifications on an application", Request: []sdk.UserNotification{}, Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline":                                     {Summary: "List the pipelines of an application", Response: []sdk.Pipeline{}},
+	"PUT /project/{key}/application/{permApplicationName}/pipeline":                                     {Summary: "Update the pipelines of an application", Request: []sdk.ApplicationPipeline{}, Response: sdk.Application{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/attach":                             {Summary: "Attach pipelines to an application", Request: []string{}, Response: sdk.Application{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}":                  {Summary: "Attach a pipeline to an application", Response: sdk.Application{}},
+	"PUT /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}":                   {Summary: "Update the pipeline of an application", Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}":                {Summary: "Detach a pipeline from an application", Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/notification":      {Summary: "Get the notifications of a pipeline of an application", Response: sdk.UserNotification{}},
+	"PUT /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/notification":      {Summary: "Update the notifications of a pipeline of an application", Request: sdk.UserNotification{}, Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/notification":   {Summary: "Delete the notifications of a pipeline of an application", Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/scheduler":         {Summary: "List the schedulers of a pipeline of an application", Response: []sdk.PipelineScheduler{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/scheduler":        {Summary: "Add a scheduler on a pipeline of an application", Request: sdk.PipelineScheduler{}, Response: sdk.Application{}},
+	"PUT /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/scheduler":         {Summary: "Update a scheduler of a pipeline of an application", Request: sdk.PipelineScheduler{}, Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/scheduler/{id}": {Summary: "Delete a scheduler of a pipeline of an application", Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/tests/flaky":                                  {Summary: "List the flaky tests of an application", Response: []sdk.FlakyTest{}},
+	"GET /project/{key}/application/{permApplicationName}/tests/history":                                {Summary: "Get the history of a test of an application", Response: []sdk.TestCaseResult{}},
+	"GET /project/{key}/application/{permApplicationName}/tests/quarantine":                             {Summary: "List the quarantined tests of an application", Response: []sdk.TestQuarantine{}},
+	"POST /project/{key}/application/{permApplicationName}/tests/quarantine":                            {Summary: "Quarantine a test of an application", Request: sdk.TestQuarantine{}, Response: sdk.TestQuarantine{}},
+	"DELETE /project/{key}/application/{permApplicationName}/tests/quarantine":                          {Summary: "Release a test of an application from the quarantine"},
+	"GET /project/{key}/application/{permApplicationName}/tree":                                         {Summary: "Get the tree of the pipelines of an application", Response: []sdk.CDPipeline{}},
+	"GET /project/{key}/application/{permApplicationName}/tree/status": {
+		Summary: "Get the status of the tree of the pipelines of an application",
+		Response: struct {
+			Builds     []sdk.PipelineBuild     `json:"builds"`
+			Schedulers []sdk.PipelineScheduler `json:"schedulers"`
+			Pollers    []sdk.RepositoryPoller  `json:"pollers"`
+			Hooks      []sdk.Hook              `json:"hooks"`
+		}{},
+	},
+	"GET /project/{key}/application/{permApplicationName}/variable":                 {Summary: "List the variables of an application", Response: []sdk.Variable{}},
+	"PUT /project/{key}/application/{permApplicationName}/variable":                 {Summary: "Update the variables of an application", Request: []sdk.Variable{}},
+	"GET /project/{key}/application/{permApplicationName}/variable/audit":           {Summary: "List the audit of the variables of an application", Response: []sdk.VariableAudit{}},
+	"PUT /project/{key}/application/{permApplicationName}/variable/audit/{auditID}": {Summary: "Restore the variables of an application from an audit"},
+	"GET /project/{key}/application/{permApplicationName}/variable/{name}":          {Summary: "Get a variable of an application", Response: sdk.Variable{}},
+	"POST /project/{key}/application/{permApplicationName}/variable/{name}":         {Summary: "Add a variable in an application", Request: sdk.Variable{}, Response: sdk.Application{}},
+	"PUT /project/{key}/application/{permApplicationName}/variable/{name}":          {Summary: "Update a variable of an application", Request: sdk.Variable{}, Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}/variable/{name}":       {Summary: "Delete a variable of an application", Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/variable/{name}/audit":    {Summary: "Get the audit of a variable of an application", Response: []sdk.ApplicationVariableAudit{}},
+
+	// Pipeline
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/history":                                              {Summary: "Get the history of a pipeline of an application", Response: []sdk.PipelineBuild{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/log":                                    {Summary: "Get the logs of a pipeline build", Response: []sdk.Log{}},
+	"POST /project/{key}/application/{app}/pipeline/{permPipelineKey}/build/{build}/test":                                                  {Summary: "Add the tests results of a pipeline build", Response: []sdk.TestQuarantine{}},
+	"GET /project/{key}/application/{app}/pipeline/{permPipelineKey}/build/{build}/test":                                                   {Summary: "Get the tests results of a pipeline build", Response: venom.Tests{}},
+	"POST /project/{key}/application/{app}/pipeline/{permPipelineKey}/build/{build}/variable":                                              {Summary: "Add a variable to a pipeline build", Request: sdk.Variable{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/action/{actionID}/step/{stepOrder}/log": {Summary: "Get the logs of a step of a pipeline build job", Response: sdk.BuildState{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/action/{actionID}/log":                  {Summary: "Get the logs of a pipeline build job", Response: sdk.BuildState{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}":                                        {Summary: "Get the state of a pipeline build", Response: sdk.PipelineBuild{}},
+	"DELETE /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}":                                     {Summary: "Delete a pipeline build"},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/triggered":                              {Summary: "List the pipeline builds triggered by a pipeline build", Response: []sdk.PipelineBuild{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/stop":                                  {Summary: "Stop a pipeline build"},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/restart":                               {Summary: "Restart a pipeline build", Response: sdk.PipelineBuild{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/commits":                                {Summary: "List the commits of a pipeline build", Response: []sdk.VCSCommit{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/commits":                                              {Summary: "List the commits since the last build of a pipeline", Response: []sdk.VCSCommit{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/run":                                                 {Summary: "Run a pipeline", Request: sdk.RunRequest{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/runwithlastparent":                                   {Summary: "Run a pipeline with the parameters of the last parent build", Request: sdk.RunRequest{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/rollback":                                            {Summary: "Rollback a pipeline", Request: sdk.RunRequest{}, Response: sdk.PipelineBuild{}},
+	"GET /project/{permProjectKey}/pipeline":                                                                                               {Summary: "List the pipelines of a project", Response: []sdk.Pipeline{}},
+	"POST /project/{permProjectKey}/pipeline":                                                                                              {Summary: "Add a pipeline in a project", Request: sdk.Pipeline{}, Response: sdk.Pipeline{}},
+	"POST /project/{permProjectKey}/import/pipeline":                                                                                       {Summary: "Import a pipeline in a project", Response: []string{}},
+	"GET /project/{key}/pipeline/{permPipelineKey}/application":                                                                            {Summary: "List the applications using a pipeline", Response: []sdk.Application{}},
+	"POST /project/{key}/pipeline/{permPipelineKey}/group":                                                                                 {Summary: "Add a group in a pipeline", Request: sdk.GroupPermission{}, Response: sdk.Pipeline{}},
+	"PUT /project/{key}/pipeline/{permPipelineKey}/group":                                                                                  {Summary: "Update the groups of a pipeline", Request: []sdk.GroupPermission{}},
+	"PUT /project/{key}/pipeline/{permPipelineKey}/group/{group}":                                                                          {Summary: "Update the role of a group on a pipeline", Request: sdk.GroupPermission{}, Response: sdk.Pipeline{}},
+	"DELETE /project/{key}/pipeline/{permPipelineKey}/group/{group}":                                                                       {Summary: "Remove a group from a pipeline", Response: sdk.Pipeline{}},
+	"GET /project/{key}/pipeline/{permPipelineKey}/parameter":                                                                              {Summary: "List the parameters of a pipeline", Response: []sdk.Parameter{}},
+	"PUT /project/{key}/pipeline/{permPipelineKey}/parameter":                                                                              {Summary: "Update the parameters of a pipeline", Request: []sdk.Parameter{}, Response: []sdk.Parameter{}},
+	"POST /project/{key}/pipeline/{permPipelineKey}/parameter/{name}":                                                                      {Summary: "Add a parameter in a pipeline", Request: sdk.Parameter{}, Response: sdk.Pipeline{}},
+	"PUT /project/{key}/pipeline/{permPipelineKey}/parameter/{name}":                                                                       {Summary: "Update a parameter of a pipeline", Request: sdk.Parameter{}, Response: sdk.Pipeline{}},
+	"DELETE /project/{key}/pipeline/{permPipelineKey}/parameter/{name}":                                                                    {Summary: "Delete a parameter of a pipeline", Response: sdk.Pipeline{}},
+	"GET /project/{key}/pipeline/{permPipelineKey}":                                                                                        {Summary: "Get a pipeline", Response: sdk.Pipeline{}},
+	"PUT /project/{key}/pipeline/{permPipelineKey}":                                                                                        {Summary: "Update a pipeline", Request: sdk.Pipeline{}, Response: sdk.Pipeline{}},
+	"DELETE /project/{key}/pipeline/{permPipelineKey}":                                                                                     {Summary: "Delete a pipeline"},
+	"POST /project/{key}/pipeline/{permPipelineKey}/stage":                                                                                 {Summary: "Add a stage in a pipeline", Request: sdk.Stage{}, Response: sdk.Pipeline{}},
+	"POST /project/{key}/pipeline/{permPipelineKey}/stage/move":                                                                            {Summary: "Move a stage of a pipeline", Request: sdk.Stage{}, Response: sdk.Pipeline{}},
+	"GET /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}":                                                                        {Summary: "Get a stage of a pipeline", Response: sdk.Stage{}},
+	"PUT /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}":                                                                        {Summary: "Update a stage of a pipeline", Request: sdk.Stage{}, Response: sdk.Pipeline{}},
+	"DELETE /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}":                                                                     {Summary: "Delete a stage of a pipeline", Response: sdk.Pipeline{}},
+	"POST /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/job":                                                                   {Summary: "Add a job in a stage", Request: sdk.Job{}, Response: sdk.Pipeline{}},
+	"PUT /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/job/{jobID}":                                                            {Summary: "Update a job of a stage", Request: sdk.Job{}, Response: sdk.Pipeline{}},
+	"DELETE /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/job/{jobID}":                                                         {Summary: "Delete a job of a stage", Response: sdk.Pipeline{}},
+
+	// Workflows
+	"POST /project/{permProjectKey}/workflows":                  {Summary: "Add a workflow", Request: sdk.Workflow{}, Response: sdk.Workflow{}},
+	"GET /project/{permProjectKey}/workflows":                   {Summary: "List the workflows of a project", Response: []sdk.Workflow{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}":    {Summary: "Get a workflow", Response: sdk.Workflow{}},
+	"PUT /project/{permProjectKey}/workflows/{workflowName}":    {Summary: "Update a workflow", Request: sdk.Workflow{}, Response: sdk.Workflow{}},
+	"DELETE /project/{permProjectKey}/workflows/{workflowName}": {Summary: "Delete a workflow"},
+
+	// Workflows run
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs":                                                     {Summary: "List the runs of a workflow", Response: []sdk.WorkflowRun{}},
+	"POST /project/{permProjectKey}/workflows/{workflowName}/runs":                                                    {Summary: "Run a workflow", Request: postWorkflowRunHandlerOption{}, Response: sdk.WorkflowRun{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/latest":                                              {Summary: "Get the latest run of a workflow", Response: sdk.WorkflowRun{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/tags":                                                {Summary: "List the tags of the runs of a workflow", Response: map[string][]string{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}":                                            {Summary: "Get a run of a workflow", Response: sdk.WorkflowRun{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/artifacts":                                  {Summary: "List the artifacts of a run of a workflow", Response: []sdk.WorkflowNodeRunArtifact{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}":                                 {Summary: "Get a node run of a workflow run", Response: sdk.WorkflowNodeRun{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/job/{runJobId}/step/{stepOrder}": {Summary: "Get the logs of a step of a node run job", Response: sdk.BuildState{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/artifacts":                       {Summary: "List the artifacts of a node run", Response: []sdk.WorkflowNodeRunArtifact{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/provenance":                      {Summary: "Get the provenance of a node run", Response: sdk.ProvenanceAttestation{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/coverage":                        {Summary: "Get the coverage report of a node run", Response: sdk.WorkflowNodeRunCoverage{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/provenance/verify":               {Summary: "Verify the provenance of a node run", Response: sdk.ProvenanceVerification{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/approval":                        {Summary: "List the approvals of a node run", Response: []sdk.WorkflowNodeRunApproval{}},
+	"POST /project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/approval":                       {Summary: "Approve or reject a node run", Request: postWorkflowNodeRunApprovalHandlerOption{}, Response: []sdk.WorkflowNodeRunApproval{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/artifact/{artifactId}":                                    {Summary: "Download an artifact of a workflow"},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/coverage":                                                 {Summary: "Get the coverage trend of a workflow by branch", Response: []sdk.WorkflowNodeRunCoverage{}},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/node/{nodeID}/triggers/condition": {
+		Summary: "List the trigger conditions of a workflow node",
+		Response: struct {
+			Operators      map[string]string `json:"operators"`
+			ConditionNames []string          `json:"names"`
+		}{},
+	},
+	"GET /project/{permProjectKey}/workflows/{workflowName}/join/{joinID}/triggers/condition": {
+		Summary: "List the trigger conditions of a workflow join",
+		Response: struct {
+			Operators      map[string]string `json:"operators"`
+			ConditionNames []string          `json:"names"`
+		}{},
+	},
+
+	// DEPRECATED
+	"PUT /project/{key}/pipeline/{permPipelineKey}/action/{jobID}":                          {Summary: "Update a job of a pipeline", Request: sdk.Job{}},
+	"DELETE /project/{key}/pipeline/{permPipelineKey}/action/{jobID}":                       {Summary: "Delete a job of a pipeline", Response: sdk.Pipeline{}},
+	"POST /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/joined":                 {Summary: "Add a job in a stage", Request: sdk.Job{}, Response: sdk.Job{}},
+	"GET /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/joined/{actionID}":       {Summary: "Get a job of a stage", Response: sdk.Action{}},
+	"PUT /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/joined/{actionID}":       {Summary: "Update a job of a stage", Request: sdk.Action{}, Response: sdk.Action{}},
+	"DELETE /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/joined/{actionID}":    {Summary: "Delete a job of a stage"},
+	"GET /project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/joined/{actionID}/audit": {Summary: "Get the audit of a job of a stage", Response: []sdk.ActionAudit{}},
+
+	// Triggers
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/trigger":         {Summary: "List the triggers of a pipeline", Response: []sdk.PipelineTrigger{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/trigger":        {Summary: "Add a trigger on a pipeline", Request: sdk.PipelineTrigger{}, Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/trigger/source":  {Summary: "List the triggers having a pipeline as source", Response: []sdk.PipelineTrigger{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/trigger/{id}":    {Summary: "Get a trigger", Response: sdk.PipelineTrigger{}},
+	"DELETE /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/trigger/{id}": {Summary: "Delete a trigger", Response: sdk.Application{}},
+	"PUT /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/trigger/{id}":    {Summary: "Update a trigger", Request: sdk.PipelineTrigger{}, Response: sdk.Application{}},
+
+	// Environment
+	"GET /project/{permProjectKey}/environment":                                  {Summary: "List the environments of a project", Response: []sdk.Environment{}},
+	"POST /project/{permProjectKey}/environment":                                 {Summary: "Add an environment in a project", Request: sdk.Environment{}, Response: sdk.Project{}},
+	"PUT /project/{permProjectKey}/environment":                                  {Summary: "Update the environments of a project", Request: []sdk.Environment{}, Response: sdk.Project{}},
+	"POST /project/{permProjectKey}/environment/import":                          {Summary: "Import an environment in a project", Response: []string{}},
+	"POST /project/{permProjectKey}/environment/import/{permEnvironmentName}":    {Summary: "Import variables in an environment", Response: []string{}},
+	"GET /project/{key}/environment/{permEnvironmentName}":                       {Summary: "Get an environment", Response: sdk.Environment{}},
+	"PUT /project/{key}/environment/{permEnvironmentName}":                       {Summary: "Update an environment", Request: sdk.Environment{}, Response: sdk.Project{}},
+	"DELETE /project/{key}/environment/{permEnvironmentName}":                    {Summary: "Delete an environment", Response: sdk.Project{}},
+	"GET /project/{key}/environment/{permEnvironmentName}/keys":                  {Summary: "List the keys of an environment", Response: []sdk.EnvironmentKey{}},
+	"POST /project/{key}/environment/{permEnvironmentName}/keys":                 {Summary: "Add a key in an environment", Request: sdk.EnvironmentKey{}, Response: sdk.EnvironmentKey{}},
+	"DELETE /project/{key}/environment/{permEnvironmentName}/keys/{name}":        {Summary: "Delete a key of an environment"},
+	"POST /project/{key}/environment/{permEnvironmentName}/clone/{cloneName}":    {Summary: "Clone an environment", Response: sdk.Project{}},
+	"GET /project/{key}/environment/{permEnvironmentName}/audit":                 {Summary: "List the audit of an environment", Response: []sdk.VariableAudit{}},
+	"PUT /project/{key}/environment/{permEnvironmentName}/audit/{auditID}":       {Summary: "Restore an environment from an audit", Response: sdk.Project{}},
+	"POST /project/{key}/environment/{permEnvironmentName}/group":                {Summary: "Add a group in an environment", Request: sdk.GroupPermission{}},
+	"POST /project/{key}/environment/{permEnvironmentName}/groups":               {Summary: "Add groups in an environment", Request: []sdk.GroupPermission{}, Response: sdk.Environment{}},
+	"PUT /project/{key}/environment/{permEnvironmentName}/group/{group}":         {Summary: "Update the role of a group on an environment", Request: sdk.GroupPermission{}, Response: sdk.Environment{}},
+	"DELETE /project/{key}/environment/{permEnvironmentName}/group/{group}":      {Summary: "Remove a group from an environment"},
+	"GET /project/{key}/environment/{permEnvironmentName}/variable":              {Summary: "List the variables of an environment", Response: []sdk.Variable{}},
+	"GET /project/{key}/environment/{permEnvironmentName}/variable/{name}":       {Summary: "Get a variable of an environment", Response: sdk.Variable{}},
+	"POST /project/{key}/environment/{permEnvironmentName}/variable/{name}":      {Summary: "Add a variable in an environment", Request: sdk.Variable{}, Response: sdk.Project{}},
+	"PUT /project/{key}/environment/{permEnvironmentName}/variable/{name}":       {Summary: "Update a variable of an environment", Request: sdk.Variable{}, Response: sdk.Project{}},
+	"DELETE /project/{key}/environment/{permEnvironmentName}/variable/{name}":    {Summary: "Delete a variable of an environment", Response: sdk.Project{}},
+	"GET /project/{key}/environment/{permEnvironmentName}/variable/{name}/audit": {Summary: "Get the audit of a variable of an environment", Response: []sdk.EnvironmentVariableAudit{}},
+
+	// Artifacts
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/artifact/{tag}":                {Summary: "List the artifacts of a tag", Response: []sdk.Artifact{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/{buildNumber}/artifact":        {Summary: "List the artifacts of a pipeline build", Response: []sdk.Artifact{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/{buildNumber}/artifact/{tag}": {Summary: "Upload an artifact"},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/artifact/download/{id}":        {Summary: "Download an artifact"},
+	"GET /artifact/{hash}": {Summary: "Download an artifact with its hash"},
+
+	// Hooks
+	"GET /project/{key}/application/{permApplicationName}/hook":                                    {Summary: "List the hooks of an application", Response: []sdk.Hook{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/hook":        {Summary: "Add a hook on a pipeline", Request: sdk.Hook{}, Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/hook":         {Summary: "List the hooks of a pipeline", Response: []sdk.Hook{}},
+	"PUT /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/hook/{id}":    {Summary: "Update a hook", Request: sdk.Hook{}, Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/hook/{id}": {Summary: "Delete a hook"},
+
+	// Pollers
+	"GET /project/{key}/application/{permApplicationName}/polling":                               {Summary: "List the pollers of an application", Response: []sdk.RepositoryPoller{}},
+	"POST /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/polling":   {Summary: "Add a poller on a pipeline", Request: sdk.RepositoryPoller{}, Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/polling":    {Summary: "List the pollers of a pipeline", Response: sdk.RepositoryPoller{}},
+	"PUT /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/polling":    {Summary: "Update the poller of a pipeline", Request: sdk.RepositoryPoller{}, Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/polling": {Summary: "Delete the poller of a pipeline", Response: sdk.Application{}},
+
+	// Build queue
+	"GET /queue":                   {Summary: "List the pipeline build jobs waiting in the queue", Response: []sdk.PipelineBuildJob{}},
+	"POST /queue/{id}/take":        {Summary: "Take a pipeline build job", Request: worker.TakeForm{}, Response: worker.PipelineBuildJobInfo{}},
+	"POST /queue/{id}/book":        {Summary: "Book a pipeline build job"},
+	"POST /queue/{id}/spawn/infos": {Summary: "Add spawn information to a pipeline build job", Request: []sdk.SpawnInfo{}},
+	"POST /queue/{id}/result":      {Summary: "Send the result of a pipeline build job", Request: sdk.Result{}},
+	"GET /queue/{id}/infos":        {Summary: "Get a pipeline build job", Response: sdk.PipelineBuildJob{}},
+	"POST /build/{id}/log":         {Summary: "Send logs of a pipeline build job", Request: sdk.Log{}},
+	"POST /build/{id}/step":        {Summary: "Update the status of a step of a pipeline build job", Request: sdk.StepStatus{}},
+
+	//Workflow queue
+	"GET /queue/workflows":                          {Summary: "List the workflow jobs waiting in the queue", Response: []sdk.WorkflowNodeJobRun{}},
+	"POST /queue/workflows/requirements/errors":     {Summary: "Report a requirements error on workflow jobs"},
+	"POST /queue/workflows/{id}/take":               {Summary: "Take a workflow job", Request: worker.TakeForm{}, Response: worker.WorkflowNodeJobRunInfo{}},
+	"POST /queue/workflows/{id}/book":               {Summary: "Book a workflow job"},
+	"GET /queue/workflows/{id}/infos":               {Summary: "Get a workflow job", Response: sdk.WorkflowNodeJobRun{}},
+	"POST /queue/workflows/{id}/spawn/infos":        {Summary: "Add spawn information to a workflow job", Request: []sdk.SpawnInfo{}},
+	"POST /queue/workflows/{permID}/result":         {Summary: "Send the result of a workflow job", Request: sdk.Result{}},
+	"POST /queue/workflows/{permID}/log":            {Summary: "Send logs of a workflow job", Request: sdk.Log{}},
+	"POST /queue/workflows/{permID}/test":           {Summary: "Send the tests results of a workflow job", Response: []sdk.TestQuarantine{}},
+	"POST /queue/workflows/{permID}/coverage":       {Summary: "Send the coverage report of a workflow job", Request: sdk.CoverageReport{}, Response: sdk.WorkflowNodeRunCoverage{}},
+	"POST /queue/workflows/{permID}/variable":       {Summary: "Add a variable to a workflow job", Request: sdk.Variable{}},
+	"POST /queue/workflows/{permID}/step":           {Summary: "Update the status of a step of a workflow job", Request: sdk.StepStatus{}},
+	"POST /queue/workflows/{permID}/artifact/{tag}": {Summary: "Upload an artifact of a workflow job"},
+	"GET /variable/type":                            {Summary: "List the variable types", Response: []string{}},
+	"GET /role":                                     {Summary: "List the roles", Response: []sdk.Role{}},
+	"GET /parameter/type":                           {Summary: "List the parameter types", Response: []string{}},
+	"GET /pipeline/type":                            {Summary: "List the pipeline types", Response: []string{}},
+	"GET /notification/type":                        {Summary: "List the notification types", Response: []sdk.UserNotificationSettingsType{}},
+	"GET /notification/state":                       {Summary: "List the notification states", Response: []sdk.UserNotificationEventType{}},
+
+	// RepositoriesManager
+	"GET /repositories_manager":                 {Summary: "List the repositories managers", Response: []sdk.RepositoriesManager{}},
+	"POST /repositories_manager/add":            {Summary: "Add a repositories manager", Request: map[string]string{}, Response: sdk.RepositoriesManager{}},
+	"GET /repositories_manager/oauth2/callback": {Summary: "OAuth2 callback of a repositories manager"},
+
+	// RepositoriesManager for projects
+	"GET /project/{permProjectKey}/repositories_manager":                            {Summary: "List the repositories managers of a project", Response: []sdk.RepositoriesManager{}},
+	"POST /project/{permProjectKey}/repositories_manager/{name}/authorize":          {Summary: "Authorize a project on a repositories manager", Response: map[string]string{}},
+	"POST /project/{permProjectKey}/repositories_manager/{name}/authorize/callback": {Summary: "Callback of the authorization of a project on a repositories manager", Request: map[string]interface{}{}, Response: sdk.Project{}},
+	"DELETE /project/{permProjectKey}/repositories_manager/{name}":                  {Summary: "Delete a repositories manager of a project", Response: sdk.Project{}},
+	"GET /project/{permProjectKey}/repositories_manager/{name}/repo":                {Summary: "Get a repository of a repositories manager", Response: sdk.VCSRepo{}},
+	"GET /project/{permProjectKey}/repositories_manager/{name}/repos":               {Summary: "List the repositories of a repositories manager", Response: []sdk.VCSRepo{}},
+
+	// RepositoriesManager for applications
+	"POST /project/{permProjectKey}/repositories_manager/{name}/application":                     {Summary: "Add an application from a repository", Request: map[string]string{}},
+	"POST /project/{key}/repositories_manager/{name}/application/{permApplicationName}/attach":   {Summary: "Attach an application to a repository", Response: sdk.Application{}},
+	"POST /project/{key}/repositories_manager/{name}/application/{permApplicationName}/detach":   {Summary: "Detach an application from its repository", Response: sdk.Application{}},
+	"GET /project/{key}/application/{permApplicationName}/repositories_manager":                  {Summary: "List the repositories managers of the applications of a project"},
+	"POST /project/{key}/application/{permApplicationName}/repositories_manager/{name}/hook":     {Summary: "Add a hook on the repository of an application", Request: map[string]string{}, Response: sdk.Application{}},
+	"DELETE /project/{key}/application/{permApplicationName}/repositories_manager/hook/{hookId}": {Summary: "Delete a hook on the repository of an application", Response: sdk.Application{}},
+
+	// Suggest
+	"GET /suggest/variable/{permProjectKey}": {Summary: "Suggest the variables available in a project", Response: []string{}},
+
+	// Templates
+	"GET /template":                           {Summary: "List the templates", Response: []sdk.TemplateExtension{}},
+	"POST /template/add":                      {Summary: "Add a template", Response: sdk.TemplateExtension{}},
+	"GET /template/build":                     {Summary: "List the build templates", Response: []sdk.Template{}},
+	"GET /template/deploy":                    {Summary: "List the deployment templates", Response: []sdk.Template{}},
+	"PUT /template/{id}":                      {Summary: "Update a template", Response: sdk.TemplateExtension{}},
+	"DELETE /template/{id}":                   {Summary: "Delete a template"},
+	"POST /project/{permProjectKey}/template": {Summary: "Create an application from a template", Request: sdk.ApplyTemplatesOptions{}, Response: sdk.Project{}},
+	"POST /project/{key}/application/{permApplicationName}/template": {Summary: "Apply a template on an application", Request: sdk.ApplyTemplatesOptions{}, Response: []string{}},
+
+	// UI
+	"GET /config/user": {Summary: "Get the user configuration of the API", Response: map[string]string{}},
+
+	// Users
+	"GET /user":                                {Summary: "List the users", Response: []*sdk.User{}},
+	"POST /user/signup":                        {Summary: "Sign up a user", Request: sdk.UserAPIRequest{}, Response: sdk.User{}},
+	"POST /user/import":                        {Summary: "Import users", Request: []sdk.User{}, Response: map[string]string{}},
+	"GET /user/{username}":                     {Summary: "Get a user", Response: sdk.User{}},
+	"PUT /user/{username}":                     {Summary: "Update a user", Request: sdk.User{}, Response: sdk.User{}},
+	"DELETE /user/{username}":                  {Summary: "Delete a user"},
+	"GET /user/{username}/groups":              {Summary: "List the groups of a user", Response: map[string][]sdk.Group{}},
+	"GET /user/{username}/accesstoken":         {Summary: "List the access tokens of a user", Response: []sdk.AccessToken{}},
+	"POST /user/{username}/accesstoken":        {Summary: "Add an access token to a user", Request: sdk.AccessToken{}, Response: sdk.AccessToken{}},
+	"DELETE /user/{username}/accesstoken/{id}": {Summary: "Delete an access token of a user"},
+	"GET /user/{username}/confirm/{token}":     {Summary: "Confirm the account of a user", Response: sdk.UserAPIResponse{}},
+	"POST /user/{username}/reset":              {Summary: "Reset the password of a user", Request: sdk.UserAPIRequest{}, Response: sdk.User{}},
+	"GET /auth/mode":                           {Summary: "Get the authentication mode", Response: map[string]string{}},
+
+	// Workers
+	"GET /worker":               {Summary: "List the workers", Response: []sdk.Worker{}},
+	"POST /worker":              {Summary: "Register a worker", Request: worker.RegistrationForm{}, Response: sdk.Worker{}},
+	"POST /worker/refresh":      {Summary: "Refresh a worker"},
+	"POST /worker/checking":     {Summary: "Set a worker as checking its requirements"},
+	"POST /worker/waiting":      {Summary: "Set a worker as waiting"},
+	"POST /worker/unregister":   {Summary: "Unregister a worker"},
+	"POST /worker/{id}/disable": {Summary: "Disable a worker"},
+
+	// Worker models
+	"POST /worker/model":                    {Summary: "Add a worker model", Request: sdk.Model{}, Response: sdk.Model{}},
+	"GET /worker/model":                     {Summary: "List the worker models", Response: []sdk.Model{}},
+	"PUT /worker/model/error/{permModelID}": {Summary: "Report a spawn error on a worker model", Request: sdk.SpawnErrorForm{}},
+	"GET /worker/model/enabled":             {Summary: "List the enabled worker models", Response: []sdk.Model{}},
+	"GET /worker/model/type":                {Summary: "List the worker model types", Response: []string{}},
+	"GET /worker/model/communication":       {Summary: "List the worker model communications", Response: []string{}},
+	"PUT /worker/model/{permModelID}":       {Summary: "Update a worker model", Request: sdk.Model{}, Response: sdk.Model{}},
+	"DELETE /worker/model/{permModelID}":    {Summary: "Delete a worker model"},
+	"GET /worker/model/capability/type":     {Summary: "List the worker model capability types", Response: []string{}},
+
+	// Workflows
+	"GET /workflow/hook":          {Summary: "List the workflow hook models", Response: []sdk.WorkflowHookModel{}},
+	"GET /workflow/hook/{model}":  {Summary: "Get a workflow hook model", Response: sdk.WorkflowHookModel{}},
+	"POST /workflow/hook/{model}": {Summary: "Add a workflow hook model", Request: sdk.WorkflowHookModel{}, Response: sdk.WorkflowHookModel{}},
+	"PUT /workflow/hook/{model}":  {Summary: "Update a workflow hook model", Request: sdk.WorkflowHookModel{}, Response: sdk.WorkflowHookModel{}},
+
+	// SSE
+	"GET /mon/lastupdates/events": {Summary: "Stream the last updates of the user (server-sent events)"},
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/database"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/metrics"
+)
+
+// apiRequestDuration is the latency of the API requests by route
+var apiRequestDuration = metrics.NewHistogramVec("cds_api_request_duration_seconds", "Latency of the API requests by method and route", metrics.DefBuckets, "method", "route")
+
+// initMetrics registers the metrics computed each time the metrics are collected: queue, database pool and cache
+func initMetrics(DBFunc func() *gorp.DbMap) {
+	//The queue stats are loaded once per collection, they are shared by the queue gauges
+	var queueStats []workflow.NodeJobRunQueueStats
+	metrics.DefaultRegistry.OnCollect(func() {
+		queueStats = nil
+		db := DBFunc()
+		if db == nil {
+			return
+		}
+		stats, err := workflow.LoadNodeJobRunQueueStats(db)
+		if err != nil {
+			log.Warning("initMetrics> %s", err)
+		}
+		queueStats = stats
+	})
+
+	metrics.NewGaugeVecFunc("cds_api_queue_workflow_jobs", "Number of jobs in the workflow queue by status", "status", func() map[string]float64 {
+		res := map[string]float64{sdk.StatusWaiting.String(): 0, sdk.StatusBuilding.String(): 0}
+		for _, s := range queueStats {
+			res[s.Status] = float64(s.Count)
+		}
+		return res
+	})
+
+	metrics.NewGaugeVecFunc("cds_api_queue_workflow_oldest_job_age_seconds", "Age of the oldest job in the workflow queue by status", "status", func() map[string]float64 {
+		res := map[string]float64{sdk.StatusWaiting.String(): 0, sdk.StatusBuilding.String(): 0}
+		for _, s := range queueStats {
+			res[s.Status] = s.OldestAge
+		}
+		return res
+	})
+
+	metrics.NewGaugeVecFunc("cds_api_database_connections", "Number of connections of the database pool by state", "state", func() map[string]float64 {
+		db := database.DB()
+		if db == nil {
+			return nil
+		}
+		s := db.Stats()
+		return map[string]float64{
+			"open":   float64(s.OpenConnections),
+			"in_use": float64(s.InUse),
+			"idle":   float64(s.Idle),
+		}
+	})
+
+	metrics.NewCounterFunc("cds_api_database_wait_total", "Total number of connections waited for in the database pool", func() float64 {
+		if db := database.DB(); db != nil {
+			return float64(db.Stats().WaitCount)
+		}
+		return 0
+	})
+
+	metrics.NewGaugeFunc("cds_api_database_wait_duration_seconds", "Time spent waiting for connections in the database pool", func() float64 {
+		if db := database.DB(); db != nil {
+			return db.Stats().WaitDuration.Seconds()
+		}
+		return 0
+	})
+
+	metrics.NewGaugeFunc("cds_api_cache_up", "1 if the cache is available", func() float64 {
+		if strings.HasSuffix(cache.Status, "OK") {
+			return 1
+		}
+		return 0
+	})
+
+	metrics.NewGaugeFunc("cds_api_uptime_seconds", "Uptime of the API", func() float64 {
+		return time.Since(startupTime).Seconds()
+	})
+}
+
+func getMetricsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	metrics.DefaultRegistry.ServeHTTP(w, r)
+	return nil
+}
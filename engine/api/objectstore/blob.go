@@ -0,0 +1,145 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Blob is an object stored once for all the artifacts with the same SHA-256 digest
+type Blob struct {
+	SHA256sum string
+}
+
+//GetName returns the name the blob: its digest
+func (b *Blob) GetName() string {
+	return b.SHA256sum
+}
+
+//GetPath returns the path of the blob, all blobs are stored in the same place
+func (b *Blob) GetPath() string {
+	return "blobs"
+}
+
+//SHA256sum computes the SHA-256 digest of data and rewinds it
+func SHA256sum(data io.ReadSeeker) (string, int64, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, data)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := data.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+//StoreBlob stores data with the default objectstore driver, only if no blob with the same SHA-256 digest is already stored.
+//The reference count of the blob is incremented in a single statement which locks the row of the blob until the end of
+//the transaction, it returns the digest and the object path of the blob
+func StoreBlob(db gorp.SqlExecutor, data io.ReadSeeker) (string, string, error) {
+	digest, size, err := SHA256sum(data)
+	if err != nil {
+		return "", "", sdk.WrapError(err, "StoreBlob> Unable to compute digest")
+	}
+
+	var objectPath string
+	var refcount int
+	query := `INSERT INTO object_store_blob (sha256sum, object_path, size, refcount) VALUES ($1, '', $2, 1)
+		ON CONFLICT (sha256sum) DO UPDATE SET refcount = object_store_blob.refcount + 1
+		RETURNING object_path, refcount`
+	if err := db.QueryRow(query, digest, size).Scan(&objectPath, &refcount); err != nil {
+		return "", "", sdk.WrapError(err, "StoreBlob> Unable to reference blob %s", digest)
+	}
+
+	//A blob referenced again after it has been released may have been deleted from the store: it is stored again
+	if refcount > 1 && objectPath != "" {
+		log.Debug("StoreBlob> Blob %s is already stored at %s", digest, objectPath)
+		return digest, objectPath, nil
+	}
+
+	objectPath, err = StoreArtifact(&Blob{SHA256sum: digest}, ioutil.NopCloser(data))
+	if err != nil {
+		return "", "", sdk.WrapError(err, "StoreBlob> Unable to store blob %s", digest)
+	}
+	if _, err := db.Exec("UPDATE object_store_blob SET object_path = $2 WHERE sha256sum = $1", digest, objectPath); err != nil {
+		return "", "", sdk.WrapError(err, "StoreBlob> Unable to update blob %s", digest)
+	}
+
+	return digest, objectPath, nil
+}
+
+//ReleaseBlob decrements the reference count of a blob. The blobs which are no more referenced are deleted by PurgeBlobs,
+//once the transaction releasing them is committed
+func ReleaseBlob(db gorp.SqlExecutor, digest string) error {
+	if _, err := db.Exec("UPDATE object_store_blob SET refcount = refcount - 1 WHERE sha256sum = $1 AND refcount > 0", digest); err != nil {
+		return sdk.WrapError(err, "ReleaseBlob> Unable to release blob %s", digest)
+	}
+	return nil
+}
+
+//PurgeBlobs deletes the blobs which are no more referenced. The row of each blob is locked while the blob is deleted from
+//the store, so that a blob referenced again at the same time is stored again once the row is deleted
+func PurgeBlobs(db *gorp.DbMap) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, sdk.WrapError(err, "PurgeBlobs> Unable to start transaction")
+	}
+	defer tx.Rollback()
+
+	digests := []string{}
+	if _, err := tx.Select(&digests, "SELECT sha256sum FROM object_store_blob WHERE refcount <= 0 LIMIT 100 FOR UPDATE SKIP LOCKED"); err != nil {
+		return 0, sdk.WrapError(err, "PurgeBlobs> Unable to load unreferenced blobs")
+	}
+
+	for _, digest := range digests {
+		if err := DeleteArtifact(&Blob{SHA256sum: digest}); err != nil && !strings.Contains(err.Error(), "404") {
+			return 0, sdk.WrapError(err, "PurgeBlobs> Unable to delete blob %s in store", digest)
+		}
+		if _, err := tx.Exec("DELETE FROM object_store_blob WHERE sha256sum = $1", digest); err != nil {
+			return 0, sdk.WrapError(err, "PurgeBlobs> Unable to delete blob %s", digest)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, sdk.WrapError(err, "PurgeBlobs> Unable to commit transaction")
+	}
+	return len(digests), nil
+}
+
+//BlobPurger deletes the unreferenced blobs every minute
+func BlobPurger(c context.Context, DBFunc func() *gorp.DbMap) {
+	tick := time.NewTicker(1 * time.Minute)
+	defer tick.Stop()
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting objectstore.BlobPurger: %v", c.Err())
+			}
+			return
+		case <-tick.C:
+			db := DBFunc()
+			if db == nil {
+				continue
+			}
+			n, err := PurgeBlobs(db)
+			if err != nil {
+				log.Warning("objectstore.BlobPurger> %s", err)
+				continue
+			}
+			if n > 0 {
+				log.Debug("objectstore.BlobPurger> %d blobs deleted", n)
+			}
+		}
+	}
+}
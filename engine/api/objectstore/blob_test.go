@@ -0,0 +1,22 @@
+package objectstore
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSHA256sum(t *testing.T) {
+	data := strings.NewReader("hello world")
+	sum, size, err := SHA256sum(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", sum)
+	assert.Equal(t, int64(11), size)
+
+	//data must be rewinded to be stored
+	b, err := ioutil.ReadAll(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+}
@@ -13,6 +13,10 @@ func getVariableTypeHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 	return WriteJSON(w, r, sdk.AvailableVariableType, http.StatusOK)
 }
 
+func getRolesHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	return WriteJSON(w, r, sdk.Roles, http.StatusOK)
+}
+
 func getParameterTypeHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	return WriteJSON(w, r, sdk.AvailableParameterType, http.StatusOK)
 }
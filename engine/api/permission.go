@@ -9,6 +9,7 @@ import (
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
@@ -16,7 +17,7 @@ import (
 )
 
 // PermCheckFunc defines func call to check permission
-type PermCheckFunc func(key string, c *businesscontext.Ctx, permission int, routeVar map[string]string) bool
+type PermCheckFunc func(key string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool
 
 var permissionMapFunction = initPermissionFunc()
 
@@ -33,22 +34,81 @@ func initPermissionFunc() map[string]PermCheckFunc {
 	}
 }
 
-func getPermissionByMethod(method string, isExecution bool) int {
+func getCapabilityByMethod(method string, isExecution bool) sdk.Capability {
 	switch method {
 	case "POST":
 		if isExecution {
-			return permission.PermissionReadExecute
+			return sdk.CapabilityExecute
 		}
-		return permission.PermissionReadWriteExecute
+		return sdk.CapabilityWrite
 	case "PUT":
-		return permission.PermissionReadWriteExecute
+		return sdk.CapabilityWrite
 	case "DELETE":
-		return permission.PermissionReadWriteExecute
+		return sdk.CapabilityWrite
 	default:
-		return permission.PermissionRead
+		return sdk.CapabilityRead
 	}
 }
 
+// getCapability returns the capability needed by a route: the one set in the handler config or the one of the method
+func getCapability(rc *HandlerConfig, method string) sdk.Capability {
+	if rc.capability != "" {
+		return rc.capability
+	}
+	return getCapabilityByMethod(method, rc.isExecution)
+}
+
+// checkAccessTokenScope checks the scopes of the access token used to call a route
+func checkAccessTokenScope(t *sdk.AccessToken, rc *HandlerConfig, method string) bool {
+	if t.HasScope(sdk.AccessTokenScopeAdmin) {
+		return true
+	}
+	if rc.needAdmin {
+		return false
+	}
+	if rc.accessTokenScope != "" {
+		return t.HasScope(rc.accessTokenScope)
+	}
+	switch getCapability(rc, method) {
+	case sdk.CapabilityRead:
+		return t.HasScope(sdk.AccessTokenScopeRead) || t.HasScope(sdk.AccessTokenScopeRun)
+	case sdk.CapabilityExecute:
+		return t.HasScope(sdk.AccessTokenScopeRun)
+	}
+	return false
+}
+
+// checkGroupRole checks the role given to a group and sets the matching numeric permission
+func checkGroupRole(gp *sdk.GroupPermission) error {
+	if gp.Role == "" {
+		return nil
+	}
+	if _, ok := sdk.RoleByName(gp.Role); !ok {
+		return sdk.WrapError(sdk.ErrWrongRequest, "checkGroupRole> Unknown role %s", gp.Role)
+	}
+	gp.Permission = permission.RolePermission(gp.Role)
+	return nil
+}
+
+// checkUsernameOrAdmin checks that the user of the context is an admin or the user named in the route. The users are
+// compared by id, and the access tokens of a group never act on behalf of a user
+func checkUsernameOrAdmin(db gorp.SqlExecutor, c *businesscontext.Ctx, username string) bool {
+	if c.AccessToken != nil && c.AccessToken.GroupID != 0 {
+		return false
+	}
+	if c.User.Admin {
+		return true
+	}
+	if c.User.ID == 0 {
+		return false
+	}
+	u, err := user.LoadUserWithoutAuth(db, username)
+	if err != nil {
+		return false
+	}
+	return u.ID == c.User.ID
+}
+
 func checkWorkerPermission(db gorp.SqlExecutor, rc *HandlerConfig, routeVar map[string]string, c *businesscontext.Ctx) bool {
 	if c.Worker == nil {
 		return false
@@ -77,7 +137,7 @@ func checkWorkerPermission(db gorp.SqlExecutor, rc *HandlerConfig, routeVar map[
 	return true
 }
 
-func checkPermission(routeVar map[string]string, c *businesscontext.Ctx, permission int) bool {
+func checkPermission(routeVar map[string]string, c *businesscontext.Ctx, capability sdk.Capability) bool {
 	for _, g := range c.User.Groups {
 		if group.SharedInfraGroup != nil && g.Name == group.SharedInfraGroup.Name {
 			return true
@@ -88,7 +148,7 @@ func checkPermission(routeVar map[string]string, c *businesscontext.Ctx, permiss
 	for key, value := range routeVar {
 		if permFunc, ok := permissionMapFunction[key]; ok {
 			log.Debug("Check permission for %s", key)
-			permissionOk = permFunc(value, c, permission, routeVar)
+			permissionOk = permFunc(value, c, capability, routeVar)
 			if !permissionOk {
 				return permissionOk
 			}
@@ -97,11 +157,11 @@ func checkPermission(routeVar map[string]string, c *businesscontext.Ctx, permiss
 	return permissionOk
 }
 
-func checkProjectPermissions(projectKey string, c *businesscontext.Ctx, permission int, routeVar map[string]string) bool {
+func checkProjectPermissions(projectKey string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool {
 	if c.User.Groups != nil {
 		for _, g := range c.User.Groups {
 			for _, p := range g.ProjectGroups {
-				if projectKey == p.Project.Key && p.Permission >= permission {
+				if projectKey == p.Project.Key && permission.Can(p.Role, p.Permission, capability) {
 					return true
 				}
 			}
@@ -111,12 +171,12 @@ func checkProjectPermissions(projectKey string, c *businesscontext.Ctx, permissi
 	return false
 }
 
-func checkPipelinePermissions(pipelineName string, c *businesscontext.Ctx, permission int, routeVar map[string]string) bool {
+func checkPipelinePermissions(pipelineName string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool {
 	// Check if param key exist
 	if projectKey, ok := routeVar["key"]; ok {
 		for _, g := range c.User.Groups {
 			for _, p := range g.PipelineGroups {
-				if pipelineName == p.Pipeline.Name && p.Permission >= permission && projectKey == p.Pipeline.ProjectKey {
+				if pipelineName == p.Pipeline.Name && permission.Can(p.Role, p.Permission, capability) && projectKey == p.Pipeline.ProjectKey {
 					return true
 				}
 			}
@@ -128,13 +188,13 @@ func checkPipelinePermissions(pipelineName string, c *businesscontext.Ctx, permi
 	return false
 }
 
-func checkEnvironmentPermissions(envName string, c *businesscontext.Ctx, permission int, routeVar map[string]string) bool {
+func checkEnvironmentPermissions(envName string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool {
 	// Check if param key exist
 	if projectKey, ok := routeVar["key"]; ok {
 		if c.User.Groups != nil {
 			for _, g := range c.User.Groups {
 				for _, p := range g.EnvironmentGroups {
-					if envName == p.Environment.Name && p.Permission >= permission && projectKey == p.Environment.ProjectKey {
+					if envName == p.Environment.Name && permission.Can(p.Role, p.Permission, capability) && projectKey == p.Environment.ProjectKey {
 						return true
 					}
 				}
@@ -147,13 +207,13 @@ func checkEnvironmentPermissions(envName string, c *businesscontext.Ctx, permiss
 	return false
 }
 
-func checkApplicationPermissions(applicationName string, c *businesscontext.Ctx, permission int, routeVar map[string]string) bool {
+func checkApplicationPermissions(applicationName string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool {
 	// Check if param key exist
 	if projectKey, ok := routeVar["key"]; ok {
 		if c.User.Groups != nil {
 			for _, g := range c.User.Groups {
 				for _, a := range g.ApplicationGroups {
-					if applicationName == a.Application.Name && a.Permission >= permission && projectKey == a.Application.ProjectKey {
+					if applicationName == a.Application.Name && permission.Can(a.Role, a.Permission, capability) && projectKey == a.Application.ProjectKey {
 						return true
 					}
 				}
@@ -166,7 +226,7 @@ func checkApplicationPermissions(applicationName string, c *businesscontext.Ctx,
 	return false
 }
 
-func checkApplicationIDPermissions(appIDS string, c *businesscontext.Ctx, permission int, routeVar map[string]string) bool {
+func checkApplicationIDPermissions(appIDS string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool {
 	appID, err := strconv.ParseInt(appIDS, 10, 64)
 	if err != nil {
 		log.Warning("checkApplicationIDPermissions> appID (%s) is not an integer: %s", appIDS, err)
@@ -176,7 +236,7 @@ func checkApplicationIDPermissions(appIDS string, c *businesscontext.Ctx, permis
 	if c.User.Groups != nil {
 		for _, g := range c.User.Groups {
 			for _, a := range g.ApplicationGroups {
-				if appID == a.Application.ID && a.Permission >= permission {
+				if appID == a.Application.ID && permission.Can(a.Role, a.Permission, capability) {
 					return true
 				}
 			}
@@ -187,11 +247,11 @@ func checkApplicationIDPermissions(appIDS string, c *businesscontext.Ctx, permis
 	return false
 }
 
-func checkGroupPermissions(groupName string, c *businesscontext.Ctx, permissionValue int, routeVar map[string]string) bool {
+func checkGroupPermissions(groupName string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool {
 	for _, g := range c.User.Groups {
 		if g.Name == groupName {
 
-			if permissionValue == permission.PermissionRead {
+			if capability == sdk.CapabilityRead {
 				return true
 			}
 
@@ -206,19 +266,19 @@ func checkGroupPermissions(groupName string, c *businesscontext.Ctx, permissionV
 	return false
 }
 
-func checkActionPermissions(groupName string, c *businesscontext.Ctx, permissionValue int, routeVar map[string]string) bool {
-	if permissionValue == permission.PermissionRead {
+func checkActionPermissions(groupName string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool {
+	if capability == sdk.CapabilityRead {
 		return true
 	}
 
-	if permissionValue != permission.PermissionRead && c.User.Admin {
+	if capability != sdk.CapabilityRead && c.User.Admin {
 		return true
 	}
 
 	return false
 }
 
-func checkWorkerModelPermissions(modelID string, c *businesscontext.Ctx, permissionValue int, routeVar map[string]string) bool {
+func checkWorkerModelPermissions(modelID string, c *businesscontext.Ctx, capability sdk.Capability, routeVar map[string]string) bool {
 	id, err := strconv.ParseInt(modelID, 10, 64)
 	if err != nil {
 		log.Warning("checkWorkerModelPermissions> modelID is not an integer: %s", err)
@@ -239,10 +299,10 @@ func checkWorkerModelPermissions(modelID string, c *businesscontext.Ctx, permiss
 	if c.Hatchery != nil {
 		return c.Hatchery.GroupID == group.SharedInfraGroup.ID || m.GroupID == c.Hatchery.GroupID
 	}
-	return checkWorkerModelPermissionsByUser(m, c.User, permissionValue)
+	return checkWorkerModelPermissionsByUser(m, c.User, capability)
 }
 
-func checkWorkerModelPermissionsByUser(m *sdk.Model, u *sdk.User, permissionValue int) bool {
+func checkWorkerModelPermissionsByUser(m *sdk.Model, u *sdk.User, capability sdk.Capability) bool {
 	if u.Admin {
 		return true
 	}
@@ -255,7 +315,7 @@ func checkWorkerModelPermissionsByUser(m *sdk.Model, u *sdk.User, permissionValu
 				}
 			}
 
-			if permissionValue == permission.PermissionRead {
+			if capability == sdk.CapabilityRead {
 				return true
 			}
 		}
@@ -24,6 +24,48 @@ var (
 	SharedInfraGroupID int64
 )
 
+// Role returns the role of a group given its role name and its numeric permission.
+// Groups without role name get the role matching their numeric permission
+func Role(roleName string, perm int) sdk.Role {
+	if roleName != "" {
+		if r, ok := sdk.RoleByName(roleName); ok {
+			return r
+		}
+		log.Warning("permission.Role> Unknown role %s, using permission %d", roleName, perm)
+	}
+
+	var r sdk.Role
+	switch {
+	case perm >= PermissionReadWriteExecute:
+		r, _ = sdk.RoleByName(sdk.RoleReadWriteExecute)
+	case perm >= PermissionReadExecute:
+		r, _ = sdk.RoleByName(sdk.RoleReadExecute)
+	case perm >= PermissionRead:
+		r, _ = sdk.RoleByName(sdk.RoleRead)
+	}
+	return r
+}
+
+// Can returns true if a group with this role name and numeric permission has the capability
+func Can(roleName string, perm int, c sdk.Capability) bool {
+	return Role(roleName, perm).Has(c)
+}
+
+// RolePermission returns the numeric permission stored along a role, for the checks based on permission levels
+func RolePermission(roleName string) int {
+	r, ok := sdk.RoleByName(roleName)
+	switch {
+	case !ok:
+		return 0
+	case r.Has(sdk.CapabilityWrite):
+		return PermissionReadWriteExecute
+	case r.Has(sdk.CapabilityExecute):
+		return PermissionReadExecute
+	default:
+		return PermissionRead
+	}
+}
+
 // ApplicationPermission  Get the permission for the given application
 func ApplicationPermission(applicationID int64, user *sdk.User) int {
 	if user.Admin {
@@ -0,0 +1,28 @@
+package permission
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestCan(t *testing.T) {
+	assert.True(t, Can("", PermissionReadWriteExecute, sdk.CapabilityManageKeys))
+	assert.True(t, Can("", PermissionReadExecute, sdk.CapabilityExecute))
+	assert.False(t, Can("", PermissionReadExecute, sdk.CapabilityWrite))
+	assert.False(t, Can("", 0, sdk.CapabilityRead))
+
+	assert.True(t, Can(sdk.RoleDeployer, PermissionReadExecute, sdk.CapabilityExecute))
+	assert.False(t, Can(sdk.RoleDeployer, PermissionReadExecute, sdk.CapabilityManageVariables))
+	assert.True(t, Can(sdk.RoleKeyManager, PermissionRead, sdk.CapabilityManageKeys))
+	assert.False(t, Can(sdk.RoleKeyManager, PermissionRead, sdk.CapabilityWrite))
+}
+
+func TestRolePermission(t *testing.T) {
+	assert.Equal(t, PermissionReadWriteExecute, RolePermission(sdk.RoleReadWriteExecute))
+	assert.Equal(t, PermissionReadExecute, RolePermission(sdk.RoleDeployer))
+	assert.Equal(t, PermissionRead, RolePermission(sdk.RoleDeploymentApprover))
+	assert.Equal(t, 0, RolePermission("unknown"))
+}
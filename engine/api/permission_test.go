@@ -11,7 +11,7 @@ func Test_checkWorkerModelPermissionsByUser(t *testing.T) {
 	type args struct {
 		m *sdk.Model
 		u *sdk.User
-		p int
+		p sdk.Capability
 	}
 	tests := []struct {
 		name string
@@ -27,7 +27,7 @@ func Test_checkWorkerModelPermissionsByUser(t *testing.T) {
 				u: &sdk.User{
 					Admin: true,
 				},
-				p: 7,
+				p: sdk.CapabilityWrite,
 			},
 			want: true,
 		},
@@ -45,7 +45,7 @@ func Test_checkWorkerModelPermissionsByUser(t *testing.T) {
 						},
 					},
 				},
-				p: 4,
+				p: sdk.CapabilityRead,
 			},
 			want: true,
 		},
@@ -63,7 +63,7 @@ func Test_checkWorkerModelPermissionsByUser(t *testing.T) {
 						},
 					},
 				},
-				p: 7,
+				p: sdk.CapabilityWrite,
 			},
 			want: false,
 		},
@@ -81,7 +81,7 @@ func Test_checkWorkerModelPermissionsByUser(t *testing.T) {
 						},
 					},
 				},
-				p: 7,
+				p: sdk.CapabilityWrite,
 			},
 			want: false,
 		},
@@ -105,7 +105,7 @@ func Test_checkWorkerModelPermissionsByUser(t *testing.T) {
 						},
 					},
 				},
-				p: 7,
+				p: sdk.CapabilityWrite,
 			},
 			want: true,
 		},
@@ -117,3 +117,40 @@ func Test_checkWorkerModelPermissionsByUser(t *testing.T) {
 		}
 	}
 }
+
+func Test_checkAccessTokenScope(t *testing.T) {
+	read := &sdk.AccessToken{Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeRead}}
+	run := &sdk.AccessToken{Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeRun}}
+	admin := &sdk.AccessToken{Scopes: []sdk.AccessTokenScope{sdk.AccessTokenScopeAdmin}}
+
+	get := GET(nil)
+	put := PUT(nil)
+	postRun := POST(nil, NeedAccessTokenScope(sdk.AccessTokenScopeRun))
+	postExecute := POSTEXECUTE(nil)
+	adminOnly := POST(nil, NeedAdmin(true))
+
+	tests := []struct {
+		name   string
+		token  *sdk.AccessToken
+		rc     *HandlerConfig
+		method string
+		want   bool
+	}{
+		{name: "read token can read", token: read, rc: get, method: "GET", want: true},
+		{name: "read token cannot write", token: read, rc: put, method: "PUT", want: false},
+		{name: "read token cannot run", token: read, rc: postRun, method: "POST", want: false},
+		{name: "read token cannot execute", token: read, rc: postExecute, method: "POST", want: false},
+		{name: "run token can read", token: run, rc: get, method: "GET", want: true},
+		{name: "run token can run", token: run, rc: postRun, method: "POST", want: true},
+		{name: "run token can execute", token: run, rc: postExecute, method: "POST", want: true},
+		{name: "run token cannot write", token: run, rc: put, method: "PUT", want: false},
+		{name: "run token cannot call admin routes", token: run, rc: adminOnly, method: "POST", want: false},
+		{name: "admin token can write", token: admin, rc: put, method: "PUT", want: true},
+		{name: "admin token can call admin routes", token: admin, rc: adminOnly, method: "POST", want: true},
+	}
+	for _, tt := range tests {
+		if got := checkAccessTokenScope(tt.token, tt.rc, tt.method); got != tt.want {
+			t.Errorf("%q. checkAccessTokenScope() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -254,7 +254,7 @@ func LoadPipelines(db gorp.SqlExecutor, projectID int64, loadDependencies bool,
 
 // LoadPipelineByGroup loads all pipelines where group has access
 func LoadPipelineByGroup(db gorp.SqlExecutor, group *sdk.Group) error {
-	query := `SELECT project.projectKey, pipeline.id, pipeline.name,pipeline_group.role FROM pipeline
+	query := `SELECT project.projectKey, pipeline.id, pipeline.name,pipeline_group.role, COALESCE(pipeline_group.role_name, '') FROM pipeline
 	 		  JOIN pipeline_group ON pipeline_group.pipeline_id = pipeline.id
 	 		  JOIN project ON pipeline.project_id = project.id
 	 		  WHERE pipeline_group.group_id = $1 ORDER BY pipeline.name ASC`
@@ -267,13 +267,15 @@ func LoadPipelineByGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 	for rows.Next() {
 		var pipeline sdk.Pipeline
 		var perm int
-		err = rows.Scan(&pipeline.ProjectKey, &pipeline.ID, &pipeline.Name, &perm)
+		var role string
+		err = rows.Scan(&pipeline.ProjectKey, &pipeline.ID, &pipeline.Name, &perm, &role)
 		if err != nil {
 			return err
 		}
 		group.PipelineGroups = append(group.PipelineGroups, sdk.PipelineGroup{
 			Pipeline:   pipeline,
 			Permission: perm,
+			Role:       role,
 		})
 	}
 	return nil
@@ -292,7 +294,7 @@ func updateParamInList(params []sdk.Parameter, paramAction sdk.Parameter) (bool,
 
 // LoadGroupByPipeline load group permission on one pipeline
 func LoadGroupByPipeline(db gorp.SqlExecutor, pipeline *sdk.Pipeline) error {
-	query := `SELECT "group".id,"group".name,pipeline_group.role FROM "group"
+	query := `SELECT "group".id,"group".name,pipeline_group.role, COALESCE(pipeline_group.role_name, '') FROM "group"
 	 		  JOIN pipeline_group ON pipeline_group.group_id = "group".id
 	 		  WHERE pipeline_group.pipeline_id = $1 ORDER BY "group".name ASC`
 
@@ -305,12 +307,14 @@ func LoadGroupByPipeline(db gorp.SqlExecutor, pipeline *sdk.Pipeline) error {
 	for rows.Next() {
 		var group sdk.Group
 		var perm int
-		if err := rows.Scan(&group.ID, &group.Name, &perm); err != nil {
+		var role string
+		if err := rows.Scan(&group.ID, &group.Name, &perm, &role); err != nil {
 			return err
 		}
 		pipeline.GroupPermission = append(pipeline.GroupPermission, sdk.GroupPermission{
 			Group:      group,
 			Permission: perm,
+			Role:       role,
 		})
 	}
 	return nil
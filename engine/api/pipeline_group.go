@@ -14,6 +14,23 @@ import (
 	"github.com/ovh/cds/sdk"
 )
 
+func getGroupsInPipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	pipName := vars["permPipelineKey"]
+
+	p, err := pipeline.LoadPipeline(db, key, pipName, false)
+	if err != nil {
+		return sdk.WrapError(err, "getGroupsInPipelineHandler> Cannot load pipeline %s", pipName)
+	}
+
+	if err := pipeline.LoadGroupByPipeline(db, p); err != nil {
+		return sdk.WrapError(err, "getGroupsInPipelineHandler> Cannot load groups of pipeline %s", pipName)
+	}
+
+	return WriteJSON(w, r, p.GroupPermission, http.StatusOK)
+}
+
 func updateGroupRoleOnPipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	vars := mux.Vars(r)
 	key := vars["key"]
@@ -24,6 +41,9 @@ func updateGroupRoleOnPipelineHandler(w http.ResponseWriter, r *http.Request, db
 	if err := UnmarshalBody(r, &groupPipeline); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler> cannot unmarshal request")
 	}
+	if err := checkGroupRole(&groupPipeline); err != nil {
+		return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler> Invalid role")
+	}
 
 	if groupName != groupPipeline.Group.Name {
 		return sdk.ErrGroupNotFound
@@ -71,6 +91,9 @@ func updateGroupRoleOnPipelineHandler(w http.ResponseWriter, r *http.Request, db
 	if err := group.UpdateGroupRoleInPipeline(tx, p.ID, g.ID, groupPipeline.Permission); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot add group %s in pipeline %s", g.Name, p.Name)
 	}
+	if err := group.UpdateGroupRoleNameInPipeline(tx, p.ID, g.ID, groupPipeline.Role); err != nil {
+		return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot set role of group %s in pipeline %s", g.Name, p.Name)
+	}
 
 	if err := pipeline.UpdatePipelineLastModified(tx, proj, p, c.User); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot update pipeline last_modified date")
@@ -105,6 +128,11 @@ func updateGroupsOnPipelineHandler(w http.ResponseWriter, r *http.Request, db *g
 	if len(groupsPermission) == 0 {
 		return sdk.WrapError(sdk.ErrGroupNeedWrite, "updateGroupsOnPipelineHandler: Cannot remove all groups for pipeline %s", pipelineName)
 	}
+	for i := range groupsPermission {
+		if err := checkGroupRole(&groupsPermission[i]); err != nil {
+			return sdk.WrapError(err, "updateGroupsOnPipelineHandler> Invalid role")
+		}
+	}
 
 	found := false
 	for _, gp := range groupsPermission {
@@ -141,6 +169,9 @@ func updateGroupsOnPipelineHandler(w http.ResponseWriter, r *http.Request, db *g
 		if err := group.InsertGroupInPipeline(tx, p.ID, groupData.ID, g.Permission); err != nil {
 			return sdk.WrapError(err, "updateGroupsOnPipelineHandler: Cannot insert group %s in pipeline %s", g.Group.Name, p.Name)
 		}
+		if err := group.UpdateGroupRoleNameInPipeline(tx, p.ID, groupData.ID, g.Role); err != nil {
+			return sdk.WrapError(err, "updateGroupsOnPipelineHandler: Cannot set role of group %s in pipeline %s", g.Group.Name, p.Name)
+		}
 	}
 
 	if err := pipeline.UpdatePipelineLastModified(tx, proj, p, c.User); err != nil {
@@ -168,6 +199,9 @@ func addGroupInPipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.
 	if err := UnmarshalBody(r, &groupPermission); err != nil {
 		return err
 	}
+	if err := checkGroupRole(&groupPermission); err != nil {
+		return sdk.WrapError(err, "addGroupInPipelineHandler> Invalid role")
+	}
 
 	p, err := pipeline.LoadPipeline(db, key, pipelineName, false)
 	if err != nil {
@@ -197,6 +231,9 @@ func addGroupInPipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.
 	if err := group.InsertGroupInPipeline(tx, p.ID, g.ID, groupPermission.Permission); err != nil {
 		return sdk.WrapError(err, "addGroupInPipeline: Cannot add group %s in pipeline %s", g.Name, p.Name)
 	}
+	if err := group.UpdateGroupRoleNameInPipeline(tx, p.ID, g.ID, groupPermission.Role); err != nil {
+		return sdk.WrapError(err, "addGroupInPipeline: Cannot set role of group %s in pipeline %s", g.Name, p.Name)
+	}
 
 	if err := pipeline.UpdatePipelineLastModified(tx, proj, p, c.User); err != nil {
 		return sdk.WrapError(err, "addGroupInPipeline: Cannot update pipeline last_modified date")
@@ -6,8 +6,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
-	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/go-gorp/gorp"
@@ -22,7 +22,7 @@ import (
 	"github.com/ovh/cds/sdk/plugin"
 )
 
-func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.ActionPlugin, *plugin.Parameters, io.ReadCloser, func(), error) {
+func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.ActionPlugin, *plugin.Parameters, *os.File, func(), error) {
 	r.ParseMultipartForm(64 << 20)
 	file, handler, err := r.FormFile("UploadFile")
 	if err != nil {
@@ -66,12 +66,33 @@ func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.Action
 
 	ap, params, err := actionplugin.Get(filename, tmpfn)
 	if err != nil {
+		content.Close()
 		return nil, nil, nil, deferFunc, sdk.WrapError(sdk.ErrPluginInvalid, "fileUploadAndGetPlugin> unable to get plugin info: %s", err)
 	}
+	ap.Version = r.FormValue("version")
 
 	return ap, params, content, deferFunc, nil
 }
 
+//storePluginBinary stores the binary of a plugin, uploaded by the API itself to read the plugin information
+func storePluginBinary(db gorp.SqlExecutor, ap *sdk.ActionPlugin, file io.ReadSeeker) (*sdk.ActionPluginBinary, error) {
+	digest, objectPath, err := objectstore.StoreBlob(db, file)
+	if err != nil {
+		return nil, sdk.WrapError(err, "storePluginBinary> Error while uploading to object store %s", ap.Name)
+	}
+	ap.ObjectPath = objectPath
+	return &sdk.ActionPluginBinary{
+		PluginName: ap.Name,
+		Version:    ap.Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Size:       ap.Size,
+		Perm:       ap.Perm,
+		SHA256sum:  digest,
+		ObjectPath: objectPath,
+	}, nil
+}
+
 func addPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	//Upload file and get plugin information
 	ap, params, file, deferFunc, err := fileUploadAndGetPlugin(w, r)
@@ -83,6 +104,13 @@ func addPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	}
 	defer file.Close()
 
+	if ap.Version == "" {
+		ap.Version = actionplugin.FirstVersion
+	}
+	if err := actionplugin.CheckVersion(ap.Version); err != nil {
+		return err
+	}
+
 	// Check that action does not already exists
 	conflict, err := action.Exists(db, ap.Name)
 	if err != nil {
@@ -93,26 +121,27 @@ func addPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	}
 
 	//Upload it to objectstore
-	objectPath, err := objectstore.StorePlugin(*ap, file)
+	bin, err := storePluginBinary(db, ap, file)
 	if err != nil {
-		return sdk.WrapError(err, "addPluginHandler> Error while uploading to object store %s", ap.Name)
+		return err
 	}
-	ap.ObjectPath = objectPath
 
 	tx, err := db.Begin()
 	if err != nil {
+		objectstore.ReleaseBlob(db, bin.SHA256sum)
 		return sdk.WrapError(err, "addPluginHandler> Cannot start transaction")
 	}
 	defer tx.Rollback()
 
 	//Insert in database
-	a, err := actionplugin.Insert(tx, ap, params)
+	a, err := actionplugin.Insert(tx, ap, params, bin)
 	if err != nil {
-		objectstore.DeletePlugin(*ap)
+		objectstore.ReleaseBlob(db, bin.SHA256sum)
 		return sdk.WrapError(err, "addPluginHandler> Error while inserting action %s in database", ap.Name)
 	}
 
 	if err := tx.Commit(); err != nil {
+		objectstore.ReleaseBlob(db, bin.SHA256sum)
 		return sdk.WrapError(err, "addPluginHandler> Cannot commit transaction")
 	}
 
@@ -128,6 +157,7 @@ func updatePluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 	if errUpload != nil {
 		return sdk.WrapError(errUpload, "updatePluginHandler> fileUploadAndGetPlugin error")
 	}
+	defer file.Close()
 
 	// Check that action does not already exists
 	exists, errExists := action.Exists(db, ap.Name)
@@ -138,76 +168,44 @@ func updatePluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 		return sdk.WrapError(sdk.ErrNoAction, "updatePluginHandler")
 	}
 
-	//Store previous file from objectstore
-	buf, errFetch := objectstore.FetchPlugin(*ap)
-	if errFetch != nil {
-		log.Warning("updatePluginHandler>Unable to fetch plugin: %s", errFetch)
-		// do no raise error... it just mean that we cannot fetch the old version of the plugin
+	//A new version is uploaded, previous versions are kept
+	versions, errV := actionplugin.LoadVersions(db, ap.Name)
+	if errV != nil {
+		return sdk.WrapError(errV, "updatePluginHandler> Unable to load versions of plugin %s", ap.Name)
 	}
-
-	var tmpFile string
-	if buf != nil && errFetch == nil {
-		defer buf.Close()
-		//Read it
-		btes, errr := ioutil.ReadAll(buf)
-		if errr != nil {
-			return sdk.WrapError(errr, "updatePluginHandler> Unable to read old plugin buffer")
-		}
-		//Get a dir
-		tmpDir, errtmp := ioutil.TempDir("", "old-plugin")
-		if errtmp != nil {
-			return sdk.WrapError(errtmp, "updatePluginHandler> error with tempdir")
-		}
-		os.MkdirAll(tmpDir, os.FileMode(0700))
-
-		//Get a temp file
-		tmpFile = path.Join(tmpDir, ap.Name)
-
-		//Write it
-		log.Debug("updatePluginHandler>store oldfile %s in case of error", tmpFile)
-		if err := ioutil.WriteFile(tmpFile, btes, os.FileMode(0600)); err != nil {
-			return sdk.WrapError(err, "updatePluginHandler>Error writing file %s", tmpFile)
-		}
-		defer func() {
-			log.Debug("updatePluginHandler> deleting file %s", tmpFile)
-			os.RemoveAll(tmpFile)
-		}()
-		//Delete previous file from objectstore
-		if err := objectstore.DeletePlugin(*ap); err != nil {
-			return sdk.WrapError(err, "updatePluginHandler>Error deleting file")
+	if ap.Version == "" {
+		ap.Version = actionplugin.NextVersion(versions)
+	}
+	if err := actionplugin.CheckVersion(ap.Version); err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if v.Version == ap.Version {
+			return sdk.WrapError(sdk.ErrConflict, "updatePluginHandler> Version %s of plugin %s already exists", ap.Version, ap.Name)
 		}
 	}
 
 	//Upload it to objectstore
-	objectPath, errStore := objectstore.StorePlugin(*ap, file)
+	bin, errStore := storePluginBinary(db, ap, file)
 	if errStore != nil {
-		return sdk.WrapError(errStore, "updatePluginHandler> Error while uploading to object store %s", ap.Name)
+		return errStore
 	}
-	ap.ObjectPath = objectPath
 
 	tx, errBegin := db.Begin()
 	if errBegin != nil {
+		objectstore.ReleaseBlob(db, bin.SHA256sum)
 		return sdk.WrapError(errBegin, "updatePluginHandler> Cannot start transaction")
 	}
 	defer tx.Rollback()
 
 	//Update in database
-	a, errDB := actionplugin.Update(tx, ap, params, c.User.ID)
-	if errDB != nil && tmpFile != "" {
-		log.Warning("updatePluginHandler> Error while updating action %s in database: %s\n", ap.Name, errDB)
-		//Restore previous file
-		oldFile, errO := os.Open(tmpFile)
-		if errO != nil {
-			return sdk.WrapError(errO, "updatePluginHandler>Error opening file %s ", tmpFile)
-		}
-		//re-store the old plugin file
-		if _, errStore := objectstore.StorePlugin(*ap, oldFile); errStore != nil {
-			return sdk.WrapError(errStore, "updatePluginHandler> Error while uploading to object store %s", ap.Name)
-		}
-
-		return sdk.WrapError(errDB, "updatePluginHandler> Unable to update plugin", ap.Name)
+	a, errDB := actionplugin.Update(tx, ap, params, bin, c.User.ID)
+	if errDB != nil {
+		objectstore.ReleaseBlob(db, bin.SHA256sum)
+		return sdk.WrapError(errDB, "updatePluginHandler> Unable to update plugin %s", ap.Name)
 	}
 	if err := tx.Commit(); err != nil {
+		objectstore.ReleaseBlob(db, bin.SHA256sum)
 		return sdk.WrapError(err, "updatePluginHandler> Cannot commit transaction")
 	}
 
@@ -222,18 +220,122 @@ func deletePluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 		return sdk.ErrWrongRequest
 	}
 
+	versions, err := actionplugin.LoadVersions(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "deletePluginHandler> Unable to load versions of plugin %s", name)
+	}
+
 	//Delete in database
 	if err := actionplugin.Delete(db, name, c.User.ID); err != nil {
 		return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in database", name)
 	}
 
-	//Delete from objectstore
-	if err := objectstore.DeletePlugin(sdk.ActionPlugin{Name: name}); err != nil {
-		return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in objectstore", name)
+	//Delete from objectstore the binary uploaded before plugins were versioned
+	for _, v := range versions {
+		for _, b := range v.Binaries {
+			if b.SHA256sum != "" {
+				continue
+			}
+			if err := objectstore.DeletePlugin(sdk.ActionPlugin{Name: name}); err != nil {
+				return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in objectstore", name)
+			}
+		}
 	}
 	return nil
 }
 
+func getPluginVersionsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	name := mux.Vars(r)["name"]
+	versions, err := actionplugin.LoadVersions(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "getPluginVersionsHandler> Unable to load versions of plugin %s", name)
+	}
+	return WriteJSON(w, r, versions, http.StatusOK)
+}
+
+func addPluginBinaryHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	version := vars["version"]
+
+	r.ParseMultipartForm(64 << 20)
+	file, handler, err := r.FormFile("UploadFile")
+	if err != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "addPluginBinaryHandler> err on formFile: %s", err)
+	}
+	defer file.Close()
+
+	bin := &sdk.ActionPluginBinary{
+		OS:   r.FormValue("os"),
+		Arch: r.FormValue("arch"),
+		Size: handler.Size,
+		Perm: 0755,
+	}
+	if bin.OS == "" || bin.Arch == "" {
+		return sdk.WrapError(sdk.ErrWrongRequest, "addPluginBinaryHandler> os and arch are mandatory")
+	}
+
+	//The binary can't be run by the API to check it: the file is only stored for the workers
+	digest, objectPath, err := objectstore.StoreBlob(db, file)
+	if err != nil {
+		return sdk.WrapError(err, "addPluginBinaryHandler> Error while uploading to object store %s", name)
+	}
+	bin.SHA256sum = digest
+	bin.ObjectPath = objectPath
+
+	if err := actionplugin.InsertBinary(db, name, version, bin); err != nil {
+		objectstore.ReleaseBlob(db, digest)
+		return sdk.WrapError(err, "addPluginBinaryHandler> Unable to insert binary of plugin %s %s", name, version)
+	}
+	return WriteJSON(w, r, bin, http.StatusCreated)
+}
+
+func putPluginCurrentVersionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	name := mux.Vars(r)["name"]
+
+	var v sdk.ActionPluginVersion
+	if err := UnmarshalBody(r, &v); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "putPluginCurrentVersionHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	a, err := actionplugin.SetCurrentVersion(tx, name, v.Version, c.User.ID)
+	if err != nil {
+		return sdk.WrapError(err, "putPluginCurrentVersionHandler> Unable to set current version of plugin %s", name)
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "putPluginCurrentVersionHandler> Cannot commit transaction")
+	}
+	return WriteJSON(w, r, a, http.StatusOK)
+}
+
+func resolvePluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	name := mux.Vars(r)["name"]
+	goos, goarch := pluginPlatform(r)
+	bin, err := actionplugin.ResolveBinary(db, name, r.FormValue("version"), goos, goarch)
+	if err != nil {
+		return sdk.WrapError(err, "resolvePluginHandler> Unable to resolve plugin %s", name)
+	}
+	return WriteJSON(w, r, bin, http.StatusOK)
+}
+
+//pluginPlatform returns the OS and the architecture of the plugin binary requested, the platform of the API by default
+func pluginPlatform(r *http.Request) (string, string) {
+	goos, goarch := r.FormValue("os"), r.FormValue("arch")
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	return goos, goarch
+}
+
 func downloadPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	vars := mux.Vars(r)
 	name := vars["name"]
@@ -242,9 +344,21 @@ func downloadPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 		return sdk.ErrWrongRequest
 	}
 
-	f, err := objectstore.FetchPlugin(sdk.ActionPlugin{Name: name})
+	//Without version, the current version is downloaded
+	goos, goarch := pluginPlatform(r)
+	bin, err := actionplugin.ResolveBinary(db, name, r.FormValue("version"), goos, goarch)
+	if err != nil {
+		return sdk.WrapError(err, "downloadPluginHandler> Unable to resolve plugin %s", name)
+	}
+
+	var f io.ReadCloser
+	if bin.SHA256sum != "" {
+		f, err = objectstore.FetchArtifact(&objectstore.Blob{SHA256sum: bin.SHA256sum})
+	} else {
+		f, err = objectstore.FetchPlugin(sdk.ActionPlugin{Name: name})
+	}
 	if err != nil {
-		return sdk.WrapError(err, "downloadPluginHandler> Error while fetching plugin", name)
+		return sdk.WrapError(err, "downloadPluginHandler> Error while fetching plugin %s", name)
 	}
 
 	w.Header().Add("Content-Type", "application/octet-stream")
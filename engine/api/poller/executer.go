@@ -3,6 +3,7 @@ package poller
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"regexp"
 	"time"
 
@@ -168,7 +169,7 @@ func executerProcess(tx gorp.SqlExecutor, p *sdk.RepositoryPoller, e *sdk.Reposi
 	}
 
 	var pbs []sdk.PipelineBuild
-	if len(e.PushEvents) > 0 {
+	if len(e.PushEvents) > 0 || len(e.PullRequestEvents) > 0 {
 		var err error
 		pbs, err = triggerPipelines(tx, projectKey, rm, p, e)
 		if err != nil {
@@ -193,7 +194,7 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 
 	var pbs []sdk.PipelineBuild
 	for _, event := range e.PushEvents {
-		pb, err := triggerPipeline(tx, rm, poller, event, proj)
+		pb, err := triggerPipeline(tx, rm, poller, event, proj, nil)
 		if err != nil {
 			return nil, sdk.WrapError(err, "Polling.triggerPipelines> cannot trigger pipeline %d", poller.Pipeline.ID)
 		}
@@ -206,7 +207,7 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 	}
 
 	for _, event := range e.CreateEvents {
-		pb, err := triggerPipeline(tx, rm, poller, sdk.VCSPushEvent(event), proj)
+		pb, err := triggerPipeline(tx, rm, poller, sdk.VCSPushEvent(event), proj, nil)
 		if err != nil {
 			return nil, sdk.WrapError(err, "Polling.triggerPipelines> cannot trigger pipeline %d", poller.Pipeline.ID)
 		}
@@ -218,6 +219,23 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 		}
 	}
 
+	for _, event := range e.PullRequestEvents {
+		if !repositoriesmanager.TriggerPullRequest(event) {
+			continue
+		}
+
+		pb, err := triggerPipeline(tx, rm, poller, event.Head, proj, sdk.PullRequestParameters(event))
+		if err != nil {
+			return nil, sdk.WrapError(err, "Polling.triggerPipelines> cannot trigger pipeline %d for pull request %d", poller.Pipeline.ID, event.ID)
+		}
+
+		if pb != nil {
+			log.Debug("Polling.triggerPipelines> Triggered %s/%s pull request %d : %s", projectKey, poller.Application.RepositoryFullname, event.ID, event.Head.Commit.Hash)
+			e.PipelineBuildVersions[fmt.Sprintf("pr-%d/%s", event.ID, shortHash(event.Head.Commit.Hash))] = pb.Version
+			pbs = append(pbs, *pb)
+		}
+	}
+
 	for _, e := range e.DeleteEvents {
 		if err := pipeline.DeleteBranchBuilds(tx, poller.Application.ID, e.Branch.DisplayID); err != nil {
 			if err != sql.ErrNoRows {
@@ -231,9 +249,17 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 	return pbs, nil
 }
 
-func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, poller *sdk.RepositoryPoller, e sdk.VCSPushEvent, proj *sdk.Project) (*sdk.PipelineBuild, error) {
-	// Create pipeline args
-	var params []sdk.Parameter
+//shortHash returns the 7 first characters of a commit hash
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+//triggerPipeline inserts a pipeline build for the push event. params are the pipeline args of the build,
+//they are set with the pull request parameters when the build is triggered by a pull request
+func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, poller *sdk.RepositoryPoller, e sdk.VCSPushEvent, proj *sdk.Project, params []sdk.Parameter) (*sdk.PipelineBuild, error) {
 
 	// Load pipeline Argument
 	parameters, errg := pipeline.GetAllParametersInPipeline(tx, poller.Pipeline.ID)
@@ -264,12 +290,14 @@ func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, poller *s
 		return nil, nil
 	}
 
-	//Check if build exists
-	if b, err := pipeline.BuildExists(tx, poller.Application.ID, poller.Pipeline.ID, sdk.DefaultEnv.ID, &trigger); err != nil || b {
-		if err != nil {
-			log.Warning("Polling> Error checking existing build : %s", err)
+	//Check if build exists. Pull requests are always built since their merge ref changes with the base branch
+	if sdk.ParameterValue(params, "git.pr.id") == "" {
+		if b, err := pipeline.BuildExists(tx, poller.Application.ID, poller.Pipeline.ID, sdk.DefaultEnv.ID, &trigger); err != nil || b {
+			if err != nil {
+				log.Warning("Polling> Error checking existing build : %s", err)
+			}
+			return nil, nil
 		}
-		return nil, nil
 	}
 
 	//Insert the build
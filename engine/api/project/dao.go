@@ -51,7 +51,7 @@ func LoadAll(db gorp.SqlExecutor, u *sdk.User, opts ...LoadOptionFunc) ([]sdk.Pr
 // LoadPermissions loads all projects where group has access
 func LoadPermissions(db gorp.SqlExecutor, group *sdk.Group) error {
 	query := `
-		SELECT project.projectKey, project.name, project.last_modified, project_group.role
+		SELECT project.projectKey, project.name, project.last_modified, project_group.role, COALESCE(project_group.role_name, '')
 		FROM project
 	 	JOIN project_group ON project_group.project_id = project.id
 	 	WHERE project_group.group_id = $1
@@ -64,10 +64,10 @@ func LoadPermissions(db gorp.SqlExecutor, group *sdk.Group) error {
 	defer rows.Close()
 
 	for rows.Next() {
-		var projectKey, projectName string
+		var projectKey, projectName, role string
 		var perm int
 		var lastModified time.Time
-		if err := rows.Scan(&projectKey, &projectName, &lastModified, &perm); err != nil {
+		if err := rows.Scan(&projectKey, &projectName, &lastModified, &perm, &role); err != nil {
 			return err
 		}
 		group.ProjectGroups = append(group.ProjectGroups, sdk.ProjectGroup{
@@ -77,6 +77,7 @@ func LoadPermissions(db gorp.SqlExecutor, group *sdk.Group) error {
 				LastModified: lastModified,
 			},
 			Permission: perm,
+			Role:       role,
 		})
 	}
 	return nil
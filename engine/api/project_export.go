@@ -0,0 +1,792 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func exportProjectHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	passphrase := r.Header.Get(sdk.PassphraseHeader)
+
+	if passphrase == "" {
+		return sdk.WrapError(sdk.ErrInvalidPassphrase, "exportProjectHandler> Missing passphrase")
+	}
+
+	//The route needs the capability to manage keys, the archive also holds the secret variables
+	if !checkPermission(vars, c, sdk.CapabilityManageVariables) {
+		return sdk.WrapError(sdk.ErrForbidden, "exportProjectHandler> Not allowed to export the variables of project %s", key)
+	}
+
+	archive, err := exportProject(db, key, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "exportProjectHandler> Unable to export project %s", key)
+	}
+
+	p := secret.NewPassphrase(passphrase)
+	if err := archive.Secrets(func(s string) (string, error) {
+		return p.Encrypt([]byte(s))
+	}); err != nil {
+		return sdk.WrapError(err, "exportProjectHandler> Unable to encrypt secrets of project %s", key)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := archive.Write(buf); err != nil {
+		return sdk.WrapError(err, "exportProjectHandler> Unable to write archive of project %s", key)
+	}
+
+	w.Header().Add("Content-Type", "application/gzip")
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.tar.gz\"", key))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+//exportProject loads a whole project with its secrets in clear
+func exportProject(db gorp.SqlExecutor, key string, u *sdk.User) (*exportentities.ProjectArchive, error) {
+	proj, err := project.Load(db, key, u, project.LoadOptions.WithGroups, project.LoadOptions.WithKeys, project.LoadOptions.WithRepositoriesManagers)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportProject> Cannot load project %s", key)
+	}
+
+	proj.Variable, err = project.GetAllVariableInProject(db, proj.ID, project.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportProject> Cannot load variables of project %s", key)
+	}
+
+	for i := range proj.Keys {
+		if err := decryptKey(&proj.Keys[i].Key); err != nil {
+			return nil, sdk.WrapError(err, "exportProject> Cannot decrypt key %s of project %s", proj.Keys[i].Name, key)
+		}
+	}
+
+	archive := &exportentities.ProjectArchive{Project: *exportentities.NewProject(proj)}
+
+	pips, err := pipeline.LoadPipelines(db, proj.ID, false, u)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportProject> Cannot load pipelines")
+	}
+	for _, pip := range pips {
+		e, err := exportPipeline(db, key, pip.Name)
+		if err != nil {
+			return nil, err
+		}
+		archive.Pipelines = append(archive.Pipelines, *e)
+	}
+
+	apps, err := application.LoadAll(db, key, u)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportProject> Cannot load applications")
+	}
+	for _, app := range apps {
+		e, err := exportApplication(db, key, app.Name, u)
+		if err != nil {
+			return nil, err
+		}
+		archive.Applications = append(archive.Applications, *e)
+	}
+
+	envs, err := environment.LoadEnvironments(db, key, false, u)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportProject> Cannot load environments")
+	}
+	for _, env := range envs {
+		if env.Name == sdk.DefaultEnv.Name {
+			continue
+		}
+		e, err := exportEnvironment(db, key, env.Name)
+		if err != nil {
+			return nil, err
+		}
+		archive.Environments = append(archive.Environments, *e)
+	}
+
+	wfs, err := workflow.LoadAll(db, key)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportProject> Cannot load workflows")
+	}
+	for _, wf := range wfs {
+		e, err := exportWorkflow(db, key, wf.Name, u)
+		if err != nil {
+			return nil, err
+		}
+		archive.Workflows = append(archive.Workflows, *e)
+	}
+
+	return archive, nil
+}
+
+func exportPipeline(db gorp.SqlExecutor, key, name string) (*exportentities.Pipeline, error) {
+	pip, err := pipeline.LoadPipeline(db, key, name, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportPipeline> Cannot load pipeline %s", name)
+	}
+
+	p := exportentities.NewPipeline(pip)
+	p.Name = pip.Name
+	return p, nil
+}
+
+func exportApplication(db gorp.SqlExecutor, key, name string, u *sdk.User) (*exportentities.Application, error) {
+	app, err := application.LoadByName(db, key, name, u,
+		application.LoadOptions.WithVariablesWithClearPassword,
+		application.LoadOptions.WithPipelines,
+		application.LoadOptions.WithTriggers,
+		application.LoadOptions.WithGroups,
+		application.LoadOptions.WithRepositoryManager,
+		application.LoadOptions.WithKeys,
+	)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportApplication> Cannot load application %s", name)
+	}
+
+	a := exportentities.NewApplication(app)
+	for i := range app.Keys {
+		if err := decryptKey(&app.Keys[i].Key); err != nil {
+			return nil, sdk.WrapError(err, "exportApplication> Cannot decrypt key %s of application %s", app.Keys[i].Name, name)
+		}
+		a.Keys = append(a.Keys, exportentities.NewKey(app.Keys[i].Key))
+	}
+	return a, nil
+}
+
+func exportEnvironment(db gorp.SqlExecutor, key, name string) (*exportentities.Environment, error) {
+	env, err := environment.LoadEnvironmentByName(db, key, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportEnvironment> Cannot load environment %s", name)
+	}
+
+	env.Variable, err = environment.GetAllVariable(db, key, name, environment.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportEnvironment> Cannot load variables of environment %s", name)
+	}
+
+	e := exportentities.NewEnvironment(env)
+	for i := range env.Keys {
+		if err := decryptKey(&env.Keys[i].Key); err != nil {
+			return nil, sdk.WrapError(err, "exportEnvironment> Cannot decrypt key %s of environment %s", env.Keys[i].Name, name)
+		}
+		e.Keys = append(e.Keys, exportentities.NewKey(env.Keys[i].Key))
+	}
+	return e, nil
+}
+
+func exportWorkflow(db gorp.SqlExecutor, key, name string, u *sdk.User) (*exportentities.Workflow, error) {
+	wf, err := workflow.Load(db, key, name, u)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportWorkflow> Cannot load workflow %s", name)
+	}
+	return exportentities.NewWorkflow(wf)
+}
+
+//decryptKey decrypts a private key as it is stored in database
+func decryptKey(k *sdk.Key) error {
+	clear, err := secret.Decrypt([]byte(k.Private))
+	if err != nil {
+		return err
+	}
+	k.Private = string(clear)
+	return nil
+}
+
+func importProjectHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	passphrase := r.Header.Get(sdk.PassphraseHeader)
+	dryRun := FormBool(r, "dryRun")
+
+	rgxp := regexp.MustCompile(sdk.ProjectKeyPattern)
+	if !rgxp.MatchString(key) {
+		return sdk.WrapError(sdk.ErrInvalidProjectKey, "importProjectHandler> Project key %s do not respect pattern %s", key, sdk.ProjectKeyPattern)
+	}
+
+	//The route is not checked on the project key, which may not exist yet: the permissions are checked before
+	//reading the archive
+	exist, err := project.Exist(db, key)
+	if err != nil {
+		return sdk.WrapError(err, "importProjectHandler> Unable to check if project %s exists", key)
+	}
+	if exist {
+		routeVars := map[string]string{"permProjectKey": key}
+		for _, capability := range []sdk.Capability{sdk.CapabilityWrite, sdk.CapabilityManageVariables, sdk.CapabilityManageKeys} {
+			if !checkPermission(routeVars, c, capability) {
+				return sdk.WrapError(sdk.ErrForbidden, "importProjectHandler> Not allowed to import in project %s", key)
+			}
+		}
+	}
+
+	archive, err := exportentities.ReadProjectArchive(r.Body)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "importProjectHandler> Unable to read archive: %s", err)
+	}
+
+	p := secret.NewPassphrase(passphrase)
+	if err := archive.Secrets(func(s string) (string, error) {
+		clear, err := p.Decrypt(s)
+		return string(clear), err
+	}); err != nil {
+		return sdk.WrapError(err, "importProjectHandler> Unable to decrypt secrets")
+	}
+	//The project may be imported under another key
+	archive.Project.Key = key
+
+	allMsg := []sdk.Message{}
+	msgChan := make(chan sdk.Message, 1)
+	done := make(chan bool)
+
+	go func() {
+		for {
+			msg, ok := <-msgChan
+			if !ok {
+				done <- true
+				return
+			}
+			allMsg = append(allMsg, msg)
+		}
+	}()
+
+	tx, errBegin := db.Begin()
+	if errBegin != nil {
+		return sdk.WrapError(errBegin, "importProjectHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	globalError := importProject(tx, archive, exist, c.User, msgChan)
+	if globalError == nil && dryRun {
+		msgChan <- sdk.NewMessage(sdk.MsgImportDryRun)
+	}
+
+	close(msgChan)
+	<-done
+
+	al := r.Header.Get("Accept-Language")
+	msgListString := []string{}
+	for _, m := range allMsg {
+		s := m.String(al)
+		if s != "" {
+			msgListString = append(msgListString, s)
+		}
+	}
+
+	if globalError != nil {
+		myError, ok := globalError.(*sdk.Error)
+		if ok {
+			return WriteJSON(w, r, msgListString, myError.Status)
+		}
+		return sdk.WrapError(globalError, "importProjectHandler> Unable to import project %s", key)
+	}
+
+	//On dry run, the transaction is rolled back
+	if !dryRun {
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "importProjectHandler> Cannot commit transaction")
+		}
+	}
+
+	return WriteJSON(w, r, msgListString, http.StatusOK)
+}
+
+//importProject creates all the entities of the archive which do not exist yet. The existing ones are left untouched
+//and compared to the archive
+func importProject(db gorp.SqlExecutor, archive *exportentities.ProjectArchive, exist bool, u *sdk.User, msgChan chan<- sdk.Message) error {
+	key := archive.Project.Key
+
+	groups, err := importGroups(db, archive, u, msgChan)
+	if err != nil {
+		return err
+	}
+
+	var proj *sdk.Project
+	if exist {
+		proj, err = project.Load(db, key, u, project.LoadOptions.WithGroups)
+		if err != nil {
+			return sdk.WrapError(err, "importProject> Cannot load project %s", key)
+		}
+		msgChan <- sdk.NewMessage(sdk.MsgProjectExists, key)
+	} else {
+		proj = &sdk.Project{Key: key, Name: archive.Project.Name}
+		if err := project.Insert(db, proj, u); err != nil {
+			return sdk.WrapError(err, "importProject> Cannot insert project %s", key)
+		}
+		msgChan <- sdk.NewMessage(sdk.MsgProjectCreated, key)
+	}
+
+	if err := importProjectSettings(db, proj, &archive.Project, groups, u, msgChan); err != nil {
+		return err
+	}
+
+	rms, err := importRepositoriesManagers(db, key, archive.Project.RepositoriesManagers, msgChan)
+	if err != nil {
+		return err
+	}
+
+	for i := range archive.Environments {
+		if err := importEnvironment(db, proj, &archive.Environments[i], groups, u, msgChan); err != nil {
+			return err
+		}
+	}
+
+	for i := range archive.Pipelines {
+		if err := importPipeline(db, proj, &archive.Pipelines[i], groups, u, msgChan); err != nil {
+			return err
+		}
+	}
+
+	if err := importApplications(db, proj, archive.Applications, rms, groups, u, msgChan); err != nil {
+		return err
+	}
+
+	//The workflows are checked against the permissions of the user, including the imported ones
+	if err := loadUserPermissions(db, u); err != nil {
+		return sdk.WrapError(err, "importProject> Cannot load permissions of user %s", u.Username)
+	}
+
+	wfs, err := workflow.LoadAll(db, key)
+	if err != nil {
+		return sdk.WrapError(err, "importProject> Cannot load workflows")
+	}
+	for i := range archive.Workflows {
+		if err := importWorkflow(db, proj, wfs, &archive.Workflows[i], u, msgChan); err != nil {
+			return err
+		}
+	}
+
+	return project.UpdateLastModified(db, u, proj)
+}
+
+//importGroups creates the missing groups of the archive. The user becomes admin of the created groups. It returns
+//the groups the user is allowed to grant permissions to: the created ones and the existing ones the user administers
+func importGroups(db gorp.SqlExecutor, archive *exportentities.ProjectArchive, u *sdk.User, msgChan chan<- sdk.Message) (map[string]bool, error) {
+	names := map[string]bool{}
+	for name := range archive.Project.Permissions {
+		names[name] = true
+	}
+	for _, p := range archive.Pipelines {
+		for name := range p.Permissions {
+			names[name] = true
+		}
+	}
+	for _, app := range archive.Applications {
+		for name := range app.Permissions {
+			names[name] = true
+		}
+	}
+	for _, env := range archive.Environments {
+		for name := range env.Permissions {
+			names[name] = true
+		}
+	}
+
+	administered, err := group.LoadGroupByAdmin(db, u.ID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "importGroups> Cannot load groups administered by user %s", u.Username)
+	}
+	isAdmin := make(map[string]bool, len(administered))
+	for _, g := range administered {
+		isAdmin[g.Name] = true
+	}
+
+	allowed := make(map[string]bool, len(names))
+	for name := range names {
+		groupID, new, errGroup := group.AddGroup(db, &sdk.Group{Name: name})
+		if groupID == 0 {
+			return nil, sdk.WrapError(errGroup, "importGroups> Cannot add group %s", name)
+		}
+		if new {
+			if err := group.InsertUserInGroup(db, groupID, u.ID, true); err != nil {
+				return nil, sdk.WrapError(err, "importGroups> Cannot add user %s in group %s", u.Username, name)
+			}
+			msgChan <- sdk.NewMessage(sdk.MsgProjectGroupCreated, name)
+		} else if !u.Admin && !isAdmin[name] {
+			msgChan <- sdk.NewMessage(sdk.MsgImportGroupSkipped, name)
+			continue
+		}
+		allowed[name] = true
+	}
+	return allowed, nil
+}
+
+//importGroupPermissions drops the permissions of the groups the user is not allowed to grant permissions to
+func importGroupPermissions(perms []sdk.GroupPermission, groups map[string]bool) []sdk.GroupPermission {
+	res := []sdk.GroupPermission{}
+	for _, gp := range perms {
+		if groups[gp.Group.Name] {
+			res = append(res, gp)
+		}
+	}
+	return res
+}
+
+//importProjectSettings adds the missing groups, variables and keys of the project
+func importProjectSettings(db gorp.SqlExecutor, proj *sdk.Project, p *exportentities.Project, groups map[string]bool, u *sdk.User, msgChan chan<- sdk.Message) error {
+	for name, perm := range p.Permissions {
+		if !groups[name] {
+			continue
+		}
+		g, err := group.LoadGroup(db, name)
+		if err != nil {
+			return sdk.WrapError(err, "importProjectSettings> Cannot load group %s", name)
+		}
+		attached, err := group.CheckGroupInProject(db, proj.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "importProjectSettings> Cannot check group %s in project %s", name, proj.Key)
+		}
+		if attached {
+			continue
+		}
+		if err := group.InsertGroupInProject(db, proj.ID, g.ID, perm); err != nil {
+			return sdk.WrapError(err, "importProjectSettings> Cannot add group %s in project %s", name, proj.Key)
+		}
+		msgChan <- sdk.NewMessage(sdk.MsgProjectGroupAdded, name, proj.Key)
+	}
+
+	vars, err := project.GetAllVariableInProject(db, proj.ID, project.WithClearPassword())
+	if err != nil {
+		return sdk.WrapError(err, "importProjectSettings> Cannot load variables of project %s", proj.Key)
+	}
+	existingVars := make(map[string]exportentities.VariableValue, len(vars))
+	for _, v := range vars {
+		existingVars[v.Name] = exportentities.VariableValue{Type: v.Type, Value: v.Value}
+	}
+	for _, v := range p.Project().Variable {
+		if existing, ok := existingVars[v.Name]; ok {
+			if err := importCompare("Variable", v.Name, existing, p.Variables[v.Name], msgChan); err != nil {
+				return err
+			}
+			continue
+		}
+		v := v
+		if err := project.InsertVariable(db, proj, &v, u); err != nil {
+			return sdk.WrapError(err, "importProjectSettings> Cannot add variable %s in project %s", v.Name, proj.Key)
+		}
+		msgChan <- sdk.NewMessage(sdk.MsgProjectVariableCreated, v.Name, proj.Key)
+	}
+
+	if err := project.LoadAllKeys(db, proj); err != nil {
+		return sdk.WrapError(err, "importProjectSettings> Cannot load keys of project %s", proj.Key)
+	}
+	existingKeys := make(map[string]exportentities.Key, len(proj.Keys))
+	for _, k := range proj.Keys {
+		existingKeys[k.Name] = exportentities.NewKey(k.Key)
+	}
+	for _, k := range p.Keys {
+		if existing, ok := existingKeys[k.Name]; ok {
+			//Private keys are not compared, they are encrypted in database
+			existing.Private, k.Private = "", ""
+			if err := importCompare("Key", k.Name, existing, k, msgChan); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := project.InsertKey(db, &sdk.ProjectKey{Key: k.Key(), ProjectID: proj.ID}); err != nil {
+			return sdk.WrapError(err, "importProjectSettings> Cannot add key %s in project %s", k.Name, proj.Key)
+		}
+		msgChan <- sdk.NewMessage(sdk.MsgProjectKeyCreated, k.Name, proj.Key)
+	}
+
+	return nil
+}
+
+//importRepositoriesManagers links the project to the repositories managers of the archive which exist on this
+//instance. It returns them indexed by name
+func importRepositoriesManagers(db gorp.SqlExecutor, key string, names []string, msgChan chan<- sdk.Message) (map[string]*sdk.RepositoriesManager, error) {
+	rms := make(map[string]*sdk.RepositoriesManager, len(names))
+	for _, name := range names {
+		rm, err := repositoriesmanager.LoadByName(db, name)
+		if err != nil {
+			msgChan <- sdk.NewMessage(sdk.MsgImportRepositoriesManagerNotFound, name)
+			continue
+		}
+		rms[name] = rm
+
+		if _, err := repositoriesmanager.LoadForProject(db, key, name); err == nil {
+			continue
+		}
+		if err := repositoriesmanager.InsertForProject(db, rm, key); err != nil {
+			return nil, sdk.WrapError(err, "importRepositoriesManagers> Cannot link repositories manager %s to project %s", name, key)
+		}
+		msgChan <- sdk.NewMessage(sdk.MsgImportRepositoriesManagerLinked, name, key)
+	}
+	return rms, nil
+}
+
+func importEnvironment(db gorp.SqlExecutor, proj *sdk.Project, e *exportentities.Environment, groups map[string]bool, u *sdk.User, msgChan chan<- sdk.Message) error {
+	exist, err := environment.Exists(db, proj.Key, e.Name)
+	if err != nil {
+		return sdk.WrapError(err, "importEnvironment> Unable to check if environment %s exists", e.Name)
+	}
+	if exist {
+		existing, err := exportEnvironment(db, proj.Key, e.Name)
+		if err != nil {
+			return err
+		}
+		return importCompare("Environment", e.Name, existing, e, msgChan)
+	}
+
+	env := e.Environment()
+	env.EnvironmentGroups = importGroupPermissions(env.EnvironmentGroups, groups)
+	for i := range env.EnvironmentGroups {
+		eg := &env.EnvironmentGroups[i]
+		g, err := group.LoadGroup(db, eg.Group.Name)
+		if err != nil {
+			return sdk.WrapError(err, "importEnvironment> Error loading groups for permission")
+		}
+		eg.Group = *g
+	}
+
+	if err := environment.Import(db, proj, env, msgChan, u); err != nil {
+		return sdk.WrapError(err, "importEnvironment> Cannot import environment %s", e.Name)
+	}
+
+	for _, k := range e.Keys {
+		if err := environment.InsertKey(db, &sdk.EnvironmentKey{Key: k.Key(), EnvironmentID: env.ID}); err != nil {
+			return sdk.WrapError(err, "importEnvironment> Cannot add key %s in environment %s", k.Name, env.Name)
+		}
+		msgChan <- sdk.NewMessage(sdk.MsgEnvironmentKeyCreated, k.Name, env.Name)
+	}
+	return nil
+}
+
+func importPipeline(db gorp.SqlExecutor, proj *sdk.Project, p *exportentities.Pipeline, groups map[string]bool, u *sdk.User, msgChan chan<- sdk.Message) error {
+	exist, err := pipeline.ExistPipeline(db, proj.ID, p.Name)
+	if err != nil {
+		return sdk.WrapError(err, "importPipeline> Unable to check if pipeline %s exists", p.Name)
+	}
+	if exist {
+		existing, err := exportPipeline(db, proj.Key, p.Name)
+		if err != nil {
+			return err
+		}
+		return importCompare("Pipeline", p.Name, existing, p, msgChan)
+	}
+
+	pip, err := p.Pipeline()
+	if err != nil {
+		return sdk.WrapError(err, "importPipeline> Unable to parse pipeline %s", p.Name)
+	}
+
+	pip.GroupPermission = importGroupPermissions(pip.GroupPermission, groups)
+	for i := range pip.GroupPermission {
+		eg := &pip.GroupPermission[i]
+		g, err := group.LoadGroup(db, eg.Group.Name)
+		if err != nil {
+			return sdk.WrapError(err, "importPipeline> Error loading groups for permission")
+		}
+		eg.Group = *g
+	}
+
+	return pipeline.Import(db, proj, pip, msgChan, u)
+}
+
+//importApplications creates the missing applications. Their pipelines and triggers are imported once all the
+//applications exist, as triggers may target any of them
+func importApplications(db gorp.SqlExecutor, proj *sdk.Project, apps []exportentities.Application, rms map[string]*sdk.RepositoriesManager, groups map[string]bool, u *sdk.User, msgChan chan<- sdk.Message) error {
+	created := []*sdk.Application{}
+	for i := range apps {
+		a := &apps[i]
+		_, err := application.LoadByName(db, proj.Key, a.Name, nil)
+		if err == nil {
+			existing, err := exportApplication(db, proj.Key, a.Name, u)
+			if err != nil {
+				return err
+			}
+			if err := importCompare("Application", a.Name, existing, a, msgChan); err != nil {
+				return err
+			}
+			continue
+		}
+		if !sdk.ErrorIs(err, sdk.ErrApplicationNotFound) {
+			return sdk.WrapError(err, "importApplications> Unable to check if application %s exists", a.Name)
+		}
+
+		app := a.Application()
+		app.ApplicationGroups = importGroupPermissions(app.ApplicationGroups, groups)
+		rm, repo := app.RepositoriesManager, app.RepositoryFullname
+		app.RepositoriesManager, app.RepositoryFullname = nil, ""
+
+		if err := application.Insert(db, proj, app, u); err != nil {
+			return sdk.WrapError(err, "importApplications> Cannot insert application %s", a.Name)
+		}
+		msgChan <- sdk.NewMessage(sdk.MsgAppCreated, app.Name)
+
+		for _, v := range a.Application().Variable {
+			if err := application.InsertVariable(db, app, v, u); err != nil {
+				return sdk.WrapError(err, "importApplications> Cannot add variable %s in application %s", v.Name, app.Name)
+			}
+		}
+		if len(a.Variables) > 0 {
+			msgChan <- sdk.NewMessage(sdk.MsgAppVariablesCreated, app.Name)
+		}
+
+		if err := application.AddGroup(db, proj, app, u, app.ApplicationGroups...); err != nil {
+			return sdk.WrapError(err, "importApplications> Cannot add groups in application %s", app.Name)
+		}
+		for _, gp := range app.ApplicationGroups {
+			msgChan <- sdk.NewMessage(sdk.MsgAppGroupSetPermission, gp.Group.Name, app.Name)
+		}
+
+		for _, k := range a.Keys {
+			if err := application.InsertKey(db, &sdk.ApplicationKey{Key: k.Key(), ApplicationID: app.ID}); err != nil {
+				return sdk.WrapError(err, "importApplications> Cannot add key %s in application %s", k.Name, app.Name)
+			}
+			msgChan <- sdk.NewMessage(sdk.MsgAppKeyCreated, k.Name, app.Name)
+		}
+
+		if rm != nil {
+			if r, ok := rms[rm.Name]; ok {
+				app.RepositoriesManager, app.RepositoryFullname = r, repo
+				if err := repositoriesmanager.InsertForApplication(db, app, proj.Key); err != nil {
+					return sdk.WrapError(err, "importApplications> Cannot link application %s to repository %s", app.Name, repo)
+				}
+				msgChan <- sdk.NewMessage(sdk.MsgImportAppRepositoryLinked, app.Name, repo, rm.Name)
+			}
+		}
+
+		for _, ap := range a.Pipelines {
+			if len(ap.Options) > 0 {
+				msgChan <- sdk.NewMessage(sdk.MsgImportAppOptionsSkipped, app.Name)
+				break
+			}
+		}
+
+		created = append(created, app)
+	}
+
+	for _, app := range created {
+		//Triggers to other projects are not imported
+		for i := range app.Pipelines {
+			ap := &app.Pipelines[i]
+			triggers := ap.Triggers[:0]
+			for _, t := range ap.Triggers {
+				if t.DestProject.Key != "" && t.DestProject.Key != proj.Key {
+					msgChan <- sdk.NewMessage(sdk.MsgImportTriggerSkipped, ap.Pipeline.Name, app.Name, t.DestProject.Key)
+					continue
+				}
+				triggers = append(triggers, t)
+			}
+			ap.Triggers = triggers
+		}
+
+		if err := application.ImportPipelines(db, proj, app, u, msgChan); err != nil {
+			return sdk.WrapError(err, "importApplications> Cannot import pipelines of application %s", app.Name)
+		}
+
+		for _, ap := range app.Pipelines {
+			if len(ap.Parameters) == 0 {
+				continue
+			}
+			if err := application.UpdatePipelineApplication(db, app, ap.Pipeline.ID, ap.Parameters, u); err != nil {
+				return sdk.WrapError(err, "importApplications> Cannot set parameters of pipeline %s in application %s", ap.Pipeline.Name, app.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func importWorkflow(db gorp.SqlExecutor, proj *sdk.Project, wfs []sdk.Workflow, w *exportentities.Workflow, u *sdk.User, msgChan chan<- sdk.Message) error {
+	for _, wf := range wfs {
+		if wf.Name != w.Name {
+			continue
+		}
+		existing, err := exportWorkflow(db, proj.Key, w.Name, u)
+		if err != nil {
+			return err
+		}
+		return importCompare("Workflow", w.Name, existing, w, msgChan)
+	}
+
+	wf, err := w.Workflow()
+	if err != nil {
+		return sdk.WrapError(err, "importWorkflow> Unable to parse workflow %s", w.Name)
+	}
+	wf.ProjectID = proj.ID
+	wf.ProjectKey = proj.Key
+
+	if err := importWorkflowNode(db, proj, wf.Root); err != nil {
+		return sdk.WrapError(err, "importWorkflow> Unable to load entities of workflow %s", w.Name)
+	}
+	for i := range wf.Joins {
+		for j := range wf.Joins[i].Triggers {
+			if err := importWorkflowNode(db, proj, &wf.Joins[i].Triggers[j].WorkflowDestNode); err != nil {
+				return sdk.WrapError(err, "importWorkflow> Unable to load entities of workflow %s", w.Name)
+			}
+		}
+	}
+
+	if err := workflow.Insert(db, wf, u); err != nil {
+		return sdk.WrapError(err, "importWorkflow> Cannot insert workflow %s", w.Name)
+	}
+	msgChan <- sdk.NewMessage(sdk.MsgWorkflowCreated, w.Name)
+	return nil
+}
+
+//importWorkflowNode loads the pipelines, applications and environments of a node and its triggers
+func importWorkflowNode(db gorp.SqlExecutor, proj *sdk.Project, n *sdk.WorkflowNode) error {
+	pip, err := pipeline.LoadPipeline(db, proj.Key, n.Pipeline.Name, false)
+	if err != nil {
+		return sdk.WrapError(err, "importWorkflowNode> Cannot load pipeline %s", n.Pipeline.Name)
+	}
+	n.Pipeline = *pip
+
+	if n.Context.Application != nil {
+		app, err := application.LoadByName(db, proj.Key, n.Context.Application.Name, nil)
+		if err != nil {
+			return sdk.WrapError(err, "importWorkflowNode> Cannot load application %s", n.Context.Application.Name)
+		}
+		n.Context.Application = app
+	}
+
+	if n.Context.Environment != nil {
+		env, err := environment.LoadEnvironmentByName(db, proj.Key, n.Context.Environment.Name)
+		if err != nil {
+			return sdk.WrapError(err, "importWorkflowNode> Cannot load environment %s", n.Context.Environment.Name)
+		}
+		n.Context.Environment = env
+	}
+
+	for i := range n.Triggers {
+		if err := importWorkflowNode(db, proj, &n.Triggers[i].WorkflowDestNode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//importCompare tells whether an existing entity is identical to the one of the archive
+func importCompare(kind, name string, existing, imported interface{}, msgChan chan<- sdk.Message) error {
+	b1, err := yaml.Marshal(existing)
+	if err != nil {
+		return sdk.WrapError(err, "importCompare> Cannot marshal %s %s", kind, name)
+	}
+	b2, err := yaml.Marshal(imported)
+	if err != nil {
+		return sdk.WrapError(err, "importCompare> Cannot marshal %s %s", kind, name)
+	}
+
+	if bytes.Equal(b1, b2) {
+		msgChan <- sdk.NewMessage(sdk.MsgImportEntityUnchanged, kind, name)
+	} else {
+		msgChan <- sdk.NewMessage(sdk.MsgImportEntityDiffers, kind, name)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_importProjectHandlerChecksPermissionFirst(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	router = newRouter(auth.TestLocalAuth(t), mux.NewRouter(), "/Test_importProjectHandlerChecksPermissionFirst")
+	router.init()
+
+	admin, _ := assets.InsertAdminUser(db)
+	pkey := sdk.RandomString(10)
+	assets.InsertTestProject(t, db, pkey, pkey, admin)
+
+	//The archive of a user without permission on the project is neither read nor decrypted
+	u, pass := assets.InsertLambdaUser(db)
+	uri := router.getRoute("POST", importProjectHandler, map[string]string{"key": pkey})
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, "not an archive")
+	req.Header.Set(sdk.PassphraseHeader, "my passphrase")
+	rec := httptest.NewRecorder()
+	router.mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
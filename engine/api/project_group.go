@@ -16,6 +16,22 @@ import (
 	"github.com/ovh/cds/sdk"
 )
 
+func getGroupsInProjectHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	p, err := project.Load(db, key, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getGroupsInProjectHandler> Cannot load %s", key)
+	}
+
+	if err := group.LoadGroupByProject(db, p); err != nil {
+		return sdk.WrapError(err, "getGroupsInProjectHandler> Cannot load groups of project %s", key)
+	}
+
+	return WriteJSON(w, r, p.ProjectGroups, http.StatusOK)
+}
+
 func deleteGroupFromProjectHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	// Get project name in URL
 	vars := mux.Vars(r)
@@ -63,6 +79,9 @@ func updateGroupRoleOnProjectHandler(w http.ResponseWriter, r *http.Request, db
 	if err := UnmarshalBody(r, &groupProject); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnProjectHandler> unable to unmarshal")
 	}
+	if err := checkGroupRole(&groupProject); err != nil {
+		return sdk.WrapError(err, "updateGroupRoleOnProjectHandler> Invalid role")
+	}
 
 	if groupName != groupProject.Group.Name {
 		return sdk.ErrGroupNotFound
@@ -107,6 +126,9 @@ func updateGroupRoleOnProjectHandler(w http.ResponseWriter, r *http.Request, db
 	if err := group.UpdateGroupRoleInProject(db, p.ID, g.ID, groupProject.Permission); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleHandler: Cannot add group %s in project %s", g.Name, p.Name)
 	}
+	if err := group.UpdateGroupRoleNameInProject(db, p.ID, g.ID, groupProject.Role); err != nil {
+		return sdk.WrapError(err, "updateGroupRoleHandler: Cannot set role of group %s in project %s", g.Name, p.Name)
+	}
 
 	if err := project.UpdateLastModified(tx, c.User, p); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleHandler: Cannot update last modified date")
@@ -132,6 +154,11 @@ func updateGroupsInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	if len(groupProject) == 0 {
 		return sdk.WrapError(sdk.ErrGroupNeedWrite, "updateGroupsInProject: Cannot remove all groups.")
 	}
+	for i := range groupProject {
+		if err := checkGroupRole(&groupProject[i]); err != nil {
+			return sdk.WrapError(err, "updateGroupsInProject> Invalid role")
+		}
+	}
 
 	found := false
 	for _, gp := range groupProject {
@@ -169,6 +196,9 @@ func updateGroupsInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 		if err := group.InsertGroupInProject(tx, p.ID, groupData.ID, g.Permission); err != nil {
 			return sdk.WrapError(err, "updateGroupsInProject: Cannot add group %s in project %s", g.Group.Name, p.Name)
 		}
+		if err := group.UpdateGroupRoleNameInProject(tx, p.ID, groupData.ID, g.Role); err != nil {
+			return sdk.WrapError(err, "updateGroupsInProject: Cannot set role of group %s in project %s", g.Group.Name, p.Name)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -186,6 +216,9 @@ func addGroupInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	if err := UnmarshalBody(r, &groupProject); err != nil {
 		return sdk.WrapError(err, "addGroupInProject> unable to unmarshal")
 	}
+	if err := checkGroupRole(&groupProject); err != nil {
+		return sdk.WrapError(err, "addGroupInProject> Invalid role")
+	}
 
 	p, errl := project.Load(db, key, c.User)
 	if errl != nil {
@@ -214,6 +247,9 @@ func addGroupInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	if err := group.InsertGroupInProject(tx, p.ID, g.ID, groupProject.Permission); err != nil {
 		return sdk.WrapError(err, "AddGroupInProject: Cannot add group %s in project %s", g.Name, p.Name)
 	}
+	if err := group.UpdateGroupRoleNameInProject(tx, p.ID, g.ID, groupProject.Role); err != nil {
+		return sdk.WrapError(err, "AddGroupInProject: Cannot set role of group %s in project %s", g.Name, p.Name)
+	}
 
 	// apply on application
 	applications, errla := application.LoadAll(tx, p.Key, c.User)
@@ -234,6 +270,9 @@ func addGroupInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 			} else if err := application.AddGroup(tx, p, &app, c.User, groupProject); err != nil {
 				return sdk.WrapError(err, "AddGroupInProject: Cannot insert group %s on application %s", g.Name, app.Name)
 			}
+			if err := group.UpdateGroupRoleNameInApplication(tx, p.Key, app.Name, g.Name, groupProject.Role); err != nil {
+				return sdk.WrapError(err, "AddGroupInProject: Cannot set role of group %s on application %s", g.Name, app.Name)
+			}
 		}
 	}
 
@@ -256,6 +295,9 @@ func addGroupInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 			} else if err := group.InsertGroupInPipeline(tx, pip.ID, g.ID, groupProject.Permission); err != nil {
 				return sdk.WrapError(err, "AddGroupInProject: Cannot insert group %s on pipeline %s", g.Name, pip.Name)
 			}
+			if err := group.UpdateGroupRoleNameInPipeline(tx, pip.ID, g.ID, groupProject.Role); err != nil {
+				return sdk.WrapError(err, "AddGroupInProject: Cannot set role of group %s on pipeline %s", g.Name, pip.Name)
+			}
 		}
 	}
 
@@ -278,6 +320,9 @@ func addGroupInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 			} else if err := group.InsertGroupInEnvironment(tx, env.ID, g.ID, groupProject.Permission); err != nil {
 				return sdk.WrapError(err, "AddGroupInProject: Cannot insert group %s on environment %s", g.Name, env.Name)
 			}
+			if err := group.UpdateGroupRoleNameInEnvironment(tx, p.Key, env.Name, g.Name, groupProject.Role); err != nil {
+				return sdk.WrapError(err, "AddGroupInProject: Cannot set role of group %s on environment %s", g.Name, env.Name)
+			}
 		}
 	}
 
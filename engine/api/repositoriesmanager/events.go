@@ -1,8 +1,10 @@
 package repositoriesmanager
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 	"github.com/mitchellh/mapstructure"
@@ -29,7 +31,7 @@ func ReceiveEvents(c context.Context, DBFunc func() *gorp.DbMap) {
 
 		db := DBFunc()
 		if db != nil {
-			if err := processEvent(db, e); err != nil {
+			if err := processEvent(db, &e); err != nil {
 				log.Error("ReceiveEvents> err while processing error=%s : %v", err, e)
 				retryEvent(&e, err)
 			}
@@ -48,34 +50,138 @@ func retryEvent(e *sdk.Event, err error) {
 	cache.Enqueue("events_repositoriesmanager", e)
 }
 
-func processEvent(db gorp.SqlExecutor, event sdk.Event) error {
+func processEvent(db gorp.SqlExecutor, event *sdk.Event) error {
 	log.Debug("repositoriesmanager>processEvent> receive: type:%s all: %+v", event.EventType, event)
 
-	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
+	var projectKey, rmName string
+	var eventNR *sdk.EventWorkflowNodeRun
+	switch event.EventType {
+	case fmt.Sprintf("%T", sdk.EventPipelineBuild{}):
+		var eventpb sdk.EventPipelineBuild
+		if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+			log.Error("Error during consumption: %s", err)
+			return err
+		}
+		projectKey, rmName = eventpb.ProjectKey, eventpb.RepositoryManagerName
+	case fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}):
+		eventNR = new(sdk.EventWorkflowNodeRun)
+		if err := mapstructure.Decode(event.Payload, eventNR); err != nil {
+			log.Error("Error during consumption: %s", err)
+			return err
+		}
+		projectKey, rmName = eventNR.ProjectKey, eventNR.RepositoryManagerName
+	default:
 		return nil
 	}
 
-	var eventpb sdk.EventPipelineBuild
-	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
-		log.Error("Error during consumption: %s", err)
-		return err
-	}
-
-	if eventpb.RepositoryManagerName == "" {
+	if rmName == "" {
 		return nil
 	}
 
 	log.Debug("repositoriesmanager>processEvent> event:%+v", event)
 
-	c, erra := AuthorizedClient(db, eventpb.ProjectKey, eventpb.RepositoryManagerName)
+	c, erra := AuthorizedClient(db, projectKey, rmName)
 	if erra != nil {
-		return fmt.Errorf("repositoriesmanager>processEvent> AuthorizedClient (%s, %s) > err:%s", eventpb.ProjectKey, eventpb.RepositoryManagerName, erra)
+		return fmt.Errorf("repositoriesmanager>processEvent> AuthorizedClient (%s, %s) > err:%s", projectKey, rmName, erra)
 	}
 
-	if err := c.SetStatus(event); err != nil {
-		retryEvent(&event, err)
-		return fmt.Errorf("repositoriesmanager>processEvent> SetStatus > err:%s", err)
+	var comment bool
+	if eventNR != nil && pullRequestCommentNeeded(eventNR) {
+		rm, err := LoadForProject(db, projectKey, rmName)
+		if err != nil {
+			return fmt.Errorf("repositoriesmanager>processEvent> LoadForProject (%s, %s) > err:%s", projectKey, rmName, err)
+		}
+		comment = pullRequestCommentEnabled(rm.Type)
+	}
+
+	return deliverEvent(c, event, eventNR, comment)
+}
+
+//deliverEvent sets the commit status of the event, then comments the pull requests of the workflow node run.
+//The delivery of the status is kept in the event, so that a retried event still comments the pull requests
+//but doesn't set the status twice
+func deliverEvent(c sdk.RepositoriesManagerClient, event *sdk.Event, eventNR *sdk.EventWorkflowNodeRun, comment bool) error {
+	if !event.StatusSent {
+		if err := c.SetStatus(*event); err != nil {
+			return fmt.Errorf("repositoriesmanager>processEvent> SetStatus > err:%s", err)
+		}
+		event.StatusSent = true
+	}
+
+	if eventNR == nil || !comment {
+		return nil
+	}
+
+	if err := commentPullRequestsOfHash(c, eventNR); err != nil {
+		return fmt.Errorf("repositoriesmanager>processEvent> Unable to comment pull requests > err:%s", err)
 	}
 
 	return nil
 }
+
+//pullRequestCommentNeeded returns true if the result of the workflow node run has to be commented on its pull requests
+func pullRequestCommentNeeded(e *sdk.EventWorkflowNodeRun) bool {
+	if e.Status != sdk.StatusSuccess && e.Status != sdk.StatusFail {
+		return false
+	}
+	return e.Hash != "" && e.RepositoryFullname != ""
+}
+
+//pullRequestCommentEnabled returns true if the results have to be commented on the pull requests of this type of repositories manager
+func pullRequestCommentEnabled(rmType sdk.RepositoriesManagerType) bool {
+	switch rmType {
+	case sdk.Github:
+		return options.EnableGithubPullRequestComment
+	case sdk.Stash:
+		return options.EnableStashPullRequestComment
+	case sdk.Gitlab:
+		return options.EnableGitlabPullRequestComment
+	case sdk.Gitea:
+		return options.EnableGiteaPullRequestComment
+	default:
+		return false
+	}
+}
+
+//commentPullRequestsOfHash comments the opened pull requests whose head is the commit of the event
+func commentPullRequestsOfHash(c sdk.RepositoriesManagerClient, e *sdk.EventWorkflowNodeRun) error {
+	prs, err := c.PullRequests(e.RepositoryFullname)
+	if err != nil {
+		return err
+	}
+
+	for _, pr := range prs {
+		if pr.Head.Commit.Hash != e.Hash {
+			continue
+		}
+		if err := c.PullRequestComment(e.RepositoryFullname, pr.ID, pullRequestCommentText(e)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func pullRequestCommentText(e *sdk.EventWorkflowNodeRun) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "CDS workflow %s - pipeline %s #%d.%d: %s\n", e.WorkflowName, e.PipelineName, e.Number, e.SubNumber, e.Status.String())
+	if e.TestsTotal > 0 {
+		fmt.Fprintf(&buf, "\nTests: %d total, %d passed, %d failed, %d skipped\n", e.TestsTotal, e.TestsOK, e.TestsKO, e.TestsSkipped)
+	}
+	if e.CoverageLines != "" {
+		fmt.Fprintf(&buf, "\nCoverage: %s%% of lines%s\n", e.CoverageLines, coverageDeltaText(e.CoverageLinesDelta))
+	}
+	fmt.Fprintf(&buf, "\n%s/project/%s/workflow/%s/run/%d/node/%d", options.UIBaseURL, e.ProjectKey, e.WorkflowName, e.Number, e.ID)
+	return buf.String()
+}
+
+//coverageDeltaText returns the coverage delta as " (+1.5)"
+func coverageDeltaText(delta string) string {
+	if delta == "" {
+		return ""
+	}
+	if !strings.HasPrefix(delta, "-") {
+		delta = "+" + delta
+	}
+	return fmt.Sprintf(" (%s)", delta)
+}
@@ -0,0 +1,151 @@
+package repositoriesmanager
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/repositoriesmanager/repogitlab"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestPullRequestCommentEnabled(t *testing.T) {
+	defer func(o InitializeOpts) { options = o }(options)
+	options = InitializeOpts{EnableGitlabPullRequestComment: true}
+
+	assert.True(t, pullRequestCommentEnabled(sdk.Gitlab))
+	assert.False(t, pullRequestCommentEnabled(sdk.Github))
+	assert.False(t, pullRequestCommentEnabled(sdk.Stash))
+	assert.False(t, pullRequestCommentEnabled(sdk.Gitea))
+}
+
+func TestCommentPullRequestsOfHash(t *testing.T) {
+	defer func(o InitializeOpts) { options = o }(options)
+	options = InitializeOpts{UIBaseURL: "https://cds.example.com"}
+
+	type comment struct {
+		path string
+		body []byte
+	}
+	comments := []comment{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`[{"iid": 3, "sha": "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b"}, {"iid": 4, "sha": "a0f9e8d7c6b0b1c4f2e6a3d9c8b7a6f5e4d3c2b1"}, {"iid": 5, "sha": "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b"}]`))
+		case http.MethodPost:
+			body, _ := ioutil.ReadAll(r.Body)
+			comments = append(comments, comment{path: r.URL.EscapedPath(), body: body})
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := repogitlab.New(ts.URL, "client-id", "client-secret", "").GetAuthorized("my-token", "")
+	assert.NoError(t, err)
+
+	e := &sdk.EventWorkflowNodeRun{
+		ID:                 42,
+		Number:             12,
+		SubNumber:          1,
+		Status:             sdk.StatusFail,
+		ProjectKey:         "DEMO",
+		WorkflowName:       "my-workflow",
+		PipelineName:       "build",
+		RepositoryFullname: "cds/demo",
+		Hash:               "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b",
+		TestsTotal:         10,
+		TestsOK:            8,
+		TestsKO:            1,
+		TestsSkipped:       1,
+	}
+	assert.NoError(t, commentPullRequestsOfHash(c, e))
+
+	//Only the pull requests whose head is the built commit are commented
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "/api/v4/projects/cds%2Fdemo/merge_requests/3/notes", comments[0].path)
+	assert.Equal(t, "/api/v4/projects/cds%2Fdemo/merge_requests/5/notes", comments[1].path)
+
+	note := repogitlab.CreateNote{}
+	assert.NoError(t, json.Unmarshal(comments[0].body, &note))
+	assert.Equal(t, "CDS workflow my-workflow - pipeline build #12.1: Fail\n\n"+
+		"Tests: 10 total, 8 passed, 1 failed, 1 skipped\n\n"+
+		"https://cds.example.com/project/DEMO/workflow/my-workflow/run/12/node/42", note.Body)
+}
+
+func TestDeliverEventRetries(t *testing.T) {
+	statuses, comments := 0, 0
+	failStatus, failComment := true, true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`[{"iid": 3, "sha": "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b"}]`))
+		case strings.Contains(r.URL.Path, "/statuses/"):
+			if failStatus {
+				failStatus = false
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			statuses++
+			w.WriteHeader(http.StatusCreated)
+		default:
+			if failComment {
+				failComment = false
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			comments++
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := repogitlab.New(ts.URL, "client-id", "client-secret", "").GetAuthorized("my-token", "")
+	assert.NoError(t, err)
+
+	eventNR := &sdk.EventWorkflowNodeRun{
+		ID:                 42,
+		Number:             12,
+		Status:             sdk.StatusSuccess,
+		ProjectKey:         "DEMO",
+		WorkflowName:       "my-workflow",
+		PipelineName:       "build",
+		RepositoryFullname: "cds/demo",
+		BranchName:         "master",
+		Hash:               "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b",
+	}
+	e := &sdk.Event{
+		EventType: "sdk.EventWorkflowNodeRun",
+		Payload: map[string]interface{}{
+			"ID":                 eventNR.ID,
+			"Number":             eventNR.Number,
+			"Status":             eventNR.Status,
+			"ProjectKey":         eventNR.ProjectKey,
+			"WorkflowName":       eventNR.WorkflowName,
+			"PipelineName":       eventNR.PipelineName,
+			"RepositoryFullname": eventNR.RepositoryFullname,
+			"BranchName":         eventNR.BranchName,
+			"Hash":               eventNR.Hash,
+		},
+	}
+
+	//The status is not set: nothing is commented
+	assert.Error(t, deliverEvent(c, e, eventNR, true))
+	assert.False(t, e.StatusSent)
+	assert.Equal(t, 0, comments)
+
+	//The status is set on the retry, the pull request is commented even if the comment fails this time
+	assert.Error(t, deliverEvent(c, e, eventNR, true))
+	assert.True(t, e.StatusSent)
+	assert.Equal(t, 1, statuses)
+	assert.Equal(t, 0, comments)
+
+	//The next retry only comments the pull request
+	assert.NoError(t, deliverEvent(c, e, eventNR, true))
+	assert.Equal(t, 1, statuses)
+	assert.Equal(t, 1, comments)
+}
@@ -0,0 +1,43 @@
+package repositoriesmanager
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Policies applied to pull requests coming from forks
+const (
+	//ForkPolicyWithholdSecrets builds fork pull requests without any secret nor private key. It's the default policy
+	ForkPolicyWithholdSecrets = "withhold-secrets"
+	//ForkPolicySkip does not build fork pull requests
+	ForkPolicySkip = "skip"
+	//ForkPolicyTrust builds fork pull requests as any other branch
+	ForkPolicyTrust = "trust"
+)
+
+//forkPolicy returns the configured fork policy, ForkPolicyWithholdSecrets if none is set
+func forkPolicy() string {
+	switch options.ForkPullRequestPolicy {
+	case ForkPolicySkip, ForkPolicyTrust:
+		return options.ForkPullRequestPolicy
+	default:
+		return ForkPolicyWithholdSecrets
+	}
+}
+
+//TriggerPullRequest returns true if the pull request event has to trigger a build
+func TriggerPullRequest(e sdk.VCSPullRequestEvent) bool {
+	if e.Action != "opened" {
+		return false
+	}
+	if e.Fork && forkPolicy() == ForkPolicySkip {
+		log.Info("TriggerPullRequest> Skipping pull request %d from fork: %s", e.ID, e.URL)
+		return false
+	}
+	return true
+}
+
+//WithholdSecrets returns true if secrets must not be sent to a job with these parameters
+func WithholdSecrets(params []sdk.Parameter) bool {
+	return sdk.IsForkPullRequest(params) && forkPolicy() != ForkPolicyTrust
+}
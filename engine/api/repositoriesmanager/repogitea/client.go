@@ -0,0 +1,254 @@
+package repogitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GiteaClient is a gitea wrapper for CDS RepositoriesManagerClient interface
+type GiteaClient struct {
+	URL              string
+	Token            string
+	DisableSetStatus bool
+	DisableStatusURL bool
+}
+
+func toVCSRepo(r Repository) sdk.VCSRepo {
+	return sdk.VCSRepo{
+		ID:           strconv.Itoa(r.ID),
+		Name:         r.Name,
+		Slug:         r.Name,
+		Fullname:     r.FullName,
+		URL:          r.HTMLURL,
+		HTTPCloneURL: r.CloneURL,
+		SSHCloneURL:  r.SSHURL,
+	}
+}
+
+// Repos list repositories of the authenticated user
+// https://try.gitea.io/api/swagger#/user/userCurrentListRepos
+func (g *GiteaClient) Repos() ([]sdk.VCSRepo, error) {
+	var repos []Repository
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(pageLimit))
+
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+		nextRepos := []Repository{}
+		if err := g.get("/user/repos", params, &nextRepos); err != nil {
+			log.Warning("GiteaClient.Repos> Error %s", err)
+			return nil, err
+		}
+		repos = append(repos, nextRepos...)
+		if len(nextRepos) < pageLimit {
+			break
+		}
+	}
+
+	res := make([]sdk.VCSRepo, 0, len(repos))
+	for _, r := range repos {
+		res = append(res, toVCSRepo(r))
+	}
+	return res, nil
+}
+
+// RepoByFullname Get only one repository
+// https://try.gitea.io/api/swagger#/repository/repoGet
+func (g *GiteaClient) RepoByFullname(fullname string) (sdk.VCSRepo, error) {
+	r, err := g.repo(fullname)
+	if err != nil {
+		return sdk.VCSRepo{}, err
+	}
+	return toVCSRepo(r), nil
+}
+
+func (g *GiteaClient) repo(fullname string) (Repository, error) {
+	r := Repository{}
+	if err := g.get(repoPath(fullname), nil, &r); err != nil {
+		log.Warning("GiteaClient.repo> Error %s", err)
+		return r, sdk.NewError(sdk.ErrRepoNotFound, err)
+	}
+	return r, nil
+}
+
+// Branches returns list of branches for a repository
+// https://try.gitea.io/api/swagger#/repository/repoListBranches
+func (g *GiteaClient) Branches(fullname string) ([]sdk.VCSBranch, error) {
+	r, err := g.repo(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := []Branch{}
+	if err := g.get(repoPath(fullname)+"/branches", nil, &branches); err != nil {
+		log.Warning("GiteaClient.Branches> Error %s", err)
+		return nil, err
+	}
+
+	res := make([]sdk.VCSBranch, 0, len(branches))
+	for _, b := range branches {
+		res = append(res, toVCSBranch(b, r.DefaultBranch))
+	}
+	return res, nil
+}
+
+// Branch returns only detail of a branch
+// https://try.gitea.io/api/swagger#/repository/repoGetBranch
+func (g *GiteaClient) Branch(fullname, branchName string) (*sdk.VCSBranch, error) {
+	r, err := g.repo(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	b := Branch{}
+	if err := g.get(repoPath(fullname)+"/branches/"+url.PathEscape(branchName), nil, &b); err != nil {
+		log.Warning("GiteaClient.Branch> Cannot find branch %s: %s", branchName, err)
+		return nil, err
+	}
+
+	branch := toVCSBranch(b, r.DefaultBranch)
+	return &branch, nil
+}
+
+func toVCSBranch(b Branch, defaultBranch string) sdk.VCSBranch {
+	return sdk.VCSBranch{
+		ID:           b.Name,
+		DisplayID:    b.Name,
+		LatestCommit: b.Commit.ID,
+		Default:      b.Name == defaultBranch,
+	}
+}
+
+func toVCSCommit(c Commit) sdk.VCSCommit {
+	commit := sdk.VCSCommit{
+		Hash:      c.SHA,
+		Message:   c.Commit.Message,
+		Timestamp: c.Commit.Author.Date.Unix() * 1000,
+		URL:       c.HTMLURL,
+		Author: sdk.VCSAuthor{
+			Name:        c.Commit.Author.Name,
+			DisplayName: c.Commit.Author.Name,
+			Email:       c.Commit.Author.Email,
+		},
+	}
+	//The git author is linked to a gitea user
+	if c.Author != nil {
+		commit.Author.Name = c.Author.Login
+		commit.Author.Avatar = c.Author.AvatarURL
+	}
+	return commit
+}
+
+// Commits returns the commits list on a branch between a commit SHA (since) until another commit SHA (until).
+// If since is empty, only the latest commit is returned
+// https://try.gitea.io/api/swagger#/repository/repoGetAllCommits
+func (g *GiteaClient) Commits(repo, branch, since, until string) ([]sdk.VCSCommit, error) {
+	log.Debug("GiteaClient.Commits> Looking for commits on repo %s since = %s until = %s", repo, since, until)
+
+	ref := branch
+	if until != "" {
+		ref = until
+	}
+
+	params := url.Values{}
+	params.Set("sha", ref)
+	params.Set("limit", strconv.Itoa(pageLimit))
+
+	commits := []sdk.VCSCommit{}
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+		nextCommits := []Commit{}
+		if err := g.get(repoPath(repo)+"/commits", params, &nextCommits); err != nil {
+			log.Warning("GiteaClient.Commits> Error %s", err)
+			return nil, err
+		}
+
+		for _, c := range nextCommits {
+			if c.SHA == since {
+				return commits, nil
+			}
+			commits = append(commits, toVCSCommit(c))
+			if since == "" {
+				return commits, nil
+			}
+		}
+		if len(nextCommits) < pageLimit {
+			break
+		}
+	}
+
+	return commits, nil
+}
+
+// Commit Get a single commit
+// https://try.gitea.io/api/swagger#/repository/repoGetSingleCommit
+func (g *GiteaClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
+	c := Commit{}
+	if err := g.get(repoPath(repo)+"/git/commits/"+hash, nil, &c); err != nil {
+		log.Warning("GiteaClient.Commit> Error %s", err)
+		return sdk.VCSCommit{}, err
+	}
+	return toVCSCommit(c), nil
+}
+
+// CreateHook adds a hook on push, delete and pull request events to a repository
+// https://try.gitea.io/api/swagger#/repository/repoCreateHook
+func (g *GiteaClient) CreateHook(repo, hookURL string) error {
+	hookType, err := g.hookType()
+	if err != nil {
+		return fmt.Errorf("GiteaClient.CreateHook> Unable to get the version of the server: %s", err)
+	}
+	h := Hook{
+		Type: hookType,
+		Config: map[string]string{
+			"url":          hookURL,
+			"content_type": "json",
+			"secret":       hookSecret,
+		},
+		Events: []string{"push", "delete", "pull_request"},
+		Active: true,
+	}
+	if err := g.post(repoPath(repo)+"/hooks", h, nil); err != nil {
+		return fmt.Errorf("GiteaClient.CreateHook> Unable to create hook on %s: %s", repo, err)
+	}
+	return nil
+}
+
+//hookType returns the type of the hooks of the server: gitea, or gogs on Gogs servers which don't implement the
+//version API of Gitea
+func (g *GiteaClient) hookType() (string, error) {
+	v := struct {
+		Version string `json:"version"`
+	}{}
+	if err := g.get("/version", nil, &v); err != nil {
+		if e, ok := err.(Error); ok && e.Status == http.StatusNotFound {
+			return "gogs", nil
+		}
+		return "", err
+	}
+	return "gitea", nil
+}
+
+// DeleteHook removes the hook of a repository which matches the url
+// https://try.gitea.io/api/swagger#/repository/repoDeleteHook
+func (g *GiteaClient) DeleteHook(repo, hookURL string) error {
+	hooks := []Hook{}
+	if err := g.get(repoPath(repo)+"/hooks", nil, &hooks); err != nil {
+		return fmt.Errorf("GiteaClient.DeleteHook> Unable to list hooks on %s: %s", repo, err)
+	}
+
+	for _, h := range hooks {
+		if h.Config["url"] != hookURL {
+			continue
+		}
+		if err := g.delete(fmt.Sprintf("%s/hooks/%d", repoPath(repo), h.ID)); err != nil {
+			return fmt.Errorf("GiteaClient.DeleteHook> Unable to delete hook %d on %s: %s", h.ID, repo, err)
+		}
+	}
+	return nil
+}
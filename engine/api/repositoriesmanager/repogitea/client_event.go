@@ -0,0 +1,159 @@
+package repogitea
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//GetEvents is not supported by Gitea which has no API for repositories events, use webhooks instead
+func (g *GiteaClient) GetEvents(fullname string, dateRef time.Time) ([]interface{}, time.Duration, error) {
+	return nil, 0, fmt.Errorf("Polling is not supported on gitea, use hooks")
+}
+
+//PushEvents is not supported by Gitea
+func (g *GiteaClient) PushEvents(string, []interface{}) ([]sdk.VCSPushEvent, error) {
+	return nil, fmt.Errorf("Polling is not supported on gitea, use hooks")
+}
+
+//CreateEvents is not supported by Gitea
+func (g *GiteaClient) CreateEvents(string, []interface{}) ([]sdk.VCSCreateEvent, error) {
+	return nil, fmt.Errorf("Polling is not supported on gitea, use hooks")
+}
+
+//DeleteEvents is not supported by Gitea
+func (g *GiteaClient) DeleteEvents(string, []interface{}) ([]sdk.VCSDeleteEvent, error) {
+	return nil, fmt.Errorf("Polling is not supported on gitea, use hooks")
+}
+
+//PullRequestEvents is not supported by Gitea
+func (g *GiteaClient) PullRequestEvents(string, []interface{}) ([]sdk.VCSPullRequestEvent, error) {
+	return nil, fmt.Errorf("Polling is not supported on gitea, use hooks")
+}
+
+type statusData struct {
+	status       string
+	desc         string
+	url          string
+	context      string
+	repoFullName string
+	hash         string
+}
+
+//SetStatus sets a commit status for a pipeline build or a workflow node run
+//https://try.gitea.io/api/swagger#/repository/repoCreateStatus
+func (g *GiteaClient) SetStatus(event sdk.Event) error {
+	log.Debug("gitea.SetStatus> receive: type:%s all: %+v", event.EventType, event)
+
+	var data statusData
+	var err error
+	switch event.EventType {
+	case fmt.Sprintf("%T", sdk.EventPipelineBuild{}):
+		data, err = processEventPipelineBuild(event)
+	case fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}):
+		data, err = processEventWorkflowNodeRun(event)
+	default:
+		return nil
+	}
+
+	if g.DisableSetStatus {
+		log.Warning("⚠ Gitea statuses are disabled")
+		return nil
+	}
+
+	if err != nil {
+		log.Warning("Error during consumption: %s", err)
+		return err
+	}
+
+	//Nothing to send
+	if data.status == "" || data.hash == "" || data.repoFullName == "" {
+		return nil
+	}
+
+	//CDS can avoid sending gitea target url in status, if it's disable
+	if g.DisableStatusURL {
+		data.url = ""
+	}
+
+	status := CreateStatus{
+		State:       data.status,
+		TargetURL:   data.url,
+		Description: data.desc,
+		Context:     data.context,
+	}
+
+	path := fmt.Sprintf("%s/statuses/%s", repoPath(data.repoFullName), data.hash)
+	if err := g.post(path, status, nil); err != nil {
+		log.Warning("SetStatus> Unable to create status on gitea: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+func processEventPipelineBuild(event sdk.Event) (statusData, error) {
+	data := statusData{}
+	var eventpb sdk.EventPipelineBuild
+	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+		return data, err
+	}
+
+	data.status = getGiteaStateFromStatus(eventpb.Status)
+	data.desc = fmt.Sprintf("Pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	data.url = fmt.Sprintf("%s/project/%s/application/%s/pipeline/%s/build/%d?envName=%s",
+		uiURL,
+		eventpb.ProjectKey,
+		eventpb.ApplicationName,
+		eventpb.PipelineName,
+		eventpb.BuildNumber,
+		url.QueryEscape(eventpb.EnvironmentName),
+	)
+	data.context = fmt.Sprintf("continuous-delivery/CDS/%s", eventpb.PipelineName)
+	data.repoFullName = eventpb.RepositoryFullname
+	data.hash = eventpb.Hash
+	return data, nil
+}
+
+func processEventWorkflowNodeRun(event sdk.Event) (statusData, error) {
+	data := statusData{}
+	var eventNR sdk.EventWorkflowNodeRun
+	if err := mapstructure.Decode(event.Payload, &eventNR); err != nil {
+		return data, err
+	}
+
+	data.status = getGiteaStateFromStatus(eventNR.Status)
+	data.desc = fmt.Sprintf("Workflow %s - pipeline %s: %s", eventNR.WorkflowName, eventNR.PipelineName, eventNR.Status.String())
+	data.url = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d",
+		uiURL,
+		eventNR.ProjectKey,
+		eventNR.WorkflowName,
+		eventNR.Number,
+		eventNR.ID,
+	)
+	data.context = fmt.Sprintf("continuous-delivery/CDS/%s/%s", eventNR.WorkflowName, eventNR.PipelineName)
+	data.repoFullName = eventNR.RepositoryFullname
+	data.hash = eventNR.Hash
+	return data, nil
+}
+
+//getGiteaStateFromStatus returns the gitea commit state, empty if the status should not be sent
+func getGiteaStateFromStatus(status sdk.Status) string {
+	switch status {
+	case sdk.StatusWaiting, sdk.StatusBuilding:
+		return "pending"
+	case sdk.StatusSuccess:
+		return "success"
+	case sdk.StatusFail:
+		return "failure"
+	case sdk.StatusDisabled, sdk.StatusSkipped:
+		return "warning"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,67 @@
+package repogitea
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// PullRequests returns the list of opened pull requests on a repository
+// https://try.gitea.io/api/swagger#/repository/repoListPullRequests
+func (g *GiteaClient) PullRequests(fullname string) ([]sdk.VCSPullRequest, error) {
+	var prs []PullRequest
+	params := url.Values{}
+	params.Set("state", "open")
+	params.Set("limit", strconv.Itoa(pageLimit))
+
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+		nextPRs := []PullRequest{}
+		if err := g.get(repoPath(fullname)+"/pulls", params, &nextPRs); err != nil {
+			log.Warning("GiteaClient.PullRequests> Error %s", err)
+			return nil, err
+		}
+		prs = append(prs, nextPRs...)
+		if len(nextPRs) < pageLimit {
+			break
+		}
+	}
+
+	res := make([]sdk.VCSPullRequest, 0, len(prs))
+	for _, pr := range prs {
+		res = append(res, toVCSPullRequest(pr))
+	}
+	return res, nil
+}
+
+func toVCSPullRequest(pr PullRequest) sdk.VCSPullRequest {
+	return sdk.VCSPullRequest{
+		ID:    pr.Number,
+		Title: pr.Title,
+		URL:   pr.HTMLURL,
+		User: sdk.VCSAuthor{
+			Name:        pr.User.Login,
+			DisplayName: pr.User.FullName,
+			Email:       pr.User.Email,
+			Avatar:      pr.User.AvatarURL,
+		},
+		Head: sdk.VCSPushEvent{
+			Branch: sdk.VCSBranch{ID: pr.Head.Ref, DisplayID: pr.Head.Ref, LatestCommit: pr.Head.SHA},
+			Commit: sdk.VCSCommit{Hash: pr.Head.SHA},
+		},
+		Base: sdk.VCSPushEvent{
+			Branch: sdk.VCSBranch{ID: pr.Base.Ref, DisplayID: pr.Base.Ref, LatestCommit: pr.Base.SHA},
+			Commit: sdk.VCSCommit{Hash: pr.Base.SHA},
+		},
+	}
+}
+
+// PullRequestComment adds a comment on a pull request
+// https://try.gitea.io/api/swagger#/issue/issueCreateComment
+func (g *GiteaClient) PullRequestComment(fullname string, id int, text string) error {
+	path := fmt.Sprintf("%s/issues/%d/comments", repoPath(fullname), id)
+	return g.post(path, CreateComment{Body: text}, nil)
+}
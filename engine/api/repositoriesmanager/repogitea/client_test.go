@@ -0,0 +1,257 @@
+package repogitea
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type fixture struct {
+	Request struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Query  string `json:"query"`
+	} `json:"request"`
+	Response struct {
+		Status int             `json:"status"`
+		Body   json.RawMessage `json:"body"`
+	} `json:"response"`
+}
+
+type receivedRequest struct {
+	method, path string
+	body         []byte
+}
+
+//newFixtureServer starts a server which replays the recorded Gitea API responses of fixtures/api.json
+func newFixtureServer(t *testing.T) (*httptest.Server, *[]receivedRequest) {
+	b, err := ioutil.ReadFile("fixtures/api.json")
+	if err != nil {
+		t.Fatalf("Unable to read fixtures: %s", err)
+	}
+	fixtures := []fixture{}
+	if err := json.Unmarshal(b, &fixtures); err != nil {
+		t.Fatalf("Unable to parse fixtures: %s", err)
+	}
+
+	received := []receivedRequest{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, receivedRequest{method: r.Method, path: r.URL.Path, body: body})
+
+		for _, f := range fixtures {
+			if f.Request.Method == r.Method && f.Request.Path == r.URL.Path && f.Request.Query == r.URL.Query().Encode() {
+				w.WriteHeader(f.Response.Status)
+				w.Write(f.Response.Body)
+				return
+			}
+		}
+		t.Errorf("No fixture for %s %s?%s", r.Method, r.URL.Path, r.URL.RawQuery)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	return ts, &received
+}
+
+func newTestClient(t *testing.T) (*GiteaClient, *[]receivedRequest, func()) {
+	ts, received := newFixtureServer(t)
+	c, err := New(ts.URL, "", "", "").GetAuthorized("my-token", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*GiteaClient), received, ts.Close
+}
+
+func TestAuthorizeToken(t *testing.T) {
+	ts, _ := newFixtureServer(t)
+	defer ts.Close()
+
+	consumer := New(ts.URL, "", "", "")
+	token, url, err := consumer.AuthorizeRedirect()
+	assert.NoError(t, err)
+	assert.Equal(t, ts.URL+"/user/settings/applications", url)
+
+	accessToken, _, err := consumer.AuthorizeToken(token, "my-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-token", accessToken)
+
+	_, _, err = consumer.AuthorizeToken(token, "bad-token")
+	assert.Error(t, err)
+}
+
+func TestRepos(t *testing.T) {
+	c, _, done := newTestClient(t)
+	defer done()
+
+	repos, err := c.Repos()
+	assert.NoError(t, err)
+	assert.Len(t, repos, 2)
+	assert.Equal(t, "cds/demo", repos[0].Fullname)
+	assert.Equal(t, "git@gitea.example.com:cds/demo.git", repos[0].SSHCloneURL)
+
+	repo, err := c.RepoByFullname("cds/demo")
+	assert.NoError(t, err)
+	assert.Equal(t, "12", repo.ID)
+	assert.Equal(t, "https://gitea.example.com/cds/demo.git", repo.HTTPCloneURL)
+
+	_, err = c.RepoByFullname("cds/unknown")
+	assert.Error(t, err)
+}
+
+func TestBranches(t *testing.T) {
+	c, _, done := newTestClient(t)
+	defer done()
+
+	branches, err := c.Branches("cds/demo")
+	assert.NoError(t, err)
+	assert.Len(t, branches, 2)
+	assert.True(t, branches[0].Default)
+	assert.False(t, branches[1].Default)
+
+	branch, err := c.Branch("cds/demo", "feat/login")
+	assert.NoError(t, err)
+	assert.Equal(t, "6f4e2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f", branch.LatestCommit)
+}
+
+func TestCommits(t *testing.T) {
+	c, _, done := newTestClient(t)
+	defer done()
+
+	commits, err := c.Commits("cds/demo", "master", "1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e", "")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "jdoe", commits[0].Author.Name)
+	assert.Equal(t, "External", commits[1].Author.Name)
+
+	commits, err = c.Commits("cds/demo", "master", "", "")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+
+	commit, err := c.Commit("cds/demo", "9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b")
+	assert.NoError(t, err)
+	assert.Equal(t, "Fix typo", commit.Message)
+	assert.Equal(t, "ext@example.org", commit.Author.Email)
+}
+
+func TestHooks(t *testing.T) {
+	Init("", "", "my-hook-secret")
+	defer Init("", "", "")
+	c, received, done := newTestClient(t)
+	defer done()
+
+	assert.NoError(t, c.CreateHook("cds/demo", "https://cds.example.com/hook?uid=abc"))
+	assert.Len(t, *received, 2)
+	assert.Equal(t, "/api/v1/version", (*received)[0].path)
+	h := Hook{}
+	assert.NoError(t, json.Unmarshal((*received)[1].body, &h))
+	assert.Equal(t, "gitea", h.Type)
+	assert.Equal(t, "https://cds.example.com/hook?uid=abc", h.Config["url"])
+	assert.Equal(t, "my-hook-secret", h.Config["secret"])
+	assert.Equal(t, []string{"push", "delete", "pull_request"}, h.Events)
+
+	assert.NoError(t, c.DeleteHook("cds/demo", "https://cds.example.com/hook?uid=abc"))
+	last := (*received)[len(*received)-1]
+	assert.Equal(t, http.MethodDelete, last.method)
+	assert.Equal(t, "/api/v1/repos/cds/demo/hooks/7", last.path)
+}
+
+func TestCreateHookGogs(t *testing.T) {
+	var h Hook
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/cds/demo/hooks":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&h))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			//Gogs doesn't implement the version API
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, "", "", "").GetAuthorized("my-token", "")
+	assert.NoError(t, err)
+	assert.NoError(t, c.CreateHook("cds/demo", "https://cds.example.com/hook?uid=abc"))
+	assert.Equal(t, "gogs", h.Type)
+	assert.Equal(t, "https://cds.example.com/hook?uid=abc", h.Config["url"])
+}
+
+func TestSetStatus(t *testing.T) {
+	c, received, done := newTestClient(t)
+	defer done()
+
+	Init("https://cds-api.example.com", "https://cds.example.com", "")
+	e := sdk.Event{
+		EventType: "sdk.EventWorkflowNodeRun",
+		Payload: map[string]interface{}{
+			"ID":                 int64(42),
+			"Number":             int64(12),
+			"Status":             sdk.StatusSuccess,
+			"ProjectKey":         "DEMO",
+			"WorkflowName":       "my-workflow",
+			"PipelineName":       "build",
+			"RepositoryFullname": "cds/demo",
+			"Hash":               "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b",
+		},
+	}
+	assert.NoError(t, c.SetStatus(e))
+	assert.Len(t, *received, 1)
+
+	status := CreateStatus{}
+	assert.NoError(t, json.Unmarshal((*received)[0].body, &status))
+	assert.Equal(t, "success", status.State)
+	assert.Equal(t, "continuous-delivery/CDS/my-workflow/build", status.Context)
+	assert.Equal(t, "https://cds.example.com/project/DEMO/workflow/my-workflow/run/12/node/42", status.TargetURL)
+
+	c.DisableSetStatus = true
+	assert.NoError(t, c.SetStatus(e))
+	assert.Len(t, *received, 1)
+}
+
+func TestPullRequests(t *testing.T) {
+	c, received, done := newTestClient(t)
+	defer done()
+
+	prs, err := c.PullRequests("cds/demo")
+	assert.NoError(t, err)
+	assert.Len(t, prs, 1)
+	assert.Equal(t, 4, prs[0].ID)
+	assert.Equal(t, "6f4e2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f", prs[0].Head.Commit.Hash)
+	assert.Equal(t, "master", prs[0].Base.Branch.ID)
+
+	assert.NoError(t, c.PullRequestComment("cds/demo", 4, "CDS workflow my-workflow #12: Success"))
+	assert.Equal(t, "/api/v1/repos/cds/demo/issues/4/comments", (*received)[1].path)
+}
+
+func TestParseHooks(t *testing.T) {
+	b, _ := ioutil.ReadFile("fixtures/hook_push.json")
+	push, err := ParsePushHook(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "feat/login", push.Branch.ID)
+	assert.False(t, push.Branch.Default)
+	assert.Equal(t, "6f4e2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f", push.Commit.Hash)
+	assert.Equal(t, "Add login page\n", push.Commit.Message)
+	assert.Equal(t, "jroe", push.Commit.Author.Name)
+
+	b, _ = ioutil.ReadFile("fixtures/hook_delete.json")
+	del, err := ParseDeleteHook(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "feat/old", del.Branch.ID)
+
+	b, _ = ioutil.ReadFile("fixtures/hook_pull_request.json")
+	pr, err := ParsePullRequestHook(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "opened", pr.Action)
+	assert.Equal(t, "feat/login", pr.Branch.ID)
+	assert.Equal(t, "master", pr.Base.Branch.ID)
+	assert.Equal(t, "jroe", pr.User.Name)
+}
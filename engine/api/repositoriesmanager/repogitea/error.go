@@ -0,0 +1,44 @@
+package repogitea
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+//Error wraps gitea error format
+type Error struct {
+	Status  int    `json:"-"`
+	ID      string `json:"error"`
+	Desc    string `json:"error_description"`
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("(gitea_%d) %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("(gitea_%s) %s", e.ID, e.Desc)
+}
+
+func (e Error) String() string {
+	return e.Error()
+}
+
+//Gitea errors
+var (
+	ErrorUnauthorized = &Error{
+		Status: 401,
+		ID:     "bad_credentials",
+		Desc:   "Bad credentials",
+	}
+)
+
+//ErrorAPI creates a new error
+func ErrorAPI(status int, body []byte) Error {
+	e := Error{}
+	if err := json.Unmarshal(body, &e); err != nil || (e.Message == "" && e.ID == "") {
+		e.Message = string(body)
+	}
+	e.Status = status
+	return e
+}
@@ -0,0 +1,127 @@
+package repogitea
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+//Gitea webhook event types, sent in the X-Gitea-Event header (X-Gogs-Event on Gogs). The HMAC of the payload
+//is sent in the X-Gitea-Signature header (X-Gogs-Signature on Gogs)
+const (
+	HookEventHeader         = "X-Gitea-Event"
+	GogsHookEventHeader     = "X-Gogs-Event"
+	HookSignatureHeader     = "X-Gitea-Signature"
+	GogsHookSignatureHeader = "X-Gogs-Signature"
+	PushHookEvent           = "push"
+	DeleteHookEvent         = "delete"
+	PullRequestHookEvent    = "pull_request"
+)
+
+//CheckHookSignature checks the signature of a received hook, the hex encoded HMAC-SHA256 of the payload with the
+//hook secret. All the hooks are rejected if no secret is configured
+func CheckHookSignature(data []byte, signature string) bool {
+	if hookSecret == "" {
+		return false
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(hookSecret))
+	mac.Write(data)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+//ParsePushHook parses the payload of a push webhook
+func ParsePushHook(data []byte) (*sdk.VCSPushEvent, error) {
+	var h PushHook
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "ParsePushHook> Unable to parse gitea push hook: %s", err)
+	}
+
+	branch := strings.TrimPrefix(h.Ref, "refs/heads/")
+	event := &sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           branch,
+			DisplayID:    branch,
+			LatestCommit: h.After,
+			Default:      branch == h.Repository.DefaultBranch,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: h.After,
+			Author: sdk.VCSAuthor{
+				Name:        h.Pusher.Login,
+				DisplayName: h.Pusher.FullName,
+				Email:       h.Pusher.Email,
+				Avatar:      h.Pusher.AvatarURL,
+			},
+		},
+	}
+
+	//Find the details of the head commit
+	for _, c := range h.Commits {
+		if c.ID == h.After {
+			event.Commit.Message = c.Message
+			event.Commit.URL = c.URL
+			event.Commit.Timestamp = c.Timestamp.Unix() * 1000
+		}
+	}
+
+	return event, nil
+}
+
+//ParseDeleteHook parses the payload of a delete webhook. It returns nil if the deleted reference is not a branch
+func ParseDeleteHook(data []byte) (*sdk.VCSDeleteEvent, error) {
+	var h DeleteHook
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "ParseDeleteHook> Unable to parse gitea delete hook: %s", err)
+	}
+
+	if h.RefType != "branch" {
+		return nil, nil
+	}
+
+	return &sdk.VCSDeleteEvent{
+		Branch: sdk.VCSBranch{
+			ID:        h.Ref,
+			DisplayID: h.Ref,
+		},
+	}, nil
+}
+
+//ParsePullRequestHook parses the payload of a pull request webhook
+func ParsePullRequestHook(data []byte) (*sdk.VCSPullRequestEvent, error) {
+	var h PullRequestHook
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "ParsePullRequestHook> Unable to parse gitea pull request hook: %s", err)
+	}
+
+	var action string
+	switch h.Action {
+	case "opened", "reopened", "synchronized":
+		action = "opened"
+	default:
+		action = h.Action
+	}
+
+	pr := toVCSPullRequest(h.PullRequest)
+	return &sdk.VCSPullRequestEvent{
+		Action: action,
+		ID:     pr.ID,
+		URL:    pr.URL,
+		User:   pr.User,
+		Head:   pr.Head,
+		Base:   pr.Base,
+		Branch: pr.Head.Branch,
+		Ref:    fmt.Sprintf("refs/pull/%d/head", pr.ID),
+		Fork:   h.PullRequest.Head.Repo.ID != h.PullRequest.Base.Repo.ID,
+		//Gitea does not provide a merge ref
+		MergeBase: pr.Base.Branch.ID,
+	}, nil
+}
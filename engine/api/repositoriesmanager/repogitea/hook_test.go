@@ -0,0 +1,24 @@
+package repogitea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHookSignature(t *testing.T) {
+	data := []byte(`{"ref": "refs/heads/master"}`)
+
+	//Without secret, anyone could trigger the workflows
+	Init("", "", "")
+	assert.False(t, CheckHookSignature(data, ""))
+
+	Init("", "", "my-hook-secret")
+	defer Init("", "", "")
+	//echo -n '{"ref": "refs/heads/master"}' | openssl dgst -sha256 -hmac my-hook-secret
+	signature := "4196989b8064c0619b227f5552fba627640f505975f13ecd57fa6d3b3afe7277"
+	assert.True(t, CheckHookSignature(data, signature))
+	assert.False(t, CheckHookSignature([]byte(`{"ref": "refs/heads/evil"}`), signature))
+	assert.False(t, CheckHookSignature(data, ""))
+	assert.False(t, CheckHookSignature(data, "not hex"))
+}
@@ -0,0 +1,131 @@
+package repogitea
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/facebookgo/httpcontrol"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Gitea http var
+var (
+	httpClient = &http.Client{
+		Transport: &httpcontrol.Transport{
+			RequestTimeout: time.Second * 30,
+			MaxTries:       5,
+		},
+	}
+)
+
+//pageLimit is the number of items requested on each page of a Gitea list API
+const pageLimit = 50
+
+//repoPath returns the path of a repository in Gitea API: /repos/:owner/:repo
+func repoPath(fullname string) string {
+	return "/repos/" + fullname
+}
+
+func (g *GiteaConsumer) postForm(path string, data url.Values) (int, []byte, error) {
+	req, err := http.NewRequest(http.MethodPost, g.URL+path, strings.NewReader(data.Encode()))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, err
+	}
+
+	if res.StatusCode >= 400 {
+		return res.StatusCode, resBody, ErrorAPI(res.StatusCode, resBody)
+	}
+
+	return res.StatusCode, resBody, nil
+}
+
+//do sends a request on the Gitea API v1. in is marshalled as the json body of the request and
+//the response is unmarshalled in out
+func (c *GiteaClient) do(method, path string, params url.Values, in interface{}, out interface{}) error {
+	uri := c.URL + "/api/v1" + path
+	if len(params) > 0 {
+		uri += "?" + params.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequest(method, uri, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	//Gitea accepts personal access tokens and oauth2 access tokens with the same scheme
+	req.Header.Set("Authorization", "token "+c.Token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Debug("Gitea API>> Request %s %s", method, req.URL.String())
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized:
+		return ErrorUnauthorized
+	case res.StatusCode >= 400:
+		return ErrorAPI(res.StatusCode, resBody)
+	}
+
+	if out != nil && len(resBody) > 0 {
+		if err := json.Unmarshal(resBody, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *GiteaClient) get(path string, params url.Values, out interface{}) error {
+	return c.do(http.MethodGet, path, params, nil, out)
+}
+
+func (c *GiteaClient) post(path string, in interface{}, out interface{}) error {
+	return c.do(http.MethodPost, path, nil, in, out)
+}
+
+func (c *GiteaClient) delete(path string) error {
+	return c.do(http.MethodDelete, path, nil, nil, nil)
+}
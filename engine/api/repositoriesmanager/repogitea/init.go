@@ -0,0 +1,14 @@
+package repogitea
+
+var (
+	apiURL     string
+	uiURL      string
+	hookSecret string
+)
+
+// Init initializes repogitea package. The hook secret signs the payloads of the hooks created by CDS
+func Init(apiurl, uiurl, hooksecret string) {
+	apiURL = apiurl
+	uiURL = uiurl
+	hookSecret = hooksecret
+}
@@ -0,0 +1,135 @@
+package repogitea
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func generateHash() (string, error) {
+	bs := make([]byte, 64)
+	if _, err := rand.Read(bs); err != nil {
+		log.Error("generateHash: rand.Read failed: %s\n", err)
+		return "", err
+	}
+	return hex.EncodeToString(bs), nil
+}
+
+//GiteaConsumer embeds a gitea consumer. Without client-id, users authenticate with a personal access token,
+//else the oauth2 flow of Gitea is used
+type GiteaConsumer struct {
+	URL                      string `json:"-"`
+	ClientID                 string `json:"client-id,omitempty"`
+	ClientSecret             string `json:"-"`
+	AuthorizationCallbackURL string `json:"-"`
+	DisableSetStatus         bool   `json:"-"`
+	DisableStatusURL         bool   `json:"-"`
+}
+
+//New creates a new GiteaConsumer
+func New(URL, ClientID, ClientSecret, AuthorizationCallbackURL string) *GiteaConsumer {
+	return &GiteaConsumer{
+		URL:                      URL,
+		ClientID:                 ClientID,
+		ClientSecret:             ClientSecret,
+		AuthorizationCallbackURL: AuthorizationCallbackURL,
+	}
+}
+
+//Data returns a serilized version of specific data
+func (g *GiteaConsumer) Data() string {
+	b, _ := json.Marshal(g)
+	return string(b)
+}
+
+//AuthorizeRedirect returns the request token, the Authorize URL
+//With token authentication, the URL is the page where the user can generate a personal access token
+//doc: https://docs.gitea.io/en-us/oauth2-provider/
+func (g *GiteaConsumer) AuthorizeRedirect() (string, string, error) {
+	requestToken, err := generateHash()
+	if err != nil {
+		return "", "", err
+	}
+
+	if g.ClientID == "" {
+		return requestToken, g.URL + "/user/settings/applications", nil
+	}
+
+	val := url.Values{}
+	val.Add("client_id", g.ClientID)
+	val.Add("redirect_uri", g.AuthorizationCallbackURL)
+	val.Add("response_type", "code")
+	val.Add("state", requestToken)
+
+	authorizeURL := fmt.Sprintf("%s/login/oauth/authorize?%s", g.URL, val.Encode())
+
+	return requestToken, authorizeURL, nil
+}
+
+//AuthorizeToken returns the authorized token (and its secret)
+//from the request token and the verifier got on authorize url.
+//With token authentication, the verifier is the personal access token of the user
+func (g *GiteaConsumer) AuthorizeToken(state, code string) (string, string, error) {
+	log.Debug("AuthorizeToken> Gitea send code for state %s", state)
+
+	if g.ClientID == "" {
+		//Check the token is valid
+		c := &GiteaClient{URL: g.URL, Token: code}
+		u := User{}
+		if err := c.get("/user", nil, &u); err != nil {
+			return "", "", err
+		}
+		log.Debug("AuthorizeToken> Gitea token authenticated as %s", u.Login)
+		return code, state, nil
+	}
+
+	params := url.Values{}
+	params.Add("client_id", g.ClientID)
+	params.Add("client_secret", g.ClientSecret)
+	params.Add("code", code)
+	params.Add("grant_type", "authorization_code")
+	params.Add("redirect_uri", g.AuthorizationCallbackURL)
+
+	status, res, err := g.postForm("/login/oauth/access_token", params)
+	if err != nil {
+		return "", "", err
+	}
+
+	giteaResponse := map[string]interface{}{}
+	if err := json.Unmarshal(res, &giteaResponse); err != nil {
+		return "", "", fmt.Errorf("Unable to parse gitea response (%d) %s ", status, string(res))
+	}
+
+	accessToken, ok := giteaResponse["access_token"].(string)
+	if !ok || accessToken == "" {
+		return "", "", fmt.Errorf("No access token in gitea response (%d) %s ", status, string(res))
+	}
+
+	return accessToken, state, nil
+}
+
+//GetAuthorized returns an authorized client
+func (g *GiteaConsumer) GetAuthorized(accessToken, accessTokenSecret string) (sdk.RepositoriesManagerClient, error) {
+	return &GiteaClient{
+		URL:              g.URL,
+		Token:            accessToken,
+		DisableSetStatus: g.DisableSetStatus,
+		DisableStatusURL: g.DisableStatusURL,
+	}, nil
+}
+
+//HooksSupported returns true if the driver technically support hook
+func (g *GiteaConsumer) HooksSupported() bool {
+	return true
+}
+
+//PollingSupported returns true if the driver technically support polling
+//Gitea has no API to list the events of a repository
+func (g *GiteaConsumer) PollingSupported() bool {
+	return false
+}
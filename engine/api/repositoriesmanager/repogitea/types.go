@@ -0,0 +1,141 @@
+package repogitea
+
+import "time"
+
+//User represents a Gitea user
+type User struct {
+	ID        int    `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	Username  string `json:"username"`
+}
+
+//Repository represents a Gitea repository
+type Repository struct {
+	ID            int    `json:"id"`
+	Owner         User   `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	SSHURL        string `json:"ssh_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+//PayloadUser represents the author or committer of a commit in branches and webhooks payloads
+type PayloadUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+//PayloadCommit represents a commit in branches and webhooks payloads
+type PayloadCommit struct {
+	ID        string      `json:"id"`
+	Message   string      `json:"message"`
+	URL       string      `json:"url"`
+	Author    PayloadUser `json:"author"`
+	Committer PayloadUser `json:"committer"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+//Branch represents a Gitea branch
+type Branch struct {
+	Name   string        `json:"name"`
+	Commit PayloadCommit `json:"commit"`
+}
+
+//CommitUser represents the git author of a commit
+type CommitUser struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+//Commit represents a commit returned by commits API
+type Commit struct {
+	SHA     string `json:"sha"`
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string     `json:"message"`
+		Author  CommitUser `json:"author"`
+	} `json:"commit"`
+	Author  *User `json:"author"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+}
+
+//Hook represents a Gitea repository webhook
+type Hook struct {
+	ID     int               `json:"id,omitempty"`
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+//PRBranch represents the head or the base of a pull request
+type PRBranch struct {
+	Label string     `json:"label"`
+	Ref   string     `json:"ref"`
+	SHA   string     `json:"sha"`
+	Repo  Repository `json:"repo"`
+}
+
+//PullRequest represents a Gitea pull request
+type PullRequest struct {
+	ID      int      `json:"id"`
+	Number  int      `json:"number"`
+	Title   string   `json:"title"`
+	State   string   `json:"state"`
+	HTMLURL string   `json:"html_url"`
+	User    User     `json:"user"`
+	Head    PRBranch `json:"head"`
+	Base    PRBranch `json:"base"`
+}
+
+//CreateStatus represents the body of a commit status creation
+type CreateStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+//CreateComment represents the body of a comment creation on an issue or a pull request
+type CreateComment struct {
+	Body string `json:"body"`
+}
+
+//PushHook is the payload of a Gitea "push" webhook
+type PushHook struct {
+	Ref        string          `json:"ref"`
+	Before     string          `json:"before"`
+	After      string          `json:"after"`
+	CompareURL string          `json:"compare_url"`
+	Commits    []PayloadCommit `json:"commits"`
+	Repository Repository      `json:"repository"`
+	Pusher     User            `json:"pusher"`
+	Sender     User            `json:"sender"`
+}
+
+//PullRequestHook is the payload of a Gitea "pull_request" webhook
+type PullRequestHook struct {
+	Action      string      `json:"action"`
+	Number      int         `json:"number"`
+	PullRequest PullRequest `json:"pull_request"`
+	Repository  Repository  `json:"repository"`
+	Sender      User        `json:"sender"`
+}
+
+//DeleteHook is the payload of a Gitea "delete" webhook
+type DeleteHook struct {
+	Ref        string     `json:"ref"`
+	RefType    string     `json:"ref_type"`
+	Repository Repository `json:"repository"`
+	Sender     User       `json:"sender"`
+}
@@ -668,5 +668,53 @@ func (g *GithubClient) DeleteEvents(fullname string, iEvents []interface{}) ([]s
 
 //PullRequestEvents checks pull request events from a event list
 func (g *GithubClient) PullRequestEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPullRequestEvent, error) {
-	return nil, nil
+	res := []sdk.VCSPullRequestEvent{}
+	for _, i := range iEvents {
+		e := i.(Event)
+		if e.Type != "PullRequestEvent" || e.Payload.PullRequest == nil {
+			continue
+		}
+
+		var action string
+		switch e.Payload.Action {
+		case "opened", "reopened", "synchronize":
+			action = "opened"
+		case "closed":
+			action = "closed"
+		default:
+			continue
+		}
+
+		pr := e.Payload.PullRequest
+		head := sdk.VCSBranch{
+			ID:           pr.Head.Ref,
+			DisplayID:    pr.Head.Ref,
+			LatestCommit: pr.Head.Sha,
+		}
+		event := sdk.VCSPullRequestEvent{
+			Action: action,
+			ID:     pr.Number,
+			URL:    pr.HTMLURL,
+			User: sdk.VCSAuthor{
+				Name:        pr.User.Login,
+				DisplayName: pr.User.Login,
+				Avatar:      pr.User.AvatarURL,
+			},
+			Head: sdk.VCSPushEvent{
+				Branch: head,
+				Commit: sdk.VCSCommit{Hash: pr.Head.Sha},
+			},
+			Base: sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: pr.Base.Ref, DisplayID: pr.Base.Ref, LatestCommit: pr.Base.Sha},
+				Commit: sdk.VCSCommit{Hash: pr.Base.Sha},
+			},
+			Branch: head,
+			Ref:    fmt.Sprintf("refs/pull/%d/merge", pr.Number),
+			Fork:   pr.Head.Repo == nil || pr.Head.Repo.FullName == nil || *pr.Head.Repo.FullName != fullname,
+		}
+		res = append(res, event)
+	}
+
+	log.Debug("GithubClient.PullRequestEvents> found %d pull request events : %#v", len(res), res)
+	return res, nil
 }
@@ -13,13 +13,28 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+type statusData struct {
+	status       string
+	desc         string
+	url          string
+	context      string
+	repoFullName string
+	hash         string
+}
+
 //SetStatus Users with push access can create commit statuses for a given ref:
 //https://developer.github.com/v3/repos/statuses/#create-a-status
 func (g *GithubClient) SetStatus(event sdk.Event) error {
 	log.Debug("github.SetStatus> receive: type:%s all: %+v", event.EventType, event)
-	var eventpb sdk.EventPipelineBuild
 
-	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
+	var data statusData
+	var err error
+	switch event.EventType {
+	case fmt.Sprintf("%T", sdk.EventPipelineBuild{}):
+		data, err = processEventPipelineBuild(event)
+	case fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}):
+		data, err = processEventWorkflowNodeRun(event)
+	default:
 		return nil
 	}
 
@@ -28,73 +43,31 @@ func (g *GithubClient) SetStatus(event sdk.Event) error {
 		return nil
 	}
 
-	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+	if err != nil {
 		log.Warning("Error during consumption: %s", err)
 		return err
 	}
 
-	log.Debug("Process event:%+v", event)
-
-	//We only manage status Success and Failure
-	if eventpb.Status == sdk.StatusChecking ||
-		eventpb.Status == sdk.StatusDisabled ||
-		eventpb.Status == sdk.StatusNeverBuilt ||
-		eventpb.Status == sdk.StatusSkipped ||
-		eventpb.Status == sdk.StatusUnknown ||
-		eventpb.Status == sdk.StatusWaiting {
+	//Nothing to send
+	if data.status == "" {
 		return nil
 	}
 
-	var status string
-	switch eventpb.Status {
-	case sdk.StatusFail:
-		status = "error"
-	case sdk.StatusSuccess:
-		status = "success"
-	default:
-		status = "pending"
-	}
-
-	url := fmt.Sprintf("%s/project/%s/application/%s/pipeline/%s/build/%d?envName=%s",
-		uiURL,
-		eventpb.ProjectKey,
-		eventpb.ApplicationName,
-		eventpb.PipelineName,
-		eventpb.BuildNumber,
-		url.QueryEscape(eventpb.EnvironmentName),
-	)
+	log.Debug("Process event:%+v", event)
 
 	//CDS can avoid sending github targer url in status, if it's disable
 	if g.DisableStatusURL {
-		url = ""
+		data.url = ""
 	}
 
-	var desc string
-	switch eventpb.PipelineType {
-	case sdk.BuildPipeline:
-		desc = fmt.Sprintf("Build pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
-	case sdk.TestingPipeline:
-		desc = fmt.Sprintf("Testing pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
-		if eventpb.Status == sdk.StatusFail {
-			status = "failure"
-		}
-	case sdk.DeploymentPipeline:
-		desc = fmt.Sprintf("Deployment pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
-	default:
-		log.Warning("Unrecognized pipeline type : %v", eventpb.PipelineType)
-		return nil
-	}
-
-	var context = fmt.Sprintf("continuous-delivery/CDS/%s", eventpb.PipelineName)
-
 	ghStatus := CreateStatus{
-		Description: desc,
-		TargetURL:   url,
-		State:       status,
-		Context:     context,
+		Description: data.desc,
+		TargetURL:   data.url,
+		State:       data.status,
+		Context:     data.context,
 	}
 
-	path := fmt.Sprintf("/repos/%s/statuses/%s", eventpb.RepositoryFullname, eventpb.Hash)
+	path := fmt.Sprintf("/repos/%s/statuses/%s", data.repoFullName, data.hash)
 
 	b, err := json.Marshal(ghStatus)
 	if err != nil {
@@ -130,3 +103,102 @@ func (g *GithubClient) SetStatus(event sdk.Event) error {
 
 	return nil
 }
+
+func processEventPipelineBuild(event sdk.Event) (statusData, error) {
+	data := statusData{}
+	var eventpb sdk.EventPipelineBuild
+	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+		return data, err
+	}
+
+	//We only manage status Success and Failure
+	if eventpb.Status == sdk.StatusChecking ||
+		eventpb.Status == sdk.StatusDisabled ||
+		eventpb.Status == sdk.StatusNeverBuilt ||
+		eventpb.Status == sdk.StatusSkipped ||
+		eventpb.Status == sdk.StatusUnknown ||
+		eventpb.Status == sdk.StatusWaiting {
+		return data, nil
+	}
+
+	status := getGithubStateFromStatus(eventpb.Status)
+
+	switch eventpb.PipelineType {
+	case sdk.BuildPipeline:
+		data.desc = fmt.Sprintf("Build pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	case sdk.TestingPipeline:
+		data.desc = fmt.Sprintf("Testing pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+		if eventpb.Status == sdk.StatusFail {
+			status = "failure"
+		}
+	case sdk.DeploymentPipeline:
+		data.desc = fmt.Sprintf("Deployment pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	default:
+		log.Warning("Unrecognized pipeline type : %v", eventpb.PipelineType)
+		return data, nil
+	}
+
+	data.status = status
+	data.url = fmt.Sprintf("%s/project/%s/application/%s/pipeline/%s/build/%d?envName=%s",
+		uiURL,
+		eventpb.ProjectKey,
+		eventpb.ApplicationName,
+		eventpb.PipelineName,
+		eventpb.BuildNumber,
+		url.QueryEscape(eventpb.EnvironmentName),
+	)
+	data.context = fmt.Sprintf("continuous-delivery/CDS/%s", eventpb.PipelineName)
+	data.repoFullName = eventpb.RepositoryFullname
+	data.hash = eventpb.Hash
+	return data, nil
+}
+
+func processEventWorkflowNodeRun(event sdk.Event) (statusData, error) {
+	data := statusData{}
+	var eventNR sdk.EventWorkflowNodeRun
+	if err := mapstructure.Decode(event.Payload, &eventNR); err != nil {
+		return data, err
+	}
+
+	//We only manage status Building, Success and Failure
+	if eventNR.Status != sdk.StatusBuilding &&
+		eventNR.Status != sdk.StatusSuccess &&
+		eventNR.Status != sdk.StatusFail {
+		return data, nil
+	}
+
+	if eventNR.Hash == "" || eventNR.RepositoryFullname == "" {
+		return data, nil
+	}
+
+	data.status = getGithubStateFromStatus(eventNR.Status)
+	if eventNR.Status == sdk.StatusFail {
+		data.status = "failure"
+	}
+	data.desc = fmt.Sprintf("Workflow %s - pipeline %s: %s", eventNR.WorkflowName, eventNR.PipelineName, eventNR.Status.String())
+	if eventNR.CoverageLines != "" {
+		data.desc += fmt.Sprintf(" - coverage %s%%", eventNR.CoverageLines)
+	}
+	data.url = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d",
+		uiURL,
+		eventNR.ProjectKey,
+		eventNR.WorkflowName,
+		eventNR.Number,
+		eventNR.ID,
+	)
+	data.context = fmt.Sprintf("continuous-delivery/CDS/%s/%s", eventNR.WorkflowName, eventNR.PipelineName)
+	data.repoFullName = eventNR.RepositoryFullname
+	data.hash = eventNR.Hash
+	return data, nil
+}
+
+func getGithubStateFromStatus(status sdk.Status) string {
+	switch status {
+	case sdk.StatusFail:
+		return "error"
+	case sdk.StatusSuccess:
+		return "success"
+	default:
+		return "pending"
+	}
+}
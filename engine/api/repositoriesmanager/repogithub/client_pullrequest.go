@@ -0,0 +1,85 @@
+package repogithub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// PullRequests returns the list of opened pull requests on a repository
+// https://developer.github.com/v3/pulls/#list-pull-requests
+func (g *GithubClient) PullRequests(fullname string) ([]sdk.VCSPullRequest, error) {
+	var pullRequests = []PullRequest{}
+	var nextPage = "/repos/" + fullname + "/pulls?state=open"
+
+	for nextPage != "" {
+		status, body, headers, err := g.get(nextPage, withoutETag)
+		if err != nil {
+			log.Warning("GithubClient.PullRequests> Error %s", err)
+			return nil, err
+		}
+		if status >= 400 {
+			return nil, sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+		}
+
+		nextPullRequests := []PullRequest{}
+		if err := json.Unmarshal(body, &nextPullRequests); err != nil {
+			log.Warning("GithubClient.PullRequests> Unable to parse github pull requests: %s", err)
+			return nil, err
+		}
+
+		pullRequests = append(pullRequests, nextPullRequests...)
+		nextPage = getNextPage(headers)
+	}
+
+	res := make([]sdk.VCSPullRequest, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		res = append(res, sdk.VCSPullRequest{
+			ID:    pr.Number,
+			Title: pr.Title,
+			URL:   pr.HTMLURL,
+			User: sdk.VCSAuthor{
+				Name:        pr.User.Login,
+				DisplayName: pr.User.Login,
+				Avatar:      pr.User.AvatarURL,
+			},
+			Head: sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: pr.Head.Ref, DisplayID: pr.Head.Ref, LatestCommit: pr.Head.Sha},
+				Commit: sdk.VCSCommit{Hash: pr.Head.Sha},
+			},
+			Base: sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: pr.Base.Ref, DisplayID: pr.Base.Ref, LatestCommit: pr.Base.Sha},
+				Commit: sdk.VCSCommit{Hash: pr.Base.Sha},
+			},
+		})
+	}
+
+	return res, nil
+}
+
+// PullRequestComment adds a comment on a pull request
+// https://developer.github.com/v3/issues/comments/#create-a-comment
+func (g *GithubClient) PullRequestComment(fullname string, id int, text string) error {
+	b, err := json.Marshal(CreateComment{Body: text})
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", fullname, id)
+	res, err := g.post(path, "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 201 {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("Unable to create comment on github. Status code : %d - Body: %s", res.StatusCode, body)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package repogithub
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type receivedRequest struct {
+	method, path, query string
+	body                []byte
+}
+
+//rewriteTransport sends the requests to the Github API to a test server
+type rewriteTransport struct {
+	url *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.url.Scheme
+	req.URL.Host = t.url.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+//newTestClient starts a server answering the Github API requests with the handler and returns a client of this server
+func newTestClient(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) (*GithubClient, *[]receivedRequest, func()) {
+	received := []receivedRequest{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, receivedRequest{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, body: body})
+		handler(w, r)
+	}))
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := httpClient
+	httpClient = &http.Client{Transport: &rewriteTransport{url: u}}
+
+	return &GithubClient{ClientID: "client-id", OAuthToken: "my-token"}, &received, func() {
+		httpClient = previous
+		ts.Close()
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", `<https://api.github.com/repositories/42/pulls?state=open&page=2>; rel="next", <https://api.github.com/repositories/42/pulls?state=open&page=2>; rel="last"`)
+			w.Write([]byte(`[{"number": 3, "title": "Fix the build", "html_url": "https://github.com/cds/demo/pull/3", "user": {"login": "alice"}, "head": {"ref": "fix", "sha": "abc"}, "base": {"ref": "master", "sha": "def"}}]`))
+			return
+		}
+		w.Write([]byte(`[{"number": 4, "title": "Add a feature", "user": {"login": "bob"}, "head": {"ref": "feat", "sha": "123"}, "base": {"ref": "master", "sha": "def"}}]`))
+	})
+	defer done()
+
+	prs, err := c.PullRequests("cds/demo")
+	assert.NoError(t, err)
+	assert.Len(t, *received, 2)
+	assert.Equal(t, "/repos/cds/demo/pulls", (*received)[0].path)
+	assert.Equal(t, "state=open", (*received)[0].query)
+	assert.Equal(t, "/repositories/42/pulls", (*received)[1].path)
+
+	assert.Len(t, prs, 2)
+	assert.Equal(t, 3, prs[0].ID)
+	assert.Equal(t, "alice", prs[0].User.Name)
+	assert.Equal(t, "fix", prs[0].Head.Branch.DisplayID)
+	assert.Equal(t, "abc", prs[0].Head.Commit.Hash)
+	assert.Equal(t, "master", prs[0].Base.Branch.ID)
+	assert.Equal(t, 4, prs[1].ID)
+	assert.Equal(t, "123", prs[1].Head.Commit.Hash)
+}
+
+func TestPullRequestComment(t *testing.T) {
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer done()
+
+	assert.NoError(t, c.PullRequestComment("cds/demo", 3, "Workflow my-workflow: Success"))
+	assert.Len(t, *received, 1)
+	assert.Equal(t, http.MethodPost, (*received)[0].method)
+	assert.Equal(t, "/repos/cds/demo/issues/3/comments", (*received)[0].path)
+
+	comment := CreateComment{}
+	assert.NoError(t, json.Unmarshal((*received)[0].body, &comment))
+	assert.Equal(t, "Workflow my-workflow: Success", comment.Body)
+}
+
+func TestPullRequestCommentError(t *testing.T) {
+	c, _, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer done()
+
+	assert.Error(t, c.PullRequestComment("cds/demo", 3, "Workflow my-workflow: Success"))
+}
+
+func TestSetStatus(t *testing.T) {
+	Init("https://cds-api.example.com", "https://cds.example.com")
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1, "state": "failure"}`))
+	})
+	defer done()
+
+	e := sdk.Event{
+		EventType: "sdk.EventWorkflowNodeRun",
+		Payload: map[string]interface{}{
+			"ID":                 int64(42),
+			"Number":             int64(12),
+			"Status":             sdk.StatusFail,
+			"ProjectKey":         "DEMO",
+			"WorkflowName":       "my-workflow",
+			"PipelineName":       "build",
+			"RepositoryFullname": "cds/demo",
+			"Hash":               "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b",
+		},
+	}
+	assert.NoError(t, c.SetStatus(e))
+	assert.Len(t, *received, 1)
+	assert.Equal(t, "/repos/cds/demo/statuses/0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b", (*received)[0].path)
+
+	status := CreateStatus{}
+	assert.NoError(t, json.Unmarshal((*received)[0].body, &status))
+	assert.Equal(t, "failure", status.State)
+	assert.Equal(t, "continuous-delivery/CDS/my-workflow/build", status.Context)
+	assert.Equal(t, "Workflow my-workflow - pipeline build: Fail", status.Description)
+	assert.Equal(t, "https://cds.example.com/project/DEMO/workflow/my-workflow/run/12/node/42", status.TargetURL)
+
+	c.DisableStatusURL = true
+	assert.NoError(t, c.SetStatus(e))
+	status = CreateStatus{}
+	assert.NoError(t, json.Unmarshal((*received)[1].body, &status))
+	assert.Equal(t, "", status.TargetURL)
+
+	c.DisableSetStatus = true
+	assert.NoError(t, c.SetStatus(e))
+	assert.Len(t, *received, 2)
+}
@@ -346,6 +346,9 @@ type Event struct {
 			Distinct bool   `json:"distinct"`
 			URL      string `json:"url"`
 		} `json:"commits"`
+		Action      string       `json:"action"`
+		Number      int          `json:"number"`
+		PullRequest *PullRequest `json:"pull_request"`
 	} `json:"payload"`
 	Public    bool      `json:"public"`
 	CreatedAt Timestamp `json:"created_at"`
@@ -397,6 +400,34 @@ type Status struct {
 	} `json:"creator"`
 }
 
+//PullRequest represents a pull request from API
+type PullRequest struct {
+	ID      int    `json:"id"`
+	Number  int    `json:"number"`
+	State   string `json:"state"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"user"`
+	Head PullRequestRef `json:"head"`
+	Base PullRequestRef `json:"base"`
+}
+
+//PullRequestRef represents the head or the base of a pull request
+type PullRequestRef struct {
+	Label string      `json:"label"`
+	Ref   string      `json:"ref"`
+	Sha   string      `json:"sha"`
+	Repo  *Repository `json:"repo"`
+}
+
+//CreateComment represents create an issue comment API Payload
+type CreateComment struct {
+	Body string `json:"body"`
+}
+
 //RateLimit represents Rate Limit API
 type RateLimit struct {
 	Resources struct {
@@ -0,0 +1,238 @@
+package repogitlab
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GitlabClient is a gitlab wrapper for CDS RepositoriesManagerClient interface
+type GitlabClient struct {
+	URL              string
+	OAuthToken       string
+	DisableSetStatus bool
+	DisableStatusURL bool
+}
+
+func (g *GitlabClient) toVCSRepo(p Project) sdk.VCSRepo {
+	return sdk.VCSRepo{
+		ID:           strconv.Itoa(p.ID),
+		Name:         p.Name,
+		Slug:         p.Path,
+		Fullname:     p.PathWithNamespace,
+		URL:          p.WebURL,
+		HTTPCloneURL: p.HTTPURLToRepo,
+		SSHCloneURL:  p.SSHURLToRepo,
+	}
+}
+
+// Repos list projects that the authenticated user is a member of
+// https://docs.gitlab.com/ce/api/projects.html#list-all-projects
+func (g *GitlabClient) Repos() ([]sdk.VCSRepo, error) {
+	var projects []Project
+	params := url.Values{}
+	params.Set("membership", "true")
+	params.Set("per_page", "100")
+
+	page := "1"
+	for page != "" {
+		params.Set("page", page)
+		nextProjects := []Project{}
+		headers, err := g.get("/projects", params, &nextProjects)
+		if err != nil {
+			log.Warning("GitlabClient.Repos> Error %s", err)
+			return nil, err
+		}
+		projects = append(projects, nextProjects...)
+		page = getNextPage(headers)
+	}
+
+	repos := make([]sdk.VCSRepo, 0, len(projects))
+	for _, p := range projects {
+		repos = append(repos, g.toVCSRepo(p))
+	}
+	return repos, nil
+}
+
+// RepoByFullname Get only one project
+// https://docs.gitlab.com/ce/api/projects.html#get-single-project
+func (g *GitlabClient) RepoByFullname(fullname string) (sdk.VCSRepo, error) {
+	p, err := g.project(fullname)
+	if err != nil {
+		return sdk.VCSRepo{}, err
+	}
+	return g.toVCSRepo(p), nil
+}
+
+func (g *GitlabClient) project(fullname string) (Project, error) {
+	p := Project{}
+	if _, err := g.get(projectPath(fullname), nil, &p); err != nil {
+		log.Warning("GitlabClient.project> Error %s", err)
+		return p, sdk.NewError(sdk.ErrRepoNotFound, err)
+	}
+	return p, nil
+}
+
+// Branches returns list of branches for a project
+// https://docs.gitlab.com/ce/api/branches.html#list-repository-branches
+func (g *GitlabClient) Branches(fullname string) ([]sdk.VCSBranch, error) {
+	p, err := g.project(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []Branch
+	params := url.Values{}
+	params.Set("per_page", "100")
+
+	page := "1"
+	for page != "" {
+		params.Set("page", page)
+		nextBranches := []Branch{}
+		headers, err := g.get(projectPath(fullname)+"/repository/branches", params, &nextBranches)
+		if err != nil {
+			log.Warning("GitlabClient.Branches> Error %s", err)
+			return nil, err
+		}
+		branches = append(branches, nextBranches...)
+		page = getNextPage(headers)
+	}
+
+	res := make([]sdk.VCSBranch, 0, len(branches))
+	for _, b := range branches {
+		res = append(res, toVCSBranch(b, p.DefaultBranch))
+	}
+	return res, nil
+}
+
+// Branch returns only detail of a branch
+// https://docs.gitlab.com/ce/api/branches.html#get-single-repository-branch
+func (g *GitlabClient) Branch(fullname, branchName string) (*sdk.VCSBranch, error) {
+	p, err := g.project(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	b := Branch{}
+	if _, err := g.get(projectPath(fullname)+"/repository/branches/"+url.QueryEscape(branchName), nil, &b); err != nil {
+		log.Warning("GitlabClient.Branch> Cannot find branch %s: %s", branchName, err)
+		return nil, err
+	}
+
+	branch := toVCSBranch(b, p.DefaultBranch)
+	return &branch, nil
+}
+
+func toVCSBranch(b Branch, defaultBranch string) sdk.VCSBranch {
+	return sdk.VCSBranch{
+		ID:           b.Name,
+		DisplayID:    b.Name,
+		LatestCommit: b.Commit.ID,
+		Default:      b.Default || b.Name == defaultBranch,
+		Parents:      b.Commit.ParentIDs,
+	}
+}
+
+func toVCSCommit(c Commit) sdk.VCSCommit {
+	return sdk.VCSCommit{
+		Hash:      c.ID,
+		Message:   c.Message,
+		Timestamp: c.AuthoredDate.Unix() * 1000,
+		URL:       c.WebURL,
+		Author: sdk.VCSAuthor{
+			Name:        c.AuthorName,
+			DisplayName: c.AuthorName,
+			Email:       c.AuthorEmail,
+		},
+	}
+}
+
+// Commits returns the commits list on a branch between a commit SHA (since) until another commit SHA (until).
+// If since is empty, only the latest commit is returned
+// https://docs.gitlab.com/ce/api/commits.html#list-repository-commits
+func (g *GitlabClient) Commits(repo, branch, since, until string) ([]sdk.VCSCommit, error) {
+	log.Debug("GitlabClient.Commits> Looking for commits on repo %s since = %s until = %s", repo, since, until)
+
+	ref := branch
+	if until != "" {
+		ref = until
+	}
+
+	params := url.Values{}
+	params.Set("ref_name", ref)
+	params.Set("per_page", "100")
+
+	commits := []sdk.VCSCommit{}
+	page := "1"
+	for page != "" {
+		params.Set("page", page)
+		nextCommits := []Commit{}
+		headers, err := g.get(projectPath(repo)+"/repository/commits", params, &nextCommits)
+		if err != nil {
+			log.Warning("GitlabClient.Commits> Error %s", err)
+			return nil, err
+		}
+
+		for _, c := range nextCommits {
+			if c.ID == since {
+				return commits, nil
+			}
+			commits = append(commits, toVCSCommit(c))
+			if since == "" {
+				return commits, nil
+			}
+		}
+		page = getNextPage(headers)
+	}
+
+	return commits, nil
+}
+
+// Commit Get a single commit
+// https://docs.gitlab.com/ce/api/commits.html#get-a-single-commit
+func (g *GitlabClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
+	c := Commit{}
+	if _, err := g.get(projectPath(repo)+"/repository/commits/"+hash, nil, &c); err != nil {
+		log.Warning("GitlabClient.Commit> Error %s", err)
+		return sdk.VCSCommit{}, err
+	}
+	return toVCSCommit(c), nil
+}
+
+// CreateHook adds a hook on push and merge request events to a project
+// https://docs.gitlab.com/ce/api/projects.html#add-project-hook
+func (g *GitlabClient) CreateHook(repo, hookURL string) error {
+	h := Hook{
+		URL:                   hookURL,
+		PushEvents:            true,
+		MergeRequestsEvents:   true,
+		EnableSSLVerification: true,
+		Token:                 hookSecret,
+	}
+	if err := g.post(projectPath(repo)+"/hooks", h, nil); err != nil {
+		return fmt.Errorf("GitlabClient.CreateHook> Unable to create hook on %s: %s", repo, err)
+	}
+	return nil
+}
+
+// DeleteHook removes the hook of a project which matches the url
+// https://docs.gitlab.com/ce/api/projects.html#delete-project-hook
+func (g *GitlabClient) DeleteHook(repo, hookURL string) error {
+	hooks := []Hook{}
+	if _, err := g.get(projectPath(repo)+"/hooks", nil, &hooks); err != nil {
+		return fmt.Errorf("GitlabClient.DeleteHook> Unable to list hooks on %s: %s", repo, err)
+	}
+
+	for _, h := range hooks {
+		if h.URL != hookURL {
+			continue
+		}
+		if err := g.delete(fmt.Sprintf("%s/hooks/%d", projectPath(repo), h.ID)); err != nil {
+			return fmt.Errorf("GitlabClient.DeleteHook> Unable to delete hook %d on %s: %s", h.ID, repo, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package repogitlab
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//GetEvents calls Gitlab project events API and returns Gitlab events as []interface{}
+//https://docs.gitlab.com/ce/api/events.html#list-a-project-s-visible-events
+func (g *GitlabClient) GetEvents(fullname string, dateRef time.Time) ([]interface{}, time.Duration, error) {
+	log.Debug("GitlabClient.GetEvents> loading events for %s after %v", fullname, dateRef)
+	interval := 60 * time.Second
+
+	params := url.Values{}
+	// Gitlab filters events by day, "after" is exclusive
+	params.Set("after", dateRef.AddDate(0, 0, -1).Format("2006-01-02"))
+	params.Set("per_page", "100")
+
+	glEvents := []Event{}
+	if _, err := g.get(projectPath(fullname)+"/events", params, &glEvents); err != nil {
+		log.Warning("GitlabClient.GetEvents> Error %s", err)
+		return nil, interval, err
+	}
+
+	events := []interface{}{}
+	for _, e := range glEvents {
+		if e.CreatedAt.After(dateRef) {
+			events = append(events, e)
+		}
+	}
+
+	return events, interval, nil
+}
+
+func filterPushData(iEvents []interface{}, action string) []Event {
+	events := []Event{}
+	for _, i := range iEvents {
+		e, ok := i.(Event)
+		if !ok || e.PushData == nil {
+			continue
+		}
+		if e.PushData.RefType == "branch" && e.PushData.Action == action {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+//PushEvents returns push events as commits
+func (g *GitlabClient) PushEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPushEvent, error) {
+	lastEventPerBranch := map[string]Event{}
+	for _, e := range filterPushData(iEvents, "pushed") {
+		l, ok := lastEventPerBranch[e.PushData.Ref]
+		if !ok || l.CreatedAt.Before(e.CreatedAt) {
+			lastEventPerBranch[e.PushData.Ref] = e
+		}
+	}
+
+	res := []sdk.VCSPushEvent{}
+	for b, e := range lastEventPerBranch {
+		branch, err := g.Branch(fullname, b)
+		if err != nil || branch == nil {
+			log.Warning("GitlabClient.PushEvents> Unable to find branch %s in %s : %s", b, fullname, err)
+			continue
+		}
+		c, err := g.Commit(fullname, e.PushData.CommitTo)
+		if err != nil {
+			log.Warning("GitlabClient.PushEvents> Unable to find commit %s in %s : %s", e.PushData.CommitTo, fullname, err)
+			continue
+		}
+		c.Author.Name = e.Author.Username
+		c.Author.Avatar = e.Author.AvatarURL
+		res = append(res, sdk.VCSPushEvent{
+			Branch: *branch,
+			Commit: c,
+		})
+	}
+
+	return res, nil
+}
+
+//CreateEvents checks create events from a event list
+func (g *GitlabClient) CreateEvents(fullname string, iEvents []interface{}) ([]sdk.VCSCreateEvent, error) {
+	res := []sdk.VCSCreateEvent{}
+	for _, e := range filterPushData(iEvents, "created") {
+		branch, err := g.Branch(fullname, e.PushData.Ref)
+		if err != nil || branch == nil {
+			log.Warning("GitlabClient.CreateEvents> Unable to find branch %s in %s : %s", e.PushData.Ref, fullname, err)
+			continue
+		}
+		c, err := g.Commit(fullname, branch.LatestCommit)
+		if err != nil {
+			log.Warning("GitlabClient.CreateEvents> Unable to find commit %s in %s : %s", branch.LatestCommit, fullname, err)
+			continue
+		}
+		res = append(res, sdk.VCSCreateEvent{
+			Branch: *branch,
+			Commit: c,
+		})
+	}
+
+	log.Debug("GitlabClient.CreateEvents> found %d create events : %#v", len(res), res)
+	return res, nil
+}
+
+//DeleteEvents checks delete events from a event list
+func (g *GitlabClient) DeleteEvents(fullname string, iEvents []interface{}) ([]sdk.VCSDeleteEvent, error) {
+	res := []sdk.VCSDeleteEvent{}
+	for _, e := range filterPushData(iEvents, "removed") {
+		res = append(res, sdk.VCSDeleteEvent{
+			Branch: sdk.VCSBranch{
+				ID:        e.PushData.Ref,
+				DisplayID: e.PushData.Ref,
+			},
+		})
+	}
+
+	log.Debug("GitlabClient.DeleteEvents> found %d delete events : %#v", len(res), res)
+	return res, nil
+}
+
+//PullRequestEvents checks merge request events from a event list
+func (g *GitlabClient) PullRequestEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPullRequestEvent, error) {
+	res := []sdk.VCSPullRequestEvent{}
+	for _, i := range iEvents {
+		e, ok := i.(Event)
+		if !ok || e.TargetType != "MergeRequest" {
+			continue
+		}
+
+		var action string
+		switch e.ActionName {
+		case "opened", "reopened":
+			action = "opened"
+		case "closed", "accepted":
+			action = "closed"
+		default:
+			continue
+		}
+
+		mr := MergeRequest{}
+		if _, err := g.get(fmt.Sprintf("%s/merge_requests/%d", projectPath(fullname), e.TargetIID), nil, &mr); err != nil {
+			log.Warning("GitlabClient.PullRequestEvents> Unable to find merge request %d in %s : %s", e.TargetIID, fullname, err)
+			continue
+		}
+
+		event := sdk.VCSPullRequestEvent{
+			Action: action,
+			ID:     mr.IID,
+			URL:    mr.WebURL,
+			User: sdk.VCSAuthor{
+				Name:        mr.Author.Username,
+				DisplayName: mr.Author.Name,
+				Avatar:      mr.Author.AvatarURL,
+			},
+			Head: sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: mr.SourceBranch, DisplayID: mr.SourceBranch, LatestCommit: mr.SHA},
+				Commit: sdk.VCSCommit{Hash: mr.SHA},
+			},
+			Base: sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: mr.TargetBranch, DisplayID: mr.TargetBranch},
+			},
+			Branch: sdk.VCSBranch{ID: mr.SourceBranch, DisplayID: mr.SourceBranch, LatestCommit: mr.SHA},
+			Ref:    mergeRequestRef(mr.IID),
+			Fork:   mr.SourceProjectID != mr.TargetProjectID,
+			//Gitlab does not always provide a merge ref
+			MergeBase: mr.TargetBranch,
+		}
+		res = append(res, event)
+	}
+
+	return res, nil
+}
+
+type statusData struct {
+	status       string
+	desc         string
+	url          string
+	context      string
+	repoFullName string
+	hash         string
+	ref          string
+}
+
+//SetStatus sets a commit status for a pipeline build or a workflow node run
+//https://docs.gitlab.com/ce/api/commits.html#post-the-build-status-to-a-commit
+func (g *GitlabClient) SetStatus(event sdk.Event) error {
+	log.Debug("gitlab.SetStatus> receive: type:%s all: %+v", event.EventType, event)
+
+	var data statusData
+	var err error
+	switch event.EventType {
+	case fmt.Sprintf("%T", sdk.EventPipelineBuild{}):
+		data, err = processEventPipelineBuild(event)
+	case fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}):
+		data, err = processEventWorkflowNodeRun(event)
+	default:
+		return nil
+	}
+
+	if g.DisableSetStatus {
+		log.Warning("⚠ Gitlab statuses are disabled")
+		return nil
+	}
+
+	if err != nil {
+		log.Warning("Error during consumption: %s", err)
+		return err
+	}
+
+	//Nothing to send
+	if data.status == "" || data.hash == "" || data.repoFullName == "" {
+		return nil
+	}
+
+	//CDS can avoid sending gitlab target url in status, if it's disable
+	if g.DisableStatusURL {
+		data.url = ""
+	}
+
+	glStatus := CreateStatus{
+		State:       data.status,
+		Ref:         data.ref,
+		Name:        data.context,
+		TargetURL:   data.url,
+		Description: data.desc,
+	}
+
+	path := fmt.Sprintf("%s/statuses/%s", projectPath(data.repoFullName), data.hash)
+	if err := g.post(path, glStatus, nil); err != nil {
+		log.Warning("SetStatus> Unable to create status on gitlab: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+func processEventPipelineBuild(event sdk.Event) (statusData, error) {
+	data := statusData{}
+	var eventpb sdk.EventPipelineBuild
+	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+		return data, err
+	}
+
+	data.status = getGitlabStateFromStatus(eventpb.Status)
+	data.desc = fmt.Sprintf("Pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	data.url = fmt.Sprintf("%s/project/%s/application/%s/pipeline/%s/build/%d?envName=%s",
+		uiURL,
+		eventpb.ProjectKey,
+		eventpb.ApplicationName,
+		eventpb.PipelineName,
+		eventpb.BuildNumber,
+		url.QueryEscape(eventpb.EnvironmentName),
+	)
+	data.context = fmt.Sprintf("continuous-delivery/CDS/%s", eventpb.PipelineName)
+	data.repoFullName = eventpb.RepositoryFullname
+	data.hash = eventpb.Hash
+	data.ref = eventpb.BranchName
+	return data, nil
+}
+
+func processEventWorkflowNodeRun(event sdk.Event) (statusData, error) {
+	data := statusData{}
+	var eventNR sdk.EventWorkflowNodeRun
+	if err := mapstructure.Decode(event.Payload, &eventNR); err != nil {
+		return data, err
+	}
+
+	data.status = getGitlabStateFromStatus(eventNR.Status)
+	data.desc = fmt.Sprintf("Workflow %s - pipeline %s: %s", eventNR.WorkflowName, eventNR.PipelineName, eventNR.Status.String())
+	data.url = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d",
+		uiURL,
+		eventNR.ProjectKey,
+		eventNR.WorkflowName,
+		eventNR.Number,
+		eventNR.ID,
+	)
+	data.context = fmt.Sprintf("continuous-delivery/CDS/%s/%s", eventNR.WorkflowName, eventNR.PipelineName)
+	data.repoFullName = eventNR.RepositoryFullname
+	data.hash = eventNR.Hash
+	data.ref = eventNR.BranchName
+	return data, nil
+}
+
+//getGitlabStateFromStatus returns the gitlab commit state, empty if the status should not be sent
+func getGitlabStateFromStatus(status sdk.Status) string {
+	switch status {
+	case sdk.StatusWaiting:
+		return "pending"
+	case sdk.StatusBuilding:
+		return "running"
+	case sdk.StatusSuccess:
+		return "success"
+	case sdk.StatusFail:
+		return "failed"
+	case sdk.StatusDisabled, sdk.StatusSkipped:
+		return "canceled"
+	default:
+		return ""
+	}
+}
+
+//mergeRequestRef returns the reference of the head of a merge request on the target project
+func mergeRequestRef(iid int) string {
+	return fmt.Sprintf("refs/merge-requests/%d/head", iid)
+}
+
+//branchFromRef removes the refs/heads/ prefix of a git reference
+func branchFromRef(ref string) string {
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
@@ -0,0 +1,60 @@
+package repogitlab
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// PullRequests returns the list of opened merge requests on a project
+// https://docs.gitlab.com/ce/api/merge_requests.html#list-project-merge-requests
+func (g *GitlabClient) PullRequests(fullname string) ([]sdk.VCSPullRequest, error) {
+	var mrs []MergeRequest
+	params := url.Values{}
+	params.Set("state", "opened")
+	params.Set("per_page", "100")
+
+	page := "1"
+	for page != "" {
+		params.Set("page", page)
+		nextMRs := []MergeRequest{}
+		headers, err := g.get(projectPath(fullname)+"/merge_requests", params, &nextMRs)
+		if err != nil {
+			log.Warning("GitlabClient.PullRequests> Error %s", err)
+			return nil, err
+		}
+		mrs = append(mrs, nextMRs...)
+		page = getNextPage(headers)
+	}
+
+	res := make([]sdk.VCSPullRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		res = append(res, sdk.VCSPullRequest{
+			ID:    mr.IID,
+			Title: mr.Title,
+			URL:   mr.WebURL,
+			User: sdk.VCSAuthor{
+				Name:        mr.Author.Username,
+				DisplayName: mr.Author.Name,
+				Avatar:      mr.Author.AvatarURL,
+			},
+			Head: sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: mr.SourceBranch, DisplayID: mr.SourceBranch, LatestCommit: mr.SHA},
+				Commit: sdk.VCSCommit{Hash: mr.SHA},
+			},
+			Base: sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: mr.TargetBranch, DisplayID: mr.TargetBranch},
+			},
+		})
+	}
+	return res, nil
+}
+
+// PullRequestComment adds a note on a merge request
+// https://docs.gitlab.com/ce/api/notes.html#create-new-merge-request-note
+func (g *GitlabClient) PullRequestComment(fullname string, id int, text string) error {
+	path := fmt.Sprintf("%s/merge_requests/%d/notes", projectPath(fullname), id)
+	return g.post(path, CreateNote{Body: text}, nil)
+}
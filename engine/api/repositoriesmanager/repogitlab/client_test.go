@@ -0,0 +1,158 @@
+package repogitlab
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type receivedRequest struct {
+	method, path, query string
+	body                []byte
+}
+
+//newTestClient starts a server answering the Gitlab API requests with the handler and returns a client of this server
+func newTestClient(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) (*GitlabClient, *[]receivedRequest, func()) {
+	received := []receivedRequest{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, receivedRequest{method: r.Method, path: r.URL.EscapedPath(), query: r.URL.RawQuery, body: body})
+		handler(w, r)
+	}))
+
+	c, err := New(ts.URL, "client-id", "client-secret", "").GetAuthorized("my-token", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*GitlabClient), &received, ts.Close
+}
+
+func TestRepos(t *testing.T) {
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			w.Write([]byte(`[{"id": 12, "name": "demo", "path": "demo", "path_with_namespace": "cds/demo"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id": 13, "name": "lib", "path": "lib", "path_with_namespace": "cds/lib"}]`))
+	})
+	defer done()
+
+	repos, err := c.Repos()
+	assert.NoError(t, err)
+	assert.Len(t, repos, 2)
+	assert.Equal(t, "cds/demo", repos[0].Fullname)
+	assert.Equal(t, "13", repos[1].ID)
+	assert.Len(t, *received, 2)
+	assert.Equal(t, "membership=true&page=2&per_page=100", (*received)[1].query)
+}
+
+func TestHooks(t *testing.T) {
+	Init("https://cds-api.example.com", "https://cds.example.com", "my-hook-secret")
+	defer Init("", "", "")
+
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			w.Write([]byte(`[{"id": 6, "url": "https://ci.example.com/notify"}, {"id": 7, "url": "https://cds.example.com/hook?uid=abc"}]`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	defer done()
+
+	assert.NoError(t, c.CreateHook("cds/demo", "https://cds.example.com/hook?uid=abc"))
+	assert.Equal(t, "/api/v4/projects/cds%2Fdemo/hooks", (*received)[0].path)
+	h := Hook{}
+	assert.NoError(t, json.Unmarshal((*received)[0].body, &h))
+	assert.Equal(t, "https://cds.example.com/hook?uid=abc", h.URL)
+	assert.Equal(t, "my-hook-secret", h.Token)
+	assert.True(t, h.PushEvents)
+	assert.True(t, h.MergeRequestsEvents)
+
+	assert.NoError(t, c.DeleteHook("cds/demo", "https://cds.example.com/hook?uid=abc"))
+	assert.Len(t, *received, 3)
+	last := (*received)[2]
+	assert.Equal(t, http.MethodDelete, last.method)
+	assert.Equal(t, "/api/v4/projects/cds%2Fdemo/hooks/7", last.path)
+}
+
+func TestSetStatus(t *testing.T) {
+	Init("https://cds-api.example.com", "https://cds.example.com", "")
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer done()
+
+	e := sdk.Event{
+		EventType: "sdk.EventWorkflowNodeRun",
+		Payload: map[string]interface{}{
+			"ID":                 int64(42),
+			"Number":             int64(12),
+			"Status":             sdk.StatusSuccess,
+			"ProjectKey":         "DEMO",
+			"WorkflowName":       "my-workflow",
+			"PipelineName":       "build",
+			"RepositoryFullname": "cds/demo",
+			"BranchName":         "master",
+			"Hash":               "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b",
+		},
+	}
+	assert.NoError(t, c.SetStatus(e))
+	assert.Len(t, *received, 1)
+	assert.Equal(t, "/api/v4/projects/cds%2Fdemo/statuses/0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b", (*received)[0].path)
+
+	status := CreateStatus{}
+	assert.NoError(t, json.Unmarshal((*received)[0].body, &status))
+	assert.Equal(t, "success", status.State)
+	assert.Equal(t, "master", status.Ref)
+	assert.Equal(t, "continuous-delivery/CDS/my-workflow/build", status.Name)
+	assert.Equal(t, "https://cds.example.com/project/DEMO/workflow/my-workflow/run/12/node/42", status.TargetURL)
+
+	c.DisableStatusURL = true
+	assert.NoError(t, c.SetStatus(e))
+	status = CreateStatus{}
+	assert.NoError(t, json.Unmarshal((*received)[1].body, &status))
+	assert.Equal(t, "", status.TargetURL)
+
+	c.DisableSetStatus = true
+	assert.NoError(t, c.SetStatus(e))
+	assert.Len(t, *received, 2)
+}
+
+func TestPullRequestComment(t *testing.T) {
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer done()
+
+	assert.NoError(t, c.PullRequestComment("cds/demo", 3, "Workflow my-workflow: Success"))
+	assert.Len(t, *received, 1)
+	assert.Equal(t, http.MethodPost, (*received)[0].method)
+	assert.Equal(t, "/api/v4/projects/cds%2Fdemo/merge_requests/3/notes", (*received)[0].path)
+
+	note := CreateNote{}
+	assert.NoError(t, json.Unmarshal((*received)[0].body, &note))
+	assert.Equal(t, "Workflow my-workflow: Success", note.Body)
+}
+
+func TestUnauthorized(t *testing.T) {
+	c, _, done := newTestClient(t, nil)
+	defer done()
+
+	c.OAuthToken = "wrong-token"
+	_, err := c.Repos()
+	assert.Equal(t, ErrorUnauthorized, err)
+}
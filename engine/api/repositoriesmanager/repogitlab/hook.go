@@ -0,0 +1,119 @@
+package repogitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+
+	"github.com/ovh/cds/sdk"
+)
+
+//Gitlab webhook event types, sent in the X-Gitlab-Event header. The secret token of the hook is sent in the
+//X-Gitlab-Token header
+const (
+	HookEventHeader       = "X-Gitlab-Event"
+	HookTokenHeader       = "X-Gitlab-Token"
+	PushHookEvent         = "Push Hook"
+	MergeRequestHookEvent = "Merge Request Hook"
+)
+
+//CheckHookToken checks the secret token of a received hook. All the hooks are rejected if no secret is configured
+func CheckHookToken(token string) bool {
+	if hookSecret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(hookSecret)) == 1
+}
+
+//nullCommit is sent as "after" commit when a branch is deleted
+const nullCommit = "0000000000000000000000000000000000000000"
+
+//ParsePushHook parses the payload of a push webhook. It returns true if the push deletes the branch
+func ParsePushHook(data []byte) (*sdk.VCSPushEvent, bool, error) {
+	var h PushHook
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, false, sdk.WrapError(sdk.ErrWrongRequest, "ParsePushHook> Unable to parse gitlab push hook: %s", err)
+	}
+
+	branch := branchFromRef(h.Ref)
+	event := &sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           branch,
+			DisplayID:    branch,
+			LatestCommit: h.After,
+			Default:      branch == h.Project.DefaultBranch,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: h.After,
+			Author: sdk.VCSAuthor{
+				Name:        h.UserUsername,
+				DisplayName: h.UserName,
+				Email:       h.UserEmail,
+				Avatar:      h.UserAvatar,
+			},
+		},
+	}
+
+	//Find the details of the head commit
+	for _, c := range h.Commits {
+		if c.ID == h.After {
+			event.Commit.Message = c.Message
+			event.Commit.URL = c.URL
+			event.Commit.Timestamp = c.Timestamp.Unix() * 1000
+		}
+	}
+
+	return event, h.After == nullCommit, nil
+}
+
+//ParseMergeRequestHook parses the payload of a merge request webhook
+func ParseMergeRequestHook(data []byte) (*sdk.VCSPullRequestEvent, error) {
+	var h MergeRequestHook
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "ParseMergeRequestHook> Unable to parse gitlab merge request hook: %s", err)
+	}
+
+	attr := h.ObjectAttributes
+	var action string
+	switch attr.Action {
+	case "open", "reopen", "update":
+		action = "opened"
+	case "close", "merge":
+		action = "closed"
+	default:
+		action = attr.Action
+	}
+
+	head := sdk.VCSBranch{ID: attr.SourceBranch, DisplayID: attr.SourceBranch, LatestCommit: attr.LastCommit.ID}
+	return &sdk.VCSPullRequestEvent{
+		Action: action,
+		ID:     attr.IID,
+		URL:    attr.URL,
+		User: sdk.VCSAuthor{
+			Name:        h.User.Username,
+			DisplayName: h.User.Name,
+			Avatar:      h.User.AvatarURL,
+		},
+		Head: sdk.VCSPushEvent{
+			Branch: head,
+			Commit: sdk.VCSCommit{
+				Hash:      attr.LastCommit.ID,
+				Message:   attr.LastCommit.Message,
+				URL:       attr.LastCommit.URL,
+				Timestamp: attr.LastCommit.Timestamp.Unix() * 1000,
+				Author: sdk.VCSAuthor{
+					Name:        attr.LastCommit.Author.Name,
+					DisplayName: attr.LastCommit.Author.Name,
+					Email:       attr.LastCommit.Author.Email,
+				},
+			},
+		},
+		Base: sdk.VCSPushEvent{
+			Branch: sdk.VCSBranch{ID: attr.TargetBranch, DisplayID: attr.TargetBranch},
+		},
+		Branch: head,
+		Ref:    mergeRequestRef(attr.IID),
+		Fork:   attr.SourceProjectID != attr.TargetProjectID,
+		//Gitlab does not always provide a merge ref
+		MergeBase: attr.TargetBranch,
+	}, nil
+}
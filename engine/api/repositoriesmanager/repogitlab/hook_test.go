@@ -0,0 +1,111 @@
+package repogitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePushHook(t *testing.T) {
+	e, deleted, err := ParsePushHook([]byte(pushHookData))
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.Equal(t, "master", e.Branch.ID)
+	assert.True(t, e.Branch.Default)
+	assert.Equal(t, "da1560886d4f094c3e6c9ef40349f7d38b5d27d7", e.Commit.Hash)
+	assert.Equal(t, "fixed readme", e.Commit.Message)
+	assert.Equal(t, "jsmith", e.Commit.Author.Name)
+	assert.Equal(t, int64(1325616180000), e.Commit.Timestamp)
+
+	_, deleted, err = ParsePushHook([]byte(deleteHookData))
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, _, err = ParsePushHook([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestParseMergeRequestHook(t *testing.T) {
+	e, err := ParseMergeRequestHook([]byte(mergeRequestHookData))
+	assert.NoError(t, err)
+	assert.Equal(t, "opened", e.Action)
+	assert.Equal(t, "ms-viewport", e.Head.Branch.ID)
+	assert.Equal(t, "da1560886d4f094c3e6c9ef40349f7d38b5d27d7", e.Head.Commit.Hash)
+	assert.Equal(t, "master", e.Base.Branch.ID)
+	assert.Equal(t, "root", e.User.Name)
+}
+
+const pushHookData = `{
+  "object_kind": "push",
+  "before": "95790bf891e76fee5e1747ab589903a6a1f80f22",
+  "after": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+  "ref": "refs/heads/master",
+  "user_name": "John Smith",
+  "user_username": "jsmith",
+  "user_email": "john@example.com",
+  "project": {
+    "path_with_namespace": "mike/diaspora",
+    "default_branch": "master"
+  },
+  "commits": [
+    {
+      "id": "b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327",
+      "message": "Update Catalan translation to e38cb41.",
+      "timestamp": "2011-12-12T14:27:31+02:00",
+      "url": "http://example.com/mike/diaspora/commit/b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327",
+      "author": {"name": "Jordi Mallach", "email": "jordi@softcatala.org"}
+    },
+    {
+      "id": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+      "message": "fixed readme",
+      "timestamp": "2012-01-03T23:43:00+05:00",
+      "url": "http://example.com/mike/diaspora/commit/da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+      "author": {"name": "GitLab dev user", "email": "gitlabdev@dv6700.(none)"}
+    }
+  ]
+}`
+
+const deleteHookData = `{
+  "object_kind": "push",
+  "before": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+  "after": "0000000000000000000000000000000000000000",
+  "ref": "refs/heads/feat/old",
+  "user_username": "jsmith",
+  "project": {"path_with_namespace": "mike/diaspora", "default_branch": "master"},
+  "commits": []
+}`
+
+const mergeRequestHookData = `{
+  "object_kind": "merge_request",
+  "user": {"name": "Administrator", "username": "root", "avatar_url": "http://www.gravatar.com/avatar/e64c7d89f26bd1972efa854d13d7dd61"},
+  "object_attributes": {
+    "id": 99,
+    "iid": 1,
+    "title": "MS-Viewport",
+    "state": "opened",
+    "action": "open",
+    "url": "http://example.com/diaspora/merge_requests/1",
+    "source_branch": "ms-viewport",
+    "target_branch": "master",
+    "last_commit": {
+      "id": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+      "message": "fixed readme",
+      "timestamp": "2012-01-03T23:36:29+02:00",
+      "url": "http://example.com/awesome_space/awesome_project/commits/da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+      "author": {"name": "GitLab dev user", "email": "gitlabdev@dv6700.(none)"}
+    }
+  }
+}`
+
+func TestCheckHookToken(t *testing.T) {
+	//Without secret, anyone could trigger the workflows
+	Init("", "", "")
+	assert.False(t, CheckHookToken(""))
+	assert.False(t, CheckHookToken("any-secret"))
+
+	Init("", "", "my-hook-secret")
+	defer Init("", "", "")
+	assert.True(t, CheckHookToken("my-hook-secret"))
+	assert.False(t, CheckHookToken(""))
+	assert.False(t, CheckHookToken("wrong-secret"))
+}
@@ -0,0 +1,137 @@
+package repogitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/facebookgo/httpcontrol"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Gitlab http var
+var (
+	httpClient = &http.Client{
+		Transport: &httpcontrol.Transport{
+			RequestTimeout: time.Second * 30,
+			MaxTries:       5,
+		},
+	}
+)
+
+//projectPath returns the url-encoded path of a project, used as project ID in Gitlab API v4
+func projectPath(fullname string) string {
+	return "/projects/" + url.QueryEscape(fullname)
+}
+
+func (g *GitlabConsumer) postForm(path string, data url.Values) (int, []byte, error) {
+	req, err := http.NewRequest(http.MethodPost, g.URL+path, strings.NewReader(data.Encode()))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, err
+	}
+
+	if res.StatusCode >= 400 {
+		return res.StatusCode, resBody, ErrorAPI(res.StatusCode, resBody)
+	}
+
+	return res.StatusCode, resBody, nil
+}
+
+//do sends a request on the Gitlab API v4. in is marshalled as the json body of the request and
+//the response is unmarshalled in out. It returns the headers of the response, used for pagination
+func (c *GitlabClient) do(method, path string, params url.Values, in interface{}, out interface{}) (http.Header, error) {
+	uri := c.URL + "/api/v4" + path
+	if len(params) > 0 {
+		uri += "?" + params.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequest(method, uri, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.OAuthToken)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Debug("Gitlab API>> Request %s %s", method, req.URL.String())
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized:
+		return res.Header, ErrorUnauthorized
+	case res.StatusCode >= 400:
+		return res.Header, ErrorAPI(res.StatusCode, resBody)
+	}
+
+	if out != nil && len(resBody) > 0 {
+		if err := json.Unmarshal(resBody, out); err != nil {
+			return res.Header, err
+		}
+	}
+
+	return res.Header, nil
+}
+
+func (c *GitlabClient) get(path string, params url.Values, out interface{}) (http.Header, error) {
+	return c.do(http.MethodGet, path, params, nil, out)
+}
+
+func (c *GitlabClient) post(path string, in interface{}, out interface{}) error {
+	_, err := c.do(http.MethodPost, path, nil, in, out)
+	return err
+}
+
+func (c *GitlabClient) delete(path string) error {
+	_, err := c.do(http.MethodDelete, path, nil, nil, nil)
+	return err
+}
+
+//getNextPage returns the next page to request from the Gitlab pagination headers, "" if the last page is reached
+func getNextPage(headers http.Header) string {
+	if headers == nil {
+		return ""
+	}
+	return headers.Get("X-Next-Page")
+}
@@ -0,0 +1,14 @@
+package repogitlab
+
+var (
+	apiURL     string
+	uiURL      string
+	hookSecret string
+)
+
+// Init initializes repogitlab package. The hook secret is the token of the hooks created by CDS
+func Init(apiurl, uiurl, hooksecret string) {
+	apiURL = apiurl
+	uiURL = uiurl
+	hookSecret = hooksecret
+}
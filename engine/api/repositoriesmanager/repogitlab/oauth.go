@@ -0,0 +1,122 @@
+package repogitlab
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Gitlab const
+var (
+	RequestedScope = "api" //https://docs.gitlab.com/ce/api/oauth2.html
+)
+
+func generateHash() (string, error) {
+	bs := make([]byte, 64)
+	if _, err := rand.Read(bs); err != nil {
+		log.Error("generateHash: rand.Read failed: %s\n", err)
+		return "", err
+	}
+	return hex.EncodeToString(bs), nil
+}
+
+//GitlabConsumer embeds a gitlab oauth2 consumer
+type GitlabConsumer struct {
+	URL                      string `json:"-"`
+	ClientID                 string `json:"client-id"`
+	ClientSecret             string `json:"-"`
+	AuthorizationCallbackURL string `json:"-"`
+	DisableSetStatus         bool   `json:"-"`
+	DisableStatusURL         bool   `json:"-"`
+}
+
+//New creates a new GitlabConsumer
+func New(URL, ClientID, ClientSecret, AuthorizationCallbackURL string) *GitlabConsumer {
+	return &GitlabConsumer{
+		URL:                      URL,
+		ClientID:                 ClientID,
+		ClientSecret:             ClientSecret,
+		AuthorizationCallbackURL: AuthorizationCallbackURL,
+	}
+}
+
+//Data returns a serilized version of specific data
+func (g *GitlabConsumer) Data() string {
+	b, _ := json.Marshal(g)
+	return string(b)
+}
+
+//AuthorizeRedirect returns the request token, the Authorize URL
+//doc: https://docs.gitlab.com/ce/api/oauth2.html#web-application-flow
+func (g *GitlabConsumer) AuthorizeRedirect() (string, string, error) {
+	requestToken, err := generateHash()
+	if err != nil {
+		return "", "", err
+	}
+
+	val := url.Values{}
+	val.Add("client_id", g.ClientID)
+	val.Add("redirect_uri", g.AuthorizationCallbackURL)
+	val.Add("response_type", "code")
+	val.Add("scope", RequestedScope)
+	val.Add("state", requestToken)
+
+	authorizeURL := fmt.Sprintf("%s/oauth/authorize?%s", g.URL, val.Encode())
+
+	return requestToken, authorizeURL, nil
+}
+
+//AuthorizeToken returns the authorized token (and its secret)
+//from the request token and the verifier got on authorize url
+func (g *GitlabConsumer) AuthorizeToken(state, code string) (string, string, error) {
+	log.Debug("AuthorizeToken> Gitlab send code %s for state %s", code, state)
+
+	params := url.Values{}
+	params.Add("client_id", g.ClientID)
+	params.Add("client_secret", g.ClientSecret)
+	params.Add("code", code)
+	params.Add("grant_type", "authorization_code")
+	params.Add("redirect_uri", g.AuthorizationCallbackURL)
+
+	status, res, err := g.postForm("/oauth/token", params)
+	if err != nil {
+		return "", "", err
+	}
+
+	glResponse := map[string]interface{}{}
+	if err := json.Unmarshal(res, &glResponse); err != nil {
+		return "", "", fmt.Errorf("Unable to parse gitlab response (%d) %s ", status, string(res))
+	}
+
+	accessToken, ok := glResponse["access_token"].(string)
+	if !ok || accessToken == "" {
+		return "", "", fmt.Errorf("No access token in gitlab response (%d) %s ", status, string(res))
+	}
+
+	return accessToken, state, nil
+}
+
+//GetAuthorized returns an authorized client
+func (g *GitlabConsumer) GetAuthorized(accessToken, accessTokenSecret string) (sdk.RepositoriesManagerClient, error) {
+	return &GitlabClient{
+		URL:              g.URL,
+		OAuthToken:       accessToken,
+		DisableSetStatus: g.DisableSetStatus,
+		DisableStatusURL: g.DisableStatusURL,
+	}, nil
+}
+
+//HooksSupported returns true if the driver technically support hook
+func (g *GitlabConsumer) HooksSupported() bool {
+	return true
+}
+
+//PollingSupported returns true if the driver technically support polling
+func (g *GitlabConsumer) PollingSupported() bool {
+	return true
+}
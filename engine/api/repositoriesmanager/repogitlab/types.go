@@ -0,0 +1,165 @@
+package repogitlab
+
+import "time"
+
+//Project represents a Gitlab project
+type Project struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+	WebURL            string `json:"web_url"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+	SSHURLToRepo      string `json:"ssh_url_to_repo"`
+}
+
+//Branch represents a branch of a Gitlab project
+type Branch struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+	Commit  Commit `json:"commit"`
+}
+
+//Commit represents a Gitlab commit
+type Commit struct {
+	ID           string    `json:"id"`
+	ShortID      string    `json:"short_id"`
+	Title        string    `json:"title"`
+	Message      string    `json:"message"`
+	AuthorName   string    `json:"author_name"`
+	AuthorEmail  string    `json:"author_email"`
+	AuthoredDate time.Time `json:"authored_date"`
+	ParentIDs    []string  `json:"parent_ids"`
+	WebURL       string    `json:"web_url"`
+}
+
+//User represents a Gitlab user
+type User struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+//Hook represents a Gitlab project hook
+type Hook struct {
+	ID                    int    `json:"id,omitempty"`
+	URL                   string `json:"url"`
+	PushEvents            bool   `json:"push_events"`
+	TagPushEvents         bool   `json:"tag_push_events"`
+	MergeRequestsEvents   bool   `json:"merge_requests_events"`
+	EnableSSLVerification bool   `json:"enable_ssl_verification"`
+	Token                 string `json:"token,omitempty"`
+}
+
+//Event represents an event from the Gitlab project events API
+type Event struct {
+	ActionName  string    `json:"action_name"`
+	TargetID    int       `json:"target_id"`
+	TargetIID   int       `json:"target_iid"`
+	TargetType  string    `json:"target_type"`
+	TargetTitle string    `json:"target_title"`
+	CreatedAt   time.Time `json:"created_at"`
+	Author      User      `json:"author"`
+	PushData    *PushData `json:"push_data"`
+}
+
+//PushData is the detail of a push event
+type PushData struct {
+	CommitCount int    `json:"commit_count"`
+	Action      string `json:"action"` // pushed, created, removed
+	RefType     string `json:"ref_type"`
+	CommitFrom  string `json:"commit_from"`
+	CommitTo    string `json:"commit_to"`
+	Ref         string `json:"ref"`
+	CommitTitle string `json:"commit_title"`
+}
+
+//MergeRequest represents a Gitlab merge request
+type MergeRequest struct {
+	ID              int    `json:"id"`
+	IID             int    `json:"iid"`
+	Title           string `json:"title"`
+	State           string `json:"state"`
+	WebURL          string `json:"web_url"`
+	SourceBranch    string `json:"source_branch"`
+	TargetBranch    string `json:"target_branch"`
+	SourceProjectID int    `json:"source_project_id"`
+	TargetProjectID int    `json:"target_project_id"`
+	SHA             string `json:"sha"`
+	Author          User   `json:"author"`
+}
+
+//CreateStatus represents create a commit status API Payload
+type CreateStatus struct {
+	State       string `json:"state"`
+	Ref         string `json:"ref,omitempty"`
+	Name        string `json:"name"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description"`
+}
+
+//CreateNote represents create a merge request note API Payload
+type CreateNote struct {
+	Body string `json:"body"`
+}
+
+//PushHook is the payload of a Gitlab "Push Hook" webhook
+type PushHook struct {
+	ObjectKind   string `json:"object_kind"`
+	Before       string `json:"before"`
+	After        string `json:"after"`
+	Ref          string `json:"ref"`
+	UserName     string `json:"user_name"`
+	UserUsername string `json:"user_username"`
+	UserEmail    string `json:"user_email"`
+	UserAvatar   string `json:"user_avatar"`
+	Project      struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		DefaultBranch     string `json:"default_branch"`
+	} `json:"project"`
+	Commits []struct {
+		ID        string    `json:"id"`
+		Message   string    `json:"message"`
+		Timestamp time.Time `json:"timestamp"`
+		URL       string    `json:"url"`
+		Author    struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	} `json:"commits"`
+}
+
+//MergeRequestHook is the payload of a Gitlab "Merge Request Hook" webhook
+type MergeRequestHook struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Name      string `json:"name"`
+		Username  string `json:"username"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"user"`
+	ObjectAttributes struct {
+		ID              int    `json:"id"`
+		IID             int    `json:"iid"`
+		Title           string `json:"title"`
+		State           string `json:"state"`
+		Action          string `json:"action"`
+		URL             string `json:"url"`
+		SourceBranch    string `json:"source_branch"`
+		TargetBranch    string `json:"target_branch"`
+		SourceProjectID int    `json:"source_project_id"`
+		TargetProjectID int    `json:"target_project_id"`
+		LastCommit      struct {
+			ID        string    `json:"id"`
+			Message   string    `json:"message"`
+			Timestamp time.Time `json:"timestamp"`
+			URL       string    `json:"url"`
+			Author    struct {
+				Name  string `json:"name"`
+				Email string `json:"email"`
+			} `json:"author"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/database"
+	"github.com/ovh/cds/engine/api/repositoriesmanager/repogitea"
 	"github.com/ovh/cds/engine/api/repositoriesmanager/repogithub"
+	"github.com/ovh/cds/engine/api/repositoriesmanager/repogitlab"
 	"github.com/ovh/cds/engine/api/repositoriesmanager/repostash"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -24,15 +26,28 @@ var (
 
 //InitializeOpts is the struct to init the package
 type InitializeOpts struct {
-	KeysDirectory          string
-	UIBaseURL              string
-	APIBaseURL             string
-	DisableStashSetStatus  bool
-	DisableGithubSetStatus bool
-	DisableGithubStatusURL bool
-	GithubSecret           string
-	StashPrivateKey        string
-	StashConsumerKey       string
+	KeysDirectory                  string
+	UIBaseURL                      string
+	APIBaseURL                     string
+	DisableStashSetStatus          bool
+	DisableGithubSetStatus         bool
+	DisableGithubStatusURL         bool
+	DisableGitlabSetStatus         bool
+	DisableGitlabStatusURL         bool
+	DisableGiteaSetStatus          bool
+	DisableGiteaStatusURL          bool
+	EnableGithubPullRequestComment bool
+	EnableStashPullRequestComment  bool
+	EnableGitlabPullRequestComment bool
+	EnableGiteaPullRequestComment  bool
+	GithubSecret                   string
+	GitlabSecret                   string
+	GitlabHookSecret               string
+	GiteaSecret                    string
+	GiteaHookSecret                string
+	ForkPullRequestPolicy          string
+	StashPrivateKey                string
+	StashConsumerKey               string
 }
 
 //Initialize initialize private keys
@@ -41,6 +56,8 @@ func Initialize(o InitializeOpts) error {
 	options = o
 	repogithub.Init(o.APIBaseURL, o.UIBaseURL)
 	repostash.Init(o.APIBaseURL, o.UIBaseURL)
+	repogitlab.Init(o.APIBaseURL, o.UIBaseURL, o.GitlabHookSecret)
+	repogitea.Init(o.APIBaseURL, o.UIBaseURL, o.GiteaHookSecret)
 
 	_db := database.DB()
 	if _db == nil {
@@ -69,6 +86,25 @@ func Initialize(o InitializeOpts) error {
 					// GithubSecret is already the real secret, not a path to a file
 					found = true
 				}
+			case sdk.Gitlab:
+				if o.GitlabSecret != "" {
+					log.Info("RepositoriesManager> Found a client-secret for %s", rm.Name)
+					found = true
+				}
+				if o.GitlabHookSecret == "" {
+					log.Warning("RepositoriesManager> No hook secret configured: the hooks of %s will be rejected", rm.Name)
+				}
+			case sdk.Gitea:
+				if o.GiteaSecret != "" {
+					log.Info("RepositoriesManager> Found a client-secret for %s", rm.Name)
+					found = true
+				} else if gitea, ok := rm.Consumer.(*repogitea.GiteaConsumer); ok && gitea.ClientID == "" {
+					// without client-id, gitea users authenticate with personal access tokens: no client-secret is needed
+					found = true
+				}
+				if o.GiteaHookSecret == "" {
+					log.Warning("RepositoriesManager> No hook secret configured: the hooks of %s will be rejected", rm.Name)
+				}
 			}
 
 			if found {
@@ -200,6 +236,82 @@ func New(t sdk.RepositoriesManagerType, id int64, name, URL string, args map[str
 			PollingSupported: *withPolling && github.PollingSupported(),
 		}
 
+		return &rm, nil
+	case sdk.Gitlab:
+		var gitlab *repogitlab.GitlabConsumer
+
+		//Check if it isn't coming from the DB
+		if id == 0 || consumerData == "" {
+			//Check args
+			if len(args) < 1 || args["client-id"] == "" || options.GitlabSecret == "" {
+				return nil, fmt.Errorf("client-id args and client-secret (in cds configuration) are mandatory to connect to gitlab : %v", args)
+			}
+			gitlab = repogitlab.New(URL, args["client-id"], options.GitlabSecret, options.APIBaseURL+"/repositories_manager/oauth2/callback")
+		} else {
+			//It's coming from the database, we just have to unmarshal data from the DB to get consumerData
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(consumerData), &data); err != nil {
+				log.Warning("New> Error %s", err)
+				return nil, err
+			}
+			clientID, _ := data["client-id"].(string)
+			gitlab = repogitlab.New(URL, clientID, options.GitlabSecret, options.APIBaseURL+"/repositories_manager/oauth2/callback")
+		}
+
+		gitlab.DisableSetStatus = options.DisableGitlabSetStatus
+		gitlab.DisableStatusURL = options.DisableGitlabStatusURL
+
+		if gitlab.DisableSetStatus {
+			log.Debug("RepositoriesManager> ⚠ Gitlab Statuses are disabled")
+		}
+
+		rm := sdk.RepositoriesManager{
+			ID:               id,
+			Consumer:         gitlab,
+			Name:             name,
+			URL:              URL,
+			Type:             sdk.Gitlab,
+			HooksSupported:   gitlab.HooksSupported(),
+			PollingSupported: gitlab.PollingSupported(),
+		}
+		return &rm, nil
+	case sdk.Gitea:
+		var gitea *repogitea.GiteaConsumer
+
+		//Check if it isn't coming from the DB
+		if id == 0 || consumerData == "" {
+			//client-id is optional, without it users authenticate with personal access tokens
+			if args["client-id"] != "" && options.GiteaSecret == "" {
+				return nil, fmt.Errorf("client-secret (in cds configuration) is mandatory to connect to gitea with oauth2 : %v", args)
+			}
+			gitea = repogitea.New(URL, args["client-id"], options.GiteaSecret, options.APIBaseURL+"/repositories_manager/oauth2/callback")
+		} else {
+			//It's coming from the database, we just have to unmarshal data from the DB to get consumerData
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(consumerData), &data); err != nil {
+				log.Warning("New> Error %s", err)
+				return nil, err
+			}
+			clientID, _ := data["client-id"].(string)
+			gitea = repogitea.New(URL, clientID, options.GiteaSecret, options.APIBaseURL+"/repositories_manager/oauth2/callback")
+		}
+
+		gitea.DisableSetStatus = options.DisableGiteaSetStatus
+		gitea.DisableStatusURL = options.DisableGiteaStatusURL
+
+		if gitea.DisableSetStatus {
+			log.Debug("RepositoriesManager> ⚠ Gitea Statuses are disabled")
+		}
+
+		rm := sdk.RepositoriesManager{
+			ID:               id,
+			Consumer:         gitea,
+			Name:             name,
+			URL:              URL,
+			Type:             sdk.Gitea,
+			HooksSupported:   gitea.HooksSupported(),
+			PollingSupported: gitea.PollingSupported(),
+		}
 		return &rm, nil
 	}
 	return nil, fmt.Errorf("Unknown type %s. Cannot instanciate repositories manager t=%s id=%d name=%s url=%s args=%s consumerData=%s", t, t, id, name, URL, args, consumerData)
@@ -226,8 +338,8 @@ func initRepositoriesManager(db gorp.SqlExecutor, rm *sdk.RepositoriesManager, d
 		return nil
 	}
 
-	if rm.Type == sdk.Github {
-		// nothing to do here for github
+	if rm.Type == sdk.Github || rm.Type == sdk.Gitlab || rm.Type == sdk.Gitea {
+		// nothing to do here for github, gitlab and gitea
 		return nil
 	}
 	return fmt.Errorf("Unsupported repositories manager : %s: %s", rm.Name, rm.Type)
@@ -345,7 +345,7 @@ func (s *StashClient) DeleteEvents(string, []interface{}) ([]sdk.VCSDeleteEvent,
 	return nil, fmt.Errorf("Not implemented on stash")
 }
 
-//PullRequestEvents is not implemented
+//PullRequestEvents is not supported: Stash events are neither polled nor received on hooks, so pull requests are not built on Stash
 func (s *StashClient) PullRequestEvents(string, []interface{}) ([]sdk.VCSPullRequestEvent, error) {
 	return nil, fmt.Errorf("Not implemented on stash")
 }
@@ -359,9 +359,16 @@ const (
 //SetStatus set build status on stash
 func (s *StashClient) SetStatus(event sdk.Event) error {
 	log.Debug("process> receive: type:%s all: %+v", event.EventType, event)
-	var eventpb sdk.EventPipelineBuild
 
-	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
+	var status *stash.Status
+	var hash string
+	var err error
+	switch event.EventType {
+	case fmt.Sprintf("%T", sdk.EventPipelineBuild{}):
+		status, hash, err = processEventPipelineBuild(event)
+	case fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}):
+		status, hash, err = processEventWorkflowNodeRun(event)
+	default:
 		return nil
 	}
 
@@ -370,11 +377,30 @@ func (s *StashClient) SetStatus(event sdk.Event) error {
 		return nil
 	}
 
-	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+	if err != nil {
 		log.Warning("Error during consumption: %s", err)
 		return err
 	}
 
+	//Nothing to send
+	if status == nil {
+		return nil
+	}
+
+	log.Debug("SetStatus> hash:%s status:%+v", hash, status)
+	if err := s.client.Commits.SetStatus(hash, *status); err != nil {
+		return fmt.Errorf("SetStatus> err on bitbucket: %ss", err)
+	}
+
+	return nil
+}
+
+func processEventPipelineBuild(event sdk.Event) (*stash.Status, string, error) {
+	var eventpb sdk.EventPipelineBuild
+	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+		return nil, "", err
+	}
+
 	log.Debug("Process event:%+v", event)
 
 	cdsProject := eventpb.ProjectKey
@@ -398,19 +424,58 @@ func (s *StashClient) SetStatus(event sdk.Event) error {
 		url.QueryEscape(cdsEnvironmentName),
 	)
 
-	status := stash.Status{
+	status := &stash.Status{
 		Key:   key,
 		Name:  fmt.Sprintf("%s%d", key, cdsBuildNumber),
 		State: getBitbucketStateFromStatus(eventpb.Status),
 		URL:   url,
 	}
 
-	log.Debug("SetStatus> hash:%s status:%+v", eventpb.Hash, status)
-	if err := s.client.Commits.SetStatus(eventpb.Hash, status); err != nil {
-		return fmt.Errorf("SetStatus> err on bitbucket: %ss", err)
+	return status, eventpb.Hash, nil
+}
+
+func processEventWorkflowNodeRun(event sdk.Event) (*stash.Status, string, error) {
+	var eventNR sdk.EventWorkflowNodeRun
+	if err := mapstructure.Decode(event.Payload, &eventNR); err != nil {
+		return nil, "", err
 	}
 
-	return nil
+	//We only manage status Building, Success and Failure
+	if eventNR.Status != sdk.StatusBuilding &&
+		eventNR.Status != sdk.StatusSuccess &&
+		eventNR.Status != sdk.StatusFail {
+		return nil, "", nil
+	}
+
+	if eventNR.Hash == "" {
+		return nil, "", nil
+	}
+
+	log.Debug("Process event:%+v", event)
+
+	key := fmt.Sprintf("%s-%s-%s",
+		eventNR.ProjectKey,
+		eventNR.WorkflowName,
+		eventNR.PipelineName,
+	)
+
+	url := fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d",
+		uiURL,
+		eventNR.ProjectKey,
+		eventNR.WorkflowName,
+		eventNR.Number,
+		eventNR.ID,
+	)
+
+	status := &stash.Status{
+		Key:         key,
+		Name:        fmt.Sprintf("%s-%d.%d", key, eventNR.Number, eventNR.SubNumber),
+		State:       getBitbucketStateFromStatus(eventNR.Status),
+		URL:         url,
+		Description: fmt.Sprintf("Workflow %s - pipeline %s: %s", eventNR.WorkflowName, eventNR.PipelineName, eventNR.Status.String()),
+	}
+
+	return status, eventNR.Hash, nil
 }
 
 func getBitbucketStateFromStatus(status sdk.Status) string {
@@ -0,0 +1,108 @@
+package repostash
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/go-stash/go-stash/oauth1"
+	"github.com/go-stash/go-stash/stash"
+
+	"github.com/ovh/cds/sdk"
+)
+
+//PullRequests returns the list of opened pull requests on a repository
+func (s *StashClient) PullRequests(fullname string) ([]sdk.VCSPullRequest, error) {
+	t := strings.Split(fullname, "/")
+	if len(t) != 2 {
+		return nil, sdk.ErrRepoNotFound
+	}
+
+	stashPRs, err := s.client.PullRequests.List(t[0], t[1], "", "", "OPEN", "", true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]sdk.VCSPullRequest, 0, len(stashPRs))
+	for _, pr := range stashPRs {
+		p := sdk.VCSPullRequest{
+			ID:    pr.Id,
+			Title: pr.Title,
+		}
+		if pr.Link != nil {
+			p.URL = fmt.Sprintf("%s%s", s.url, pr.Link.URL)
+		}
+		if pr.Author != nil && pr.Author.User != nil {
+			p.User = sdk.VCSAuthor{
+				Name:        pr.Author.User.Username,
+				DisplayName: pr.Author.User.DisplayName,
+				Email:       pr.Author.User.EmailAddress,
+			}
+		}
+		if pr.FromRef != nil {
+			p.Head = sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: pr.FromRef.Id, DisplayID: pr.FromRef.DisplayId, LatestCommit: pr.FromRef.LatestChangeset},
+				Commit: sdk.VCSCommit{Hash: pr.FromRef.LatestChangeset},
+			}
+		}
+		if pr.ToRef != nil {
+			p.Base = sdk.VCSPushEvent{
+				Branch: sdk.VCSBranch{ID: pr.ToRef.Id, DisplayID: pr.ToRef.DisplayId, LatestCommit: pr.ToRef.LatestChangeset},
+				Commit: sdk.VCSCommit{Hash: pr.ToRef.LatestChangeset},
+			}
+		}
+		prs = append(prs, p)
+	}
+	return prs, nil
+}
+
+//PullRequestComment adds a comment on a pull request
+func (s *StashClient) PullRequestComment(fullname string, id int, text string) error {
+	t := strings.Split(fullname, "/")
+	if len(t) != 2 {
+		return sdk.ErrRepoNotFound
+	}
+
+	b, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/comments", t[0], t[1], id)
+	return s.do(http.MethodPost, path, b)
+}
+
+//do sends a signed request to the bitbucket core API. It is used for routes which are not handled by go-stash
+func (s *StashClient) do(method, path string, values []byte) error {
+	consumer := oauth1.Consumer{
+		ConsumerKey:           s.client.ConsumerKey,
+		ConsumerSecret:        s.client.ConsumerSecret,
+		ConsumerPrivateKeyPem: s.client.ConsumerPrivateKeyPem,
+	}
+	token := oauth1.NewAccessToken(s.client.AccessToken, s.client.TokenSecret, nil)
+
+	req, err := http.NewRequest(method, s.client.GetFullApiUrl("core")+path, bytes.NewBuffer(values))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := consumer.Sign(req, token); err != nil {
+		return err
+	}
+
+	resp, err := stash.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("bitbucket error %d on %s %s: %s", resp.StatusCode, method, path, body)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package repostash
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type receivedRequest struct {
+	method, path, query string
+	body                []byte
+}
+
+//newTestClient starts a server answering the Bitbucket API requests with the handler and returns a client of this server.
+//The client signs its requests with a private key generated for the test
+func newTestClient(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) (*StashClient, *[]receivedRequest, func()) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "cds-stash-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pem.Encode(f, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	received := []receivedRequest{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.Contains(auth, `oauth_consumer_key="cds"`) || !strings.Contains(auth, `oauth_token="my-token"`) || !strings.Contains(auth, `oauth_signature_method="RSA-SHA1"`) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, receivedRequest{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, body: body})
+		handler(w, r)
+	}))
+
+	c, err := New(ts.URL, "cds", f.Name()).GetAuthorized("my-token", "my-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*StashClient), &received, func() {
+		ts.Close()
+		os.Remove(f.Name())
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == "" {
+			w.Write([]byte(`{"isLastPage": false, "nextPageStart": 1, "values": [{"id": 3, "title": "Fix the build", "link": {"url": "/projects/PRJ/repos/demo/pull-requests/3"}, "author": {"user": {"name": "alice", "displayName": "Alice", "emailAddress": "alice@example.com"}}, "fromRef": {"id": "refs/heads/fix", "displayId": "fix", "latestChangeset": "abc"}, "toRef": {"id": "refs/heads/master", "displayId": "master", "latestChangeset": "def"}}]}`))
+			return
+		}
+		w.Write([]byte(`{"isLastPage": true, "values": [{"id": 4, "title": "Add a feature", "fromRef": {"id": "refs/heads/feat", "displayId": "feat", "latestChangeset": "123"}}]}`))
+	})
+	defer done()
+
+	prs, err := c.PullRequests("PRJ/demo")
+	assert.NoError(t, err)
+	assert.Len(t, *received, 2)
+	assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/demo/pull-requests", (*received)[0].path)
+	assert.Equal(t, "state=OPEN&withAttributes=false&withProperties=false", (*received)[0].query)
+	assert.Contains(t, (*received)[1].query, "start=1")
+
+	assert.Len(t, prs, 2)
+	assert.Equal(t, 3, prs[0].ID)
+	assert.Equal(t, c.url+"/projects/PRJ/repos/demo/pull-requests/3", prs[0].URL)
+	assert.Equal(t, "alice", prs[0].User.Name)
+	assert.Equal(t, "Alice", prs[0].User.DisplayName)
+	assert.Equal(t, "fix", prs[0].Head.Branch.DisplayID)
+	assert.Equal(t, "abc", prs[0].Head.Commit.Hash)
+	assert.Equal(t, "refs/heads/master", prs[0].Base.Branch.ID)
+	assert.Equal(t, 4, prs[1].ID)
+	assert.Equal(t, "123", prs[1].Head.Commit.Hash)
+}
+
+func TestPullRequestComment(t *testing.T) {
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer done()
+
+	assert.NoError(t, c.PullRequestComment("PRJ/demo", 3, "Workflow my-workflow: Success"))
+	assert.Len(t, *received, 1)
+	assert.Equal(t, http.MethodPost, (*received)[0].method)
+	assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/demo/pull-requests/3/comments", (*received)[0].path)
+
+	comment := map[string]string{}
+	assert.NoError(t, json.Unmarshal((*received)[0].body, &comment))
+	assert.Equal(t, map[string]string{"text": "Workflow my-workflow: Success"}, comment)
+
+	assert.Equal(t, sdk.ErrRepoNotFound, c.PullRequestComment("demo", 3, "Workflow my-workflow: Success"))
+}
+
+func TestPullRequestCommentError(t *testing.T) {
+	c, _, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	defer done()
+
+	assert.Error(t, c.PullRequestComment("PRJ/demo", 3, "Workflow my-workflow: Success"))
+}
+
+func TestSetStatus(t *testing.T) {
+	Init("https://cds-api.example.com", "https://cds.example.com")
+	c, received, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer done()
+
+	e := sdk.Event{
+		EventType: "sdk.EventWorkflowNodeRun",
+		Payload: map[string]interface{}{
+			"ID":           int64(42),
+			"Number":       int64(12),
+			"SubNumber":    int64(1),
+			"Status":       sdk.StatusSuccess,
+			"ProjectKey":   "DEMO",
+			"WorkflowName": "my-workflow",
+			"PipelineName": "build",
+			"Hash":         "0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b",
+		},
+	}
+	assert.NoError(t, c.SetStatus(e))
+	assert.Len(t, *received, 1)
+	assert.Equal(t, http.MethodPost, (*received)[0].method)
+	assert.Equal(t, "/rest/build-status/1.0/commits/0b1c4f2e6a3d9c8b7a6f5e4d3c2b1a0f9e8d7c6b", (*received)[0].path)
+
+	status := map[string]string{}
+	assert.NoError(t, json.Unmarshal((*received)[0].body, &status))
+	assert.Equal(t, map[string]string{
+		"key":         "DEMO-my-workflow-build",
+		"name":        "DEMO-my-workflow-build-12.1",
+		"state":       successful,
+		"url":         "https://cds.example.com/project/DEMO/workflow/my-workflow/run/12/node/42",
+		"description": "Workflow my-workflow - pipeline build: Success",
+	}, status)
+
+	c.disableSetStatus = true
+	assert.NoError(t, c.SetStatus(e))
+	assert.Len(t, *received, 1)
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -20,6 +22,7 @@ import (
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 var (
@@ -53,6 +56,11 @@ type HandlerConfig struct {
 	method              string
 	handler             Handler
 	isDeprecated        bool
+	readOnly            bool
+	capability          sdk.Capability
+	accessTokenScope    sdk.AccessTokenScope
+	auditSnapshot       bool
+	doc                 *HandlerDoc
 }
 
 // ServeAbsoluteFile Serve file to download
@@ -63,6 +71,7 @@ func (r *Router) ServeAbsoluteFile(uri, path, filename string) {
 		http.ServeFile(w, r, path)
 	}
 	router.mux.HandleFunc(r.prefix+uri, f)
+	mapAbsoluteFiles[r.prefix+uri] = filename
 }
 
 func compress(fn http.HandlerFunc) http.HandlerFunc {
@@ -145,6 +154,9 @@ func newRouter(a auth.Driver, m *mux.Router, p string) *Router {
 
 var mapRouterConfigs = map[string]*RouterConfig{}
 
+// mapAbsoluteFiles contains the name of the files served by ServeAbsoluteFile, by route
+var mapAbsoluteFiles = map[string]string{}
+
 // HandlerConfigParam is a type used in handler configuration, to set specific config on a route given a method
 type HandlerConfigParam func(*HandlerConfig)
 
@@ -153,6 +165,7 @@ type HandlerConfigFunc func(Handler, ...HandlerConfigParam) *HandlerConfig
 
 // Handle adds all handler for their specific verb in gorilla router for given uri
 func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
+	route := uri
 	uri = r.prefix + uri
 	cfg := &RouterConfig{
 		config: map[string]*HandlerConfig{},
@@ -160,6 +173,9 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 	mapRouterConfigs[uri] = cfg
 
 	for i := range handlers {
+		if doc, ok := routesDoc[handlers[i].method+" "+route]; ok {
+			handlers[i].doc = &doc
+		}
 		cfg.config[handlers[i].method] = handlers[i]
 	}
 
@@ -169,7 +185,7 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 		// Authorization
 		w.Header().Add("Access-Control-Allow-Origin", "*")
 		w.Header().Add("Access-Control-Allow-Methods", "GET,OPTIONS,PUT,POST,DELETE")
-		w.Header().Add("Access-Control-Allow-Headers", "Accept, Origin, Referer, User-Agent, Content-Type, Authorization, Session-Token, Last-Event-Id, If-Modified-Since, Content-Disposition")
+		w.Header().Add("Access-Control-Allow-Headers", "Accept, Origin, Referer, User-Agent, Content-Type, Authorization, Session-Token, Access-Token, Last-Event-Id, If-Modified-Since, Content-Disposition")
 		w.Header().Add("Access-Control-Expose-Headers", "Accept, Origin, Referer, User-Agent, Content-Type, Authorization, Session-Token, Last-Event-Id, ETag, Content-Disposition")
 		w.Header().Add("X-Api-Time", time.Now().Format(time.RFC3339))
 		w.Header().Add("ETag", fmt.Sprintf("%d", time.Now().Unix()))
@@ -185,10 +201,19 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 			return
 		}
 
+		//Start the span of the request, child of the span of the caller
+		ctx, span := tracing.Start(tracing.ExtractHTTP(req), req.Method+" "+uri)
+		span.SetAttribute("http.method", req.Method)
+		span.SetAttribute("http.route", uri)
+		span.SetAttribute("http.url", req.URL.String())
+		defer span.Finish()
+		req = req.WithContext(ctx)
+
 		start := time.Now()
 		defer func() {
 			end := time.Now()
 			latency := end.Sub(start)
+			apiRequestDuration.Observe(latency.Seconds(), req.Method, uri)
 			if rc.isDeprecated {
 				log.Error("%-7s | %13v | DEPRECATED ROUTE | %v", req.Method, latency, req.URL)
 				w.Header().Add("X-CDS-WARNING", "deprecated route")
@@ -212,17 +237,36 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 		if rc.auth {
 			if err := r.checkAuthentication(db, req.Header, c); err != nil {
 				WriteError(w, req, sdk.WrapError(sdk.ErrUnauthorized, "Router> Authorization denied on %s %s for %s agent %s : %s", req.Method, req.URL, req.RemoteAddr, c.Agent, err))
+				if isDeniedAudited(c) {
+					recordAudit(db, req, uri, c, sdk.ErrUnauthorized.Status, nil, nil)
+				}
 				return
 			}
 		}
 
-		if c.User != nil {
+		//The rate limits are checked before loading the permissions, to spare the database
+		if ok, wait := checkRateLimit(strings.TrimPrefix(uri, r.prefix), c); !ok {
+			w.Header().Set("Retry-After", retryAfter(wait))
+			WriteError(w, req, sdk.ErrTooManyRequests)
+			return
+		}
+
+		if c.User != nil && (c.AccessToken == nil || c.AccessToken.GroupID == 0) {
 			if err := loadUserPermissions(db, c.User); err != nil {
 				WriteError(w, req, sdk.WrapError(sdk.ErrUnauthorized, "Router> Unable to load user %s permission: %s", c.User.ID, err))
 				return
 			}
 		}
 
+		if c.AccessToken != nil && c.AccessToken.GroupID != 0 {
+			g, err := loadGroupPermissions(db, c.AccessToken.GroupID)
+			if err != nil {
+				WriteError(w, req, sdk.WrapError(sdk.ErrUnauthorized, "Router> cannot load group permissions for access token %d err:%s", c.AccessToken.ID, err))
+				return
+			}
+			c.User.Groups = append(c.User.Groups, *g)
+		}
+
 		if c.Hatchery != nil {
 			g, err := loadGroupPermissions(db, c.Hatchery.GroupID)
 			if err != nil {
@@ -280,17 +324,17 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 				permissionOk = checkWorkerPermission(db, rc, mux.Vars(req), c)
 			}
 
-			if rc.needUsernameOrAdmin && (c.User.Admin || (c.User.Username == mux.Vars(req)["username"])) {
+			if rc.needUsernameOrAdmin {
 				// get / update / delete user -> for admin or current user
 				// if not admin and currentUser != username in request -> ko
-				permissionOk = true
+				permissionOk = checkUsernameOrAdmin(db, c, mux.Vars(req)["username"])
 			}
 
 			if rc.needAdmin && c.User.Admin {
 				permissionOk = true
 			}
-			if !rc.needAdmin && !c.User.Admin {
-				permissionOk = checkPermission(mux.Vars(req), c, getPermissionByMethod(req.Method, rc.isExecution))
+			if !rc.needAdmin && !rc.needUsernameOrAdmin && !c.User.Admin {
+				permissionOk = checkPermission(mux.Vars(req), c, getCapability(rc, req.Method))
 			}
 
 			// else case, just need auth
@@ -299,19 +343,59 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 			}
 		}
 
+		if permissionOk && c.AccessToken != nil && !checkAccessTokenScope(c.AccessToken, rc, req.Method) {
+			permissionOk = false
+		}
+
+		audited := isAudited(req, c)
+
 		if !permissionOk {
 			WriteError(w, req, sdk.ErrForbidden)
+			if isDeniedAudited(c) {
+				recordAudit(db, req, uri, c, sdk.ErrForbidden.Status, nil, nil)
+			}
 			return
 		}
 
-		if err := rc.handler(w, req, db, c); err != nil {
+		//Keep the state of the resource before the call for the audit log
+		var before json.RawMessage
+		if audited && rc.auditSnapshot {
+			before = auditSnapshot(auditGetHandler(uri), req, db, c)
+		}
+
+		//Only the audited requests are wrapped: streaming handlers need the original writer
+		aw := &auditResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		if audited {
+			w = aw
+		}
+
+		//The read only handlers run on the replica, if it is up to date. Authentication and audit stay on the primary
+		handlerDB := db
+		if rc.readOnly && req.Method == "GET" {
+			if rdb := database.GetReadDBMap(); rdb != nil && rdb.Db != nil {
+				handlerDB = rdb
+			}
+		}
+		if err := rc.handler(w, req, handlerDB, c); err != nil {
+			span.SetError(err)
 			WriteError(w, req, err)
+			if audited {
+				recordAudit(db, req, uri, c, aw.status, before, nil)
+			}
 			return
 		}
 
 		if req.Method == "POST" || req.Method == "PUT" || req.Method == "DELETE" {
 			deleteUserPermissionCache(c)
 		}
+
+		if audited {
+			var after json.RawMessage
+			if rc.auditSnapshot {
+				after = auditSnapshot(auditGetHandler(uri), req, db, c)
+			}
+			recordAudit(db, req, uri, c, aw.status, before, after)
+		}
 	}
 	router.mux.HandleFunc(uri, compress(recoverWrap(f)))
 }
@@ -382,6 +466,14 @@ func DELETE(h Handler, cfg ...HandlerConfigParam) *HandlerConfig {
 	return rc
 }
 
+// ReadOnly set the GET handler as read only: it runs on the read replica of the database when there is one
+func ReadOnly() HandlerConfigParam {
+	f := func(rc *HandlerConfig) {
+		rc.readOnly = true
+	}
+	return f
+}
+
 // NeedAdmin set the route for cds admin only (or not)
 func NeedAdmin(admin bool) HandlerConfigParam {
 	f := func(rc *HandlerConfig) {
@@ -414,6 +506,30 @@ func NeedWorker() HandlerConfigParam {
 	return f
 }
 
+// NeedCapability set the capability needed on the route resources, instead of the one given by the method
+func NeedCapability(capability sdk.Capability) HandlerConfigParam {
+	f := func(rc *HandlerConfig) {
+		rc.capability = capability
+	}
+	return f
+}
+
+// NeedAccessTokenScope set the scope an access token needs to call the route, instead of the one given by the capability
+func NeedAccessTokenScope(scope sdk.AccessTokenScope) HandlerConfigParam {
+	f := func(rc *HandlerConfig) {
+		rc.accessTokenScope = scope
+	}
+	return f
+}
+
+// AuditSnapshot records in the audit log the state of the resource before and after the call, computed by the GET handler of the route
+func AuditSnapshot() HandlerConfigParam {
+	f := func(rc *HandlerConfig) {
+		rc.auditSnapshot = true
+	}
+	return f
+}
+
 // Auth set manually whether authorisation layer should be applied
 // Authorization is enabled by default
 func Auth(v bool) HandlerConfigParam {
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// auditResponseWriter keeps the status code written by a handler
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// isAudited returns true if the request has to be recorded in the audit log.
+// Calls from workers and hatcheries are not user actions and are not audited
+func isAudited(req *http.Request, c *businesscontext.Ctx) bool {
+	if req.Method != "POST" && req.Method != "PUT" && req.Method != "DELETE" {
+		return false
+	}
+	return c.Worker == nil && c.Hatchery == nil
+}
+
+// isDeniedAudited returns true if a denied request has to be recorded in the audit log.
+// Denied calls are recorded whatever their method, reads included
+func isDeniedAudited(c *businesscontext.Ctx) bool {
+	return c.Worker == nil && c.Hatchery == nil
+}
+
+// auditGetHandler returns the GET handler describing the resource of a route: the GET handler of the same route,
+// or the one of the parent route, e.g. the list of keys of a project for the deletion of a key
+func auditGetHandler(uri string) *HandlerConfig {
+	if cfg, ok := mapRouterConfigs[uri]; ok && cfg.config["GET"] != nil {
+		return cfg.config["GET"]
+	}
+	if i := strings.LastIndex(uri, "/"); i > 0 {
+		if cfg, ok := mapRouterConfigs[uri[:i]]; ok {
+			return cfg.config["GET"]
+		}
+	}
+	return nil
+}
+
+// auditSnapshot returns the state of the resource of a route, computed by the GET handler of the same route
+func auditSnapshot(get *HandlerConfig, req *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) json.RawMessage {
+	if get == nil || db == nil {
+		return nil
+	}
+
+	getReq := req.WithContext(req.Context())
+	getReq.Method = "GET"
+	getReq.Body = ioutil.NopCloser(bytes.NewReader(nil))
+	getReq.ContentLength = 0
+
+	rec := httptest.NewRecorder()
+	if err := get.handler(rec, getReq, db, c); err != nil || rec.Code != http.StatusOK {
+		return nil
+	}
+	b := rec.Body.Bytes()
+	if !json.Valid(b) {
+		return nil
+	}
+	return audit.Sanitize(b)
+}
+
+// auditTrustedProxies are the reverse proxies allowed to set the X-Forwarded-For header
+var auditTrustedProxies []*net.IPNet
+
+// setAuditTrustedProxies parses the comma separated list of IPs or CIDRs of the trusted reverse proxies
+func setAuditTrustedProxies(s string) error {
+	auditTrustedProxies = nil
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return fmt.Errorf("invalid trusted proxy %s", p)
+			}
+			auditTrustedProxies = append(auditTrustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %s: %v", p, err)
+		}
+		auditTrustedProxies = append(auditTrustedProxies, n)
+	}
+	return nil
+}
+
+// isTrustedProxy returns true if the address is one of a trusted reverse proxy
+func isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range auditTrustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the IP of the caller. X-Forwarded-For is read only when the request comes from a trusted proxy:
+// the caller is the last address which is not a trusted proxy
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	if !isTrustedProxy(host) {
+		return host
+	}
+	fwd := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(fwd) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(fwd[i])
+		if addr == "" {
+			continue
+		}
+		host = addr
+		if !isTrustedProxy(addr) {
+			break
+		}
+	}
+	return host
+}
+
+// recordAudit stores the audit log entry of a request
+func recordAudit(db *gorp.DbMap, req *http.Request, route string, c *businesscontext.Ctx, status int, before, after json.RawMessage) {
+	if db == nil {
+		return
+	}
+	l := &sdk.AuditLog{
+		Created:  time.Now(),
+		RemoteIP: remoteIP(req),
+		Method:   req.Method,
+		Route:    route,
+		URL:      req.URL.String(),
+		Status:   status,
+		Before:   before,
+		After:    after,
+	}
+	if c.User != nil {
+		l.UserID = c.User.ID
+		l.Username = c.User.Username
+	}
+	if err := audit.Insert(db, l); err != nil {
+		log.Error("recordAudit> Unable to record %s %s: %s", req.Method, req.URL, err)
+	}
+}
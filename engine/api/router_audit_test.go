@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_remoteIP(t *testing.T) {
+	defer setAuditTrustedProxies("")
+
+	req := httptest.NewRequest("POST", "/project", nil)
+	req.RemoteAddr = "203.0.113.7:4242"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	//Without trusted proxies, X-Forwarded-For is ignored
+	assert.NoError(t, setAuditTrustedProxies(""))
+	assert.Equal(t, "203.0.113.7", remoteIP(req))
+
+	//X-Forwarded-For is ignored when the caller is not a trusted proxy
+	assert.NoError(t, setAuditTrustedProxies("10.0.0.0/8, 192.168.1.1"))
+	assert.Equal(t, "203.0.113.7", remoteIP(req))
+
+	//Behind trusted proxies, the caller is the last address which is not a proxy
+	req.RemoteAddr = "10.1.2.3:4242"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 198.51.100.1, 192.168.1.1")
+	assert.Equal(t, "198.51.100.1", remoteIP(req))
+
+	assert.Error(t, setAuditTrustedProxies("not an ip"))
+}
+
+func Test_auditRouter(t *testing.T) {
+	db := test.SetupPG(t)
+
+	router = newRouter(auth.TestLocalAuth(t), mux.NewRouter(), "/Test_auditRouter")
+	router.init()
+
+	u, pass := assets.InsertAdminUser(db)
+	pkey := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, pkey, pkey, u)
+
+	k := &sdk.ProjectKey{
+		Key: sdk.Key{
+			Name:    "mykey",
+			Type:    "pgp",
+			Public:  "pub",
+			Private: "priv",
+		},
+		ProjectID: proj.ID,
+	}
+	test.NoError(t, project.InsertKey(db, k))
+
+	call := func(method, route string, body interface{}) *httptest.ResponseRecorder {
+		var b []byte
+		if body != nil {
+			b, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, route, bytes.NewReader(b))
+		req.Header = assets.AuthHeaders(t, u, pass)
+		//The caller is not a trusted proxy, so it can't choose the recorded IP
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		rec := httptest.NewRecorder()
+		router.mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	//The deletion of a key is recorded with the keys of the project before and after the call
+	route := router.getRoute("DELETE", deleteKeyInProjectHandler, map[string]string{"permProjectKey": proj.Key, "name": k.Name})
+	assert.Equal(t, http.StatusOK, call("DELETE", route, nil).Code)
+
+	logs, err := audit.Load(db, audit.Filter{Username: u.Username, Method: "DELETE"})
+	test.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, route, logs[0].URL)
+		assert.Equal(t, "192.0.2.1", logs[0].RemoteIP)
+		assert.True(t, strings.Contains(string(logs[0].Before), "mykey"))
+		assert.False(t, strings.Contains(string(logs[0].After), "mykey"))
+	}
+
+	//Routes without snapshots only record the call
+	route = router.getRoute("POST", addKeyInProjectHandler, map[string]string{"permProjectKey": proj.Key})
+	call("POST", route, sdk.ProjectKey{Key: sdk.Key{Name: "proj-newkey", Type: "ssh"}})
+
+	logs, err = audit.Load(db, audit.Filter{Username: u.Username, Method: "POST"})
+	test.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, route, logs[0].URL)
+		assert.Nil(t, logs[0].Before)
+		assert.Nil(t, logs[0].After)
+	}
+
+	//The changes of the permissions of a group are recorded with the groups of the project before and after the call
+	g := &sdk.Group{Name: sdk.RandomString(10)}
+	test.NoError(t, group.InsertGroup(db, g))
+	route = router.getRoute("POST", addGroupInProject, map[string]string{"permProjectKey": proj.Key})
+	assert.Equal(t, http.StatusOK, call("POST", route, sdk.GroupPermission{Group: *g, Permission: permission.PermissionRead}).Code)
+
+	logs, err = audit.Load(db, audit.Filter{Username: u.Username, Method: "POST", URL: route})
+	test.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.False(t, strings.Contains(string(logs[0].Before), g.Name))
+		assert.True(t, strings.Contains(string(logs[0].After), g.Name))
+	}
+
+	//Denied calls are recorded, reads included
+	lambda, lambdaPass := assets.InsertLambdaUser(db)
+	route = router.getRoute("GET", getProjectHandler, map[string]string{"permProjectKey": proj.Key})
+	req := assets.NewAuthentifiedRequest(t, lambda, lambdaPass, "GET", route, nil)
+	rec := httptest.NewRecorder()
+	router.mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	logs, err = audit.Load(db, audit.Filter{Username: lambda.Username, Method: "GET"})
+	test.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, http.StatusForbidden, logs[0].Status)
+	}
+
+	route = router.getRoute("DELETE", deleteKeyInProjectHandler, map[string]string{"permProjectKey": proj.Key, "name": "unauthenticated"})
+	req = httptest.NewRequest("DELETE", route, nil)
+	rec = httptest.NewRecorder()
+	router.mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	logs, err = audit.Load(db, audit.Filter{Method: "DELETE", URL: route})
+	test.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, http.StatusUnauthorized, logs[0].Status)
+		assert.Equal(t, "", logs[0].Username)
+	}
+}
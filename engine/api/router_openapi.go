@@ -0,0 +1,417 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/sdk"
+)
+
+// HandlerDoc documents a handler in the OpenAPI specification: Request and Response are values of the types of the
+// request and response bodies, nil if the handler has no JSON body
+type HandlerDoc struct {
+	Summary  string
+	Request  interface{}
+	Response interface{}
+}
+
+// openAPISpec is an OpenAPI 3 document
+type openAPISpec struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Servers    []openAPIServer                         `json:"servers,omitempty"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIOperation struct {
+	Summary     string                  `json:"summary,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	OperationID string                  `json:"operationId"`
+	Tags        []string                `json:"tags,omitempty"`
+	Deprecated  bool                    `json:"deprecated,omitempty"`
+	Parameters  []openAPIParameter      `json:"parameters,omitempty"`
+	RequestBody *openAPIBody            `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIBody `json:"responses"`
+	Security    []map[string][]string   `json:"security"`
+	//The permissions needed by the route, as extensions
+	NeedAdmin           bool `json:"x-cds-need-admin,omitempty"`
+	NeedWorker          bool `json:"x-cds-need-worker,omitempty"`
+	NeedHatchery        bool `json:"x-cds-need-hatchery,omitempty"`
+	NeedUsernameOrAdmin bool `json:"x-cds-need-username-or-admin,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Description string                       `json:"description,omitempty"`
+	Required    bool                         `json:"required,omitempty"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// openAPIPathParam matches the variables of the routes, with their optional pattern: {key} or {key:[a-z]+}
+var openAPIPathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// openAPISecuritySchemes are the authentications of the users, workers and hatcheries
+var openAPISecuritySchemes = map[string]*openAPISecurityScheme{
+	"session": {
+		Type:        "apiKey",
+		In:          "header",
+		Name:        sdk.SessionTokenHeader,
+		Description: "Session token of a user, returned by POST /login",
+	},
+	"accessToken": {
+		Type:        "apiKey",
+		In:          "header",
+		Name:        sdk.AccessTokenHeader,
+		Description: "Access token of a user or a group",
+	},
+	"worker": {
+		Type:        "apiKey",
+		In:          "header",
+		Name:        sdk.AuthHeader,
+		Description: "Base64 encoded ID of a registered worker, with the User-Agent " + sdk.WorkerAgent,
+	},
+	"hatchery": {
+		Type:        "apiKey",
+		In:          "header",
+		Name:        sdk.AuthHeader,
+		Description: "Base64 encoded UID of a registered hatchery, with the User-Agent " + sdk.HatcheryAgent,
+	},
+}
+
+// openAPISecurity returns the authentications accepted by a handler
+func openAPISecurity(rc *HandlerConfig) []map[string][]string {
+	var schemes []string
+	switch {
+	case !rc.auth:
+	case rc.needWorker:
+		schemes = []string{"worker"}
+	case rc.needHatchery:
+		schemes = []string{"hatchery"}
+	case rc.needAdmin, rc.needUsernameOrAdmin:
+		schemes = []string{"session", "accessToken"}
+	default:
+		schemes = []string{"session", "accessToken", "worker", "hatchery"}
+	}
+
+	security := []map[string][]string{}
+	for _, s := range schemes {
+		security = append(security, map[string][]string{s: {}})
+	}
+	return security
+}
+
+// openAPIDescription describes the permissions needed by a handler
+func openAPIDescription(rc *HandlerConfig) string {
+	switch {
+	case !rc.auth:
+		return "Public route, no authentication needed."
+	case rc.needWorker:
+		return "Workers only."
+	case rc.needHatchery:
+		return "Hatcheries only."
+	case rc.needAdmin:
+		return "CDS administrators only."
+	case rc.needUsernameOrAdmin:
+		return "CDS administrators or the user of the route only."
+	}
+	return ""
+}
+
+// handlerName returns the name of the function of a handler
+func handlerName(h Handler) string {
+	name := runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	name = name[strings.LastIndex(name, ".")+1:]
+	return name
+}
+
+// openAPISchemas builds the schemas of the types of the request and response bodies
+type openAPISchemas map[string]*openAPISchema
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schema returns the schema of t. The named structs are added to the components and referenced
+func (s openAPISchemas) schema(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case t.Implements(marshalerType), reflect.PtrTo(t).Implements(marshalerType):
+		//The types marshaling themselves can't be described
+		return &openAPISchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		name := t.Name()
+		if pkg := path.Base(t.PkgPath()); pkg != "main" {
+			name = pkg + "." + name
+		}
+		if _, ok := s[name]; !ok {
+			//Registered before the properties, for the recursive types
+			s[name] = &openAPISchema{Type: "object"}
+			s[name] = s.object(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	}
+	return &openAPISchema{}
+}
+
+// object returns the schema of the struct t, with the properties marshaled by encoding/json
+func (s openAPISchemas) object(t reflect.Type) *openAPISchema {
+	o := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range s.object(ft).Properties {
+					if _, ok := o.Properties[k]; !ok {
+						o.Properties[k] = v
+					}
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(tag, ",string") {
+			o.Properties[name] = &openAPISchema{Type: "string"}
+			continue
+		}
+		o.Properties[name] = s.schema(f.Type)
+	}
+	return o
+}
+
+// body returns the JSON body of type of v
+func (s openAPISchemas) body(description string, v interface{}) *openAPIBody {
+	return &openAPIBody{
+		Description: description,
+		Content: map[string]*openAPIMediaType{
+			"application/json": {Schema: s.schema(reflect.TypeOf(v))},
+		},
+	}
+}
+
+// openAPI returns the OpenAPI specification of the routes of the router
+func (r *Router) openAPI() *openAPISpec {
+	spec := &openAPISpec{
+		OpenAPI: "3.0.0",
+		Info:    openAPIInfo{Title: "CDS API", Version: sdk.VERSION},
+		Paths:   map[string]map[string]*openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas:         openAPISchemas{},
+			SecuritySchemes: openAPISecuritySchemes,
+		},
+	}
+	if r.url != "" {
+		spec.Servers = []openAPIServer{{URL: r.url}}
+	}
+	schemas := openAPISchemas(spec.Components.Schemas)
+
+	uris := []string{}
+	for uri := range mapRouterConfigs {
+		if strings.HasPrefix(uri, r.prefix) {
+			uris = append(uris, uri)
+		}
+	}
+	sort.Strings(uris)
+
+	operationIDs := map[string]int{}
+	for _, uri := range uris {
+		route := strings.TrimPrefix(uri, r.prefix)
+		p := openAPIPathParam.ReplaceAllString(route, "{$1}")
+		params := []openAPIParameter{}
+		for _, m := range openAPIPathParam.FindAllStringSubmatch(route, -1) {
+			params = append(params, openAPIParameter{Name: m[1], In: "path", Required: true, Schema: &openAPISchema{Type: "string"}})
+		}
+
+		methods := []string{}
+		for method := range mapRouterConfigs[uri].config {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			rc := mapRouterConfigs[uri].config[method]
+			op := &openAPIOperation{
+				OperationID: handlerName(rc.handler),
+				Tags:        []string{strings.SplitN(strings.TrimPrefix(route, "/"), "/", 2)[0]},
+				Deprecated:  rc.isDeprecated,
+				Parameters:  params,
+				Description: openAPIDescription(rc),
+				Responses: map[string]*openAPIBody{
+					"default": schemas.body("Error", sdk.Error{}),
+				},
+				Security:            openAPISecurity(rc),
+				NeedAdmin:           rc.needAdmin,
+				NeedWorker:          rc.needWorker,
+				NeedHatchery:        rc.needHatchery,
+				NeedUsernameOrAdmin: rc.needUsernameOrAdmin,
+			}
+			//The operation IDs must be unique, some handlers are used by several routes
+			if n := operationIDs[op.OperationID]; n > 0 {
+				operationIDs[op.OperationID]++
+				op.OperationID = fmt.Sprintf("%s%d", op.OperationID, n+1)
+			} else {
+				operationIDs[op.OperationID] = 1
+			}
+
+			if rc.doc != nil {
+				op.Summary = rc.doc.Summary
+				if rc.doc.Request != nil {
+					op.RequestBody = schemas.body("", rc.doc.Request)
+					op.RequestBody.Required = true
+				}
+			}
+			if rc.doc != nil && rc.doc.Response != nil {
+				op.Responses["200"] = schemas.body("OK", rc.doc.Response)
+			} else {
+				op.Responses["200"] = &openAPIBody{Description: "OK"}
+			}
+
+			if spec.Paths[p] == nil {
+				spec.Paths[p] = map[string]*openAPIOperation{}
+			}
+			spec.Paths[p][strings.ToLower(method)] = op
+		}
+	}
+
+	for uri, filename := range mapAbsoluteFiles {
+		if !strings.HasPrefix(uri, r.prefix) {
+			continue
+		}
+		route := strings.TrimPrefix(uri, r.prefix)
+		spec.Paths[route] = map[string]*openAPIOperation{
+			"get": {
+				Summary:     "Download " + filename,
+				OperationID: "download" + strings.Replace(strings.Title(strings.Replace(strings.TrimPrefix(route, "/download/"), "/", " ", -1)), " ", "", -1),
+				Tags:        []string{"download"},
+				Responses: map[string]*openAPIBody{
+					"200": {
+						Description: "OK",
+						Content: map[string]*openAPIMediaType{
+							"application/octet-stream": {Schema: &openAPISchema{Type: "string", Format: "binary"}},
+						},
+					},
+				},
+				Security: []map[string][]string{},
+			},
+		}
+	}
+
+	return spec
+}
+
+// undocumentedRoutes returns the routes of the router without documentation, as "METHOD /route"
+func (r *Router) undocumentedRoutes() []string {
+	res := []string{}
+	for uri, cfg := range mapRouterConfigs {
+		if !strings.HasPrefix(uri, r.prefix) {
+			continue
+		}
+		for method, rc := range cfg.config {
+			if rc.doc == nil || rc.doc.Summary == "" {
+				res = append(res, method+" "+strings.TrimPrefix(uri, r.prefix))
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func getOpenAPIHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	return WriteJSON(w, r, router.openAPI(), http.StatusOK)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_routesDoc(t *testing.T) {
+	router = newRouter(nil, mux.NewRouter(), "/Test_routesDoc")
+	router.init()
+
+	assert.Empty(t, router.undocumentedRoutes(), "Every route must be documented in main_routes_doc.go")
+
+	//The documentation of a removed route must be removed too
+	for route := range routesDoc {
+		method := strings.SplitN(route, " ", 2)[0]
+		uri := router.prefix + strings.SplitN(route, " ", 2)[1]
+		rc, ok := mapRouterConfigs[uri]
+		if assert.True(t, ok, "%s is documented but not registered", route) {
+			assert.NotNil(t, rc.config[method], "%s is documented but not registered", route)
+		}
+	}
+}
+
+func Test_openAPI(t *testing.T) {
+	router = newRouter(nil, mux.NewRouter(), "/Test_openAPI")
+	router.init()
+
+	spec := router.openAPI()
+	_, err := json.Marshal(spec)
+	assert.NoError(t, err)
+
+	//The workflow queue is for the workers only
+	op := spec.Paths["/queue/workflows/{permID}/result"]["post"]
+	if assert.NotNil(t, op) {
+		assert.True(t, op.NeedWorker)
+		assert.Equal(t, []map[string][]string{{"worker": {}}}, op.Security)
+		assert.NotNil(t, op.RequestBody)
+	}
+
+	op = spec.Paths["/admin/maintenance"]["get"]
+	if assert.NotNil(t, op) {
+		assert.True(t, op.NeedAdmin)
+		assert.Equal(t, []map[string][]string{{"session": {}}, {"accessToken": {}}}, op.Security)
+	}
+
+	op = spec.Paths["/mon/openapi"]["get"]
+	if assert.NotNil(t, op) {
+		assert.Empty(t, op.Security)
+	}
+
+	op = spec.Paths["/project/{permProjectKey}/workflows/{workflowName}"]["get"]
+	if assert.NotNil(t, op) {
+		assert.Len(t, op.Parameters, 2)
+		assert.Equal(t, "#/components/schemas/sdk.Workflow", op.Responses["200"].Content["application/json"].Schema.Ref)
+	}
+	assert.NotNil(t, spec.Components.Schemas["sdk.Workflow"])
+
+	ids := map[string]bool{}
+	for p, ops := range spec.Paths {
+		for m, op := range ops {
+			assert.False(t, ids[op.OperationID], "Duplicate operation ID %s on %s %s", op.OperationID, m, p)
+			ids[op.OperationID] = true
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/metrics"
+)
+
+// rateLimitGroupDefault is the route group of the routes without a specific rate limit
+const rateLimitGroupDefault = "default"
+
+// apiRateLimited counts the requests rejected by the rate limits by route group
+var apiRateLimited = metrics.NewCounterVec("cds_api_rate_limited_requests_total", "Number of requests rejected by the rate limits by route group", "group")
+
+// rateLimit allows rate requests per second to each identity, with bursts up to burst requests
+type rateLimit struct {
+	rate  float64
+	burst int
+}
+
+// rateLimits are the rate limits by route group, the requests are not limited if nil
+var rateLimits map[string]rateLimit
+
+// initRateLimits loads the rate limits from the configuration
+func initRateLimits() {
+	if !viper.GetBool(viperRateLimitEnabled) {
+		rateLimits = nil
+		return
+	}
+
+	rateLimits = map[string]rateLimit{
+		rateLimitGroupDefault: {
+			rate:  viper.GetFloat64(viperRateLimitDefault + ".rate"),
+			burst: viper.GetInt(viperRateLimitDefault + ".burst"),
+		},
+	}
+	for group := range viper.GetStringMap(viperRateLimitRoutes) {
+		key := viperRateLimitRoutes + "." + group
+		rateLimits[group] = rateLimit{
+			rate:  viper.GetFloat64(key + ".rate"),
+			burst: viper.GetInt(key + ".burst"),
+		}
+	}
+	for group, l := range rateLimits {
+		log.Info("initRateLimits> %s: %.2f requests per second, burst %d", group, l.rate, l.burst)
+	}
+}
+
+// rateLimitGroup returns the route group of a route: the first element of its path, e.g. queue for /queue/workflows
+func rateLimitGroup(uri string) string {
+	group := strings.SplitN(strings.TrimPrefix(uri, "/"), "/", 2)[0]
+	if _, ok := rateLimits[group]; ok {
+		return group
+	}
+	return rateLimitGroupDefault
+}
+
+// rateLimitIdentity returns the identity the requests are limited by: the hatchery, the worker, the group access token or the user
+func rateLimitIdentity(c *businesscontext.Ctx) string {
+	switch {
+	case c.Hatchery != nil:
+		return fmt.Sprintf("hatchery:%d", c.Hatchery.ID)
+	case c.Worker != nil:
+		return "worker:" + c.Worker.ID
+	case c.AccessToken != nil && c.AccessToken.GroupID != 0:
+		return fmt.Sprintf("accesstoken:%d", c.AccessToken.ID)
+	case c.User != nil:
+		return "user:" + c.User.Username
+	}
+	return ""
+}
+
+// checkRateLimit takes a token from the bucket of the identity for the route group of uri. The buckets are stored in the
+// cache, shared by all the API instances. It returns false and the duration to wait if the rate limit is exceeded
+func checkRateLimit(uri string, c *businesscontext.Ctx) (bool, time.Duration) {
+	if rateLimits == nil {
+		return true, 0
+	}
+	identity := rateLimitIdentity(c)
+	if identity == "" {
+		return true, 0
+	}
+
+	group := rateLimitGroup(uri)
+	l := rateLimits[group]
+	if l.rate <= 0 {
+		return true, 0
+	}
+	burst := l.burst
+	if burst < 1 {
+		burst = int(math.Ceil(l.rate))
+	}
+
+	ok, wait := cache.TakeToken(cache.Key("api", "ratelimit", group, identity), l.rate, burst)
+	if !ok {
+		apiRateLimited.Inc(group)
+		log.Warning("checkRateLimit> Rate limit exceeded on %s for %s", group, identity)
+	}
+	return ok, wait
+}
+
+// retryAfter returns the value of the Retry-After header, in seconds
+func retryAfter(wait time.Duration) string {
+	return fmt.Sprintf("%d", int64(math.Ceil(wait.Seconds())))
+}
@@ -0,0 +1,117 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Passphrase encryption is used to protect the secrets of an exported project: they don't depend on the cipher key
+// of the CDS instance and can be imported into another one knowing only the passphrase
+const (
+	passphraseSaltSize   = 16
+	passphraseIterations = 100000
+)
+
+// Passphrase encrypts and decrypts the secrets of an archive with a key derived from the passphrase (PBKDF2-SHA256
+// and AES-256-GCM). The key is derived once: all the secrets encrypted by a Passphrase share the same salt
+type Passphrase struct {
+	passphrase string
+	salt       []byte
+	ciphers    map[string]cipher.AEAD
+}
+
+// NewPassphrase returns a Passphrase to encrypt or decrypt the secrets of an archive
+func NewPassphrase(passphrase string) *Passphrase {
+	return &Passphrase{passphrase: passphrase, ciphers: map[string]cipher.AEAD{}}
+}
+
+// Encrypt encrypts data. It returns base64(salt|nonce|ciphertext)
+func (p *Passphrase) Encrypt(data []byte) (string, error) {
+	if p.passphrase == "" {
+		return "", sdk.ErrInvalidPassphrase
+	}
+
+	if p.salt == nil {
+		salt := make([]byte, passphraseSaltSize)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return "", err
+		}
+		p.salt = salt
+	}
+
+	gcm, err := p.cipher(p.salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	out := append(append([]byte{}, p.salt...), nonce...)
+	out = gcm.Seal(out, nonce, data, nil)
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt. It returns sdk.ErrInvalidPassphrase if the passphrase is wrong or the
+// data has been altered. All the secrets decrypted by a Passphrase must share the salt of the first one: deriving a
+// key per secret would let an archive cost a key derivation per secret
+func (p *Passphrase) Decrypt(data string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+	if len(raw) < passphraseSaltSize {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+
+	salt := raw[:passphraseSaltSize]
+	if p.salt == nil {
+		p.salt = append([]byte{}, salt...)
+	} else if !bytes.Equal(p.salt, salt) {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+
+	gcm, err := p.cipher(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	raw = raw[passphraseSaltSize:]
+	if len(raw) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+
+	out, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, sdk.ErrInvalidPassphrase
+	}
+	return out, nil
+}
+
+//cipher returns the AES-GCM cipher keyed from the passphrase and the salt. The key is derived once per salt
+func (p *Passphrase) cipher(salt []byte) (cipher.AEAD, error) {
+	if gcm, ok := p.ciphers[string(salt)]; ok {
+		return gcm, nil
+	}
+	c, err := aes.NewCipher(pbkdf2.Key([]byte(p.passphrase), salt, passphraseIterations, ckeySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(c)
+	if err != nil {
+		return nil, err
+	}
+	p.ciphers[string(salt)] = gcm
+	return gcm, nil
+}
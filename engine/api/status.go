@@ -78,6 +78,7 @@ func statusHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *bu
 
 	// Check database
 	output = append(output, database.Status())
+	output = append(output, database.ReplicaStatus())
 	log.Debug("Status> %s", database.Status())
 
 	// Check LastUpdate Connected User
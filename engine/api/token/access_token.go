@@ -0,0 +1,163 @@
+package token
+
+import (
+	"crypto/sha512"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const accessTokenColumns = "id, name, user_id, group_id, scopes, created, expire_at, last_used, revoked"
+
+// hashAccessToken returns the hash stored in database for an access token
+func hashAccessToken(token string) string {
+	h := sha512.Sum512([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// InsertAccessToken generates a new access token and stores its hash in database.
+// The generated token is set in t.Token
+func InsertAccessToken(db gorp.SqlExecutor, t *sdk.AccessToken) error {
+	tk, err := GenerateToken()
+	if err != nil {
+		return sdk.WrapError(err, "InsertAccessToken> cannot generate token")
+	}
+
+	scopes := make([]string, len(t.Scopes))
+	for i := range t.Scopes {
+		scopes[i] = string(t.Scopes[i])
+	}
+
+	var userID, groupID sql.NullInt64
+	if t.UserID != 0 {
+		userID = sql.NullInt64{Int64: t.UserID, Valid: true}
+	}
+	if t.GroupID != 0 {
+		groupID = sql.NullInt64{Int64: t.GroupID, Valid: true}
+	}
+	var expireAt pq.NullTime
+	if t.ExpireAt != nil {
+		expireAt = pq.NullTime{Time: *t.ExpireAt, Valid: true}
+	}
+
+	t.Created = time.Now()
+	query := `INSERT INTO access_token (name, hash, user_id, group_id, scopes, created, expire_at, revoked) VALUES ($1, $2, $3, $4, $5, $6, $7, false) RETURNING id`
+	if err := db.QueryRow(query, t.Name, hashAccessToken(tk), userID, groupID, strings.Join(scopes, ","), t.Created, expireAt).Scan(&t.ID); err != nil {
+		return sdk.WrapError(err, "InsertAccessToken> cannot insert access token %s", t.Name)
+	}
+	t.Token = tk
+	return nil
+}
+
+func scanAccessToken(s interface {
+	Scan(dest ...interface{}) error
+}) (*sdk.AccessToken, error) {
+	var t sdk.AccessToken
+	var userID, groupID sql.NullInt64
+	var scopes sql.NullString
+	var expireAt, lastUsed pq.NullTime
+	if err := s.Scan(&t.ID, &t.Name, &userID, &groupID, &scopes, &t.Created, &expireAt, &lastUsed, &t.Revoked); err != nil {
+		return nil, err
+	}
+	t.UserID = userID.Int64
+	t.GroupID = groupID.Int64
+	t.Scopes = []sdk.AccessTokenScope{}
+	if scopes.String != "" {
+		for _, s := range strings.Split(scopes.String, ",") {
+			t.Scopes = append(t.Scopes, sdk.AccessTokenScope(s))
+		}
+	}
+	if expireAt.Valid {
+		t.ExpireAt = &expireAt.Time
+	}
+	if lastUsed.Valid {
+		t.LastUsed = &lastUsed.Time
+	}
+	return &t, nil
+}
+
+func loadAccessTokens(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.AccessToken, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []sdk.AccessToken{}
+	for rows.Next() {
+		t, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *t)
+	}
+	return tokens, nil
+}
+
+// LoadAccessToken loads an access token from its value
+func LoadAccessToken(db gorp.SqlExecutor, token string) (*sdk.AccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM access_token WHERE hash = $1`
+	t, err := scanAccessToken(db.QueryRow(query, hashAccessToken(token)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrInvalidToken
+		}
+		return nil, sdk.WrapError(err, "LoadAccessToken> cannot load access token")
+	}
+	return t, nil
+}
+
+// LoadAccessTokenByID loads an access token from its id
+func LoadAccessTokenByID(db gorp.SqlExecutor, id int64) (*sdk.AccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM access_token WHERE id = $1`
+	t, err := scanAccessToken(db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadAccessTokenByID> cannot load access token %d", id)
+	}
+	return t, nil
+}
+
+// LoadAccessTokensByUser loads all the personal access tokens of a user
+func LoadAccessTokensByUser(db gorp.SqlExecutor, userID int64) ([]sdk.AccessToken, error) {
+	tokens, err := loadAccessTokens(db, `SELECT `+accessTokenColumns+` FROM access_token WHERE user_id = $1 ORDER BY created`, userID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadAccessTokensByUser> cannot load access tokens of user %d", userID)
+	}
+	return tokens, nil
+}
+
+// LoadAccessTokensByGroup loads all the service account tokens of a group
+func LoadAccessTokensByGroup(db gorp.SqlExecutor, groupID int64) ([]sdk.AccessToken, error) {
+	tokens, err := loadAccessTokens(db, `SELECT `+accessTokenColumns+` FROM access_token WHERE group_id = $1 ORDER BY created`, groupID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadAccessTokensByGroup> cannot load access tokens of group %d", groupID)
+	}
+	return tokens, nil
+}
+
+// RevokeAccessToken revokes an access token. The token is kept in database to keep track of it
+func RevokeAccessToken(db gorp.SqlExecutor, id int64) error {
+	if _, err := db.Exec(`UPDATE access_token SET revoked = true WHERE id = $1`, id); err != nil {
+		return sdk.WrapError(err, "RevokeAccessToken> cannot revoke access token %d", id)
+	}
+	return nil
+}
+
+// UpdateAccessTokenLastUsed updates the last use date of an access token, at most once a minute
+func UpdateAccessTokenLastUsed(db gorp.SqlExecutor, id int64) error {
+	query := `UPDATE access_token SET last_used = $2 WHERE id = $1 AND (last_used IS NULL OR last_used < $3)`
+	now := time.Now()
+	if _, err := db.Exec(query, id, now, now.Add(-time.Minute)); err != nil {
+		return sdk.WrapError(err, "UpdateAccessTokenLastUsed> cannot update access token %d", id)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//LoadNodeRunApprovals loads all the decisions recorded on a node run
+func LoadNodeRunApprovals(db gorp.SqlExecutor, nodeRunID int64) ([]sdk.WorkflowNodeRunApproval, error) {
+	res := []NodeRunApproval{}
+	if _, err := db.Select(&res, "SELECT * FROM workflow_node_run_approval WHERE workflow_node_run_id = $1 ORDER BY created", nodeRunID); err != nil {
+		return nil, sdk.WrapError(err, "LoadNodeRunApprovals> Unable to load approvals of node run %d", nodeRunID)
+	}
+	approvals := make([]sdk.WorkflowNodeRunApproval, len(res))
+	for i := range res {
+		approvals[i] = sdk.WorkflowNodeRunApproval(res[i])
+	}
+	return approvals, nil
+}
+
+//nodeRunApprovalGate returns the approval gate of the node of a node run
+func nodeRunApprovalGate(run *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun) (*sdk.WorkflowNode, *sdk.WorkflowNodeApproval) {
+	node := run.Workflow.GetNode(nodeRun.WorkflowNodeID)
+	if node == nil || node.Context == nil {
+		return node, nil
+	}
+	return node, node.Context.Approval
+}
+
+//ApproveNodeRun records the decision of a user on a node run waiting for approval.
+//The node run is executed as soon as enough members of the groups have approved it, and fails if one of them rejects it
+func ApproveNodeRun(db gorp.SqlExecutor, nodeRun *sdk.WorkflowNodeRun, u *sdk.User, approved bool, comment string) error {
+	if nodeRun.Status != sdk.StatusWaitingApproval.String() {
+		return sdk.ErrWorkflowNodeRunNotWaitingApproval
+	}
+
+	run, errR := loadAndLockRunByID(db, nodeRun.WorkflowRunID)
+	if errR != nil {
+		return sdk.WrapError(errR, "ApproveNodeRun> Unable to load workflow run %d", nodeRun.WorkflowRunID)
+	}
+
+	node, gate := nodeRunApprovalGate(run, nodeRun)
+	if gate == nil {
+		return sdk.ErrWorkflowNodeRunNotWaitingApproval
+	}
+	if !gate.IsApprover(u) {
+		return sdk.ErrWorkflowNodeRunNotApprover
+	}
+
+	approvals, errA := LoadNodeRunApprovals(db, nodeRun.ID)
+	if errA != nil {
+		return errA
+	}
+	for _, a := range approvals {
+		if a.UserID == u.ID {
+			return sdk.ErrWorkflowNodeRunAlreadyApproved
+		}
+	}
+
+	a := NodeRunApproval{
+		WorkflowNodeRunID: nodeRun.ID,
+		UserID:            u.ID,
+		Username:          u.Username,
+		Approved:          approved,
+		Comment:           comment,
+		Created:           time.Now(),
+	}
+	if err := db.Insert(&a); err != nil {
+		return sdk.WrapError(err, "ApproveNodeRun> Unable to insert approval of node run %d", nodeRun.ID)
+	}
+
+	if !approved {
+		return rejectNodeRun(db, run, nodeRun, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeRunRejected.ID,
+			Args: []interface{}{node.Pipeline.Name, u.Username},
+		})
+	}
+
+	AddWorkflowRunInfo(run, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeRunApproved.ID,
+		Args: []interface{}{node.Pipeline.Name, u.Username},
+	})
+	if err := updateWorkflowRun(db, run); err != nil {
+		return sdk.WrapError(err, "ApproveNodeRun> Unable to update workflow run %d", run.ID)
+	}
+
+	var count = 1
+	for _, a := range approvals {
+		if a.Approved {
+			count++
+		}
+	}
+	if count < gate.MinApprovals {
+		log.Debug("ApproveNodeRun> Node run %d approved by %s (%d/%d)", nodeRun.ID, u.Username, count, gate.MinApprovals)
+		//The node run is still waiting for approval, the event notifies the new decision
+		event.PublishWorkflowNodeRun(run, nodeRun)
+		return nil
+	}
+
+	//Enough approvals: the node run can be executed
+	nodeRun.Status = sdk.StatusWaiting.String()
+	if err := UpdateNodeRun(db, nodeRun); err != nil {
+		return sdk.WrapError(err, "ApproveNodeRun> Unable to update node run %d", nodeRun.ID)
+	}
+	event.PublishWorkflowNodeRun(run, nodeRun)
+
+	return execute(db, nodeRun)
+}
+
+//rejectNodeRun fails a node run waiting for approval
+func rejectNodeRun(db gorp.SqlExecutor, run *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun, info sdk.SpawnMsg) error {
+	nodeRun.Status = sdk.StatusFail.String()
+	nodeRun.Done = time.Now()
+	if err := UpdateNodeRun(db, nodeRun); err != nil {
+		return sdk.WrapError(err, "rejectNodeRun> Unable to update node run %d", nodeRun.ID)
+	}
+
+	AddWorkflowRunInfo(run, info)
+	if err := updateWorkflowRun(db, run); err != nil {
+		return sdk.WrapError(err, "rejectNodeRun> Unable to update workflow run %d", run.ID)
+	}
+
+	updatedWorkflowRun, err := LoadRunByID(db, run.ID)
+	if err != nil {
+		return sdk.WrapError(err, "rejectNodeRun> Unable to reload workflow run %d", run.ID)
+	}
+
+	// The rejected node run is over: reprocess the workflow as for any other finished node run (in the same transaction)
+	if err := processWorkflowRun(db, updatedWorkflowRun, nil, nil, nil); err != nil {
+		return sdk.WrapError(err, "rejectNodeRun> Unable to reprocess workflow run %d", run.ID)
+	}
+
+	event.PublishWorkflowNodeRun(updatedWorkflowRun, nodeRun)
+	event.PublishWorkflowRun(updatedWorkflowRun)
+	return nil
+}
+
+//ApprovalTimeoutChecker rejects the node runs which have been waiting for approval longer than the timeout of their node
+func ApprovalTimeoutChecker(c context.Context, DBFunc func() *gorp.DbMap) {
+	tick := time.NewTicker(1 * time.Minute)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting workflow.ApprovalTimeoutChecker: %v", c.Err())
+			}
+			return
+		case <-tick.C:
+			ids := []int64{}
+			if _, err := DBFunc().Select(&ids, "SELECT id FROM workflow_node_run WHERE status = $1", sdk.StatusWaitingApproval.String()); err != nil {
+				log.Warning("ApprovalTimeoutChecker> Unable to load node runs waiting for approval: %s", err)
+				continue
+			}
+			for _, id := range ids {
+				if err := checkApprovalTimeout(DBFunc(), id); err != nil {
+					log.Warning("ApprovalTimeoutChecker> Unable to check approval timeout of node run %d: %s", id, err)
+				}
+			}
+		}
+	}
+}
+
+func checkApprovalTimeout(db *gorp.DbMap, id int64) error {
+	tx, errb := db.Begin()
+	if errb != nil {
+		return errb
+	}
+	defer tx.Rollback()
+
+	nodeRun, err := LoadAndLockNodeRunByID(tx, id)
+	if err != nil {
+		return err
+	}
+	if nodeRun.Status != sdk.StatusWaitingApproval.String() {
+		return nil
+	}
+
+	run, err := loadAndLockRunByID(tx, nodeRun.WorkflowRunID)
+	if err != nil {
+		return err
+	}
+
+	node, gate := nodeRunApprovalGate(run, nodeRun)
+	if gate == nil || gate.Timeout <= 0 || time.Since(nodeRun.Start) < time.Duration(gate.Timeout)*time.Second {
+		return nil
+	}
+
+	a := NodeRunApproval{
+		WorkflowNodeRunID: nodeRun.ID,
+		Username:          "cds",
+		Approved:          false,
+		Comment:           "approval timeout exceeded",
+		Created:           time.Now(),
+	}
+	if err := tx.Insert(&a); err != nil {
+		return sdk.WrapError(err, "checkApprovalTimeout> Unable to insert approval of node run %d", nodeRun.ID)
+	}
+
+	if err := rejectNodeRun(tx, run, nodeRun, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeRunApprovalTimeout.ID,
+		Args: []interface{}{node.Pipeline.Name},
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,226 @@
+package workflow
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// Coverage build parameters, they can be used in the trigger conditions
+const (
+	CoverageLinesParameter             = "cds.coverage.lines"
+	CoverageBranchesParameter          = "cds.coverage.branches"
+	CoverageLinesDeltaParameter        = "cds.coverage.lines.delta"
+	CoverageBranchesDeltaParameter     = "cds.coverage.branches.delta"
+	CoverageLinesBaseDeltaParameter    = "cds.coverage.lines.base_delta"
+	CoverageBranchesBaseDeltaParameter = "cds.coverage.branches.base_delta"
+)
+
+func (c *NodeRunCoverage) toSDK(withFiles bool) (*sdk.WorkflowNodeRunCoverage, error) {
+	res := &sdk.WorkflowNodeRunCoverage{
+		WorkflowNodeRunID: c.WorkflowNodeRunID,
+		WorkflowID:        c.WorkflowID,
+		NodeName:          c.NodeName,
+		Num:               c.Num,
+		Branch:            c.Branch,
+		Created:           c.Created,
+		Report: sdk.CoverageReport{
+			Format: c.Format,
+			Total: sdk.CoverageStats{
+				TotalLines:      c.TotalLines,
+				CoveredLines:    c.CoveredLines,
+				TotalBranches:   c.TotalBranches,
+				CoveredBranches: c.CoveredBranches,
+			},
+		},
+	}
+	if withFiles {
+		if err := gorpmapping.JSONNullString(c.Files, &res.Report.Files); err != nil {
+			return nil, sdk.WrapError(err, "NodeRunCoverage.toSDK> Unable to unmarshal files")
+		}
+	}
+	return res, nil
+}
+
+func loadCoverage(db gorp.SqlExecutor, withFiles bool, query string, args ...interface{}) ([]sdk.WorkflowNodeRunCoverage, error) {
+	var cs []NodeRunCoverage
+	if _, err := db.Select(&cs, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "loadCoverage> Unable to load coverage")
+	}
+	res := make([]sdk.WorkflowNodeRunCoverage, 0, len(cs))
+	for i := range cs {
+		c, err := cs[i].toSDK(withFiles)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *c)
+	}
+	return res, nil
+}
+
+func loadCoverageByNodeRunID(db gorp.SqlExecutor, nodeRunID int64) (*sdk.WorkflowNodeRunCoverage, error) {
+	cs, err := loadCoverage(db, true, "SELECT * FROM workflow_node_run_coverage WHERE workflow_node_run_id = $1", nodeRunID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cs) == 0 {
+		return nil, sdk.ErrNotFound
+	}
+	return &cs[0], nil
+}
+
+//loadLastCoverage loads the last coverage of a node on a branch, up to the run number beforeNum if it's set.
+//The node run excludedID is ignored
+func loadLastCoverage(db gorp.SqlExecutor, workflowID int64, nodeName, branch string, beforeNum, excludedID int64) (*sdk.WorkflowNodeRunCoverage, error) {
+	query := `SELECT workflow_node_run_id, workflow_id, node_name, num, branch, format, total_lines, covered_lines, total_branches, covered_branches, NULL AS files, created
+	FROM workflow_node_run_coverage
+	WHERE workflow_id = $1 AND node_name = $2 AND branch = $3 AND workflow_node_run_id <> $4`
+	args := []interface{}{workflowID, nodeName, branch, excludedID}
+	if beforeNum > 0 {
+		query += " AND num <= $5"
+		args = append(args, beforeNum)
+	}
+	query += " ORDER BY num DESC, created DESC LIMIT 1"
+
+	cs, err := loadCoverage(db, false, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(cs) == 0 {
+		return nil, nil
+	}
+	return &cs[0], nil
+}
+
+//computeCoverageDeltas computes the delta with the previous run on the same branch and with the last run on the base branch of the pull request
+func computeCoverageDeltas(db gorp.SqlExecutor, c *sdk.WorkflowNodeRunCoverage, baseBranch string) error {
+	previous, err := loadLastCoverage(db, c.WorkflowID, c.NodeName, c.Branch, c.Num, c.WorkflowNodeRunID)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		c.Delta = sdk.NewCoverageDelta(c.Report.Total, *previous)
+	}
+
+	if baseBranch == "" || baseBranch == c.Branch {
+		return nil
+	}
+	base, err := loadLastCoverage(db, c.WorkflowID, c.NodeName, baseBranch, 0, c.WorkflowNodeRunID)
+	if err != nil {
+		return err
+	}
+	if base != nil {
+		c.BaseDelta = sdk.NewCoverageDelta(c.Report.Total, *base)
+	}
+	return nil
+}
+
+//LoadCoverage loads the coverage of a node run, with its deltas
+func LoadCoverage(db gorp.SqlExecutor, nodeRun *sdk.WorkflowNodeRun) (*sdk.WorkflowNodeRunCoverage, error) {
+	c, err := loadCoverageByNodeRunID(db, nodeRun.ID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadCoverage> Unable to load coverage of node run %d", nodeRun.ID)
+	}
+	if err := computeCoverageDeltas(db, c, sdk.ParameterValue(nodeRun.BuildParameters, "git.pr.base")); err != nil {
+		return nil, sdk.WrapError(err, "LoadCoverage> Unable to compute coverage deltas of node run %d", nodeRun.ID)
+	}
+	return c, nil
+}
+
+//LoadCoverageTrend loads the last coverage totals of a node on a branch, the most recent first
+func LoadCoverageTrend(db gorp.SqlExecutor, workflowID int64, nodeName, branch string, limit int) ([]sdk.WorkflowNodeRunCoverage, error) {
+	cs, err := loadCoverage(db, false, `SELECT workflow_node_run_id, workflow_id, node_name, num, branch, format, total_lines, covered_lines, total_branches, covered_branches, NULL AS files, created
+	FROM workflow_node_run_coverage
+	WHERE workflow_id = $1 AND node_name = $2 AND branch = $3
+	ORDER BY num DESC, created DESC LIMIT $4`, workflowID, nodeName, branch, limit)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadCoverageTrend> Unable to load coverage of %s on branch %s", nodeName, branch)
+	}
+	return cs, nil
+}
+
+//AddCoverage adds a coverage report to a node run. The reports of the jobs of the node run are merged line by line,
+//so a report posted twice is counted once.
+//The coverage and its deltas are added to the build parameters of the node run, the caller has to update the node run
+func AddCoverage(db gorp.SqlExecutor, run *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun, report sdk.CoverageReport) (*sdk.WorkflowNodeRunCoverage, error) {
+	nodeName := fmt.Sprintf("%d", nodeRun.WorkflowNodeID)
+	if node := run.Workflow.GetNode(nodeRun.WorkflowNodeID); node != nil {
+		nodeName = node.Name
+	}
+
+	c, err := loadCoverageByNodeRunID(db, nodeRun.ID)
+	if err != nil && err != sdk.ErrNotFound {
+		return nil, sdk.WrapError(err, "AddCoverage> Unable to load coverage of node run %d", nodeRun.ID)
+	}
+	if c == nil {
+		c = &sdk.WorkflowNodeRunCoverage{
+			WorkflowNodeRunID: nodeRun.ID,
+			WorkflowID:        run.WorkflowID,
+			NodeName:          nodeName,
+			Num:               nodeRun.Number,
+			Branch:            sdk.ParameterValue(nodeRun.BuildParameters, "git.branch"),
+		}
+	}
+	c.Created = time.Now()
+	c.Report.Merge(report)
+
+	files, err := gorpmapping.JSONToNullString(c.Report.Files)
+	if err != nil {
+		return nil, sdk.WrapError(err, "AddCoverage> Unable to marshal files")
+	}
+	dbc := NodeRunCoverage{
+		WorkflowNodeRunID: c.WorkflowNodeRunID,
+		WorkflowID:        c.WorkflowID,
+		NodeName:          c.NodeName,
+		Num:               c.Num,
+		Branch:            c.Branch,
+		Format:            c.Report.Format,
+		TotalLines:        c.Report.Total.TotalLines,
+		CoveredLines:      c.Report.Total.CoveredLines,
+		TotalBranches:     c.Report.Total.TotalBranches,
+		CoveredBranches:   c.Report.Total.CoveredBranches,
+		Files:             files,
+		Created:           c.Created,
+	}
+	if _, err := db.Exec("DELETE FROM workflow_node_run_coverage WHERE workflow_node_run_id = $1", nodeRun.ID); err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "AddCoverage> Unable to delete previous coverage of node run %d", nodeRun.ID)
+	}
+	if err := db.Insert(&dbc); err != nil {
+		return nil, sdk.WrapError(err, "AddCoverage> Unable to insert coverage of node run %d", nodeRun.ID)
+	}
+
+	if err := computeCoverageDeltas(db, c, sdk.ParameterValue(nodeRun.BuildParameters, "git.pr.base")); err != nil {
+		return nil, sdk.WrapError(err, "AddCoverage> Unable to compute coverage deltas of node run %d", nodeRun.ID)
+	}
+
+	setCoverageParameter(nodeRun, CoverageLinesParameter, c.Report.Total.Lines())
+	setCoverageParameter(nodeRun, CoverageBranchesParameter, c.Report.Total.Branches())
+	if c.Delta != nil {
+		setCoverageParameter(nodeRun, CoverageLinesDeltaParameter, c.Delta.Lines)
+		setCoverageParameter(nodeRun, CoverageBranchesDeltaParameter, c.Delta.Branches)
+	}
+	if c.BaseDelta != nil {
+		setCoverageParameter(nodeRun, CoverageLinesBaseDeltaParameter, c.BaseDelta.Lines)
+		setCoverageParameter(nodeRun, CoverageBranchesBaseDeltaParameter, c.BaseDelta.Branches)
+	}
+
+	return c, nil
+}
+
+//setCoverageParameter sets a build parameter of a node run, the value of the previous report is replaced
+func setCoverageParameter(nodeRun *sdk.WorkflowNodeRun, name string, value float64) {
+	v := strconv.FormatFloat(value, 'f', -1, 64)
+	for i := range nodeRun.BuildParameters {
+		if nodeRun.BuildParameters[i].Name == name {
+			nodeRun.BuildParameters[i].Value = v
+			return
+		}
+	}
+	sdk.AddParameter(&nodeRun.BuildParameters, name, sdk.StringParameter, v)
+}
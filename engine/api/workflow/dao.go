@@ -262,6 +262,20 @@ func IsValid(db gorp.SqlExecutor, w *sdk.Workflow, u *sdk.User) error {
 		}
 	}
 
+	//Check approval gates
+	if w.Root != nil {
+		if err := checkApprovals(w.Root); err != nil {
+			return err
+		}
+	}
+	for _, j := range w.Joins {
+		for i := range j.Triggers {
+			if err := checkApprovals(&j.Triggers[i].WorkflowDestNode); err != nil {
+				return err
+			}
+		}
+	}
+
 	//Load the project
 	proj, err := project.Load(db, w.ProjectKey, u, project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments)
 	if err != nil {
@@ -315,3 +329,16 @@ func IsValid(db gorp.SqlExecutor, w *sdk.Workflow, u *sdk.User) error {
 
 	return nil
 }
+
+//checkApprovals checks that the approval gates of the node and its children can be approved
+func checkApprovals(n *sdk.WorkflowNode) error {
+	if n.Context != nil && n.Context.Approval != nil && n.Context.Approval.MinApprovals > 0 && len(n.Context.Approval.Groups) == 0 {
+		return sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Approval of node %s needs at least one group", n.Ref))
+	}
+	for i := range n.Triggers {
+		if err := checkApprovals(&n.Triggers[i].WorkflowDestNode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLoadArtifactWithoutDigest(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+		Type:       sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, proj, &pip, u))
+
+	w := sdk.Workflow{
+		Name:       "test_artifact",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Root: &sdk.WorkflowNode{
+			Pipeline: pip,
+		},
+	}
+	test.NoError(t, Insert(db, &w, u))
+	w1, err := Load(db, key, "test_artifact", u)
+	test.NoError(t, err)
+
+	wr, err := ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{User: *u})
+	test.NoError(t, err)
+	nodeRun := wr.WorkflowNodeRuns[w1.RootID][0]
+
+	//Artifacts uploaded before the SHA-256 digests have no digest
+	var id int64
+	test.NoError(t, db.QueryRow(`INSERT INTO workflow_node_run_artifacts (workflow_run_id, workflow_node_run_id, name, tag, download_hash, size, perm, md5sum, object_path)
+		VALUES ($1, $2, 'file.txt', 'v1', 'hash', 42, 420, 'md5', 'path') RETURNING id`, wr.ID, nodeRun.ID).Scan(&id))
+
+	art, err := LoadArtifactByIDs(db, w1.ID, id)
+	test.NoError(t, err)
+	assert.Equal(t, "file.txt", art.Name)
+	assert.Equal(t, "", art.SHA256sum)
+
+	arts, err := loadArtifactByNodeRunID(db, nodeRun.ID)
+	test.NoError(t, err)
+	assert.Len(t, arts, 1)
+	assert.Equal(t, "", arts[0].SHA256sum)
+}
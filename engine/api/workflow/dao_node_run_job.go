@@ -69,6 +69,28 @@ func LoadNodeJobRunQueue(db gorp.SqlExecutor, groupsID []int64, since *time.Time
 	return jobs, nil
 }
 
+// NodeJobRunQueueStats is the number of jobs of the queue and the age of the oldest one for a status
+type NodeJobRunQueueStats struct {
+	Status    string  `db:"status"`
+	Count     int64   `db:"count"`
+	OldestAge float64 `db:"oldest_age"`
+}
+
+// LoadNodeJobRunQueueStats returns the number of waiting and building jobs of the queue and the age in seconds of the oldest ones
+func LoadNodeJobRunQueueStats(db gorp.SqlExecutor) ([]NodeJobRunQueueStats, error) {
+	query := `select status, count(1) as count, coalesce(extract(epoch from now() - min(queued)), 0) as oldest_age
+	from workflow_node_run_job
+	where status = ANY(string_to_array($1, ','))
+	group by status`
+
+	stats := []NodeJobRunQueueStats{}
+	statuses := []string{sdk.StatusWaiting.String(), sdk.StatusBuilding.String()}
+	if _, err := db.Select(&stats, query, strings.Join(statuses, ",")); err != nil {
+		return nil, sdk.WrapError(err, "workflow.LoadNodeJobRunQueueStats> Unable to load queue stats")
+	}
+	return stats, nil
+}
+
 //LoadNodeJobRun load a NodeJobRun given its ID
 func LoadNodeJobRun(db gorp.SqlExecutor, id int64) (*sdk.WorkflowNodeJobRun, error) {
 	j := JobRun{}
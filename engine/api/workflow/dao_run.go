@@ -198,6 +198,15 @@ func LoadRuns(db gorp.SqlExecutor, projectkey, workflowname string, offset, limi
 	return wruns, offset, limit, int(count), nil
 }
 
+//loadRunTraceParent returns the trace context of a workflow run
+func loadRunTraceParent(db gorp.SqlExecutor, id int64) (string, error) {
+	tp, err := db.SelectStr("select trace_parent from workflow_run where id = $1", id)
+	if err != nil {
+		return "", sdk.WrapError(err, "loadRunTraceParent> Unable to load trace of workflow run %d", id)
+	}
+	return tp, nil
+}
+
 func loadRun(db gorp.SqlExecutor, query string, args ...interface{}) (*sdk.WorkflowRun, error) {
 	runDB := &Run{}
 	if err := db.SelectOne(runDB, query, args...); err != nil {
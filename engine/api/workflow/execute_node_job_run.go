@@ -80,6 +80,12 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, st
 		if err := UpdateNodeRun(db, node); err != nil {
 			return sdk.WrapError(err, "workflow.UpdateNodeJobRunStatus> Unable to update workflow node run %d", node.ID)
 		}
+		wr, errW := LoadRunByID(db, node.WorkflowRunID)
+		if errW != nil {
+			return sdk.WrapError(errW, "workflow.UpdateNodeJobRunStatus> Unable to load workflow run %d", node.WorkflowRunID)
+		}
+		event.PublishWorkflowNodeRun(wr, node)
+		event.PublishWorkflowRun(wr)
 	} else {
 		if errE := execute(db, node); errE != nil {
 			return sdk.WrapError(errE, "workflow.UpdateNodeJobRunStatus> Cannot execute sync node")
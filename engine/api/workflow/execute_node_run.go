@@ -64,6 +64,7 @@ func execute(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (err error) {
 		return nil
 	}
 
+	var previousStatus = n.Status
 	var newStatus = n.Status
 
 	//If no stages ==> success
@@ -144,6 +145,19 @@ func execute(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (err error) {
 		}
 	}
 
+	//Send events only if the status has changed
+	if n.Status != previousStatus {
+		event.PublishWorkflowNodeRun(updatedWorkflowRun, n)
+		event.PublishWorkflowRun(updatedWorkflowRun)
+	}
+
+	//Sign the provenance of the artifacts built by the node, before deleting the jobs
+	if n.Status == sdk.StatusSuccess.String() && n.Status != previousStatus {
+		if err := generateProvenance(db, updatedWorkflowRun, n); err != nil {
+			log.Warning("workflow.execute> Unable to generate provenance of node run %d: %s", n.ID, err)
+		}
+	}
+
 	//Delete jobs only when node is over
 	if n.Status == sdk.StatusSuccess.String() || n.Status == sdk.StatusFail.String() {
 		//Delete the line in workflow_node_run_job
@@ -167,6 +181,12 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 	if !conditionsOK {
 		stage.Status = sdk.StatusSkipped
 	}
+
+	//The jobs are traced in the trace of the workflow run
+	traceParent, err := loadRunTraceParent(db, run.WorkflowRunID)
+	if err != nil {
+		return err
+	}
 	if !stage.Enabled {
 		stage.Status = sdk.StatusDisabled
 	}
@@ -186,6 +206,7 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 			Job: sdk.ExecutedJob{
 				Job: job,
 			},
+			TraceParent: traceParent,
 		}
 
 		if !stage.Enabled || !job.Job.Enabled {
@@ -1,6 +1,9 @@
 package workflow
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
 
 	"github.com/ovh/cds/sdk"
@@ -45,6 +48,28 @@ type NodeHook sdk.WorkflowNodeHook
 // NodeHookModel is a gorp wrapper around sdk.WorkflowHookModel
 type NodeHookModel sdk.WorkflowHookModel
 
+// NodeRunProvenance is a gorp wrapper around sdk.ProvenanceAttestation
+type NodeRunProvenance sdk.ProvenanceAttestation
+
+// NodeRunApproval is a gorp wrapper around sdk.WorkflowNodeRunApproval
+type NodeRunApproval sdk.WorkflowNodeRunApproval
+
+// NodeRunCoverage is the database representation of sdk.WorkflowNodeRunCoverage, the totals are stored in columns for the trends
+type NodeRunCoverage struct {
+	WorkflowNodeRunID int64          `db:"workflow_node_run_id"`
+	WorkflowID        int64          `db:"workflow_id"`
+	NodeName          string         `db:"node_name"`
+	Num               int64          `db:"num"`
+	Branch            string         `db:"branch"`
+	Format            string         `db:"format"`
+	TotalLines        int            `db:"total_lines"`
+	CoveredLines      int            `db:"covered_lines"`
+	TotalBranches     int            `db:"total_branches"`
+	CoveredBranches   int            `db:"covered_branches"`
+	Files             sql.NullString `db:"files"`
+	Created           time.Time      `db:"created"`
+}
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(Workflow{}, "workflow", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Node{}, "workflow_node", true, "id"))
@@ -61,4 +86,7 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(NodeRunArtifact{}, "workflow_node_run_artifacts", true, "id"))
 	gorpmapping.Register(gorpmapping.New(RunTag{}, "workflow_run_tag", false, "workflow_run_id", "tag"))
 	gorpmapping.Register(gorpmapping.New(NodeHookModel{}, "workflow_hook_model", true, "id"))
+	gorpmapping.Register(gorpmapping.New(NodeRunProvenance{}, "workflow_node_run_provenance", false, "workflow_node_run_id"))
+	gorpmapping.Register(gorpmapping.New(NodeRunApproval{}, "workflow_node_run_approval", true, "id"))
+	gorpmapping.Register(gorpmapping.New(NodeRunCoverage{}, "workflow_node_run_coverage", false, "workflow_node_run_id"))
 }
@@ -1,14 +1,18 @@
 package workflow
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fsamin/go-dump"
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // processWorkflowRun triggers workflow node for every workflow.
@@ -16,8 +20,12 @@ import (
 func processWorkflowRun(db gorp.SqlExecutor, w *sdk.WorkflowRun, hookEvent *sdk.WorkflowNodeRunHookEvent, manual *sdk.WorkflowNodeRunManual, startingFromNode *int64) error {
 	t0 := time.Now()
 	log.Debug("processWorkflowRun> Begin [#%d]%s", w.Number, w.Workflow.Name)
+	_, span := tracing.StartFromTraceParent(context.Background(), "workflow.processWorkflowRun", w.TraceParent)
+	span.SetAttribute("workflow", w.Workflow.Name)
+	span.SetAttribute("workflow_run_number", w.Number)
 	defer func() {
 		log.Debug("processWorkflowRun> End [#%d]%s - %.3fs", w.Number, w.Workflow.Name, time.Since(t0).Seconds())
+		span.Finish()
 	}()
 
 	//Checks startingFromNode
@@ -49,6 +57,7 @@ func processWorkflowRun(db gorp.SqlExecutor, w *sdk.WorkflowRun, hookEvent *sdk.
 		if err := processWorkflowNodeRun(db, w, w.Workflow.Root, 0, nil, hookEvent, manual); err != nil {
 			return sdk.WrapError(err, "processWorkflowRun> Unable to process workflow node run")
 		}
+		event.PublishWorkflowRun(w)
 		return nil
 	}
 
@@ -253,6 +262,15 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, w *sdk.WorkflowRun, n *sdk.Work
 		Stages:         stages,
 	}
 
+	//The node run waits for the approval of the groups before being executed
+	if n.Context != nil && n.Context.Approval != nil && n.Context.Approval.MinApprovals > 0 {
+		run.Status = string(sdk.StatusWaitingApproval)
+		AddWorkflowRunInfo(w, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeRunWaitingApproval.ID,
+			Args: []interface{}{n.Pipeline.Name, n.Context.Approval.MinApprovals, strings.Join(n.Context.Approval.Groups, ",")},
+		})
+	}
+
 	run.SourceNodeRuns = sourceNodeRuns
 	if sourceNodeRuns != nil {
 		//Get all the nodeRun from the sources
@@ -290,6 +308,18 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, w *sdk.WorkflowRun, n *sdk.Work
 	if h != nil {
 		run.Payload = h.Payload
 		run.PipelineParameters = h.PipelineParameters
+		//The pull request parameters are set in the payload, so they are inherited by the triggered nodes
+		if h.PullRequest != nil {
+			p, errp := pullRequestPayload(h.Payload, *h.PullRequest)
+			if errp != nil {
+				AddWorkflowRunInfo(w, sdk.SpawnMsg{
+					ID:   sdk.MsgWorkflowError.ID,
+					Args: []interface{}{errp},
+				})
+				log.Error("processWorkflowNodeRun> Unable to compute pull request payload: %v", errp)
+			}
+			run.Payload = p
+		}
 	}
 
 	run.Manual = m
@@ -332,6 +362,13 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, w *sdk.WorkflowRun, n *sdk.Work
 		return sdk.WrapError(err, "processWorkflowNodeRun> unable to update workflow run")
 	}
 
+	event.PublishWorkflowNodeRun(w, run)
+
+	//The node run will be executed when approved
+	if run.Status == string(sdk.StatusWaitingApproval) {
+		return nil
+	}
+
 	//Execute the node run !
 	if err := execute(db, run); err != nil {
 		return sdk.WrapError(err, "processWorkflowNodeRun> unable to execute workflow run")
@@ -340,6 +377,23 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, w *sdk.WorkflowRun, n *sdk.Work
 	return nil
 }
 
+//pullRequestPayload returns the payload of a hook merged with the parameters of the pull request which triggered it
+func pullRequestPayload(payload interface{}, pr sdk.VCSPullRequestEvent) (map[string]string, error) {
+	prParams := sdk.ParametersToMap(sdk.PullRequestParameters(pr))
+	if payload == nil {
+		return prParams, nil
+	}
+	m, err := dump.ToMap(payload, dump.WithDefaultLowerCaseFormatter())
+	if err != nil {
+		return prParams, err
+	}
+	//The pull request parameters override the ones of the payload
+	for k, v := range prParams {
+		m[k] = v
+	}
+	return m, nil
+}
+
 // AddWorkflowRunInfo add WorkflowRunInfo on a WorkflowRun
 func AddWorkflowRunInfo(run *sdk.WorkflowRun, infos ...sdk.SpawnMsg) {
 	for _, i := range infos {
@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//ProvenanceKeyMetadata is the project metadata which names the PGP key used to sign provenance attestations.
+//Without it, the first PGP key of the project is used
+const ProvenanceKeyMetadata = "provenance_key"
+
+//provenanceKey returns the project key used to sign provenance attestations
+func provenanceKey(proj *sdk.Project) *sdk.ProjectKey {
+	name := proj.Metadata[ProvenanceKeyMetadata]
+	for i := range proj.Keys {
+		k := &proj.Keys[i]
+		if k.Type != sdk.KeyTypePgp {
+			continue
+		}
+		if name == "" || k.Name == name {
+			return k
+		}
+	}
+	return nil
+}
+
+//generateProvenance signs and stores the provenance attestation of a node run
+func generateProvenance(db gorp.SqlExecutor, run *sdk.WorkflowRun, n *sdk.WorkflowNodeRun) error {
+	proj, err := project.LoadByID(db, run.ProjectID, nil, project.LoadOptions.WithKeys)
+	if err != nil {
+		return sdk.WrapError(err, "generateProvenance> Unable to load project %d", run.ProjectID)
+	}
+
+	k := provenanceKey(proj)
+	if k == nil {
+		log.Debug("generateProvenance> No PGP key on project %s, provenance of node run %d is not generated", proj.Key, n.ID)
+		return nil
+	}
+
+	arts, err := loadArtifactByNodeRunID(db, n.ID)
+	if err != nil {
+		return sdk.WrapError(err, "generateProvenance> Unable to load artifacts of node run %d", n.ID)
+	}
+	nodeRun := *n
+	nodeRun.Artifacts = arts
+
+	modelsSet := map[string]bool{}
+	for _, s := range n.Stages {
+		for _, j := range s.RunJobs {
+			if j.Model != "" {
+				modelsSet[j.Model] = true
+			}
+		}
+	}
+	models := []string{}
+	for m := range modelsSet {
+		models = append(models, m)
+	}
+	sort.Strings(models)
+
+	nodeName := fmt.Sprintf("%d", n.WorkflowNodeID)
+	if node := run.Workflow.GetNode(n.WorkflowNodeID); node != nil {
+		nodeName = node.Name
+	}
+
+	statement := sdk.NewProvenanceStatement(fmt.Sprintf("%s/%s/%s", proj.Key, run.Workflow.Name, nodeName), nodeRun, models)
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return sdk.WrapError(err, "generateProvenance> Unable to marshal provenance of node run %d", n.ID)
+	}
+
+	signature, err := keys.SignPGP(k.Private, payload)
+	if err != nil {
+		return sdk.WrapError(err, "generateProvenance> Unable to sign provenance of node run %d with key %s", n.ID, k.Name)
+	}
+
+	p := NodeRunProvenance{
+		WorkflowNodeRunID: n.ID,
+		PayloadType:       sdk.ProvenancePayloadType,
+		Payload:           base64.StdEncoding.EncodeToString(payload),
+		KeyName:           k.Name,
+		KeyID:             k.KeyID,
+		Signature:         signature,
+		Created:           time.Now(),
+	}
+	if _, err := db.Exec("DELETE FROM workflow_node_run_provenance WHERE workflow_node_run_id = $1", n.ID); err != nil {
+		return sdk.WrapError(err, "generateProvenance> Unable to delete previous provenance of node run %d", n.ID)
+	}
+	if err := db.Insert(&p); err != nil {
+		return sdk.WrapError(err, "generateProvenance> Unable to insert provenance of node run %d", n.ID)
+	}
+	return nil
+}
+
+//LoadProvenance loads the provenance attestation of a node run
+func LoadProvenance(db gorp.SqlExecutor, nodeRunID int64) (*sdk.ProvenanceAttestation, error) {
+	var p NodeRunProvenance
+	if err := db.SelectOne(&p, "SELECT * FROM workflow_node_run_provenance WHERE workflow_node_run_id = $1", nodeRunID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadProvenance> Unable to load provenance of node run %d", nodeRunID)
+	}
+	att := sdk.ProvenanceAttestation(p)
+	return &att, nil
+}
+
+//VerifyProvenance checks the signature of a provenance attestation with the public key of the project which signed it
+func VerifyProvenance(db gorp.SqlExecutor, projectKey string, att *sdk.ProvenanceAttestation) (*sdk.ProvenanceVerification, error) {
+	proj, err := project.Load(db, projectKey, nil, project.LoadOptions.WithKeys)
+	if err != nil {
+		return nil, sdk.WrapError(err, "VerifyProvenance> Unable to load project %s", projectKey)
+	}
+
+	res := &sdk.ProvenanceVerification{KeyName: att.KeyName, KeyID: att.KeyID}
+	for _, k := range proj.Keys {
+		if k.Name != att.KeyName || k.Type != sdk.KeyTypePgp {
+			continue
+		}
+		if err := att.Verify(k.Public); err != nil {
+			res.Error = err.Error()
+			return res, nil
+		}
+		res.Verified = true
+		return res, nil
+	}
+	res.Error = fmt.Sprintf("key %s not found on project %s", att.KeyName, projectKey)
+	return res, nil
+}
@@ -1,24 +1,44 @@
 package workflow
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
-//RunFromHook is the entry point to trigger a workflow from a hook
-func RunFromHook(db gorp.SqlExecutor, w *sdk.Workflow, e *sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error) {
-	return nil, nil
+//RunFromHook is the entry point to trigger a workflow from a hook. The run is traced in the trace of the context.
+//Pull request events are filtered with the fork policy before any run is created
+func RunFromHook(ctx context.Context, db gorp.SqlExecutor, w *sdk.Workflow, e *sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error) {
+	if e.PullRequest != nil && !repositoriesmanager.TriggerPullRequest(*e.PullRequest) {
+		return nil, sdk.WrapError(sdk.ErrForkPullRequestSkipped, "RunFromHook> Pull request %d of workflow %s/%s not triggered", e.PullRequest.ID, w.ProjectKey, w.Name)
+	}
+
+	wr, err := insertNewRun(ctx, db, w)
+	if err != nil {
+		return nil, sdk.WrapError(err, "RunFromHook> Unable to run workflow %s/%s", w.ProjectKey, w.Name)
+	}
+
+	return wr, processWorkflowRun(db, wr, e, nil, nil)
 }
 
-//ManualRunFromNode is the entry point to trigger manually a piece of an existing run workflow
-func ManualRunFromNode(db gorp.SqlExecutor, w *sdk.Workflow, number int64, e *sdk.WorkflowNodeRunManual, nodeID int64) (*sdk.WorkflowRun, error) {
+//ManualRunFromNode is the entry point to trigger manually a piece of an existing run workflow.
+//The new jobs of the run are traced in the trace of the context
+func ManualRunFromNode(ctx context.Context, db gorp.SqlExecutor, w *sdk.Workflow, number int64, e *sdk.WorkflowNodeRunManual, nodeID int64) (*sdk.WorkflowRun, error) {
 	lastWorkflowRun, err := LoadRun(db, w.ProjectKey, w.Name, number)
 	if err != nil {
 		return nil, sdk.WrapError(err, "ManualRunFromNode> Unable to load last run")
 	}
+	if tp := tracing.TraceParent(ctx); tp != "" {
+		lastWorkflowRun.TraceParent = tp
+		if err := updateWorkflowRun(db, lastWorkflowRun); err != nil {
+			return nil, sdk.WrapError(err, "ManualRunFromNode> Unable to save trace of the run")
+		}
+	}
 
 	if err := processWorkflowRun(db, lastWorkflowRun, nil, e, &nodeID); err != nil {
 		return nil, sdk.WrapError(err, "ManualRunFromNode> Unable to process workflow run")
@@ -32,12 +52,22 @@ func ManualRunFromNode(db gorp.SqlExecutor, w *sdk.Workflow, number int64, e *sd
 	return lastWorkflowRun, nil
 }
 
-//ManualRun is the entry point to trigger a workflow manually
-func ManualRun(db gorp.SqlExecutor, w *sdk.Workflow, e *sdk.WorkflowNodeRunManual) (*sdk.WorkflowRun, error) {
+//ManualRun is the entry point to trigger a workflow manually. The run is traced in the trace of the context
+func ManualRun(ctx context.Context, db gorp.SqlExecutor, w *sdk.Workflow, e *sdk.WorkflowNodeRunManual) (*sdk.WorkflowRun, error) {
+	wr, err := insertNewRun(ctx, db, w)
+	if err != nil {
+		return nil, sdk.WrapError(err, "ManualRun> Unable to manually run workflow %s/%s", w.ProjectKey, w.Name)
+	}
+
+	return wr, processWorkflowRun(db, wr, nil, e, nil)
+}
+
+//insertNewRun inserts a new run of the workflow, numbered after the last one
+func insertNewRun(ctx context.Context, db gorp.SqlExecutor, w *sdk.Workflow) (*sdk.WorkflowRun, error) {
 	lastWorkflowRun, err := LoadLastRun(db, w.ProjectKey, w.Name)
 	if err != nil {
 		if err != sdk.ErrWorkflowNotFound {
-			return nil, sdk.WrapError(err, "ManualRun> Unable to load last run")
+			return nil, sdk.WrapError(err, "insertNewRun> Unable to load last run")
 		}
 	}
 
@@ -53,11 +83,11 @@ func ManualRun(db gorp.SqlExecutor, w *sdk.Workflow, e *sdk.WorkflowNodeRunManua
 		Start:        time.Now(),
 		LastModified: time.Now(),
 		ProjectID:    w.ProjectID,
+		TraceParent:  tracing.TraceParent(ctx),
 	}
 
 	if err := insertWorkflowRun(db, wr); err != nil {
-		return nil, sdk.WrapError(err, "ManualRun> Unable to manually run workflow %s/%s", w.ProjectKey, w.Name)
+		return nil, err
 	}
-
-	return wr, processWorkflowRun(db, wr, nil, e, nil)
+	return wr, nil
 }
@@ -8,11 +8,13 @@ import (
 
 	dump "github.com/fsamin/go-dump"
 	"github.com/go-gorp/gorp"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
 	"github.com/ovh/cds/sdk"
@@ -89,7 +91,7 @@ func TestManualRun1(t *testing.T) {
 	w1, err := Load(db, key, "test_1", u)
 	test.NoError(t, err)
 
-	_, err = ManualRun(db, w1, &sdk.WorkflowNodeRunManual{
+	_, err = ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 		Payload: map[string]string{
 			"git.branch": "master",
@@ -97,7 +99,7 @@ func TestManualRun1(t *testing.T) {
 	})
 	test.NoError(t, err)
 
-	wr1, err := ManualRun(db, w1, &sdk.WorkflowNodeRunManual{User: *u})
+	wr1, err := ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{User: *u})
 	test.NoError(t, err)
 
 	m1, _ := dump.ToMap(wr1)
@@ -156,7 +158,7 @@ func TestManualRun1(t *testing.T) {
 	}
 
 	//TestprocessWorkflowRun
-	wr2, err := ManualRunFromNode(db, w1, 2, &sdk.WorkflowNodeRunManual{User: *u}, w1.RootID)
+	wr2, err := ManualRunFromNode(context.Background(), db, w1, 2, &sdk.WorkflowNodeRunManual{User: *u}, w1.RootID)
 	test.NoError(t, err)
 	assert.NotNil(t, wr2)
 
@@ -257,16 +259,16 @@ func TestManualRun2(t *testing.T) {
 	w1, err := Load(db, key, "test_1", u)
 	test.NoError(t, err)
 
-	_, err = ManualRun(db, w1, &sdk.WorkflowNodeRunManual{
+	_, err = ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	test.NoError(t, err)
 
-	_, err = ManualRun(db, w1, &sdk.WorkflowNodeRunManual{User: *u})
+	_, err = ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{User: *u})
 	test.NoError(t, err)
 
 	//TestprocessWorkflowRun
-	_, err = ManualRunFromNode(db, w1, 1, &sdk.WorkflowNodeRunManual{User: *u}, w1.RootID)
+	_, err = ManualRunFromNode(context.Background(), db, w1, 1, &sdk.WorkflowNodeRunManual{User: *u}, w1.RootID)
 	test.NoError(t, err)
 
 	c, cancel := context.WithTimeout(context.Background(), time.Second*2)
@@ -355,7 +357,7 @@ func TestManualRun3(t *testing.T) {
 	w1, err := Load(db, key, "test_1", u)
 	test.NoError(t, err)
 
-	ManualRun(db, w1, &sdk.WorkflowNodeRunManual{
+	ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	test.NoError(t, err)
@@ -477,3 +479,249 @@ func TestManualRun3(t *testing.T) {
 		assert.Equal(t, "job20", jobs[0].Job.Job.Action.Name)
 	}
 }
+
+func TestManualRunWithApproval(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+		Type:       sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, proj, &pip, u))
+
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	pipeline.InsertStage(db, s)
+	j := &sdk.Job{
+		Enabled: true,
+		Action: sdk.Action{
+			Enabled: true,
+		},
+	}
+	pipeline.InsertJob(db, j, s.ID, &pip)
+	s.Jobs = append(s.Jobs, *j)
+	pip.Stages = append(pip.Stages, *s)
+
+	w := sdk.Workflow{
+		Name:       "test_approval",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Root: &sdk.WorkflowNode{
+			Pipeline: pip,
+			Context: &sdk.WorkflowNodeContext{
+				Approval: &sdk.WorkflowNodeApproval{
+					Groups:       []string{"approvers"},
+					MinApprovals: 1,
+				},
+			},
+		},
+	}
+
+	test.NoError(t, Insert(db, &w, u))
+	w1, err := Load(db, key, "test_approval", u)
+	test.NoError(t, err)
+
+	wr, err := ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{User: *u})
+	test.NoError(t, err)
+
+	nodeRun := wr.WorkflowNodeRuns[w1.RootID][0]
+	assert.Equal(t, sdk.StatusWaitingApproval.String(), nodeRun.Status)
+
+	//Only the members of the groups can approve
+	approver := *u
+	approver.Groups = nil
+	assert.Equal(t, sdk.ErrWorkflowNodeRunNotApprover, ApproveNodeRun(db, &nodeRun, &approver, true, ""))
+
+	approver.Groups = []sdk.Group{{Name: "approvers"}}
+	test.NoError(t, ApproveNodeRun(db, &nodeRun, &approver, true, "lgtm"))
+	assert.Equal(t, sdk.StatusWaiting.String(), nodeRun.Status)
+
+	approvals, err := LoadNodeRunApprovals(db, nodeRun.ID)
+	test.NoError(t, err)
+	assert.Len(t, approvals, 1)
+	assert.Equal(t, "lgtm", approvals[0].Comment)
+
+	assert.Equal(t, sdk.ErrWorkflowNodeRunNotWaitingApproval, ApproveNodeRun(db, &nodeRun, &approver, true, ""))
+}
+
+func TestManualRunWithApprovalRejected(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+		Type:       sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, proj, &pip, u))
+
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	pipeline.InsertStage(db, s)
+	j := &sdk.Job{
+		Enabled: true,
+		Action: sdk.Action{
+			Enabled: true,
+		},
+	}
+	pipeline.InsertJob(db, j, s.ID, &pip)
+	s.Jobs = append(s.Jobs, *j)
+	pip.Stages = append(pip.Stages, *s)
+
+	w := sdk.Workflow{
+		Name:       "test_approval_rejected",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Root: &sdk.WorkflowNode{
+			Pipeline: pip,
+			Context: &sdk.WorkflowNodeContext{
+				Approval: &sdk.WorkflowNodeApproval{
+					MinApprovals: 1,
+				},
+			},
+			Triggers: []sdk.WorkflowNodeTrigger{
+				sdk.WorkflowNodeTrigger{
+					WorkflowDestNode: sdk.WorkflowNode{
+						Pipeline: pip,
+					},
+				},
+			},
+		},
+	}
+
+	//An approval gate without groups can never be approved
+	assert.Error(t, Insert(db, &w, u))
+
+	w.Root.Context.Approval.Groups = []string{"approvers"}
+	test.NoError(t, Insert(db, &w, u))
+	w1, err := Load(db, key, "test_approval_rejected", u)
+	test.NoError(t, err)
+
+	wr, err := ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{User: *u})
+	test.NoError(t, err)
+
+	nodeRun := wr.WorkflowNodeRuns[w1.RootID][0]
+	assert.Equal(t, sdk.StatusWaitingApproval.String(), nodeRun.Status)
+
+	approver := *u
+	approver.Groups = []sdk.Group{{Name: "approvers"}}
+	test.NoError(t, ApproveNodeRun(db, &nodeRun, &approver, false, "nope"))
+	assert.Equal(t, sdk.StatusFail.String(), nodeRun.Status)
+
+	//The rejected node run is over, so its triggers have been processed
+	wr, err = LoadRunByID(db, wr.ID)
+	test.NoError(t, err)
+	assert.Len(t, wr.WorkflowNodeRuns, 2)
+}
+
+func TestRunFromHookWithPullRequest(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+		Type:       sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, proj, &pip, u))
+
+	w := sdk.Workflow{
+		Name:       "test_pull_request",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Root: &sdk.WorkflowNode{
+			Pipeline: pip,
+		},
+	}
+
+	test.NoError(t, Insert(db, &w, u))
+	w1, err := Load(db, key, "test_pull_request", u)
+	test.NoError(t, err)
+
+	wr, err := RunFromHook(context.Background(), db, w1, &sdk.WorkflowNodeRunHookEvent{
+		Payload: map[string]string{"git.pr.fork": "false"},
+		PullRequest: &sdk.VCSPullRequestEvent{
+			Action: "opened",
+			ID:     42,
+			Head:   sdk.VCSPushEvent{Branch: sdk.VCSBranch{DisplayID: "feat"}},
+			Base:   sdk.VCSPushEvent{Branch: sdk.VCSBranch{DisplayID: "master"}},
+			Ref:    "refs/pull/42/merge",
+			Fork:   true,
+		},
+	})
+	test.NoError(t, err)
+
+	params := wr.WorkflowNodeRuns[w1.RootID][0].BuildParameters
+	assert.Equal(t, "42", sdk.ParameterValue(params, "git.pr.id"))
+	assert.Equal(t, "master", sdk.ParameterValue(params, "git.pr.base"))
+	assert.Equal(t, "refs/pull/42/merge", sdk.ParameterValue(params, "git.pr.ref"))
+	//The payload cannot hide a pull request coming from a fork
+	assert.True(t, sdk.IsForkPullRequest(params))
+}
+
+func TestRunFromHookWithForkPullRequestSkipped(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, key, key, u)
+
+	test.NoError(t, repositoriesmanager.Initialize(repositoriesmanager.InitializeOpts{ForkPullRequestPolicy: repositoriesmanager.ForkPolicySkip}))
+	defer repositoriesmanager.Initialize(repositoriesmanager.InitializeOpts{})
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+		Type:       sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, proj, &pip, u))
+
+	w := sdk.Workflow{
+		Name:       "test_fork_pull_request",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Root: &sdk.WorkflowNode{
+			Pipeline: pip,
+		},
+	}
+
+	test.NoError(t, Insert(db, &w, u))
+	w1, err := Load(db, key, "test_fork_pull_request", u)
+	test.NoError(t, err)
+
+	pr := sdk.VCSPullRequestEvent{
+		Action: "opened",
+		ID:     42,
+		Head:   sdk.VCSPushEvent{Branch: sdk.VCSBranch{DisplayID: "feat"}},
+		Base:   sdk.VCSPushEvent{Branch: sdk.VCSBranch{DisplayID: "master"}},
+		Ref:    "refs/pull/42/merge",
+		Fork:   true,
+	}
+
+	//The pull request from a fork is refused before any run is created
+	wr, err := RunFromHook(context.Background(), db, w1, &sdk.WorkflowNodeRunHookEvent{PullRequest: &pr})
+	assert.Nil(t, wr)
+	assert.Equal(t, sdk.ErrForkPullRequestSkipped, errors.Cause(err))
+	_, err = LoadLastRun(db, key, "test_fork_pull_request")
+	assert.Equal(t, sdk.ErrWorkflowNotFound, err)
+
+	//The same pull request from the repository itself is built
+	pr.Fork = false
+	wr, err = RunFromHook(context.Background(), db, w1, &sdk.WorkflowNodeRunHookEvent{PullRequest: &pr})
+	test.NoError(t, err)
+	assert.Equal(t, int64(1), wr.Number)
+	assert.False(t, sdk.IsForkPullRequest(wr.WorkflowNodeRuns[w1.RootID][0].BuildParameters))
+}
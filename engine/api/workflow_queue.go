@@ -15,7 +15,8 @@ import (
 
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/businesscontext"
-	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/flakytest"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
@@ -135,6 +136,12 @@ func postTakeWorkflowJobHandler(w http.ResponseWriter, r *http.Request, db *gorp
 	pbji.Secrets = append(pbji.Secrets, secretsKeys...)
 	pbji.NodeJobRun.Parameters = append(pbji.NodeJobRun.Parameters, params...)
 
+	//Pull requests from forks must not have access to project secrets
+	if repositoriesmanager.WithholdSecrets(job.Parameters) {
+		log.Info("postTakeWorkflowJobHandler> Withholding secrets of job %d triggered by a pull request from a fork", job.ID)
+		pbji.Secrets = nil
+	}
+
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot commit transaction")
 	}
@@ -364,6 +371,31 @@ func postWorkflowJobTestsResultsHandler(w http.ResponseWriter, r *http.Request,
 		return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot load node job")
 	}
 
+	run, err := workflow.LoadRunByID(tx, wnjr.WorkflowRunID)
+	if err != nil {
+		return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot load workflow run")
+	}
+
+	// Record the tests history of the application to detect flaky tests
+	quarantine := []sdk.TestQuarantine{}
+	if node := run.Workflow.GetNode(wnjr.WorkflowNodeID); node != nil && node.Context != nil && node.Context.ApplicationID != 0 {
+		appID := node.Context.ApplicationID
+		branch := sdk.ParameterValue(wnjr.BuildParameters, "git.branch")
+		hash := sdk.ParameterValue(wnjr.BuildParameters, "git.hash")
+		flaky, err := flakytest.Record(tx, appID, branch, hash, wnjr.Number, new)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot record tests history")
+		}
+		for _, f := range flaky {
+			log.Info("postWorkflowJobTestsResultsHandler> %s/%s is flaky on application %d branch %s (%d flips)", f.TestSuite, f.TestName, appID, branch, f.Flips)
+		}
+
+		quarantine, err = flakytest.LoadQuarantine(tx, appID)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot load quarantined tests")
+		}
+	}
+
 	if wnjr.Tests == nil {
 		wnjr.Tests = &venom.Tests{}
 	}
@@ -399,7 +431,59 @@ func postWorkflowJobTestsResultsHandler(w http.ResponseWriter, r *http.Request,
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot update node run")
 	}
-	return nil
+
+	//The worker ignores the failures of the quarantined tests
+	return WriteJSON(w, r, quarantine, http.StatusOK)
+}
+
+func postWorkflowJobCoverageHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	var report sdk.CoverageReport
+	if err := UnmarshalBody(r, &report); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobCoverageHandler> cannot unmarshal request")
+	}
+
+	id, errI := requestVarInt(r, "permID")
+	if errI != nil {
+		return sdk.WrapError(errI, "postWorkflowJobCoverageHandler> Invalid node job run ID")
+	}
+
+	nodeRunJob, errJobRun := workflow.LoadNodeJobRun(db, id)
+	if errJobRun != nil {
+		return sdk.WrapError(errJobRun, "postWorkflowJobCoverageHandler> Cannot load node run job")
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "postWorkflowJobCoverageHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	nodeRun, err := workflow.LoadAndLockNodeRunByID(tx, nodeRunJob.WorkflowNodeRunID)
+	if err != nil {
+		return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot load node run")
+	}
+
+	run, err := workflow.LoadRunByID(tx, nodeRun.WorkflowRunID)
+	if err != nil {
+		return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot load workflow run")
+	}
+
+	cov, err := workflow.AddCoverage(tx, run, nodeRun, report)
+	if err != nil {
+		return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot add coverage")
+	}
+
+	if err := workflow.UpdateNodeRun(tx, nodeRun); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot update node run")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot commit transaction")
+	}
+
+	//Files are not sent back to the worker
+	cov.Report.Files = nil
+	return WriteJSON(w, r, cov, http.StatusOK)
 }
 
 func postWorkflowJobVariableHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
@@ -474,7 +558,7 @@ func postWorkflowJobArtifactHandler(w http.ResponseWriter, r *http.Request, db *
 	//get a ref to the parsed multipart form
 	m := r.MultipartForm
 
-	var sizeStr, permStr, md5sum string
+	var sizeStr, permStr, md5sum, sha256sum string
 	if len(m.Value["size"]) > 0 {
 		sizeStr = m.Value["size"][0]
 	}
@@ -484,6 +568,9 @@ func postWorkflowJobArtifactHandler(w http.ResponseWriter, r *http.Request, db *
 	if len(m.Value["md5sum"]) > 0 {
 		md5sum = m.Value["md5sum"][0]
 	}
+	if len(m.Value["sha256sum"]) > 0 {
+		sha256sum = m.Value["sha256sum"][0]
+	}
 
 	if fileName == "" {
 		log.Warning("uploadArtifactHandler> %s header is not set", "Content-Disposition")
@@ -536,15 +623,21 @@ func postWorkflowJobArtifactHandler(w http.ResponseWriter, r *http.Request, db *
 
 		}
 
-		if err := artifact.SaveWorkflowFile(&art, file); err != nil {
+		if err := artifact.SaveWorkflowFile(db, &art, file); err != nil {
+			file.Close()
 			return sdk.WrapError(err, "postWorkflowJobArtifactHandler> Cannot save artifact in store")
 		}
 		file.Close()
+
+		if sha256sum != "" && sha256sum != art.SHA256sum {
+			_ = artifact.DeleteWorkflowFile(db, &art)
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowJobArtifactHandler> Invalid sha256sum for %s: got %s, expected %s", fileName, art.SHA256sum, sha256sum)
+		}
 	}
 
 	nodeRun.Artifacts = append(nodeRun.Artifacts, art)
 	if err := workflow.InsertArtifact(db, &art); err != nil {
-		_ = objectstore.DeleteArtifact(&art)
+		_ = artifact.DeleteWorkflowFile(db, &art)
 		return sdk.WrapError(err, "postWorkflowJobArtifactHandler> Cannot update workflow node run")
 	}
 	return nil
@@ -178,6 +178,174 @@ func getWorkflowNodeRunHandler(w http.ResponseWriter, r *http.Request, db *gorp.
 	return WriteJSON(w, r, run, http.StatusOK)
 }
 
+func getWorkflowNodeRunProvenanceHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	att, err := loadWorkflowNodeRunProvenance(r, db)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowNodeRunProvenanceHandler> Unable to load provenance")
+	}
+	return WriteJSON(w, r, att, http.StatusOK)
+}
+
+func getWorkflowNodeRunProvenanceVerifyHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	att, err := loadWorkflowNodeRunProvenance(r, db)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowNodeRunProvenanceVerifyHandler> Unable to load provenance")
+	}
+	res, err := workflow.VerifyProvenance(db, mux.Vars(r)["permProjectKey"], att)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowNodeRunProvenanceVerifyHandler> Unable to verify provenance")
+	}
+	return WriteJSON(w, r, res, http.StatusOK)
+}
+
+func loadWorkflowNodeRunProvenance(r *http.Request, db gorp.SqlExecutor) (*sdk.ProvenanceAttestation, error) {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+	number, err := requestVarInt(r, "number")
+	if err != nil {
+		return nil, err
+	}
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return nil, err
+	}
+	//Check the node run belongs to the workflow run
+	if _, err := workflow.LoadNodeRun(db, key, name, number, id); err != nil {
+		return nil, sdk.WrapError(err, "loadWorkflowNodeRunProvenance> Unable to load node run %d", id)
+	}
+	return workflow.LoadProvenance(db, id)
+}
+
+func getWorkflowNodeRunApprovalsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+	number, err := requestVarInt(r, "number")
+	if err != nil {
+		return err
+	}
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return err
+	}
+	if _, err := workflow.LoadNodeRun(db, key, name, number, id); err != nil {
+		return sdk.WrapError(err, "getWorkflowNodeRunApprovalsHandler> Unable to load node run %d", id)
+	}
+	approvals, err := workflow.LoadNodeRunApprovals(db, id)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowNodeRunApprovalsHandler> Unable to load approvals")
+	}
+	return WriteJSON(w, r, approvals, http.StatusOK)
+}
+
+type postWorkflowNodeRunApprovalHandlerOption struct {
+	Approved bool   `json:"approved"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+func postWorkflowNodeRunApprovalHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+	number, err := requestVarInt(r, "number")
+	if err != nil {
+		return err
+	}
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return err
+	}
+
+	opts := &postWorkflowNodeRunApprovalHandlerOption{}
+	if err := UnmarshalBody(r, opts); err != nil {
+		return err
+	}
+
+	tx, errb := db.Begin()
+	if errb != nil {
+		return errb
+	}
+	defer tx.Rollback()
+
+	if _, err := workflow.LoadNodeRun(tx, key, name, number, id); err != nil {
+		return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Unable to load node run %d", id)
+	}
+	nodeRun, err := workflow.LoadAndLockNodeRunByID(tx, id)
+	if err != nil {
+		return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Unable to lock node run %d", id)
+	}
+
+	if err := workflow.ApproveNodeRun(tx, nodeRun, c.User, opts.Approved, opts.Comment); err != nil {
+		return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Unable to approve node run %d", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Unable to commit transaction")
+	}
+
+	approvals, err := workflow.LoadNodeRunApprovals(db, id)
+	if err != nil {
+		return sdk.WrapError(err, "postWorkflowNodeRunApprovalHandler> Unable to load approvals")
+	}
+	return WriteJSON(w, r, approvals, http.StatusOK)
+}
+
+func getWorkflowNodeRunCoverageHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+	number, err := requestVarInt(r, "number")
+	if err != nil {
+		return err
+	}
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return err
+	}
+	nodeRun, err := workflow.LoadNodeRun(db, key, name, number, id)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowNodeRunCoverageHandler> Unable to load node run %d", id)
+	}
+	cov, err := workflow.LoadCoverage(db, nodeRun)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowNodeRunCoverageHandler> Unable to load coverage")
+	}
+	return WriteJSON(w, r, cov, http.StatusOK)
+}
+
+func getWorkflowCoverageTrendHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+
+	node := r.FormValue("node")
+	if node == "" {
+		return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowCoverageTrendHandler> node is mandatory")
+	}
+	branch := r.FormValue("branch")
+
+	limit := defaultLimit
+	if limitS := r.FormValue("limit"); limitS != "" {
+		var errAtoi error
+		limit, errAtoi = strconv.Atoi(limitS)
+		if errAtoi != nil || limit <= 0 || limit > rangeMax {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowCoverageTrendHandler> Invalid limit %s", limitS)
+		}
+	}
+
+	wf, err := workflow.Load(db, key, name, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowCoverageTrendHandler> Unable to load workflow %s", name)
+	}
+
+	trend, err := workflow.LoadCoverageTrend(db, wf.ID, node, branch, limit)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowCoverageTrendHandler> Unable to load coverage trend")
+	}
+	return WriteJSON(w, r, trend, http.StatusOK)
+}
+
 type postWorkflowRunHandlerOption struct {
 	Hook       *sdk.WorkflowNodeRunHookEvent `json:"hook,omitempty"`
 	Manual     *sdk.WorkflowNodeRunManual    `json:"manual,omitempty"`
@@ -220,7 +388,7 @@ func postWorkflowRunHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 	//Run from hook
 	if opts.Hook != nil {
 		var errfh error
-		wr, errfh = workflow.RunFromHook(tx, wf, opts.Hook)
+		wr, errfh = workflow.RunFromHook(r.Context(), tx, wf, opts.Hook)
 		if errfh != nil {
 			return sdk.WrapError(errfh, "postWorkflowRunHandler> Unable to run workflow")
 		}
@@ -264,13 +432,13 @@ func postWorkflowRunHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 				opts.FromNodeID = &lastRun.Workflow.RootID
 			}
 			var errmr error
-			wr, errmr = workflow.ManualRunFromNode(tx, wf, lastRun.Number, opts.Manual, *opts.FromNodeID)
+			wr, errmr = workflow.ManualRunFromNode(r.Context(), tx, wf, lastRun.Number, opts.Manual, *opts.FromNodeID)
 			if errmr != nil {
 				return sdk.WrapError(errmr, "postWorkflowRunHandler> Unable to run workflow")
 			}
 		} else {
 			var errmr error
-			wr, errmr = workflow.ManualRun(tx, wf, opts.Manual)
+			wr, errmr = workflow.ManualRun(r.Context(), tx, wf, opts.Manual)
 			if errmr != nil {
 				return sdk.WrapError(errmr, "postWorkflowRunHandler> Unable to run workflow")
 			}
@@ -331,7 +499,7 @@ func getDownloadArtifactHandler(w http.ResponseWriter, r *http.Request, db *gorp
 	w.Header().Add("Content-Type", "application/octet-stream")
 	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
 
-	if err := artifact.StreamFile(w, art); err != nil {
+	if err := artifact.StreamWorkflowFile(w, art); err != nil {
 		return sdk.WrapError(err, "Cannot stream artifact %s", art.Name)
 	}
 	return nil
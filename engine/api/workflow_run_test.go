@@ -93,7 +93,7 @@ func Test_getWorkflowRunsHandler(t *testing.T) {
 	test.NoError(t, err)
 
 	for i := 0; i < 10; i++ {
-		_, err = workflow.ManualRun(db, w1, &sdk.WorkflowNodeRunManual{
+		_, err = workflow.ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{
 			User: *u,
 		})
 		test.NoError(t, err)
@@ -220,7 +220,7 @@ func Test_getLatestWorkflowRunHandler(t *testing.T) {
 	test.NoError(t, err)
 
 	for i := 0; i < 10; i++ {
-		_, err = workflow.ManualRun(db, w1, &sdk.WorkflowNodeRunManual{
+		_, err = workflow.ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{
 			User: *u,
 			Payload: map[string]string{
 				"git.branch": "master",
@@ -340,7 +340,7 @@ func Test_getWorkflowRunHandler(t *testing.T) {
 	test.NoError(t, err)
 
 	for i := 0; i < 10; i++ {
-		_, err = workflow.ManualRun(db, w1, &sdk.WorkflowNodeRunManual{
+		_, err = workflow.ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{
 			User: *u,
 		})
 		test.NoError(t, err)
@@ -440,7 +440,7 @@ func Test_getWorkflowNodeRunHandler(t *testing.T) {
 	w1, err := workflow.Load(db, key, "test_1", u)
 	test.NoError(t, err)
 
-	_, err = workflow.ManualRun(db, w1, &sdk.WorkflowNodeRunManual{
+	_, err = workflow.ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	test.NoError(t, err)
@@ -638,7 +638,7 @@ func Test_getWorkflowNodeRunJobStepHandler(t *testing.T) {
 	w1, err := workflow.Load(db, key, "test_1", u)
 	test.NoError(t, err)
 
-	_, err = workflow.ManualRun(db, w1, &sdk.WorkflowNodeRunManual{
+	_, err = workflow.ManualRun(context.Background(), db, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	test.NoError(t, err)
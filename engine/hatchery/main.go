@@ -14,6 +14,8 @@ import (
 	"github.com/ovh/cds/engine/hatchery/swarm"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/metrics"
+	"github.com/ovh/cds/sdk/tracing"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -56,6 +58,17 @@ var rootCmd = &cobra.Command{
 				sdk.Exit("Error on starting gops agent", err)
 			}
 		}
+
+		metrics.Serve(viper.GetString("metrics-addr"))
+
+		if err := tracing.Init(tracing.Config{
+			Exporter:    viper.GetString("tracing-exporter"),
+			File:        viper.GetString("tracing-file"),
+			Endpoint:    viper.GetString("tracing-endpoint"),
+			ServiceName: "cds-hatchery",
+		}); err != nil {
+			sdk.Exit("Error on tracing initialization: %s\n", err)
+		}
 	},
 }
 
@@ -127,6 +140,18 @@ func addFlags() {
 	rootCmd.PersistentFlags().Int64("grace-time-queued", 4, "if worker is queued less than this value (seconds), hatchery does not take care of it")
 	viper.BindPFlag("grace-time-queued", rootCmd.PersistentFlags().Lookup("grace-time-queued"))
 
+	rootCmd.PersistentFlags().String("metrics-addr", "", "Expose Prometheus metrics on http://<metrics-addr>/mon/metrics. Ex: --metrics-addr=:9110")
+	viper.BindPFlag("metrics-addr", rootCmd.PersistentFlags().Lookup("metrics-addr"))
+
+	rootCmd.PersistentFlags().String("tracing-exporter", "", "Export the traces of the spawns: stdout, file or otlp. Disabled if empty")
+	viper.BindPFlag("tracing-exporter", rootCmd.PersistentFlags().Lookup("tracing-exporter"))
+
+	rootCmd.PersistentFlags().String("tracing-file", "", "File the traces are written to with the file exporter")
+	viper.BindPFlag("tracing-file", rootCmd.PersistentFlags().Lookup("tracing-file"))
+
+	rootCmd.PersistentFlags().String("tracing-endpoint", "", "OpenTelemetry collector with the otlp exporter. Ex: --tracing-endpoint=http://localhost:4318")
+	viper.BindPFlag("tracing-endpoint", rootCmd.PersistentFlags().Lookup("tracing-endpoint"))
+
 	rootCmd.PersistentFlags().String("graylog-protocol", "", "Ex: --graylog-protocol=xxxx-yyyy")
 	viper.BindPFlag("graylog_protocol", rootCmd.PersistentFlags().Lookup("graylog-protocol"))
 
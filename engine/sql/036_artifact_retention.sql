@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "project_artifact_retention" (
+    project_id BIGINT PRIMARY KEY,
+    keep_last INT NOT NULL DEFAULT 0,
+    keep_tagged BOOLEAN NOT NULL DEFAULT TRUE,
+    max_age INT NOT NULL DEFAULT 0
+);
+
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_ARTIFACT_RETENTION_PROJECT', 'project_artifact_retention', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE project_artifact_retention;
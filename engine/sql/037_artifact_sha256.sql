@@ -0,0 +1,14 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run_artifacts ADD COLUMN sha256sum TEXT;
+
+CREATE TABLE IF NOT EXISTS "object_store_blob" (
+    sha256sum TEXT PRIMARY KEY,
+    object_path TEXT,
+    size BIGINT,
+    refcount INT NOT NULL DEFAULT 0,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+-- +migrate Down
+ALTER TABLE workflow_node_run_artifacts DROP COLUMN sha256sum;
+DROP TABLE object_store_blob;
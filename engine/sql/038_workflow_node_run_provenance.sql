@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_provenance" (
+    workflow_node_run_id BIGINT PRIMARY KEY,
+    payload_type TEXT,
+    payload TEXT,
+    key_name TEXT,
+    key_id TEXT,
+    signature TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_PROVENANCE_NODE_RUN', 'workflow_node_run_provenance', 'workflow_node_run', 'workflow_node_run_id', 'id');
+
+-- +migrate Down
+DROP TABLE workflow_node_run_provenance;
@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE project_group ADD COLUMN role_name TEXT;
+ALTER TABLE application_group ADD COLUMN role_name TEXT;
+ALTER TABLE pipeline_group ADD COLUMN role_name TEXT;
+ALTER TABLE environment_group ADD COLUMN role_name TEXT;
+
+-- +migrate Down
+ALTER TABLE project_group DROP COLUMN role_name;
+ALTER TABLE application_group DROP COLUMN role_name;
+ALTER TABLE pipeline_group DROP COLUMN role_name;
+ALTER TABLE environment_group DROP COLUMN role_name;
@@ -0,0 +1,18 @@
+-- +migrate Up
+ALTER TABLE workflow_node_context ADD COLUMN approval TEXT;
+
+CREATE TABLE IF NOT EXISTS "workflow_node_run_approval" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_node_run_id BIGINT,
+    user_id BIGINT,
+    username TEXT,
+    approved BOOLEAN,
+    comment TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_APPROVAL_NODE_RUN', 'workflow_node_run_approval', 'workflow_node_run', 'workflow_node_run_id', 'id');
+
+-- +migrate Down
+DROP TABLE workflow_node_run_approval;
+ALTER TABLE workflow_node_context DROP COLUMN approval;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "access_token" (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT,
+    hash TEXT,
+    user_id BIGINT,
+    group_id BIGINT,
+    scopes TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    expire_at TIMESTAMP WITH TIME ZONE,
+    last_used TIMESTAMP WITH TIME ZONE,
+    revoked BOOLEAN DEFAULT false
+);
+
+SELECT create_unique_index('access_token', 'IDX_ACCESS_TOKEN_HASH', 'hash');
+SELECT create_foreign_key_idx_cascade('FK_ACCESS_TOKEN_USER', 'access_token', 'user', 'user_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ACCESS_TOKEN_GROUP', 'access_token', 'group', 'group_id', 'id');
+
+-- +migrate Down
+DROP TABLE access_token;
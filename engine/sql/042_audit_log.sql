@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "audit_log" (
+    id BIGSERIAL PRIMARY KEY,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    user_id BIGINT,
+    username TEXT,
+    remote_ip TEXT,
+    method TEXT,
+    route TEXT,
+    url TEXT,
+    status INT,
+    data_before TEXT,
+    data_after TEXT,
+    diff TEXT
+);
+
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_CREATED', 'created');
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_USERNAME', 'username');
+
+-- +migrate Down
+DROP TABLE audit_log;
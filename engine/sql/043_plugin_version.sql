@@ -0,0 +1,38 @@
+-- +migrate Up
+ALTER TABLE plugin ADD COLUMN current_version TEXT;
+
+CREATE TABLE IF NOT EXISTS "plugin_version" (
+    id BIGSERIAL PRIMARY KEY,
+    plugin_id BIGINT NOT NULL,
+    version TEXT NOT NULL,
+    parameters JSONB,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_unique_index('plugin_version', 'IDX_PLUGIN_VERSION_UNIQ', 'plugin_id,version');
+SELECT create_foreign_key_idx_cascade('FK_PLUGIN_VERSION_PLUGIN', 'plugin_version', 'plugin', 'plugin_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "plugin_binary" (
+    id BIGSERIAL PRIMARY KEY,
+    plugin_version_id BIGINT NOT NULL,
+    os TEXT NOT NULL DEFAULT '',
+    arch TEXT NOT NULL DEFAULT '',
+    size BIGINT,
+    perm INT,
+    sha256sum TEXT,
+    object_path TEXT
+);
+
+SELECT create_unique_index('plugin_binary', 'IDX_PLUGIN_BINARY_UNIQ', 'plugin_version_id,os,arch');
+SELECT create_foreign_key_idx_cascade('FK_PLUGIN_BINARY_PLUGIN_VERSION', 'plugin_binary', 'plugin_version', 'plugin_version_id', 'id');
+
+-- Existing plugins become the version 0.0.0, their binary is available for all OS and architectures
+INSERT INTO plugin_version (plugin_id, version) SELECT id, '0.0.0' FROM plugin;
+INSERT INTO plugin_binary (plugin_version_id, size, perm, object_path)
+    SELECT plugin_version.id, plugin.size, plugin.perm, plugin.object_path FROM plugin JOIN plugin_version ON plugin_version.plugin_id = plugin.id;
+UPDATE plugin SET current_version = '0.0.0';
+
+-- +migrate Down
+DROP TABLE plugin_binary;
+DROP TABLE plugin_version;
+ALTER TABLE plugin DROP COLUMN current_version;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE workflow_run ADD COLUMN trace_parent TEXT NOT NULL DEFAULT '';
+ALTER TABLE workflow_node_run_job ADD COLUMN trace_parent TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE workflow_run DROP COLUMN trace_parent;
+ALTER TABLE workflow_node_run_job DROP COLUMN trace_parent;
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_coverage" (
+    workflow_node_run_id BIGINT PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    node_name TEXT NOT NULL,
+    num BIGINT NOT NULL,
+    branch TEXT NOT NULL DEFAULT '',
+    format TEXT,
+    total_lines INT NOT NULL DEFAULT 0,
+    covered_lines INT NOT NULL DEFAULT 0,
+    total_branches INT NOT NULL DEFAULT 0,
+    covered_branches INT NOT NULL DEFAULT 0,
+    files TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_COVERAGE_NODE_RUN', 'workflow_node_run_coverage', 'workflow_node_run', 'workflow_node_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_COVERAGE_WORKFLOW', 'workflow_node_run_coverage', 'workflow', 'workflow_id', 'id');
+CREATE INDEX idx_workflow_node_run_coverage_branch ON workflow_node_run_coverage(workflow_id, node_name, branch, num);
+
+-- +migrate Down
+DROP TABLE workflow_node_run_coverage;
@@ -0,0 +1,45 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "application_test_history" (
+    id BIGSERIAL PRIMARY KEY,
+    application_id BIGINT NOT NULL,
+    branch TEXT NOT NULL DEFAULT '',
+    hash TEXT NOT NULL DEFAULT '',
+    num BIGINT NOT NULL DEFAULT 0,
+    test_suite TEXT NOT NULL,
+    test_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_TEST_HISTORY', 'application_test_history', 'application', 'application_id', 'id');
+SELECT create_index('application_test_history', 'IDX_APPLICATION_TEST_HISTORY_TEST', 'application_id, branch, test_suite, test_name');
+SELECT create_index('application_test_history', 'IDX_APPLICATION_TEST_HISTORY_CREATED', 'created');
+
+CREATE TABLE IF NOT EXISTS "application_flaky_test" (
+    application_id BIGINT NOT NULL,
+    branch TEXT NOT NULL DEFAULT '',
+    test_suite TEXT NOT NULL,
+    test_name TEXT NOT NULL,
+    flips BIGINT NOT NULL DEFAULT 0,
+    last_hash TEXT NOT NULL DEFAULT '',
+    last_flip TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    PRIMARY KEY (application_id, branch, test_suite, test_name)
+);
+
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_FLAKY_TEST', 'application_flaky_test', 'application', 'application_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "application_test_quarantine" (
+    application_id BIGINT NOT NULL,
+    test_suite TEXT NOT NULL,
+    test_name TEXT NOT NULL,
+    author TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    PRIMARY KEY (application_id, test_suite, test_name)
+);
+
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_TEST_QUARANTINE', 'application_test_quarantine', 'application', 'application_id', 'id');
+
+-- +migrate Down
+DROP TABLE application_test_quarantine;
+DROP TABLE application_flaky_test;
+DROP TABLE application_test_history;
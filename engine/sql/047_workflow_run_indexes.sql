@@ -0,0 +1,13 @@
+-- +cds online
+-- +cds precheck SELECT to_regclass('workflow_node_run_job') IS NOT NULL
+-- +cds postcheck SELECT COUNT(1) = 3 FROM pg_indexes WHERE indexname IN ('idx_workflow_node_run_workflow_run_id', 'idx_workflow_node_run_status', 'idx_workflow_node_run_job_workflow_node_run_id')
+
+-- +migrate Up
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_workflow_node_run_workflow_run_id ON workflow_node_run(workflow_run_id);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_workflow_node_run_status ON workflow_node_run(status);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_workflow_node_run_job_workflow_node_run_id ON workflow_node_run_job(workflow_node_run_id);
+
+-- +migrate Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_workflow_node_run_job_workflow_node_run_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_workflow_node_run_status;
+DROP INDEX CONCURRENTLY IF EXISTS idx_workflow_node_run_workflow_run_id;
@@ -0,0 +1,8 @@
+-- +migrate Up
+UPDATE workflow_node_run_artifacts SET sha256sum = '' WHERE sha256sum IS NULL;
+ALTER TABLE workflow_node_run_artifacts ALTER COLUMN sha256sum SET DEFAULT '';
+ALTER TABLE workflow_node_run_artifacts ALTER COLUMN sha256sum SET NOT NULL;
+
+-- +migrate Down
+ALTER TABLE workflow_node_run_artifacts ALTER COLUMN sha256sum DROP NOT NULL;
+ALTER TABLE workflow_node_run_artifacts ALTER COLUMN sha256sum DROP DEFAULT;
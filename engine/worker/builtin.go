@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
-	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
 	"github.com/ovh/cds/sdk/log"
 	"github.com/ovh/cds/sdk/plugin"
 )
@@ -19,6 +22,7 @@ func init() {
 	mapBuiltinActions[sdk.ArtifactDownload] = runArtifactDownload
 	mapBuiltinActions[sdk.ScriptAction] = runScriptAction
 	mapBuiltinActions[sdk.JUnitAction] = runParseJunitTestResultAction
+	mapBuiltinActions[sdk.CoverageAction] = runCoverageReport
 	mapBuiltinActions[sdk.GitCloneAction] = runGitClone
 	mapBuiltinActions[sdk.GitTagAction] = runGitTag
 }
@@ -59,17 +63,57 @@ func (w *currentWorker) runBuiltin(ctx context.Context, a *sdk.Action, buildID i
 	return f(w)(ctx, a, buildID, params, sendLog)
 }
 
+//pluginRequirement returns the requirement of the current job on a plugin, a requirement pinning a version is preferred
+func (w *currentWorker) pluginRequirement(name string) sdk.Requirement {
+	reqs := w.currentJob.pbJob.Job.Action.Requirements
+	if w.currentJob.wJob != nil {
+		reqs = w.currentJob.wJob.Job.Action.Requirements
+	}
+	for _, r := range reqs {
+		if r.Type != sdk.PluginRequirement {
+			continue
+		}
+		if n, c := sdk.PluginRequirementVersion(r.Value); n == name && c != "" {
+			return r
+		}
+	}
+	return sdk.Requirement{Name: name, Type: sdk.PluginRequirement, Value: name}
+}
+
 func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, stepOrder int, sendLog LoggerFunc) sdk.Result {
+	//For the moment we consider that plugin name = action name = plugin binary file name
+	pluginName := a.Name
+	//The binary file has been downloaded in the plugins cache during requirement check
+	pluginBinary, err := w.pluginBinary(w.pluginRequirement(pluginName))
+	if err != nil {
+		res := sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Unable to get plugin %s: %s\n", pluginName, err),
+		}
+		sendLog(res.Reason)
+		return res
+	}
+
+	//Plugins implementing the GRPC protocol are preferred, the others use net/rpc
+	grpcClient, err := grpcplugin.Start(ctx, pluginBinary)
+	if err == nil {
+		defer grpcClient.Kill()
+		return w.runGRPCPlugin(ctx, grpcClient, a, buildID, params, stepOrder, sendLog)
+	}
+	if err != grpcplugin.ErrNotGRPCPlugin {
+		res := sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Unable to init plugin %s: %s\n", pluginName, err),
+		}
+		sendLog(res.Reason)
+		return res
+	}
+
 	chanRes := make(chan sdk.Result)
 
 	go func(buildID int64, params []sdk.Parameter) {
 		res := sdk.Result{Status: sdk.StatusFail.String()}
 
-		//For the moment we consider that plugin name = action name = plugin binary file name
-		pluginName := a.Name
-		//The binary file has been downloaded during requirement check in /tmp
-		pluginBinary := path.Join(w.basedir, a.Name)
-
 		var tlsskipverify bool
 		if os.Getenv("CDS_SKIP_VERIFY") != "" {
 			tlsskipverify = true
@@ -91,24 +135,12 @@ func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID in
 		}
 
 		//Manage all parameters
+		args, secrets := w.pluginArguments(a, params)
 		pluginSecrets := plugin.Secrets{
-			Data: map[string]string{},
+			Data: secrets,
 		}
-
 		pluginArgs := plugin.Arguments{
-			Data: map[string]string{},
-		}
-		for _, p := range a.Parameters {
-			pluginArgs.Data[p.Name] = p.Value
-		}
-		for _, p := range params {
-			pluginArgs.Data[p.Name] = p.Value
-			if sdk.NeedPlaceholder(p.Type) {
-				pluginSecrets.Data[p.Name] = p.Value
-			}
-		}
-		for _, v := range w.currentJob.buildVariables {
-			pluginArgs.Data[v.Name] = v.Value
+			Data: args,
 		}
 
 		//Call the Run function on the plugin interface
@@ -123,6 +155,7 @@ func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID in
 			OrderStep:          stepOrder,
 			Args:               pluginArgs,
 			Secrts:             pluginSecrets,
+			WorkerHTTPPort:     w.exportPort,
 		}
 
 		pluginResult := _plugin.Run(pluginAction)
@@ -148,3 +181,95 @@ func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID in
 		}
 	}
 }
+
+//pluginArguments returns the arguments and the secrets of a plugin: the action parameters, the job parameters and the build variables
+func (w *currentWorker) pluginArguments(a *sdk.Action, params []sdk.Parameter) (map[string]string, map[string]string) {
+	args := map[string]string{}
+	secrets := map[string]string{}
+	for _, p := range a.Parameters {
+		args[p.Name] = p.Value
+	}
+	for _, p := range params {
+		args[p.Name] = p.Value
+		if sdk.NeedPlaceholder(p.Type) {
+			secrets[p.Name] = p.Value
+		}
+	}
+	for _, v := range w.currentJob.buildVariables {
+		args[v.Name] = v.Value
+	}
+	return args, secrets
+}
+
+//runGRPCPlugin runs a plugin implementing the GRPC protocol. Logs, output variables and artifacts are handled while the plugin runs,
+//the run is canceled on the plugin side when the job is canceled
+func (w *currentWorker) runGRPCPlugin(ctx context.Context, client *grpcplugin.Client, a *sdk.Action, buildID int64, params *[]sdk.Parameter, stepOrder int, sendLog LoggerFunc) sdk.Result {
+	args, secrets := w.pluginArguments(a, *params)
+	q := &grpcplugin.ActionQuery{
+		Options:   args,
+		Secrets:   secrets,
+		JobId:     buildID,
+		StepOrder: int64(stepOrder),
+	}
+
+	res, err := client.Exec(ctx, q, func(e *grpcplugin.ActionEvent) error {
+		switch {
+		case e.Log != "":
+			sendLog(e.Log)
+		case e.Variable != nil:
+			v := sdk.Variable{
+				Name:  "cds.build." + e.Variable.Name,
+				Type:  sdk.StringVariable,
+				Value: e.Variable.Value,
+			}
+			if _, err := w.addVariableInPipelineBuild(v, params); err != nil {
+				return err
+			}
+		case e.Artifact != "":
+			sendLog(fmt.Sprintf("Uploading '%s'\n", filepath.Base(e.Artifact)))
+			if err := w.uploadPluginArtifact(buildID, *params, e.Artifact); err != nil {
+				return fmt.Errorf("unable to upload artifact %s: %s", e.Artifact, err)
+			}
+		}
+		return nil
+	})
+
+	if ctx.Err() != nil {
+		log.Error("CDS Worker execution canceled: %v", ctx.Err())
+		w.sendLog(buildID, "CDS Worker execution canceled\n", stepOrder, false)
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: "CDS Worker execution canceled",
+		}
+	}
+	if err != nil {
+		result := sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error while running plugin %s: %s\n", a.Name, err),
+		}
+		sendLog(result.Reason)
+		return result
+	}
+	if !res.Success {
+		if res.Reason != "" {
+			sendLog(res.Reason)
+		}
+		return sdk.Result{Status: sdk.StatusFail.String(), Reason: res.Reason}
+	}
+	return sdk.Result{Status: sdk.StatusSuccess.String()}
+}
+
+//uploadPluginArtifact uploads a file as an artifact of the current job, tagged with the version of the build
+func (w *currentWorker) uploadPluginArtifact(buildID int64, params []sdk.Parameter, filePath string) error {
+	tag := url.QueryEscape(strings.Replace(sdk.ParameterValue(params, "cds.version"), "/", "-", -1))
+	if w.currentJob.wJob != nil {
+		return w.client.QueueArtifactUpload(buildID, tag, filePath)
+	}
+
+	buildNumber, err := strconv.Atoi(sdk.ParameterValue(params, "cds.buildNumber"))
+	if err != nil {
+		return fmt.Errorf("BuilNumber is not an integer %s", err)
+	}
+	return sdk.UploadArtifact(sdk.ParameterValue(params, "cds.project"), sdk.ParameterValue(params, "cds.pipeline"),
+		sdk.ParameterValue(params, "cds.application"), tag, filePath, buildNumber, sdk.ParameterValue(params, "cds.environment"))
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -229,7 +232,8 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				return res
 			}
-			if err := w.client.WorkflowNodeRunArtifactDownload(project, workflow, a.ID, f); err != nil {
+			hash := sha256.New()
+			if err := w.client.WorkflowNodeRunArtifactDownload(project, workflow, a.ID, io.MultiWriter(f, hash)); err != nil {
 				res.Status = sdk.StatusFail.String()
 				res.Reason = err.Error()
 				log.Warning("Cannot download artifacts: %s", err)
@@ -243,6 +247,17 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				return res
 			}
+
+			//Artifacts uploaded before the computation of SHA-256 digests can't be checked
+			if a.SHA256sum != "" {
+				if sum := hex.EncodeToString(hash.Sum(nil)); sum != a.SHA256sum {
+					os.Remove(a.Name)
+					res.Status = sdk.StatusFail.String()
+					res.Reason = fmt.Sprintf("Integrity check failed for artifact %s: sha256 is %s, expected %s", a.Name, sum, a.SHA256sum)
+					sendLog(res.Reason)
+					return res
+				}
+			}
 		}
 
 		return res
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runCoverageReport(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusFail.String()}
+
+		p := sdk.ParameterValue(a.Parameters, "path")
+		if p == "" {
+			res.Reason = "Coverage parser: path not provided"
+			sendLog(res.Reason)
+			return res
+		}
+		format := sdk.ParameterValue(a.Parameters, "format")
+
+		files, errg := filepath.Glob(p)
+		if errg != nil {
+			res.Reason = fmt.Sprintf("Coverage parser: Cannot find requested files, invalid pattern")
+			sendLog(res.Reason)
+			return res
+		}
+		if len(files) == 0 {
+			res.Reason = fmt.Sprintf("Coverage parser: no file matches %s", p)
+			sendLog(res.Reason)
+			return res
+		}
+		sendLog(fmt.Sprintf("%d file(s) to analyze", len(files)))
+
+		var report sdk.CoverageReport
+		for _, f := range files {
+			data, errRead := ioutil.ReadFile(f)
+			if errRead != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: cannot read file %s (%s)", f, errRead)
+				sendLog(res.Reason)
+				return res
+			}
+
+			r, err := sdk.ParseCoverageReport(format, data)
+			if err != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: cannot parse file %s (%s)", f, err)
+				sendLog(res.Reason)
+				return res
+			}
+			sendLog(fmt.Sprintf("Coverage parser: %s (%s): %.2f%% of %d lines, %.2f%% of %d branches", f, r.Format, r.Total.Lines(), r.Total.TotalLines, r.Total.Branches(), r.Total.TotalBranches))
+			report.Merge(r)
+		}
+
+		sendLog(fmt.Sprintf("Coverage parser: total %.2f%% of %d lines, %.2f%% of %d branches", report.Total.Lines(), report.Total.TotalLines, report.Total.Branches(), report.Total.TotalBranches))
+
+		if w.currentJob.wJob == nil {
+			sendLog("Coverage parser: the coverage is only recorded for workflows")
+			res.Status = sdk.StatusSuccess.String()
+			return res
+		}
+
+		cov, err := w.client.QueueJobSendCoverage(buildID, report)
+		if err != nil {
+			res.Reason = fmt.Sprintf("Coverage parser: failed to send coverage: %s", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		if cov.Delta != nil {
+			sendLog(fmt.Sprintf("Coverage parser: %+.2f%% of lines since run #%d on branch %s", cov.Delta.Lines, cov.Delta.Num, cov.Delta.Branch))
+		}
+		if cov.BaseDelta != nil {
+			sendLog(fmt.Sprintf("Coverage parser: %+.2f%% of lines compared to run #%d on branch %s", cov.BaseDelta.Lines, cov.BaseDelta.Num, cov.BaseDelta.Branch))
+		}
+
+		res.Status = sdk.StatusSuccess.String()
+		return res
+	}
+}
@@ -108,6 +108,21 @@ func runGitClone(w *currentWorker) BuiltInAction {
 			clone.Depth = 1
 		}
 
+		//Builds triggered by a pull request checkout its ref on the base repository, which may not contain the head branch
+		prRef := sdk.ParameterFind(*params, "git.pr.ref")
+		if prRef != nil && prRef.Value != "" && isApplicationRepository(*params, url.Value) {
+			sendLog(fmt.Sprintf("Checking out pull request ref %s", prRef.Value))
+			clone.Branch = ""
+			clone.SingleBranch = false
+			clone.CheckoutCommit = ""
+			clone.CheckoutRef = prRef.Value
+			//Without a merge ref on the repositories manager, the base branch is merged on the worker
+			if base := sdk.ParameterValue(*params, "git.pr.merge_base"); base != "" {
+				sendLog(fmt.Sprintf("Merging base branch %s", base))
+				clone.MergeBranch = base
+			}
+		}
+
 		var dir string
 		if directory != nil {
 			dir = directory.Value
@@ -227,3 +242,13 @@ func runGitClone(w *currentWorker) BuiltInAction {
 		return sdk.Result{Status: sdk.StatusSuccess.String()}
 	}
 }
+
+//isApplicationRepository returns true if url is the git url of the application repository
+func isApplicationRepository(params []sdk.Parameter, url string) bool {
+	for _, name := range []string{"git.url", "git.http_url"} {
+		if v := sdk.ParameterValue(params, name); v != "" && v == url {
+			return true
+		}
+	}
+	return false
+}
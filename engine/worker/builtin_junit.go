@@ -13,7 +13,7 @@ import (
 	"github.com/ovh/venom"
 )
 
-func runParseJunitTestResultAction(*currentWorker) BuiltInAction {
+func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
 		var res sdk.Result
 		res.Status = sdk.StatusFail.String()
@@ -69,31 +69,78 @@ func runParseJunitTestResultAction(*currentWorker) BuiltInAction {
 			sendLog(r)
 		}
 
-		data, err := json.Marshal(tests)
-		if err != nil {
-			res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
-			res.Status = sdk.StatusFail.String()
-			sendLog(res.Reason)
-			return res
-		}
+		var quarantine []sdk.TestQuarantine
+		if w.currentJob.wJob != nil {
+			var err error
+			quarantine, err = w.client.QueueJobSendTestsResults(buildID, tests)
+			if err != nil {
+				res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
+				res.Status = sdk.StatusFail.String()
+				sendLog(res.Reason)
+				return res
+			}
+		} else {
+			data, err := json.Marshal(tests)
+			if err != nil {
+				res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
+				res.Status = sdk.StatusFail.String()
+				sendLog(res.Reason)
+				return res
+			}
 
-		uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%s/test?envName=%s", proj, app, pip, bnS, url.QueryEscape(envName))
-		_, code, err := sdk.Request("POST", uri, data)
-		if err == nil && code > 300 {
-			err = fmt.Errorf("HTTP %d", code)
+			uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%s/test?envName=%s", proj, app, pip, bnS, url.QueryEscape(envName))
+			body, code, err := sdk.Request("POST", uri, data)
+			if err == nil && code > 300 {
+				err = fmt.Errorf("HTTP %d", code)
+			}
+
+			if err != nil {
+				res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
+				res.Status = sdk.StatusFail.String()
+				sendLog(res.Reason)
+				return res
+			}
+
+			if len(body) > 0 {
+				if err := json.Unmarshal(body, &quarantine); err != nil {
+					sendLog(fmt.Sprintf("JUnit parse: unable to read the quarantined tests: %s", err))
+				}
+			}
 		}
 
-		if err != nil {
-			res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
-			res.Status = sdk.StatusFail.String()
-			sendLog(res.Reason)
-			return res
+		for _, r := range applyQuarantine(&res, tests, quarantine) {
+			sendLog(r)
 		}
 
 		return res
 	}
 }
 
+// applyQuarantine ignores the failures of the quarantined tests: if all the failed tests
+// are quarantined, the result is successful. It returns a list of log to send to API
+func applyQuarantine(res *sdk.Result, v venom.Tests, quarantine []sdk.TestQuarantine) []string {
+	reasons := []string{}
+	var nbKO, nbQuarantined int
+	for _, ts := range v.TestSuites {
+		for _, tc := range ts.TestCases {
+			if sdk.TestCaseStatus(tc) != sdk.TestCaseStatusFail {
+				continue
+			}
+			nbKO++
+			if sdk.IsTestQuarantined(quarantine, ts.Name, tc.Name) {
+				nbQuarantined++
+				reasons = append(reasons, fmt.Sprintf("JUnit parser: testcase %s of testsuite %s is quarantined, its failure is ignored", tc.Name, ts.Name))
+			}
+		}
+	}
+
+	if res.Status == sdk.StatusFail.String() && nbKO > 0 && nbKO == nbQuarantined {
+		reasons = append(reasons, fmt.Sprintf("JUnit parser: the %d failed test(s) are quarantined", nbQuarantined))
+		res.Status = sdk.StatusSuccess.String()
+	}
+	return reasons
+}
+
 // computeStats computes failures / errors on testSuites,
 // set result.Status and return a list of log to send to API
 func computeStats(res *sdk.Result, v *venom.Tests) []string {
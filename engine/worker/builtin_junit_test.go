@@ -216,3 +216,34 @@ func Test_computeStats(t *testing.T) {
 		})
 	}
 }
+
+func Test_applyQuarantine(t *testing.T) {
+	v := venom.Tests{
+		TestSuites: []venom.TestSuite{
+			{
+				Name: "api",
+				TestCases: []venom.TestCase{
+					{Name: "TestOK"},
+					{Name: "TestFlaky", Failures: []venom.Failure{{Value: "Foo"}}},
+					{Name: "TestKO", Errors: []venom.Failure{{Value: "Foo"}}},
+				},
+			},
+		},
+	}
+	quarantine := []sdk.TestQuarantine{{TestSuite: "api", TestName: "TestFlaky"}}
+
+	res := sdk.Result{Status: sdk.StatusFail.String()}
+	applyQuarantine(&res, v, quarantine)
+	if res.Status != sdk.StatusFail.String() {
+		t.Errorf("status = %v, want %v", res.Status, sdk.StatusFail)
+	}
+
+	quarantine = append(quarantine, sdk.TestQuarantine{TestSuite: "api", TestName: "TestKO"})
+	res = sdk.Result{Status: sdk.StatusFail.String()}
+	if got := applyQuarantine(&res, v, quarantine); len(got) != 3 {
+		t.Errorf("applyQuarantine() = %v, want 3 logs", got)
+	}
+	if res.Status != sdk.StatusSuccess.String() {
+		t.Errorf("status = %v, want %v", res.Status, sdk.StatusSuccess)
+	}
+}
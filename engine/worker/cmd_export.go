@@ -106,6 +106,18 @@ func (wk *currentWorker) addVariableInPipelineBuild(v sdk.Variable, params *[]sd
 		log.Error("addBuildVarHandler> Cannot Marshal err: %s", errm)
 		return http.StatusBadRequest, fmt.Errorf("addBuildVarHandler> Cannot Marshal err: %s", errm)
 	}
+	// For workflow jobs, the variable is added to the build parameters of the node run, they are
+	// used by the next stages and by the conditions of the triggers
+	if wk.currentJob.wJob != nil {
+		wv := v
+		wv.Name = strings.TrimPrefix(wv.Name, "cds.build.")
+		if err := wk.client.QueueJobSendVariable(wk.currentJob.wJob.ID, wv); err != nil {
+			log.Error("addBuildVarHandler> Cannot export variable: %s", err)
+			return http.StatusServiceUnavailable, fmt.Errorf("addBuildVarHandler> Cannot export variable: %s", err)
+		}
+		return http.StatusOK, nil
+	}
+
 	// Retrieve build info
 	var proj, app, pip, bnS, env string
 	for _, p := range wk.currentJob.pbJob.Parameters {
@@ -15,6 +15,8 @@ import (
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/metrics"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 func cmdMain(w *currentWorker) *cobra.Command {
@@ -70,6 +72,18 @@ func cmdMain(w *currentWorker) *cobra.Command {
 	flags.Bool("grpc-insecure", false, "Disable GRPC TLS encryption")
 	viper.BindPFlag("grpc_insecure", flags.Lookup("grpc-insecure"))
 
+	flags.String("metrics-addr", "", "Expose Prometheus metrics on http://<metrics-addr>/mon/metrics. Ex: --metrics-addr=:9111")
+	viper.BindPFlag("metrics_addr", flags.Lookup("metrics-addr"))
+
+	flags.String("tracing-exporter", "", "Export the traces of the jobs: stdout, file or otlp. Disabled if empty")
+	viper.BindPFlag("tracing_exporter", flags.Lookup("tracing-exporter"))
+
+	flags.String("tracing-file", "", "File the traces are written to with the file exporter")
+	viper.BindPFlag("tracing_file", flags.Lookup("tracing-file"))
+
+	flags.String("tracing-endpoint", "", "OpenTelemetry collector with the otlp exporter. Ex: --tracing-endpoint=http://localhost:4318")
+	viper.BindPFlag("tracing_endpoint", flags.Lookup("tracing-endpoint"))
+
 	flags.String("graylog-protocol", "", "Ex: --graylog-protocol=xxxx-yyyy")
 	viper.BindPFlag("graylog_protocol", flags.Lookup("graylog-protocol"))
 
@@ -98,6 +112,16 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 		initViper(w)
 		log.Info("What a good time to be alive, I'm in version %s", sdk.VERSION)
 		w.initServer(ctx)
+		metrics.Serve(viper.GetString("metrics_addr"))
+		if err := tracing.Init(tracing.Config{
+			Exporter:    viper.GetString("tracing_exporter"),
+			File:        viper.GetString("tracing_file"),
+			Endpoint:    viper.GetString("tracing_endpoint"),
+			ServiceName: "cds-worker",
+		}); err != nil {
+			log.Error("Unable to initialize tracing: %s", err)
+		}
+		defer tracing.Close()
 
 		// Gracefully shutdown connections
 		c := make(chan os.Signal, 1)
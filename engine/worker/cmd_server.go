@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/metrics"
 )
 
 // WorkerServerPort is name of environment variable set to local worker HTTP server port
@@ -35,6 +36,7 @@ func (w *currentWorker) serve(c context.Context) (int, error) {
 	r.HandleFunc("/var", w.addBuildVarHandler)
 	r.HandleFunc("/upload", w.uploadHandler)
 	r.HandleFunc("/tmpl", w.tmplHandler)
+	r.Handle("/mon/metrics", metrics.DefaultRegistry)
 
 	srv := &http.Server{
 		Handler:      r,
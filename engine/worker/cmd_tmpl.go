@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -20,14 +21,21 @@ func cmdTmpl(w *currentWorker) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "tmpl",
 		Short: "worker tmpl <input file> <output file>",
-		Run:   tmplCmd(w),
+		Long: `Interpolate the CDS variables of the input file, see https://ovh.github.io/cds/building-pipelines/building-pipelines.variables/
+
+The expressions referencing unknown variables are kept as is, unless --strict is set.`,
+		Run: tmplCmd(w),
 	}
+	c.Flags().BoolVar(&cmdTmplStrict, "strict", false, "Fail if the input file references an unknown variable")
 	return c
 }
 
+var cmdTmplStrict bool
+
 type tmplPath struct {
 	Path        string `json:"path"`
 	Destination string `json:"destination"`
+	Strict      bool   `json:"strict"`
 }
 
 func tmplCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
@@ -46,7 +54,7 @@ func tmplCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Wrong usage: Example : worker tmpl filea fileb")
 		}
 
-		a := tmplPath{args[0], args[1]}
+		a := tmplPath{args[0], args[1], cmdTmplStrict}
 
 		data, errMarshal := json.Marshal(a)
 		if errMarshal != nil {
@@ -67,7 +75,8 @@ func tmplCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 		}
 
 		if resp.StatusCode >= 300 {
-			sdk.Exit("tmpl failed: %d\n", resp.StatusCode)
+			body, _ := ioutil.ReadAll(resp.Body)
+			sdk.Exit("tmpl failed: %d %s\n", resp.StatusCode, body)
 		}
 	}
 }
@@ -97,12 +106,17 @@ func (wk *currentWorker) tmplHandler(w http.ResponseWriter, r *http.Request) {
 		vars[v.Name] = v.Value
 	}
 
-	res, err := sdk.Interpolate(string(btes), vars)
+	opts := interpolate.Options{}
+	if a.Strict {
+		opts.Missing = interpolate.ErrorOnMissing
+	}
+
+	res, err := interpolate.DoWithOptions(string(btes), vars, opts)
 	if err != nil {
 		log.Error("Unable to interpolate: %v", err)
 		s, _ := sdk.ProcessError(err, "")
-		w.Write([]byte(s))
 		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(s))
 		return
 	}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 func initViper(w *currentWorker) {
@@ -84,11 +85,16 @@ type grpcCreds struct {
 }
 
 // GetRequestMetadata gets the request metadata as a map from a grpcCreds.
-func (c *grpcCreds) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
-	return map[string]string{
+// The trace context of the call is propagated to the API
+func (c *grpcCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	m := map[string]string{
 		"name":  c.Name,
 		"token": c.Token,
-	}, nil
+	}
+	if tp := tracing.TraceParent(ctx); tp != "" {
+		m[tracing.Header] = tp
+	}
+	return m, nil
 }
 
 // RequireTransportSecurity indicates whether the credentials requires transport security.
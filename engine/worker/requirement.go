@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/exec"
 	"path"
+	"runtime"
 	"strconv"
 	"time"
 
 	"github.com/shirou/gopsutil/mem"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
 	"github.com/ovh/cds/sdk/log"
 	"github.com/ovh/cds/sdk/plugin"
 )
@@ -71,16 +76,23 @@ func checkRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 }
 
 func checkPluginRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
-	pluginBinary := path.Join(w.basedir, r.Name)
+	pluginBinary, err := w.pluginBinary(r)
+	if err != nil {
+		return false, err
+	}
 
-	if _, err := os.Stat(pluginBinary); os.IsNotExist(err) {
-		//If the file doesn't exist. Download it.
-		if err := sdk.DownloadPlugin(r.Name, w.basedir); err != nil {
-			return false, err
-		}
-		if err := os.Chmod(pluginBinary, 0700); err != nil {
+	if grpcClient, err := grpcplugin.Start(context.Background(), pluginBinary); err == nil {
+		defer grpcClient.Kill()
+		m, err := grpcClient.GetManifest(context.Background())
+		if err != nil {
+			log.Warning("[WARNING] Error Checkin %s requirement : %s", r.Name, err)
 			return false, err
 		}
+		log.Warning("[NOTICE] Plugin %s successfully started", m.Name)
+		return true, nil
+	} else if err != grpcplugin.ErrNotGRPCPlugin {
+		log.Warning("[WARNING] Error Checkin %s requirement : %s", r.Name, err)
+		return false, err
 	}
 
 	pluginClient := plugin.NewClient(context.Background(), r.Name, pluginBinary, "", "", false)
@@ -96,6 +108,68 @@ func checkPluginRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	return true, nil
 }
 
+//pluginBinary returns the path of the binary of the plugin version matching a requirement.
+//Binaries are cached by version in the worker basedir, a binary is downloaded only if it's not in the cache
+func (w *currentWorker) pluginBinary(r sdk.Requirement) (string, error) {
+	name, constraint := sdk.PluginRequirementVersion(r.Value)
+	if name == "" {
+		name = r.Name
+	}
+
+	bin, err := w.client.PluginResolve(name, constraint, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve plugin %s: %s", r.Value, err)
+	}
+
+	dir := path.Join(w.basedir, "plugins", name, bin.Version)
+	pluginBinary := path.Join(dir, name)
+	if sum, err := sha256File(pluginBinary); err == nil && (bin.SHA256sum == "" || sum == bin.SHA256sum) {
+		return pluginBinary, nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	tmp := pluginBinary + ".download"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0700)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	hash := sha256.New()
+	errD := w.client.PluginDownload(name, bin.Version, runtime.GOOS, runtime.GOARCH, io.MultiWriter(f, hash))
+	if err := f.Close(); err != nil && errD == nil {
+		errD = err
+	}
+	if errD != nil {
+		return "", fmt.Errorf("unable to download plugin %s %s: %s", name, bin.Version, errD)
+	}
+
+	//Binaries uploaded before plugins were versioned can't be checked
+	if sum := hex.EncodeToString(hash.Sum(nil)); bin.SHA256sum != "" && sum != bin.SHA256sum {
+		return "", fmt.Errorf("integrity check failed for plugin %s %s: sha256 is %s, expected %s", name, bin.Version, sum, bin.SHA256sum)
+	}
+	if err := os.Rename(tmp, pluginBinary); err != nil {
+		return "", err
+	}
+	log.Info("pluginBinary> Plugin %s %s downloaded in %s", name, bin.Version, pluginBinary)
+	return pluginBinary, nil
+}
+
+func sha256File(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 func checkHostnameRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	h, err := os.Hostname()
 	if err != nil {
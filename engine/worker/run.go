@@ -14,31 +14,41 @@ import (
 
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/metrics"
+	"github.com/ovh/cds/sdk/tracing"
 	"github.com/ovh/cds/sdk/vcs"
 )
 
-func processJobParameter(params *[]sdk.Parameter, secrets []sdk.Variable) {
-	parameters := *params
+// stepDuration is the duration of the steps of the jobs by action and status
+var stepDuration = metrics.NewHistogramVec("cds_worker_step_duration_seconds", "Duration of the steps of the jobs by action and status", metrics.LongBuckets, "action", "status")
 
-	for i := range parameters {
-		keepReplacing := true
-		for keepReplacing {
-			t := parameters[i].Value
+// interpolateValue interpolates a value, the expressions referencing unknown variables are kept as is.
+// The value is unchanged if it can't be interpolated
+func interpolateValue(value string, vars map[string]string) string {
+	v, err := interpolate.Do(value, vars)
+	if err != nil {
+		log.Warning("interpolateValue> %s", err)
+		return value
+	}
+	return v
+}
 
-			for _, p := range parameters {
-				parameters[i].Value = strings.Replace(parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
+func processJobParameter(params *[]sdk.Parameter, secrets []sdk.Variable) {
+	parameters := *params
 
-			for _, p := range secrets {
-				parameters[i].Value = strings.Replace(parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
+	vars := make(map[string]string, len(parameters)+len(secrets))
+	for _, p := range secrets {
+		vars[p.Name] = p.Value
+	}
+	for _, p := range parameters {
+		vars[p.Name] = p.Value
+	}
 
-			// If parameters wasn't updated, consider it done
-			if parameters[i].Value == t {
-				keepReplacing = false
-			}
-		}
+	vars = interpolate.Resolve(vars)
+	for i := range parameters {
+		parameters[i].Value = interpolateValue(parameters[i].Value, vars)
 	}
 
 	params = &parameters
@@ -54,32 +64,24 @@ func processJobParameter(params *[]sdk.Parameter, secrets []sdk.Variable) {
 func (w *currentWorker) processActionVariables(a *sdk.Action, parent *sdk.Action, jobParameters []sdk.Parameter, secrets []sdk.Variable) error {
 	// replaces placeholder in parameters with ActionBuild variables
 	// replaces placeholder in parameters with Parent params
-	for i := range a.Parameters {
-		keepReplacing := true
-		for keepReplacing {
-			t := a.Parameters[i].Value
-
-			if parent != nil {
-				for _, p := range parent.Parameters {
-					a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-				}
-			}
-
-			for _, p := range jobParameters {
-				a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
-
-			for _, p := range secrets {
-				a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
-
-			// If parameters wasn't updated, consider it done
-			if a.Parameters[i].Value == t {
-				keepReplacing = false
-			}
+	vars := map[string]string{}
+	for _, p := range secrets {
+		vars[p.Name] = p.Value
+	}
+	for _, p := range jobParameters {
+		vars[p.Name] = p.Value
+	}
+	if parent != nil {
+		for _, p := range parent.Parameters {
+			vars[p.Name] = p.Value
 		}
 	}
 
+	vars = interpolate.Resolve(vars)
+	for i := range a.Parameters {
+		a.Parameters[i].Value = interpolateValue(a.Parameters[i].Value, vars)
+	}
+
 	// replaces placeholder in all children recursively
 	for i := range a.Actions {
 		if err := w.processActionVariables(&a.Actions[i], a, jobParameters, secrets); err != nil {
@@ -104,13 +106,16 @@ func (w *currentWorker) startAction(ctx context.Context, a *sdk.Action, buildID
 }
 
 func (w *currentWorker) replaceVariablesPlaceholder(a *sdk.Action, params []sdk.Parameter) {
+	vars := make(map[string]string, len(params)+len(w.currentJob.buildVariables))
+	for _, v := range params {
+		vars[v.Name] = v.Value
+	}
+	for _, v := range w.currentJob.buildVariables {
+		vars[v.Name] = v.Value
+	}
+
 	for i := range a.Parameters {
-		for _, v := range w.currentJob.buildVariables {
-			a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+v.Name+"}}", v.Value, -1)
-		}
-		for _, v := range params {
-			a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+v.Name+"}}", v.Value, -1)
-		}
+		a.Parameters[i].Value = interpolateValue(a.Parameters[i].Value, vars)
 	}
 }
 
@@ -209,7 +214,12 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			}
 			w.sendLog(buildID, fmt.Sprintf("Starting step %s\n", childName), w.currentJob.currentStep, false)
 
-			r = w.startAction(ctx, &child, buildID, params, w.currentJob.currentStep, childName)
+			start := time.Now()
+			stepCtx, span := tracing.Start(ctx, "step "+childName)
+			r = w.startAction(stepCtx, &child, buildID, params, w.currentJob.currentStep, childName)
+			span.SetAttribute("status", r.Status)
+			span.Finish()
+			stepDuration.Observe(time.Since(start).Seconds(), child.Name, r.Status)
 			if r.Status != sdk.StatusSuccess.String() && !child.Optional {
 				criticalStepFailed = true
 			}
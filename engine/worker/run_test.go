@@ -43,6 +43,41 @@ func Test_processJobParameter(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Should use helpers and keep unknown variables",
+			args: args{
+				pbJob: &sdk.PipelineBuildJob{
+					Parameters: []sdk.Parameter{
+						{
+							Name:  "cds.app.xxx",
+							Value: "{{.cds.env.yyy | upper}} {{.cds.build.unknown}}",
+						},
+						{
+							Name:  "cds.env.yyy",
+							Value: "{{.cds.proj.zzz}}",
+						},
+						{
+							Name:  "cds.proj.zzz",
+							Value: "value",
+						},
+					},
+				},
+			},
+			want: []sdk.Parameter{
+				{
+					Name:  "cds.app.xxx",
+					Value: "VALUE {{.cds.build.unknown}}",
+				},
+				{
+					Name:  "cds.env.yyy",
+					Value: "value",
+				},
+				{
+					Name:  "cds.proj.zzz",
+					Value: "value",
+				},
+			},
+		},
 	}
 	for _, tt := range testcases {
 		processJobParameter(&tt.args.pbJob.Parameters, tt.args.secrets)
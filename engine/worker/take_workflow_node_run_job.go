@@ -11,9 +11,19 @@ import (
 	"github.com/ovh/cds/engine/api/grpc"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNodeJobRun) error {
+	ctx, span := tracing.StartFromTraceParent(ctx, "worker.takeWorkflowJob", job.TraceParent)
+	span.SetAttribute("job_id", job.ID)
+	defer span.Finish()
+
+	//The calls to the API are traced in the trace of the job
+	client := w.client
+	w.client = client.WithContext(ctx)
+	defer func() { w.client = client }()
+
 	info, err := w.client.QueueTakeJob(job, w.bookedJobID == job.ID)
 	if err != nil {
 		return sdk.WrapError(err, "takeWorkflowJob> Unable to take workflob node run job")
@@ -68,6 +78,7 @@ func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNod
 	//Run !
 	res := w.processJob(ctx, info)
 	tick.Stop()
+	span.SetAttribute("status", res.Status)
 
 	now, _ := ptypes.TimestampProto(time.Now())
 	res.RemoteTime = now
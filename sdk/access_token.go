@@ -0,0 +1,62 @@
+package sdk
+
+import "time"
+
+//AccessTokenScope restricts the API calls allowed with an access token
+type AccessTokenScope string
+
+// Access token scopes
+const (
+	//AccessTokenScopeRead allows only read requests
+	AccessTokenScopeRead AccessTokenScope = "read"
+	//AccessTokenScopeRun allows read requests and runs of workflows and pipelines
+	AccessTokenScopeRun AccessTokenScope = "run"
+	//AccessTokenScopeAdmin allows everything the owner of the token is allowed to do
+	AccessTokenScopeAdmin AccessTokenScope = "admin"
+)
+
+//AccessTokenScopes are all the valid access token scopes
+var AccessTokenScopes = []AccessTokenScope{AccessTokenScopeRead, AccessTokenScopeRun, AccessTokenScopeAdmin}
+
+//AccessToken is a personal access token of a user, or a service account token of a group when GroupID is set.
+//Only the hash of the token is stored, the token itself is returned once at creation
+type AccessToken struct {
+	ID       int64              `json:"id" cli:"id,key"`
+	Name     string             `json:"name" cli:"name"`
+	UserID   int64              `json:"user_id,omitempty" cli:"-"`
+	GroupID  int64              `json:"group_id,omitempty" cli:"-"`
+	Token    string             `json:"token,omitempty" cli:"-"`
+	Scopes   []AccessTokenScope `json:"scopes" cli:"scopes"`
+	Created  time.Time          `json:"created" cli:"created"`
+	ExpireAt *time.Time         `json:"expire_at,omitempty" cli:"expire_at"`
+	LastUsed *time.Time         `json:"last_used,omitempty" cli:"last_used"`
+	Revoked  bool               `json:"revoked" cli:"revoked"`
+}
+
+//HasScope returns true if the token has the scope
+func (t *AccessToken) HasScope(s AccessTokenScope) bool {
+	for _, ts := range t.Scopes {
+		if ts == s {
+			return true
+		}
+	}
+	return false
+}
+
+//IsValid returns false if the token has been revoked or is expired
+func (t *AccessToken) IsValid() bool {
+	if t.Revoked {
+		return false
+	}
+	return t.ExpireAt == nil || t.ExpireAt.After(time.Now())
+}
+
+//AccessTokenScopeFromString returns a typed scope from a string
+func AccessTokenScopeFromString(s string) (AccessTokenScope, bool) {
+	for _, sc := range AccessTokenScopes {
+		if string(sc) == s {
+			return sc, true
+		}
+	}
+	return "", false
+}
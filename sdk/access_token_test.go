@@ -0,0 +1,30 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessTokenIsValid(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	assert.True(t, (&AccessToken{}).IsValid())
+	assert.True(t, (&AccessToken{ExpireAt: &future}).IsValid())
+	assert.False(t, (&AccessToken{ExpireAt: &past}).IsValid())
+	assert.False(t, (&AccessToken{Revoked: true}).IsValid())
+}
+
+func TestAccessTokenScopes(t *testing.T) {
+	tk := AccessToken{Scopes: []AccessTokenScope{AccessTokenScopeRun}}
+	assert.True(t, tk.HasScope(AccessTokenScopeRun))
+	assert.False(t, tk.HasScope(AccessTokenScopeAdmin))
+
+	s, ok := AccessTokenScopeFromString("read")
+	assert.True(t, ok)
+	assert.Equal(t, AccessTokenScopeRead, s)
+	_, ok = AccessTokenScopeFromString("write")
+	assert.False(t, ok)
+}
@@ -39,6 +39,7 @@ type ActionPlugin struct {
 	Author      string `json:"author"`
 	Filename    string `json:"filename"`
 	Path        string `json:"path"`
+	Version     string `json:"version,omitempty"`
 
 	Size       int64  `json:"size,omitempty"`
 	Perm       uint32 `json:"perm,omitempty"`
@@ -68,6 +69,7 @@ const (
 const (
 	ScriptAction   = "Script"
 	JUnitAction    = "JUnit"
+	CoverageAction = "CoverageReport"
 	GitCloneAction = "GitClone"
 	GitTagAction   = "GitTag"
 )
@@ -0,0 +1,114 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+)
+
+// ActionPluginVersion is a released version of an action plugin. A version is immutable, a new version has to be uploaded to change a plugin
+type ActionPluginVersion struct {
+	ID         int64                `json:"id" cli:"-"`
+	PluginName string               `json:"plugin_name" cli:"-"`
+	Version    string               `json:"version" cli:"version"`
+	Current    bool                 `json:"current" cli:"current"`
+	Parameters []Parameter          `json:"parameters,omitempty" cli:"-"`
+	Binaries   []ActionPluginBinary `json:"binaries" cli:"-"`
+	Created    time.Time            `json:"created" cli:"created"`
+}
+
+// ActionPluginBinary is the binary of a version of an action plugin for an OS and an architecture.
+// Binaries uploaded before plugins were versioned have no OS, no architecture and no checksum
+type ActionPluginBinary struct {
+	ID              int64  `json:"id" cli:"-"`
+	PluginVersionID int64  `json:"plugin_version_id" cli:"-"`
+	PluginName      string `json:"plugin_name" cli:"name"`
+	Version         string `json:"version" cli:"version"`
+	OS              string `json:"os" cli:"os"`
+	Arch            string `json:"arch" cli:"arch"`
+	Size            int64  `json:"size" cli:"size"`
+	Perm            uint32 `json:"perm" cli:"-"`
+	SHA256sum       string `json:"sha256sum" cli:"sha256sum"`
+	ObjectPath      string `json:"object_path,omitempty" cli:"-"`
+}
+
+// Binary returns the binary of the version for an OS and an architecture, or the binary available for all platforms
+func (v *ActionPluginVersion) Binary(os, arch string) *ActionPluginBinary {
+	var any *ActionPluginBinary
+	for i := range v.Binaries {
+		b := &v.Binaries[i]
+		if b.OS == os && b.Arch == arch {
+			return b
+		}
+		if b.OS == "" && b.Arch == "" {
+			any = b
+		}
+	}
+	return any
+}
+
+// PluginRequirementVersion splits the value of a plugin requirement in the plugin name and the version constraint:
+// "plugin-download@1.2.3" pins the version 1.2.3, "plugin-download@1" floats on the major 1 and "plugin-download" uses the current version
+func PluginRequirementVersion(value string) (string, string) {
+	i := strings.LastIndex(value, "@")
+	if i < 0 {
+		return value, ""
+	}
+	return value[:i], strings.TrimSpace(value[i+1:])
+}
+
+// PluginVersionRange returns the range of versions matching a constraint: an exact version "1.2.3", a major "1" or "1.x",
+// a minor "1.2" or "1.2.x" or a semver range ">=1.2.0 <2.0.0"
+func PluginVersionRange(constraint string) (semver.Range, error) {
+	c := strings.TrimPrefix(strings.TrimSpace(constraint), "v")
+	if t := strings.Split(strings.TrimSuffix(c, ".x"), "."); len(t) <= 2 {
+		major, errMajor := strconv.ParseUint(t[0], 10, 64)
+		if len(t) == 1 && errMajor == nil {
+			return semver.ParseRange(fmt.Sprintf(">=%d.0.0 <%d.0.0", major, major+1))
+		}
+		if len(t) == 2 {
+			minor, errMinor := strconv.ParseUint(t[1], 10, 64)
+			if errMajor == nil && errMinor == nil {
+				return semver.ParseRange(fmt.Sprintf(">=%d.%d.0 <%d.%d.0", major, minor, major, minor+1))
+			}
+		}
+	}
+
+	if v, err := semver.Parse(c); err == nil {
+		return func(o semver.Version) bool { return o.EQ(v) }, nil
+	}
+	r, err := semver.ParseRange(constraint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin version constraint %s: %s", constraint, err)
+	}
+	return r, nil
+}
+
+// LatestPluginVersion returns the highest version matching a constraint. Pre-releases are only returned when they are pinned
+func LatestPluginVersion(versions []string, constraint string) (string, error) {
+	exact := strings.TrimPrefix(strings.TrimSpace(constraint), "v")
+	r, err := PluginVersionRange(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	var latest *semver.Version
+	var res string
+	for _, s := range versions {
+		v, err := semver.Parse(s)
+		if err != nil || !r(v) || (len(v.Pre) > 0 && s != exact) {
+			continue
+		}
+		if latest == nil || v.GT(*latest) {
+			latest = &v
+			res = s
+		}
+	}
+	if latest == nil {
+		return "", ErrPluginVersionNotFound
+	}
+	return res, nil
+}
@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginRequirementVersion(t *testing.T) {
+	name, constraint := PluginRequirementVersion("plugin-download@1.2")
+	assert.Equal(t, "plugin-download", name)
+	assert.Equal(t, "1.2", constraint)
+
+	name, constraint = PluginRequirementVersion("plugin-download")
+	assert.Equal(t, "plugin-download", name)
+	assert.Equal(t, "", constraint)
+}
+
+func TestLatestPluginVersion(t *testing.T) {
+	versions := []string{"1.0.0", "1.2.0", "1.2.3", "1.10.0", "2.0.0", "2.1.0-beta", "legacy"}
+
+	tests := map[string]string{
+		"1.2.3":          "1.2.3",
+		"v1.2.0":         "1.2.0",
+		"1":              "1.10.0",
+		"1.x":            "1.10.0",
+		"1.2":            "1.2.3",
+		"1.2.x":          "1.2.3",
+		"2":              "2.0.0",
+		">=1.0.0 <1.5.0": "1.2.3",
+	}
+	for constraint, expected := range tests {
+		v, err := LatestPluginVersion(versions, constraint)
+		assert.NoError(t, err, constraint)
+		assert.Equal(t, expected, v, constraint)
+	}
+
+	_, err := LatestPluginVersion(versions, "3")
+	assert.Equal(t, ErrPluginVersionNotFound, err)
+
+	_, err = LatestPluginVersion(versions, "foo")
+	assert.Error(t, err)
+}
@@ -33,6 +33,7 @@ type Artifact struct {
 	Size         int64  `json:"size,omitempty"`
 	Perm         uint32 `json:"perm,omitempty"`
 	MD5sum       string `json:"md5sum,omitempty"`
+	SHA256sum    string `json:"sha256sum,omitempty"`
 	ObjectPath   string `json:"object_path,omitempty"`
 }
 
@@ -49,6 +50,54 @@ func (a *Artifact) GetPath() string {
 	return container
 }
 
+//ArtifactRetention represents the artifact retention rules of a project.
+//Artifacts of the KeepLast last runs of their branch are always kept. The other artifacts are purged once older than
+//MaxAge days, or right away without MaxAge. Zero values disable the rule. Artifacts of runs built from a git tag are
+//always kept if KeepTagged is set.
+type ArtifactRetention struct {
+	ProjectID  int64 `json:"-" db:"project_id"`
+	KeepLast   int   `json:"keep_last" db:"keep_last"`
+	KeepTagged bool  `json:"keep_tagged" db:"keep_tagged"`
+	MaxAge     int   `json:"max_age" db:"max_age"`
+}
+
+//IsEnabled returns true if at least one rule purges artifacts
+func (r ArtifactRetention) IsEnabled() bool {
+	return r.KeepLast > 0 || r.MaxAge > 0
+}
+
+//ArtifactPurgeReport lists the artifacts removed, or which would be removed on dry-run, by the retention rules of a project
+type ArtifactPurgeReport struct {
+	ProjectKey string            `json:"project_key"`
+	DryRun     bool              `json:"dry_run"`
+	Retention  ArtifactRetention `json:"retention"`
+	Artifacts  []PurgedArtifact  `json:"artifacts"`
+	Size       int64             `json:"size"`
+}
+
+//PurgedArtifact is an artifact selected by the retention rules of its project
+type PurgedArtifact struct {
+	ID          int64     `json:"id"`
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	Tag         string    `json:"tag"`
+	Application string    `json:"application,omitempty"`
+	Pipeline    string    `json:"pipeline,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Workflow    string    `json:"workflow,omitempty"`
+	Number      int64     `json:"number"`
+	Branch      string    `json:"branch,omitempty"`
+	Size        int64     `json:"size"`
+	Created     time.Time `json:"created"`
+	Reason      string    `json:"reason"`
+}
+
+//Types of purged artifacts
+const (
+	PipelineArtifactType = "pipeline"
+	WorkflowArtifactType = "workflow"
+)
+
 // Builtin artifact manipulation actions
 const (
 	ArtifactUpload   = "Artifact Upload"
@@ -1,8 +1,37 @@
 package sdk
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Different type of Audit event
 const (
 	AuditAdd    = "add"
 	AuditUpdate = "update"
 	AuditDelete = "delete"
 )
+
+//AuditLog records a call to the API which modified something: who did it, from where, on which resource,
+//and the state of the resource before and after the call
+type AuditLog struct {
+	ID       int64           `json:"id" cli:"id,key"`
+	Created  time.Time       `json:"created" cli:"created"`
+	UserID   int64           `json:"user_id,omitempty" cli:"-"`
+	Username string          `json:"username" cli:"username"`
+	RemoteIP string          `json:"remote_ip" cli:"remote_ip"`
+	Method   string          `json:"method" cli:"method"`
+	Route    string          `json:"route" cli:"-"`
+	URL      string          `json:"url" cli:"url"`
+	Status   int             `json:"status" cli:"status"`
+	Before   json.RawMessage `json:"before,omitempty" cli:"-"`
+	After    json.RawMessage `json:"after,omitempty" cli:"-"`
+	Diff     []AuditDiff     `json:"diff,omitempty" cli:"-"`
+}
+
+//AuditDiff is a field of a resource modified by a call to the API
+type AuditDiff struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
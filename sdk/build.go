@@ -29,6 +29,8 @@ type SpawnInfo struct {
 	Message    SpawnMsg  `json:"message,omitempty" db:"-"`
 	// UserMessage contains msg translated for end user
 	UserMessage string `json:"user_message,omitempty" db:"-"`
+	// TraceParent is the trace context of the component which sent the info
+	TraceParent string `json:"trace_parent,omitempty" db:"-"`
 }
 
 // SpawnMsg represents a msg for spawnInfo
@@ -82,6 +84,8 @@ func StatusFromString(in string) Status {
 		return StatusDisabled
 	case StatusSkipped.String():
 		return StatusSkipped
+	case StatusWaitingApproval.String():
+		return StatusWaitingApproval
 	default:
 		return StatusUnknown
 	}
@@ -93,15 +97,16 @@ func (t Status) String() string {
 
 // Action status in queue
 const (
-	StatusWaiting    Status = "Waiting"
-	StatusChecking   Status = "Checking"
-	StatusBuilding   Status = "Building"
-	StatusSuccess    Status = "Success"
-	StatusFail       Status = "Fail"
-	StatusDisabled   Status = "Disabled"
-	StatusNeverBuilt Status = "Never Built"
-	StatusUnknown    Status = "Unknown"
-	StatusSkipped    Status = "Skipped"
+	StatusWaiting         Status = "Waiting"
+	StatusWaitingApproval Status = "Waiting approval"
+	StatusChecking        Status = "Checking"
+	StatusBuilding        Status = "Building"
+	StatusSuccess         Status = "Success"
+	StatusFail            Status = "Fail"
+	StatusDisabled        Status = "Disabled"
+	StatusNeverBuilt      Status = "Never Built"
+	StatusUnknown         Status = "Unknown"
+	StatusSkipped         Status = "Skipped"
 )
 
 // Translate translates messages in pipelineBuildJob
@@ -1,6 +1,7 @@
 package cdsclient
 
 import (
+	"context"
 	"crypto/tls"
 	"io"
 	"net/http"
@@ -16,6 +17,7 @@ type client struct {
 	isHatchery bool
 	HTTPClient HTTPClient
 	config     Config
+	ctx        context.Context
 }
 
 // New returns a client from a config struct
@@ -90,6 +92,20 @@ func (c *client) init() {
 	}
 }
 
+// WithContext returns a copy of the client whose requests carry the trace context of ctx
+func (c *client) WithContext(ctx context.Context) Interface {
+	cli := *c
+	cli.ctx = ctx
+	return &cli
+}
+
+func (c *client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
 func (c *client) APIURL() string {
 	return c.config.Host
 }
@@ -0,0 +1,67 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) UserAccessTokenList(username string) ([]sdk.AccessToken, error) {
+	return c.accessTokenList("/user/" + url.QueryEscape(username) + "/accesstoken")
+}
+
+func (c *client) UserAccessTokenCreate(username string, t *sdk.AccessToken) error {
+	return c.accessTokenCreate("/user/"+url.QueryEscape(username)+"/accesstoken", t)
+}
+
+func (c *client) UserAccessTokenRevoke(username string, id int64) error {
+	return c.accessTokenRevoke(fmt.Sprintf("/user/%s/accesstoken/%d", url.QueryEscape(username), id))
+}
+
+func (c *client) GroupAccessTokenList(groupName string) ([]sdk.AccessToken, error) {
+	return c.accessTokenList("/group/" + url.QueryEscape(groupName) + "/accesstoken")
+}
+
+func (c *client) GroupAccessTokenCreate(groupName string, t *sdk.AccessToken) error {
+	return c.accessTokenCreate("/group/"+url.QueryEscape(groupName)+"/accesstoken", t)
+}
+
+func (c *client) GroupAccessTokenRevoke(groupName string, id int64) error {
+	return c.accessTokenRevoke(fmt.Sprintf("/group/%s/accesstoken/%d", url.QueryEscape(groupName), id))
+}
+
+func (c *client) accessTokenList(path string) ([]sdk.AccessToken, error) {
+	tokens := []sdk.AccessToken{}
+	code, err := c.GetJSON(path, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return tokens, nil
+}
+
+func (c *client) accessTokenCreate(path string, t *sdk.AccessToken) error {
+	code, err := c.PostJSON(path, t, t)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusCreated {
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+	return nil
+}
+
+func (c *client) accessTokenRevoke(path string) error {
+	_, code, err := c.Request("DELETE", path, nil)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusOK {
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ApplicationFlakyTestsList(projectKey string, appName string, branch string) ([]sdk.FlakyTest, error) {
+	fs := []sdk.FlakyTest{}
+	code, err := c.GetJSON("/project/"+projectKey+"/application/"+appName+"/tests/flaky?branch="+url.QueryEscape(branch), &fs)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (c *client) ApplicationTestQuarantineList(projectKey string, appName string) ([]sdk.TestQuarantine, error) {
+	qs := []sdk.TestQuarantine{}
+	code, err := c.GetJSON("/project/"+projectKey+"/application/"+appName+"/tests/quarantine", &qs)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (c *client) ApplicationTestQuarantineAdd(projectKey string, appName string, q *sdk.TestQuarantine) error {
+	code, err := c.PostJSON("/project/"+projectKey+"/application/"+appName+"/tests/quarantine", q, q)
+	if code != 201 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
+
+func (c *client) ApplicationTestQuarantineDelete(projectKey string, appName string, testSuite string, testName string) error {
+	q := url.Values{}
+	q.Set("suite", testSuite)
+	q.Set("name", testName)
+	_, code, err := c.Request("DELETE", "/project/"+projectKey+"/application/"+appName+"/tests/quarantine?"+q.Encode(), nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package cdsclient
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) PluginVersions(name string) ([]sdk.ActionPluginVersion, error) {
+	versions := []sdk.ActionPluginVersion{}
+	if _, err := c.GetJSON("/plugin/"+url.QueryEscape(name)+"/version", &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (c *client) PluginSetCurrentVersion(name, version string) error {
+	v := sdk.ActionPluginVersion{Version: version}
+	_, err := c.PutJSON("/plugin/"+url.QueryEscape(name)+"/current", v, nil)
+	return err
+}
+
+func (c *client) PluginResolve(name, constraint, os, arch string) (*sdk.ActionPluginBinary, error) {
+	q := url.Values{}
+	q.Set("version", constraint)
+	q.Set("os", os)
+	q.Set("arch", arch)
+	bin := sdk.ActionPluginBinary{}
+	if _, err := c.GetJSON("/plugin/"+url.QueryEscape(name)+"/resolve?"+q.Encode(), &bin); err != nil {
+		return nil, err
+	}
+	return &bin, nil
+}
+
+func (c *client) PluginDownload(name, version, os, arch string, w io.Writer) error {
+	q := url.Values{}
+	q.Set("version", version)
+	q.Set("os", os)
+	q.Set("arch", arch)
+	reader, code, err := c.Stream("GET", "/plugin/download/"+url.QueryEscape(name)+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	if _, err := io.Copy(w, reader); err != nil {
+		return err
+	}
+	return nil
+}
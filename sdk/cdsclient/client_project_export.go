@@ -0,0 +1,48 @@
+package cdsclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ProjectExport(key, passphrase string, w io.Writer) error {
+	reader, code, err := c.Stream("POST", "/project/"+key+"/export", nil, SetHeader(PassphraseHeader, passphrase))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if code >= 300 {
+		body, _ := ioutil.ReadAll(reader)
+		if err := sdk.DecodeError(body); err != nil {
+			return err
+		}
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+
+	if _, err := io.Copy(w, reader); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) ProjectImport(key string, archive []byte, passphrase string, dryRun bool) ([]string, error) {
+	path := fmt.Sprintf("/project/%s/import?dryRun=%t", key, dryRun)
+	body, code, err := c.Request("POST", path, archive, SetHeader(PassphraseHeader, passphrase), SetHeader("Content-Type", "application/gzip"))
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := []string{}
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return msgs, fmt.Errorf("HTTP Code %d", code)
+	}
+	return msgs, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -14,6 +15,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ovh/venom"
+
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 )
@@ -174,6 +177,41 @@ func (c *client) QueueSendResult(id int64, res sdk.Result) error {
 	return nil
 }
 
+func (c *client) QueueJobSendVariable(id int64, v sdk.Variable) error {
+	path := fmt.Sprintf("/queue/workflows/%d/variable", id)
+
+	if code, err := c.PostJSON(path, v, nil); err != nil {
+		return err
+	} else if code != http.StatusOK {
+		return fmt.Errorf("HTTP Error: %d", code)
+	}
+	return nil
+}
+
+func (c *client) QueueJobSendCoverage(id int64, report sdk.CoverageReport) (*sdk.WorkflowNodeRunCoverage, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/coverage", id)
+
+	var cov sdk.WorkflowNodeRunCoverage
+	if code, err := c.PostJSON(path, report, &cov); err != nil {
+		return nil, err
+	} else if code != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Error: %d", code)
+	}
+	return &cov, nil
+}
+
+func (c *client) QueueJobSendTestsResults(id int64, tests venom.Tests) ([]sdk.TestQuarantine, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/test", id)
+
+	quarantine := []sdk.TestQuarantine{}
+	if code, err := c.PostJSON(path, tests, &quarantine); err != nil {
+		return nil, err
+	} else if code != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Error: %d", code)
+	}
+	return quarantine, nil
+}
+
 func (c *client) QueueArtifactUpload(id int64, tag, filePath string) error {
 	fileForMD5, errop := os.Open(filePath)
 	if errop != nil {
@@ -186,13 +224,15 @@ func (c *client) QueueArtifactUpload(id int64, tag, filePath string) error {
 		return errst
 	}
 
-	//Compute md5sum
+	//Compute md5sum and sha256sum
 	hash := md5.New()
-	if _, errcopy := io.Copy(hash, fileForMD5); errcopy != nil {
+	hash256 := sha256.New()
+	if _, errcopy := io.Copy(io.MultiWriter(hash, hash256), fileForMD5); errcopy != nil {
 		return errcopy
 	}
 	hashInBytes := hash.Sum(nil)[:16]
 	md5sumStr := hex.EncodeToString(hashInBytes)
+	sha256sumStr := hex.EncodeToString(hash256.Sum(nil))
 	fileForMD5.Close()
 
 	//Reopen the file because we already read it for md5
@@ -217,6 +257,7 @@ func (c *client) QueueArtifactUpload(id int64, tag, filePath string) error {
 	writer.WriteField("size", strconv.FormatInt(stat.Size(), 10))
 	writer.WriteField("perm", strconv.FormatUint(uint64(stat.Mode().Perm()), 10))
 	writer.WriteField("md5sum", md5sumStr)
+	writer.WriteField("sha256sum", sha256sumStr)
 
 	if errclose := writer.Close(); errclose != nil {
 		return errclose
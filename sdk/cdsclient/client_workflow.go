@@ -74,3 +74,21 @@ func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, name string,
 	}
 	return nil
 }
+
+func (c *client) WorkflowNodeRunProvenance(projectKey string, name string, number int64, nodeRunID int64) (*sdk.ProvenanceAttestation, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/provenance", projectKey, name, number, nodeRunID)
+	att := sdk.ProvenanceAttestation{}
+	if _, err := c.GetJSON(url, &att); err != nil {
+		return nil, err
+	}
+	return &att, nil
+}
+
+func (c *client) WorkflowNodeRunProvenanceVerify(projectKey string, name string, number int64, nodeRunID int64) (*sdk.ProvenanceVerification, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/provenance/verify", projectKey, name, number, nodeRunID)
+	res := sdk.ProvenanceVerification{}
+	if _, err := c.GetJSON(url, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
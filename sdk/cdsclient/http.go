@@ -9,14 +9,21 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 const (
 	//SessionTokenHeader is user as HTTP header
 	SessionTokenHeader = "Session-Token"
+	//AccessTokenHeader is used as HTTP header with personal access tokens and service account tokens
+	AccessTokenHeader = "Access-Token"
+	//PassphraseHeader is used as HTTP header to send the passphrase of project archives
+	PassphraseHeader = "Passphrase"
 	// AuthHeader is used as HTTP header
 	AuthHeader = "X_AUTH_HEADER"
 	// RequestedWithHeader is used as HTTP header
@@ -25,6 +32,13 @@ const (
 	RequestedWithValue = "X-CDS-SDK"
 )
 
+const (
+	// rateLimitMaxRetry is the number of times a request rejected by the rate limits of the API is retried
+	rateLimitMaxRetry = 5
+	// rateLimitMaxWait is the maximum duration to wait before retrying a request rejected by the rate limits
+	rateLimitMaxWait = time.Minute
+)
+
 // RequestModifier is used to modify behavior of Request and Steam functions
 type RequestModifier func(req *http.Request)
 
@@ -125,6 +139,7 @@ func (c *client) Stream(method string, path string, args []byte, mods ...Request
 		log.Printf("Request %s Body : %s", c.config.Host+path, string(args))
 	}
 
+	var rateLimited int
 	for i := 0; i <= c.config.Retry; i++ {
 		var requestError error
 		var req *http.Request
@@ -142,6 +157,7 @@ func (c *client) Stream(method string, path string, args []byte, mods ...Request
 		req.Header.Set("User-Agent", c.config.userAgent)
 		req.Header.Set("Connection", "close")
 		req.Header.Add(RequestedWithHeader, RequestedWithValue)
+		tracing.InjectHTTP(c.context(), req)
 
 		for i := range mods {
 			mods[i](req)
@@ -157,10 +173,32 @@ func (c *client) Stream(method string, path string, args []byte, mods ...Request
 				req.Header.Add(SessionTokenHeader, c.config.Token)
 				req.SetBasicAuth(c.config.User, c.config.Token)
 			}
+			if c.config.AccessToken != "" {
+				req.Header.Set(AccessTokenHeader, c.config.AccessToken)
+			}
 		}
 
 		resp, err := c.HTTPClient.Do(req)
 
+		// if the request is rejected by the rate limits, wait and retry
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests && rateLimited < rateLimitMaxRetry {
+			rateLimited++
+			wait := retryAfter(resp)
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			if c.config.Verbose {
+				log.Printf("Request %s rate limited, retry in %s", c.config.Host+path, wait)
+			}
+			select {
+			case <-c.context().Done():
+				return nil, 0, c.context().Err()
+			case <-time.After(wait):
+			}
+			// the rate limited requests don't count as retries
+			i--
+			continue
+		}
+
 		// if everything is fine, return body
 		if err == nil && resp.StatusCode < 500 {
 			return resp.Body, resp.StatusCode, nil
@@ -207,6 +245,25 @@ func (c *client) Stream(method string, path string, args []byte, mods ...Request
 	return nil, 0, fmt.Errorf("x%d: %s", c.config.Retry, savederror)
 }
 
+// retryAfter returns the duration to wait before retrying a request rejected by the rate limits,
+// from the Retry-After header: a number of seconds or a date
+func retryAfter(resp *http.Response) time.Duration {
+	wait := time.Second
+	h := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(h); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(h); err == nil {
+		wait = time.Until(t)
+	}
+	if wait <= 0 {
+		wait = 100 * time.Millisecond
+	}
+	if wait > rateLimitMaxWait {
+		wait = rateLimitMaxWait
+	}
+	return wait
+}
+
 // UploadMultiPart upload multipart
 func (c *client) UploadMultiPart(method string, path string, body *bytes.Buffer, mods ...RequestModifier) ([]byte, int, error) {
 	var req *http.Request
@@ -219,6 +276,7 @@ func (c *client) UploadMultiPart(method string, path string, body *bytes.Buffer,
 	req.Header.Set("User-Agent", c.config.userAgent)
 	req.Header.Set("Connection", "close")
 	req.Header.Add(RequestedWithHeader, RequestedWithValue)
+	tracing.InjectHTTP(c.context(), req)
 
 	for i := range mods {
 		mods[i](req)
@@ -234,6 +292,9 @@ func (c *client) UploadMultiPart(method string, path string, body *bytes.Buffer,
 			req.Header.Add(SessionTokenHeader, c.config.Token)
 			req.SetBasicAuth(c.config.User, c.config.Token)
 		}
+		if c.config.AccessToken != "" {
+			req.Header.Set(AccessTokenHeader, c.config.AccessToken)
+		}
 	}
 
 	resp, err := c.HTTPClient.Do(req)
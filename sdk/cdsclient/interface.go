@@ -5,12 +5,15 @@ import (
 	"io"
 	"time"
 
+	"github.com/ovh/venom"
+
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 )
 
 // Interface is the main interface for cdsclient package
 type Interface interface {
+	WithContext(ctx context.Context) Interface
 	APIURL() string
 	ApplicationCreate(string, *sdk.Application) error
 	ApplicationDelete(string, string) error
@@ -19,6 +22,10 @@ type Interface interface {
 	ApplicationKeysList(string, string) ([]sdk.ApplicationKey, error)
 	ApplicationKeyCreate(string, string, *sdk.ApplicationKey) error
 	ApplicationKeysDelete(string, string, string) error
+	ApplicationFlakyTestsList(projectKey string, appName string, branch string) ([]sdk.FlakyTest, error)
+	ApplicationTestQuarantineList(projectKey string, appName string) ([]sdk.TestQuarantine, error)
+	ApplicationTestQuarantineAdd(projectKey string, appName string, q *sdk.TestQuarantine) error
+	ApplicationTestQuarantineDelete(projectKey string, appName string, testSuite string, testName string) error
 	ConfigUser() (map[string]string, error)
 	EnvironmentCreate(string, *sdk.Environment) error
 	EnvironmentDelete(string, string) error
@@ -27,13 +34,22 @@ type Interface interface {
 	EnvironmentKeysList(string, string) ([]sdk.EnvironmentKey, error)
 	EnvironmentKeyCreate(string, string, *sdk.EnvironmentKey) error
 	EnvironmentKeysDelete(string, string, string) error
+	GroupAccessTokenList(groupName string) ([]sdk.AccessToken, error)
+	GroupAccessTokenCreate(groupName string, t *sdk.AccessToken) error
+	GroupAccessTokenRevoke(groupName string, id int64) error
 	HatcheryRefresh(int64) error
 	HatcheryRegister(sdk.Hatchery) (*sdk.Hatchery, bool, error)
 	MonStatus() ([]string, error)
+	PluginVersions(name string) ([]sdk.ActionPluginVersion, error)
+	PluginSetCurrentVersion(name, version string) error
+	PluginResolve(name, constraint, os, arch string) (*sdk.ActionPluginBinary, error)
+	PluginDownload(name, version, os, arch string, w io.Writer) error
 	ProjectCreate(*sdk.Project) error
 	ProjectDelete(string) error
 	ProjectGet(string, ...RequestModifier) (*sdk.Project, error)
 	ProjectList() ([]sdk.Project, error)
+	ProjectExport(key, passphrase string, w io.Writer) error
+	ProjectImport(key string, archive []byte, passphrase string, dryRun bool) ([]string, error)
 	ProjectKeysList(string) ([]sdk.ProjectKey, error)
 	ProjectKeyCreate(string, *sdk.ProjectKey) error
 	ProjectKeysDelete(string, string) error
@@ -44,6 +60,9 @@ type Interface interface {
 	QueueJobInfo(id int64) (*sdk.WorkflowNodeJobRun, error)
 	QueueJobSendSpawnInfo(isWorkflowJob bool, id int64, in []sdk.SpawnInfo) error
 	QueueSendResult(int64, sdk.Result) error
+	QueueJobSendVariable(id int64, v sdk.Variable) error
+	QueueJobSendCoverage(id int64, report sdk.CoverageReport) (*sdk.WorkflowNodeRunCoverage, error)
+	QueueJobSendTestsResults(id int64, tests venom.Tests) ([]sdk.TestQuarantine, error)
 	QueueArtifactUpload(id int64, tag, filePath string) error
 	Requirements() ([]sdk.Requirement, error)
 	UserLogin(username, password string) (bool, string, error)
@@ -53,6 +72,9 @@ type Interface interface {
 	UserGetGroups(username string) (map[string][]sdk.Group, error)
 	UserReset(username, email string) error
 	UserConfirm(username, token string) (bool, string, error)
+	UserAccessTokenList(username string) ([]sdk.AccessToken, error)
+	UserAccessTokenCreate(username string, t *sdk.AccessToken) error
+	UserAccessTokenRevoke(username string, id int64) error
 	WorkerList() ([]sdk.Worker, error)
 	WorkerModelSpawnError(id int64, info string) error
 	WorkerModelsEnabled() ([]sdk.Model, error)
@@ -66,4 +88,6 @@ type Interface interface {
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifacts(projectKey string, name string, number int64, nodeRunID int64) ([]sdk.Artifact, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, artifactID int64, w io.Writer) error
+	WorkflowNodeRunProvenance(projectKey string, name string, number int64, nodeRunID int64) (*sdk.ProvenanceAttestation, error)
+	WorkflowNodeRunProvenanceVerify(projectKey string, name string, number int64, nodeRunID int64) (*sdk.ProvenanceVerification, error)
 }
@@ -0,0 +1,424 @@
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Coverage report formats
+const (
+	CoverageFormatCobertura = "cobertura"
+	CoverageFormatLCOV      = "lcov"
+	CoverageFormatGo        = "go"
+)
+
+//CoverageStats is the line and branch coverage of a report or of a file
+type CoverageStats struct {
+	TotalLines      int `json:"total_lines" cli:"total_lines"`
+	CoveredLines    int `json:"covered_lines" cli:"covered_lines"`
+	TotalBranches   int `json:"total_branches" cli:"total_branches"`
+	CoveredBranches int `json:"covered_branches" cli:"covered_branches"`
+}
+
+//Add adds the coverage of another report or file
+func (s *CoverageStats) Add(o CoverageStats) {
+	s.TotalLines += o.TotalLines
+	s.CoveredLines += o.CoveredLines
+	s.TotalBranches += o.TotalBranches
+	s.CoveredBranches += o.CoveredBranches
+}
+
+//Lines returns the percentage of covered lines, rounded to 2 decimals
+func (s CoverageStats) Lines() float64 {
+	return coveragePercent(s.CoveredLines, s.TotalLines)
+}
+
+//Branches returns the percentage of covered branches, rounded to 2 decimals
+func (s CoverageStats) Branches() float64 {
+	return coveragePercent(s.CoveredBranches, s.TotalBranches)
+}
+
+func coveragePercent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Floor(float64(covered)*10000/float64(total)) / 100
+}
+
+//CoverageFile is the coverage of a source file
+type CoverageFile struct {
+	Path string `json:"path"`
+	CoverageStats
+	Lines []CoverageLine `json:"lines,omitempty"`
+}
+
+//CoverageLine is the coverage of a line of a source file, or of a block of statements for go coverage profiles
+type CoverageLine struct {
+	ID              string `json:"id"`
+	Statements      int    `json:"statements"` // number of statements of a go block, 1 for a line
+	Hits            int64  `json:"hits"`
+	Branches        int    `json:"branches,omitempty"`
+	CoveredBranches int    `json:"covered_branches,omitempty"`
+}
+
+//merge merges the lines of another coverage of the same file: a line is covered if it's covered in one of the coverages
+func (f *CoverageFile) merge(o CoverageFile) {
+	//Without lines, the coverage of the file can't be merged, the last one is kept
+	if len(f.Lines) == 0 || len(o.Lines) == 0 {
+		f.CoverageStats = o.CoverageStats
+		f.Lines = o.Lines
+		return
+	}
+
+	idx := map[string]int{}
+	for i, l := range f.Lines {
+		idx[l.ID] = i
+	}
+	for _, l := range o.Lines {
+		i, ok := idx[l.ID]
+		if !ok {
+			idx[l.ID] = len(f.Lines)
+			f.Lines = append(f.Lines, l)
+			continue
+		}
+		cl := &f.Lines[i]
+		if l.Hits > cl.Hits {
+			cl.Hits = l.Hits
+		}
+		if l.Branches > cl.Branches {
+			cl.Branches = l.Branches
+		}
+		if l.CoveredBranches > cl.CoveredBranches {
+			cl.CoveredBranches = l.CoveredBranches
+		}
+	}
+	sort.Slice(f.Lines, func(i, j int) bool { return f.Lines[i].ID < f.Lines[j].ID })
+	f.computeStats()
+}
+
+//computeStats computes the coverage of the file from its lines
+func (f *CoverageFile) computeStats() {
+	f.CoverageStats = CoverageStats{}
+	for _, l := range f.Lines {
+		f.TotalLines += l.Statements
+		if l.Hits > 0 {
+			f.CoveredLines += l.Statements
+		}
+		f.TotalBranches += l.Branches
+		f.CoveredBranches += l.CoveredBranches
+	}
+}
+
+//CoverageReport is a coverage report parsed by the CoverageReport builtin action
+type CoverageReport struct {
+	Format string         `json:"format"`
+	Files  []CoverageFile `json:"files,omitempty"`
+	Total  CoverageStats  `json:"total"`
+}
+
+//Merge adds the files of another report, the lines of the files found in both reports are merged
+func (r *CoverageReport) Merge(o CoverageReport) {
+	if r.Format == "" {
+		r.Format = o.Format
+	} else if r.Format != o.Format {
+		r.Format = "mixed"
+	}
+
+	idx := map[string]int{}
+	for i, f := range r.Files {
+		idx[f.Path] = i
+	}
+	for _, f := range o.Files {
+		if i, ok := idx[f.Path]; ok {
+			r.Files[i].merge(f)
+			continue
+		}
+		idx[f.Path] = len(r.Files)
+		r.Files = append(r.Files, f)
+	}
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+
+	r.Total = CoverageStats{}
+	for _, f := range r.Files {
+		r.Total.Add(f.CoverageStats)
+	}
+}
+
+//CoverageDelta is the difference, in percentage points, between the coverage of a node run and a previous one
+type CoverageDelta struct {
+	Branch            string  `json:"branch"`
+	Num               int64   `json:"num"`
+	WorkflowNodeRunID int64   `json:"workflow_node_run_id"`
+	Lines             float64 `json:"lines"`
+	Branches          float64 `json:"branches"`
+}
+
+//NewCoverageDelta returns the delta between the coverage current and the coverage previous
+func NewCoverageDelta(current CoverageStats, previous WorkflowNodeRunCoverage) *CoverageDelta {
+	return &CoverageDelta{
+		Branch:            previous.Branch,
+		Num:               previous.Num,
+		WorkflowNodeRunID: previous.WorkflowNodeRunID,
+		Lines:             math.Floor((current.Lines()-previous.Report.Total.Lines())*100+0.5) / 100,
+		Branches:          math.Floor((current.Branches()-previous.Report.Total.Branches())*100+0.5) / 100,
+	}
+}
+
+//WorkflowNodeRunCoverage is the coverage of a workflow node run
+type WorkflowNodeRunCoverage struct {
+	WorkflowNodeRunID int64          `json:"workflow_node_run_id"`
+	WorkflowID        int64          `json:"workflow_id"`
+	NodeName          string         `json:"node_name"`
+	Num               int64          `json:"num"`
+	Branch            string         `json:"branch"`
+	Created           time.Time      `json:"created"`
+	Report            CoverageReport `json:"report"`
+	// Delta is the delta with the previous run on the same branch
+	Delta *CoverageDelta `json:"delta,omitempty"`
+	// BaseDelta is the delta with the last run on the base branch of the pull request
+	BaseDelta *CoverageDelta `json:"base_delta,omitempty"`
+}
+
+//DetectCoverageFormat returns the format of a coverage report from its content
+func DetectCoverageFormat(data []byte) (string, error) {
+	s := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(s, []byte("mode:")):
+		return CoverageFormatGo, nil
+	case bytes.Contains(s, []byte("<coverage")):
+		return CoverageFormatCobertura, nil
+	case bytes.HasPrefix(s, []byte("TN:")) || bytes.HasPrefix(s, []byte("SF:")):
+		return CoverageFormatLCOV, nil
+	}
+	return "", fmt.Errorf("unknown coverage report format")
+}
+
+//ParseCoverageReport parses a cobertura, lcov or go coverage report. The format is detected if empty
+func ParseCoverageReport(format string, data []byte) (CoverageReport, error) {
+	if format == "" {
+		var err error
+		format, err = DetectCoverageFormat(data)
+		if err != nil {
+			return CoverageReport{}, err
+		}
+	}
+
+	var files []CoverageFile
+	var err error
+	switch format {
+	case CoverageFormatCobertura:
+		files, err = parseCobertura(data)
+	case CoverageFormatLCOV:
+		files, err = parseLCOV(data)
+	case CoverageFormatGo:
+		files, err = parseGoCoverProfile(data)
+	default:
+		return CoverageReport{}, fmt.Errorf("unsupported coverage report format %s", format)
+	}
+	if err != nil {
+		return CoverageReport{}, fmt.Errorf("invalid %s coverage report: %v", format, err)
+	}
+
+	r := CoverageReport{Format: format}
+	r.Merge(CoverageReport{Format: format, Files: files})
+	return r, nil
+}
+
+type coberturaReport struct {
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number            int    `xml:"number,attr"`
+				Hits              int64  `xml:"hits,attr"`
+				Branch            bool   `xml:"branch,attr"`
+				ConditionCoverage string `xml:"condition-coverage,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+type coverageLine struct {
+	hits             int64
+	branches, bcover int
+}
+
+func coverageFiles(lines map[string]map[int]coverageLine) []CoverageFile {
+	files := make([]CoverageFile, 0, len(lines))
+	for path, ls := range lines {
+		f := CoverageFile{Path: path}
+		for n, l := range ls {
+			f.Lines = append(f.Lines, CoverageLine{
+				ID:              strconv.Itoa(n),
+				Statements:      1,
+				Hits:            l.hits,
+				Branches:        l.branches,
+				CoveredBranches: l.bcover,
+			})
+		}
+		sort.Slice(f.Lines, func(i, j int) bool { return f.Lines[i].ID < f.Lines[j].ID })
+		f.computeStats()
+		files = append(files, f)
+	}
+	return files
+}
+
+func parseCobertura(data []byte) ([]CoverageFile, error) {
+	var r coberturaReport
+	if err := xml.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	lines := map[string]map[int]coverageLine{}
+	for _, p := range r.Packages {
+		for _, c := range p.Classes {
+			if lines[c.Filename] == nil {
+				lines[c.Filename] = map[int]coverageLine{}
+			}
+			for _, l := range c.Lines {
+				cl := lines[c.Filename][l.Number]
+				if l.Hits > cl.hits {
+					cl.hits = l.Hits
+				}
+				// condition-coverage="50% (1/2)"
+				if l.Branch && l.ConditionCoverage != "" {
+					var percent, covered, total int
+					if _, err := fmt.Sscanf(l.ConditionCoverage, "%d%% (%d/%d)", &percent, &covered, &total); err == nil && total >= cl.branches {
+						cl.branches, cl.bcover = total, covered
+					}
+				}
+				lines[c.Filename][l.Number] = cl
+			}
+		}
+	}
+	return coverageFiles(lines), nil
+}
+
+func parseLCOV(data []byte) ([]CoverageFile, error) {
+	lines := map[string]map[int]coverageLine{}
+	branches := map[string]map[string]bool{}
+	var current string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		l := strings.TrimSpace(scanner.Text())
+		i := strings.Index(l, ":")
+		if i < 0 {
+			continue
+		}
+		key, value := l[:i], strings.Split(l[i+1:], ",")
+		switch key {
+		case "SF":
+			current = l[i+1:]
+			if lines[current] == nil {
+				lines[current] = map[int]coverageLine{}
+				branches[current] = map[string]bool{}
+			}
+		case "DA":
+			if current == "" || len(value) < 2 {
+				return nil, fmt.Errorf("malformed line %s", l)
+			}
+			n, err1 := strconv.Atoi(value[0])
+			hits, err2 := strconv.ParseInt(value[1], 10, 64)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("malformed line %s", l)
+			}
+			cl := lines[current][n]
+			if hits > cl.hits {
+				cl.hits = hits
+			}
+			lines[current][n] = cl
+		case "BRDA":
+			// BRDA:<line>,<block>,<branch>,<taken>, taken is - if the line was never executed
+			if current == "" || len(value) < 4 {
+				return nil, fmt.Errorf("malformed line %s", l)
+			}
+			n, err := strconv.Atoi(value[0])
+			if err != nil {
+				return nil, fmt.Errorf("malformed line %s", l)
+			}
+			k := strings.Join(value[:3], ",")
+			taken := value[3] != "-" && value[3] != "0"
+			covered, known := branches[current][k]
+			cl := lines[current][n]
+			if !known {
+				cl.branches++
+			}
+			if taken && !covered {
+				cl.bcover++
+				branches[current][k] = true
+			} else if !known {
+				branches[current][k] = false
+			}
+			lines[current][n] = cl
+		case "end_of_record":
+			current = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return coverageFiles(lines), nil
+}
+
+func parseGoCoverProfile(data []byte) ([]CoverageFile, error) {
+	type block struct {
+		stmts int
+		count int64
+	}
+	blocks := map[string]map[string]block{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" || strings.HasPrefix(l, "mode:") {
+			continue
+		}
+		// github.com/ovh/cds/sdk/coverage.go:10.40,12.2 1 3
+		i := strings.LastIndex(l, ":")
+		fields := strings.Fields(l[i+1:])
+		if i < 0 || len(fields) != 3 {
+			return nil, fmt.Errorf("malformed line %s", l)
+		}
+		stmts, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.ParseInt(fields[2], 10, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("malformed line %s", l)
+		}
+		path := l[:i]
+		if blocks[path] == nil {
+			blocks[path] = map[string]block{}
+		}
+		b := blocks[path][fields[0]]
+		b.stmts = stmts
+		if count > b.count {
+			b.count = count
+		}
+		blocks[path][fields[0]] = b
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// go coverage is computed on statements, they are reported as lines
+	files := make([]CoverageFile, 0, len(blocks))
+	for path, bs := range blocks {
+		f := CoverageFile{Path: path}
+		for pos, b := range bs {
+			f.Lines = append(f.Lines, CoverageLine{ID: pos, Statements: b.stmts, Hits: b.count})
+		}
+		sort.Slice(f.Lines, func(i, j int) bool { return f.Lines[i].ID < f.Lines[j].ID })
+		f.computeStats()
+		files = append(files, f)
+	}
+	return files, nil
+}
@@ -0,0 +1,117 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoverageReportCobertura(t *testing.T) {
+	data := `<?xml version="1.0" ?>
+<coverage line-rate="0.75" branch-rate="0.5" version="1.9">
+	<packages>
+		<package name="app">
+			<classes>
+				<class name="main" filename="app/main.py">
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="0"/>
+						<line number="3" hits="4" branch="true" condition-coverage="50% (1/2)"/>
+					</lines>
+				</class>
+				<class name="util" filename="app/util.py">
+					<lines>
+						<line number="1" hits="2"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`
+
+	r, err := ParseCoverageReport("", []byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, CoverageFormatCobertura, r.Format)
+	assert.Len(t, r.Files, 2)
+	assert.Equal(t, "app/main.py", r.Files[0].Path)
+	assert.Equal(t, CoverageStats{TotalLines: 4, CoveredLines: 3, TotalBranches: 2, CoveredBranches: 1}, r.Total)
+	assert.Equal(t, 75.0, r.Total.Lines())
+	assert.Equal(t, 50.0, r.Total.Branches())
+}
+
+func TestParseCoverageReportLCOV(t *testing.T) {
+	data := `TN:
+SF:src/index.js
+DA:1,1
+DA:2,0
+DA:3,5
+BRDA:3,0,0,1
+BRDA:3,0,1,-
+BRDA:3,0,1,0
+LF:3
+LH:2
+end_of_record
+SF:src/util.js
+DA:1,0
+end_of_record
+`
+	r, err := ParseCoverageReport("", []byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, CoverageFormatLCOV, r.Format)
+	assert.Len(t, r.Files, 2)
+	assert.Equal(t, CoverageStats{TotalLines: 3, CoveredLines: 2, TotalBranches: 2, CoveredBranches: 1}, r.Files[0].CoverageStats)
+	assert.Equal(t, CoverageStats{TotalLines: 4, CoveredLines: 2, TotalBranches: 2, CoveredBranches: 1}, r.Total)
+}
+
+func TestParseCoverageReportGo(t *testing.T) {
+	data := `mode: set
+github.com/ovh/cds/sdk/a.go:10.40,12.2 2 1
+github.com/ovh/cds/sdk/a.go:14.2,16.3 3 0
+github.com/ovh/cds/sdk/b.go:5.10,6.2 1 0
+github.com/ovh/cds/sdk/b.go:5.10,6.2 1 1
+`
+	r, err := ParseCoverageReport("", []byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, CoverageFormatGo, r.Format)
+	assert.Equal(t, CoverageStats{TotalLines: 6, CoveredLines: 3}, r.Total)
+	assert.Equal(t, 50.0, r.Total.Lines())
+
+	_, err = ParseCoverageReport(CoverageFormatGo, []byte("mode: set\nnot a profile"))
+	assert.Error(t, err)
+}
+
+func TestCoverageReportMerge(t *testing.T) {
+	r := CoverageReport{}
+	r.Merge(CoverageReport{Format: CoverageFormatGo, Files: []CoverageFile{{Path: "a.go", CoverageStats: CoverageStats{TotalLines: 2, CoveredLines: 1}}}})
+	r.Merge(CoverageReport{Format: CoverageFormatLCOV, Files: []CoverageFile{{Path: "a.js", CoverageStats: CoverageStats{TotalLines: 2, CoveredLines: 2}}}})
+	assert.Equal(t, "mixed", r.Format)
+	assert.Equal(t, CoverageStats{TotalLines: 4, CoveredLines: 3}, r.Total)
+
+	d := NewCoverageDelta(r.Total, WorkflowNodeRunCoverage{Branch: "master", Num: 3, Report: CoverageReport{Total: CoverageStats{TotalLines: 3, CoveredLines: 2}}})
+	assert.Equal(t, 8.34, d.Lines)
+	assert.Equal(t, "master", d.Branch)
+}
+
+func TestCoverageReportMergeSameFile(t *testing.T) {
+	job1, err := ParseCoverageReport(CoverageFormatLCOV, []byte("SF:a.js\nDA:1,1\nDA:2,0\nDA:3,0\nend_of_record\n"))
+	assert.NoError(t, err)
+	job2, err := ParseCoverageReport(CoverageFormatLCOV, []byte("SF:a.js\nDA:1,1\nDA:2,4\nDA:3,0\nend_of_record\n"))
+	assert.NoError(t, err)
+
+	//Lines covered by several jobs are counted once
+	r := CoverageReport{}
+	r.Merge(job1)
+	r.Merge(job2)
+	assert.Equal(t, CoverageStats{TotalLines: 3, CoveredLines: 2}, r.Total)
+
+	//Posting the same report again does not change the coverage
+	r.Merge(job2)
+	assert.Equal(t, CoverageStats{TotalLines: 3, CoveredLines: 2}, r.Total)
+
+	goReport, err := ParseCoverageReport(CoverageFormatGo, []byte("mode: set\na.go:10.40,12.2 2 1\na.go:14.2,16.3 3 0\n"))
+	assert.NoError(t, err)
+	r = CoverageReport{}
+	r.Merge(goReport)
+	r.Merge(goReport)
+	assert.Equal(t, CoverageStats{TotalLines: 5, CoveredLines: 2}, r.Total)
+}
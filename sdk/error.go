@@ -116,6 +116,14 @@ var (
 	ErrParameterNotExists                    = &Error{ID: 100, Status: http.StatusNotFound}
 	ErrUnknownKeyType                        = &Error{ID: 101, Status: http.StatusBadRequest}
 	ErrInvalidKeyPattern                     = &Error{ID: 102, Status: http.StatusBadRequest}
+	ErrWorkflowNodeRunNotWaitingApproval     = &Error{ID: 103, Status: http.StatusBadRequest}
+	ErrWorkflowNodeRunAlreadyApproved        = &Error{ID: 104, Status: http.StatusConflict}
+	ErrWorkflowNodeRunNotApprover            = &Error{ID: 105, Status: http.StatusForbidden}
+	ErrPluginVersionNotFound                 = &Error{ID: 106, Status: http.StatusNotFound}
+	ErrPluginVersionInvalid                  = &Error{ID: 107, Status: http.StatusBadRequest}
+	ErrTooManyRequests                       = &Error{ID: 108, Status: http.StatusTooManyRequests}
+	ErrInvalidPassphrase                     = &Error{ID: 109, Status: http.StatusBadRequest}
+	ErrForkPullRequestSkipped                = &Error{ID: 110, Status: http.StatusForbidden}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -221,6 +229,14 @@ var errorsAmericanEnglish = map[int]string{
 	ErrParameterNotExists.ID:                    "This parameter doesn't exist",
 	ErrUnknownKeyType.ID:                        "Unknown key type",
 	ErrInvalidKeyPattern.ID:                     "key name must respect the following pattern: '^[a-zA-Z0-9.-_-]{1,}$'",
+	ErrWorkflowNodeRunNotWaitingApproval.ID:     "Workflow node run is not waiting for approval",
+	ErrWorkflowNodeRunAlreadyApproved.ID:        "You have already approved this workflow node run",
+	ErrWorkflowNodeRunNotApprover.ID:            "You are not a member of the groups allowed to approve this workflow node run",
+	ErrPluginVersionNotFound.ID:                 "Plugin version not found",
+	ErrPluginVersionInvalid.ID:                  "Invalid plugin version, versions must follow semantic versioning",
+	ErrTooManyRequests.ID:                       "Too many requests, retry later",
+	ErrInvalidPassphrase.ID:                     "Invalid passphrase",
+	ErrForkPullRequestSkipped.ID:                "Pull requests from forks are not built",
 }
 
 var errorsFrench = map[int]string{
@@ -326,6 +342,14 @@ var errorsFrench = map[int]string{
 	ErrParameterNotExists.ID:                    "Ce paramètre n'existe pas",
 	ErrUnknownKeyType.ID:                        "Le type de clé n'est pas connu",
 	ErrInvalidKeyPattern.ID:                     "le nom de la clé doit respecter le pattern suivant; '^[a-zA-Z0-9.-_-]{1,}$'",
+	ErrWorkflowNodeRunNotWaitingApproval.ID:     "Le noeud de workflow n'est pas en attente d'approbation",
+	ErrWorkflowNodeRunAlreadyApproved.ID:        "Vous avez déjà approuvé ce noeud de workflow",
+	ErrWorkflowNodeRunNotApprover.ID:            "Vous n'êtes pas membre des groupes autorisés à approuver ce noeud de workflow",
+	ErrPluginVersionNotFound.ID:                 "Version du plugin introuvable",
+	ErrPluginVersionInvalid.ID:                  "Version du plugin invalide, les versions doivent respecter le versionnage sémantique",
+	ErrTooManyRequests.ID:                       "Trop de requêtes, réessayez plus tard",
+	ErrInvalidPassphrase.ID:                     "Phrase de passe invalide",
+	ErrForkPullRequestSkipped.ID:                "Les pull requests provenant de forks ne sont pas construites",
 }
 
 var errorsLanguages = []map[int]string{
@@ -15,6 +15,8 @@ type Event struct {
 	EventType string                 `json:"type_event"` // go type of payload
 	Payload   map[string]interface{} `json:"payload"`
 	Attempts  int                    `json:"attempt"`
+	// StatusSent is set once the commit status of the event is sent, a retry only comments the pull requests
+	StatusSent bool `json:"status_sent,omitempty"`
 }
 
 // EventEngine contains event data for engine
@@ -59,6 +61,61 @@ type EventJob struct {
 	Hash            string `json:"hash,omitempty"`
 }
 
+// EventWorkflowRun contains event data for a workflow run
+type EventWorkflowRun struct {
+	ID           int64            `json:"id,omitempty"`
+	Number       int64            `json:"number,omitempty"`
+	Status       Status           `json:"status,omitempty"`
+	Start        int64            `json:"start,omitempty"`
+	LastModified int64            `json:"lastModified,omitempty"`
+	ProjectKey   string           `json:"projectKey,omitempty"`
+	WorkflowName string           `json:"workflowName,omitempty"`
+	Tags         []WorkflowRunTag `json:"tags,omitempty"`
+}
+
+// EventWorkflowNodeRun contains event data for a workflow node run
+type EventWorkflowNodeRun struct {
+	ID                    int64  `json:"id,omitempty"`
+	WorkflowRunID         int64  `json:"workflowRunID,omitempty"`
+	WorkflowNodeID        int64  `json:"workflowNodeID,omitempty"`
+	Number                int64  `json:"number,omitempty"`
+	SubNumber             int64  `json:"subNumber,omitempty"`
+	Status                Status `json:"status,omitempty"`
+	Start                 int64  `json:"start,omitempty"`
+	Done                  int64  `json:"done,omitempty"`
+	ProjectKey            string `json:"projectKey,omitempty"`
+	WorkflowName          string `json:"workflowName,omitempty"`
+	PipelineName          string `json:"pipelineName,omitempty"`
+	ApplicationName       string `json:"applicationName,omitempty"`
+	EnvironmentName       string `json:"environmentName,omitempty"`
+	BranchName            string `json:"branchName,omitempty"`
+	Hash                  string `json:"hash,omitempty"`
+	RepositoryManagerName string `json:"repositoryManagerName,omitempty"`
+	RepositoryFullname    string `json:"repositoryFullname,omitempty"`
+	TestsTotal            int    `json:"testsTotal,omitempty"`
+	TestsOK               int    `json:"testsOK,omitempty"`
+	TestsKO               int    `json:"testsKO,omitempty"`
+	TestsSkipped          int    `json:"testsSkipped,omitempty"`
+	CoverageLines         string `json:"coverageLines,omitempty"`
+	CoverageLinesDelta    string `json:"coverageLinesDelta,omitempty"`
+}
+
+// EventWorkflowNodeJobRun contains event data for a workflow node job run
+type EventWorkflowNodeJobRun struct {
+	ID                int64  `json:"id,omitempty"`
+	WorkflowNodeRunID int64  `json:"workflowNodeRunID,omitempty"`
+	WorkflowRunID     int64  `json:"workflowRunID,omitempty"`
+	Number            int64  `json:"number,omitempty"`
+	SubNumber         int64  `json:"subNumber,omitempty"`
+	JobName           string `json:"jobName,omitempty"`
+	Status            Status `json:"status,omitempty"`
+	Queued            int64  `json:"queued,omitempty"`
+	Start             int64  `json:"start,omitempty"`
+	Done              int64  `json:"done,omitempty"`
+	ModelName         string `json:"modelName,omitempty"`
+	WorkerName        string `json:"workerName,omitempty"`
+}
+
 // EventNotif contains event data for a job
 type EventNotif struct {
 	Recipients []string `json:"recipients"`
@@ -1,6 +1,7 @@
 package exportentities
 
 import (
+	"sort"
 	"text/template"
 
 	"github.com/ovh/cds/sdk"
@@ -14,6 +15,7 @@ type Application struct {
 	Permissions       map[string]int                 `json:"permissions,omitempty" yaml:"permissions,omitempty"`
 	Variables         map[string]VariableValue       `json:"variables,omitempty" yaml:"variables,omitempty"`
 	Pipelines         map[string]ApplicationPipeline `json:"pipelines,omitempty" yaml:"pipelines,omitempty"`
+	Keys              []Key                          `json:"keys,omitempty" yaml:"keys,omitempty"`
 }
 
 // ApplicationPipeline represents exported sdk.ApplicationPipeline
@@ -95,8 +97,7 @@ func NewApplication(app *sdk.Application) (a *Application) {
 		for _, t := range ap.Triggers {
 
 			c := make([]Condition, len(t.Prerequisites))
-			var i int
-			for _, pr := range t.Prerequisites {
+			for i, pr := range t.Prerequisites {
 				c[i] = Condition{
 					Variable: pr.Parameter,
 					Expected: pr.ExpectedValue,
@@ -191,10 +192,17 @@ func NewApplication(app *sdk.Application) (a *Application) {
 			}
 		}
 
-		//Compute all
+		//Compute all, sorted by environment to get a stable export
+		envs := make([]string, 0, len(mapEnvOpts))
+		for k := range mapEnvOpts {
+			envs = append(envs, k)
+		}
+		sort.Strings(envs)
+
 		pip.Options = make([]ApplicationPipelineOptions, len(mapEnvOpts))
 		var i int
-		for k, v := range mapEnvOpts {
+		for _, k := range envs {
+			v := mapEnvOpts[k]
 			if k != sdk.DefaultEnv.Name {
 				s := k
 				pip.Options[i].Environment = &s
@@ -217,6 +225,75 @@ func NewApplication(app *sdk.Application) (a *Application) {
 	return
 }
 
+//Application returns a sdk.Application entity. Pipelines and triggers only have names, and options are not converted
+func (a *Application) Application() (app *sdk.Application) {
+	app = new(sdk.Application)
+	app.Name = a.Name
+	if a.RepositoryManager != "" {
+		app.RepositoriesManager = &sdk.RepositoriesManager{Name: a.RepositoryManager}
+		app.RepositoryFullname = a.RepositoryName
+	}
+
+	app.Variable = make([]sdk.Variable, 0, len(a.Variables))
+	for k, v := range a.Variables {
+		app.Variable = append(app.Variable, sdk.Variable{
+			Name:  k,
+			Type:  v.Type,
+			Value: v.Value,
+		})
+	}
+
+	app.ApplicationGroups = make([]sdk.GroupPermission, 0, len(a.Permissions))
+	for k, v := range a.Permissions {
+		app.ApplicationGroups = append(app.ApplicationGroups, sdk.GroupPermission{
+			Group:      sdk.Group{Name: k},
+			Permission: v,
+		})
+	}
+
+	app.Pipelines = make([]sdk.ApplicationPipeline, 0, len(a.Pipelines))
+	for name, ap := range a.Pipelines {
+		pip := sdk.ApplicationPipeline{
+			Pipeline: sdk.Pipeline{Name: name},
+		}
+		for k, v := range ap.Parameters {
+			pip.Parameters = append(pip.Parameters, sdk.Parameter{
+				Name:  k,
+				Type:  v.Type,
+				Value: v.Value,
+			})
+		}
+		for dest, t := range ap.Triggers {
+			trig := sdk.PipelineTrigger{
+				DestPipeline: sdk.Pipeline{Name: dest},
+				Manual:       t.Manual,
+			}
+			if t.ProjectKey != nil {
+				trig.DestProject.Key = *t.ProjectKey
+			}
+			if t.ApplicationName != nil {
+				trig.DestApplication.Name = *t.ApplicationName
+			}
+			if t.FromEnvironment != nil {
+				trig.SrcEnvironment.Name = *t.FromEnvironment
+			}
+			if t.ToEnvironment != nil {
+				trig.DestEnvironment.Name = *t.ToEnvironment
+			}
+			for _, c := range t.Conditions {
+				trig.Prerequisites = append(trig.Prerequisites, sdk.Prerequisite{
+					Parameter:     c.Variable,
+					ExpectedValue: c.Expected,
+				})
+			}
+			pip.Triggers = append(pip.Triggers, trig)
+		}
+		app.Pipelines = append(app.Pipelines, pip)
+	}
+
+	return
+}
+
 //HCLTemplate returns text/template
 func (a *Application) HCLTemplate() (*template.Template, error) {
 	tmpl := `name = "{{.Name}}"
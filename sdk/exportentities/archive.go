@@ -0,0 +1,266 @@
+package exportentities
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// ProjectArchive is a whole exported project. It is written as a gzipped tar of yaml files: project.yml and one
+// file per entity in the pipelines, applications, environments and workflows directories
+type ProjectArchive struct {
+	Project      Project
+	Pipelines    []Pipeline
+	Applications []Application
+	Environments []Environment
+	Workflows    []Workflow
+}
+
+// SecretFunc transforms the value of a secret. It is used to encrypt and decrypt the secrets of a ProjectArchive
+type SecretFunc func(string) (string, error)
+
+//All the files and directories of a project archive
+const (
+	projectArchiveFile         = "project.yml"
+	projectArchivePipelines    = "pipelines"
+	projectArchiveApplications = "applications"
+	projectArchiveEnvironments = "environments"
+	projectArchiveWorkflows    = "workflows"
+)
+
+// Secrets applies f on all the secrets of the archive: the password and key variables and parameters, and the
+// private keys
+func (a *ProjectArchive) Secrets(f SecretFunc) error {
+	if err := secretValues(a.Project.Variables, f); err != nil {
+		return fmt.Errorf("project %s: %s", a.Project.Key, err)
+	}
+	if err := secretKeys(a.Project.Keys, f); err != nil {
+		return fmt.Errorf("project %s: %s", a.Project.Key, err)
+	}
+
+	for i := range a.Pipelines {
+		p := &a.Pipelines[i]
+		for k, v := range p.Parameters {
+			if !sdk.NeedPlaceholder(v.Type) {
+				continue
+			}
+			s, err := f(v.DefaultValue)
+			if err != nil {
+				return fmt.Errorf("pipeline %s parameter %s: %s", p.Name, k, err)
+			}
+			v.DefaultValue = s
+			p.Parameters[k] = v
+		}
+	}
+
+	for i := range a.Applications {
+		app := &a.Applications[i]
+		if err := secretValues(app.Variables, f); err != nil {
+			return fmt.Errorf("application %s: %s", app.Name, err)
+		}
+		if err := secretKeys(app.Keys, f); err != nil {
+			return fmt.Errorf("application %s: %s", app.Name, err)
+		}
+		for name, ap := range app.Pipelines {
+			if err := secretValues(ap.Parameters, f); err != nil {
+				return fmt.Errorf("application %s pipeline %s: %s", app.Name, name, err)
+			}
+		}
+	}
+
+	for i := range a.Environments {
+		env := &a.Environments[i]
+		if err := secretValues(env.Values, f); err != nil {
+			return fmt.Errorf("environment %s: %s", env.Name, err)
+		}
+		if err := secretKeys(env.Keys, f); err != nil {
+			return fmt.Errorf("environment %s: %s", env.Name, err)
+		}
+	}
+
+	for i := range a.Workflows {
+		w := &a.Workflows[i]
+		if err := secretNodes(&w.Root, f); err != nil {
+			return fmt.Errorf("workflow %s: %s", w.Name, err)
+		}
+		for j := range w.Joins {
+			for k := range w.Joins[j].Triggers {
+				if err := secretNodes(&w.Joins[j].Triggers[k].Node, f); err != nil {
+					return fmt.Errorf("workflow %s: %s", w.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func secretValues(values map[string]VariableValue, f SecretFunc) error {
+	for k, v := range values {
+		if !sdk.NeedPlaceholder(v.Type) {
+			continue
+		}
+		s, err := f(v.Value)
+		if err != nil {
+			return fmt.Errorf("variable %s: %s", k, err)
+		}
+		v.Value = s
+		values[k] = v
+	}
+	return nil
+}
+
+func secretKeys(keys []Key, f SecretFunc) error {
+	for i := range keys {
+		s, err := f(keys[i].Private)
+		if err != nil {
+			return fmt.Errorf("key %s: %s", keys[i].Name, err)
+		}
+		keys[i].Private = s
+	}
+	return nil
+}
+
+func secretNodes(n *WorkflowNode, f SecretFunc) error {
+	if err := secretValues(n.Parameters, f); err != nil {
+		return fmt.Errorf("node %s: %s", n.Name, err)
+	}
+	for i := range n.Triggers {
+		if err := secretNodes(&n.Triggers[i].Node, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type archiveFile struct {
+	name   string
+	entity interface{}
+}
+
+// Write writes the archive as a gzipped tar
+func (a *ProjectArchive) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	files := []archiveFile{{projectArchiveFile, a.Project}}
+	for _, p := range a.Pipelines {
+		files = append(files, archiveFile{path.Join(projectArchivePipelines, p.Name+".yml"), p})
+	}
+	for _, app := range a.Applications {
+		files = append(files, archiveFile{path.Join(projectArchiveApplications, app.Name+".yml"), app})
+	}
+	for _, env := range a.Environments {
+		files = append(files, archiveFile{path.Join(projectArchiveEnvironments, env.Name+".yml"), env})
+	}
+	for _, wf := range a.Workflows {
+		files = append(files, archiveFile{path.Join(projectArchiveWorkflows, wf.Name+".yml"), wf})
+	}
+
+	now := time.Now()
+	for _, f := range files {
+		btes, err := yaml.Marshal(f.entity)
+		if err != nil {
+			return fmt.Errorf("cannot marshal %s: %s", f.name, err)
+		}
+		hdr := &tar.Header{
+			Name:    f.name,
+			Mode:    0600,
+			Size:    int64(len(btes)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(btes); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadProjectArchive reads an archive written by ProjectArchive.Write
+func ReadProjectArchive(r io.Reader) (*ProjectArchive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project archive: %s", err)
+	}
+	defer gz.Close()
+
+	a := &ProjectArchive{}
+	var hasProject bool
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid project archive: %s", err)
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		btes, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		dir, file := path.Split(path.Clean(hdr.Name))
+		name := strings.TrimSuffix(file, path.Ext(file))
+		switch strings.TrimSuffix(dir, "/") {
+		case "":
+			if file != projectArchiveFile {
+				continue
+			}
+			err = yaml.Unmarshal(btes, &a.Project)
+			hasProject = true
+		case projectArchivePipelines:
+			p := Pipeline{}
+			if err = yaml.Unmarshal(btes, &p); err == nil {
+				//The name of the pipeline is omitted when it is the name of its type
+				if p.Name == "" {
+					p.Name = name
+				}
+				a.Pipelines = append(a.Pipelines, p)
+			}
+		case projectArchiveApplications:
+			app := Application{}
+			if err = yaml.Unmarshal(btes, &app); err == nil {
+				a.Applications = append(a.Applications, app)
+			}
+		case projectArchiveEnvironments:
+			env := Environment{}
+			if err = yaml.Unmarshal(btes, &env); err == nil {
+				a.Environments = append(a.Environments, env)
+			}
+		case projectArchiveWorkflows:
+			wf := Workflow{}
+			if err = yaml.Unmarshal(btes, &wf); err == nil {
+				a.Workflows = append(a.Workflows, wf)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %s", hdr.Name, err)
+		}
+	}
+
+	if !hasProject {
+		return nil, fmt.Errorf("invalid project archive: %s not found", projectArchiveFile)
+	}
+	return a, nil
+}
@@ -0,0 +1,132 @@
+package exportentities
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func testWorkflow() *sdk.Workflow {
+	build := sdk.WorkflowNode{
+		ID:       1,
+		Name:     "build",
+		Pipeline: sdk.Pipeline{Name: "build"},
+		Context: &sdk.WorkflowNodeContext{
+			Application:    &sdk.Application{Name: "app"},
+			DefaultPayload: map[string]interface{}{"git.branch": "master"},
+		},
+		Hooks: []sdk.WorkflowNodeHook{
+			{WorkflowHookModel: sdk.WorkflowHookModel{Name: "Webhook"}, Config: sdk.WorkflowNodeHookConfig{"method": "POST"}},
+		},
+	}
+	test := sdk.WorkflowNode{
+		ID:       2,
+		Name:     "test",
+		Pipeline: sdk.Pipeline{Name: "test"},
+		Context:  &sdk.WorkflowNodeContext{Application: &sdk.Application{Name: "app"}},
+	}
+	build.Triggers = []sdk.WorkflowNodeTrigger{
+		{
+			WorkflowDestNode: test,
+			Conditions:       []sdk.WorkflowTriggerCondition{{Variable: "cds.status", Operator: "=", Value: "Success"}},
+		},
+	}
+
+	return &sdk.Workflow{
+		Name: "wf",
+		Root: &build,
+		Joins: []sdk.WorkflowNodeJoin{
+			{
+				SourceNodeIDs: []int64{1, 2},
+				Triggers: []sdk.WorkflowNodeJoinTrigger{
+					{
+						WorkflowDestNode: sdk.WorkflowNode{
+							ID:       3,
+							Name:     "deploy",
+							Pipeline: sdk.Pipeline{Name: "deploy"},
+							Context: &sdk.WorkflowNodeContext{
+								Application: &sdk.Application{Name: "app"},
+								Environment: &sdk.Environment{Name: "production"},
+								Approval:    &sdk.WorkflowNodeApproval{Groups: []string{"ops"}, MinApprovals: 1},
+								DefaultPipelineParameters: []sdk.Parameter{
+									{Name: "token", Type: sdk.SecretVariable, Value: "s3cr3t"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNewWorkflow(t *testing.T) {
+	w, err := NewWorkflow(testWorkflow())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1", w.Root.Ref)
+	assert.Equal(t, "app", w.Root.Application)
+	assert.Equal(t, `{"git.branch":"master"}`, w.Root.Payload)
+	assert.Equal(t, "Webhook", w.Root.Hooks[0].Model)
+	assert.Equal(t, "2", w.Root.Triggers[0].Node.Ref)
+	assert.Equal(t, []string{"1", "2"}, w.Joins[0].Sources)
+	assert.Equal(t, "", w.Joins[0].Triggers[0].Node.Ref)
+	assert.Equal(t, "production", w.Joins[0].Triggers[0].Node.Environment)
+
+	wf, err := w.Workflow()
+	assert.NoError(t, err)
+	assert.Equal(t, "build", wf.Root.Pipeline.Name)
+	assert.Equal(t, "app", wf.Root.Context.Application.Name)
+	assert.Equal(t, map[string]interface{}{"git.branch": "master"}, wf.Root.Context.DefaultPayload)
+	assert.Equal(t, "=", wf.Root.Triggers[0].Conditions[0].Operator)
+	assert.Equal(t, []string{"1", "2"}, wf.Joins[0].SourceNodeRefs)
+	assert.Equal(t, 1, wf.Joins[0].Triggers[0].WorkflowDestNode.Context.Approval.MinApprovals)
+}
+
+func TestProjectArchive(t *testing.T) {
+	w, err := NewWorkflow(testWorkflow())
+	assert.NoError(t, err)
+
+	a := &ProjectArchive{
+		Project: Project{
+			Key:  "PROJ",
+			Name: "My project",
+			Variables: map[string]VariableValue{
+				"foo":      {Type: sdk.StringVariable, Value: "bar"},
+				"password": {Type: sdk.SecretVariable, Value: "s3cr3t"},
+			},
+			Keys: []Key{{Name: "proj-key", Type: "ssh", Public: "public", Private: "private"}},
+		},
+		Pipelines:    []Pipeline{{Name: "build"}},
+		Applications: []Application{{Name: "app", Keys: []Key{{Name: "app-key", Private: "private"}}}},
+		Environments: []Environment{
+			{
+				Name:        "production",
+				Values:      map[string]VariableValue{"url": {Type: sdk.StringVariable, Value: "https://example.com"}},
+				Permissions: map[string]int{"ops": 7},
+			},
+		},
+		Workflows: []Workflow{*w},
+	}
+
+	encrypt := func(s string) (string, error) { return "encrypted:" + s, nil }
+	assert.NoError(t, a.Secrets(encrypt))
+	assert.Equal(t, "bar", a.Project.Variables["foo"].Value)
+	assert.Equal(t, "encrypted:s3cr3t", a.Project.Variables["password"].Value)
+	assert.Equal(t, "encrypted:private", a.Project.Keys[0].Private)
+	assert.Equal(t, "encrypted:private", a.Applications[0].Keys[0].Private)
+	assert.Equal(t, "encrypted:s3cr3t", a.Workflows[0].Joins[0].Triggers[0].Node.Parameters["token"].Value)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, a.Write(buf))
+
+	a2, err := ReadProjectArchive(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, a, a2)
+
+	_, err = ReadProjectArchive(bytes.NewBufferString("not an archive"))
+	assert.Error(t, err)
+}
@@ -11,6 +11,7 @@ type Environment struct {
 	Name        string                   `json:"name" yaml:"name"`
 	Values      map[string]VariableValue `json:"values" yaml:"values"`
 	Permissions map[string]int           `json:"permissions" yaml:"permissions"`
+	Keys        []Key                    `json:"keys,omitempty" yaml:"keys,omitempty"`
 }
 
 //NewEnvironment returns an Environment from an sdk.Environment pointer
@@ -753,7 +753,7 @@ func computeJobRequirements(req []Requirement) []sdk.Requirement {
 			val = r.Network
 			tpe = sdk.NetworkAccessRequirement
 		} else if r.Plugin != "" {
-			name = r.Plugin
+			name, _ = sdk.PluginRequirementVersion(r.Plugin)
 			val = r.Plugin
 			tpe = sdk.PluginRequirement
 		} else if r.Service.Name != "" {
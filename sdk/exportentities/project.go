@@ -0,0 +1,108 @@
+package exportentities
+
+import (
+	"github.com/ovh/cds/sdk"
+)
+
+// Project represents exported sdk.Project
+type Project struct {
+	Key                  string                   `json:"key" yaml:"key"`
+	Name                 string                   `json:"name" yaml:"name"`
+	Permissions          map[string]int           `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Variables            map[string]VariableValue `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Keys                 []Key                    `json:"keys,omitempty" yaml:"keys,omitempty"`
+	RepositoriesManagers []string                 `json:"repositories_managers,omitempty" yaml:"repositories_managers,omitempty"`
+}
+
+// Key represents exported sdk.Key
+type Key struct {
+	Name    string `json:"name" yaml:"name"`
+	Type    string `json:"type" yaml:"type"`
+	Public  string `json:"public" yaml:"public"`
+	Private string `json:"private" yaml:"private"`
+	KeyID   string `json:"key_id,omitempty" yaml:"key_id,omitempty"`
+}
+
+//NewProject returns a Project from an sdk.Project pointer
+func NewProject(proj *sdk.Project) (p *Project) {
+	p = new(Project)
+	p.Key = proj.Key
+	p.Name = proj.Name
+
+	p.Permissions = make(map[string]int, len(proj.ProjectGroups))
+	for _, gp := range proj.ProjectGroups {
+		p.Permissions[gp.Group.Name] = gp.Permission
+	}
+
+	p.Variables = make(map[string]VariableValue, len(proj.Variable))
+	for _, v := range proj.Variable {
+		p.Variables[v.Name] = VariableValue{
+			Type:  string(v.Type),
+			Value: v.Value,
+		}
+	}
+
+	for _, k := range proj.Keys {
+		p.Keys = append(p.Keys, NewKey(k.Key))
+	}
+
+	for _, rm := range proj.ReposManager {
+		p.RepositoriesManagers = append(p.RepositoriesManagers, rm.Name)
+	}
+	return
+}
+
+//Project returns a sdk.Project entity. Repositories managers only have names
+func (p *Project) Project() (proj *sdk.Project) {
+	proj = new(sdk.Project)
+	proj.Key = p.Key
+	proj.Name = p.Name
+
+	proj.ProjectGroups = make([]sdk.GroupPermission, 0, len(p.Permissions))
+	for k, v := range p.Permissions {
+		proj.ProjectGroups = append(proj.ProjectGroups, sdk.GroupPermission{
+			Group:      sdk.Group{Name: k},
+			Permission: v,
+		})
+	}
+
+	proj.Variable = make([]sdk.Variable, 0, len(p.Variables))
+	for k, v := range p.Variables {
+		proj.Variable = append(proj.Variable, sdk.Variable{
+			Name:  k,
+			Type:  v.Type,
+			Value: v.Value,
+		})
+	}
+
+	for _, k := range p.Keys {
+		proj.Keys = append(proj.Keys, sdk.ProjectKey{Key: k.Key()})
+	}
+
+	for _, name := range p.RepositoriesManagers {
+		proj.ReposManager = append(proj.ReposManager, sdk.RepositoriesManager{Name: name})
+	}
+	return
+}
+
+//NewKey returns a Key from an sdk.Key
+func NewKey(k sdk.Key) Key {
+	return Key{
+		Name:    k.Name,
+		Type:    k.Type,
+		Public:  k.Public,
+		Private: k.Private,
+		KeyID:   k.KeyID,
+	}
+}
+
+//Key returns a sdk.Key entity
+func (k Key) Key() sdk.Key {
+	return sdk.Key{
+		Name:    k.Name,
+		Type:    k.Type,
+		Public:  k.Public,
+		Private: k.Private,
+		KeyID:   k.KeyID,
+	}
+}
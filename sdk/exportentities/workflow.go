@@ -0,0 +1,306 @@
+package exportentities
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Workflow represents exported sdk.Workflow. Pipelines, applications, environments and hook models are referenced by
+// their names
+type Workflow struct {
+	Name        string         `json:"name" yaml:"name"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Root        WorkflowNode   `json:"root" yaml:"root"`
+	Joins       []WorkflowJoin `json:"joins,omitempty" yaml:"joins,omitempty"`
+}
+
+// WorkflowNode represents exported sdk.WorkflowNode. Ref is only set on the sources of joins
+type WorkflowNode struct {
+	Name        string                   `json:"name" yaml:"name"`
+	Ref         string                   `json:"ref,omitempty" yaml:"ref,omitempty"`
+	Pipeline    string                   `json:"pipeline" yaml:"pipeline"`
+	Application string                   `json:"application,omitempty" yaml:"application,omitempty"`
+	Environment string                   `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Payload     string                   `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Parameters  map[string]VariableValue `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Approval    *WorkflowApproval        `json:"approval,omitempty" yaml:"approval,omitempty"`
+	Hooks       []WorkflowHook           `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+	Triggers    []WorkflowTrigger        `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+}
+
+// WorkflowApproval represents exported sdk.WorkflowNodeApproval
+type WorkflowApproval struct {
+	Groups       []string `json:"groups" yaml:"groups"`
+	MinApprovals int      `json:"min_approvals" yaml:"min_approvals"`
+	Timeout      int64    `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// WorkflowHook represents exported sdk.WorkflowNodeHook
+type WorkflowHook struct {
+	Model      string              `json:"model" yaml:"model"`
+	Config     map[string]string   `json:"config,omitempty" yaml:"config,omitempty"`
+	Conditions []WorkflowCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+}
+
+// WorkflowTrigger represents exported sdk.WorkflowNodeTrigger and sdk.WorkflowNodeJoinTrigger
+type WorkflowTrigger struct {
+	Conditions []WorkflowCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Node       WorkflowNode        `json:"node" yaml:"node"`
+}
+
+// WorkflowJoin represents exported sdk.WorkflowNodeJoin
+type WorkflowJoin struct {
+	Sources  []string          `json:"sources" yaml:"sources"`
+	Triggers []WorkflowTrigger `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+}
+
+// WorkflowCondition represents exported sdk.WorkflowTriggerCondition
+type WorkflowCondition struct {
+	Variable string `json:"variable" yaml:"variable"`
+	Operator string `json:"operator" yaml:"operator"`
+	Value    string `json:"value" yaml:"value"`
+}
+
+//NewWorkflow returns a Workflow from an sdk.Workflow pointer
+func NewWorkflow(w *sdk.Workflow) (*Workflow, error) {
+	wf := &Workflow{
+		Name:        w.Name,
+		Description: w.Description,
+	}
+	if w.Root == nil {
+		return nil, fmt.Errorf("workflow %s has no root", w.Name)
+	}
+
+	//Join sources are referenced by their position in the workflow
+	refs := map[int64]string{}
+	for _, j := range w.Joins {
+		for _, id := range j.SourceNodeIDs {
+			refs[id] = ""
+		}
+	}
+	var index int
+	var computeRefs func(n *sdk.WorkflowNode)
+	computeRefs = func(n *sdk.WorkflowNode) {
+		index++
+		if _, ok := refs[n.ID]; ok {
+			refs[n.ID] = strconv.Itoa(index)
+		}
+		for i := range n.Triggers {
+			computeRefs(&n.Triggers[i].WorkflowDestNode)
+		}
+	}
+	computeRefs(w.Root)
+	for _, j := range w.Joins {
+		for i := range j.Triggers {
+			computeRefs(&j.Triggers[i].WorkflowDestNode)
+		}
+	}
+
+	root, err := newWorkflowNode(w.Root, refs)
+	if err != nil {
+		return nil, err
+	}
+	wf.Root = *root
+
+	for _, j := range w.Joins {
+		join := WorkflowJoin{}
+		for _, id := range j.SourceNodeIDs {
+			if refs[id] == "" {
+				return nil, fmt.Errorf("unknown join source %d in workflow %s", id, w.Name)
+			}
+			join.Sources = append(join.Sources, refs[id])
+		}
+		for i := range j.Triggers {
+			t := &j.Triggers[i]
+			n, err := newWorkflowNode(&t.WorkflowDestNode, refs)
+			if err != nil {
+				return nil, err
+			}
+			join.Triggers = append(join.Triggers, WorkflowTrigger{
+				Conditions: newWorkflowConditions(t.Conditions),
+				Node:       *n,
+			})
+		}
+		wf.Joins = append(wf.Joins, join)
+	}
+
+	return wf, nil
+}
+
+func newWorkflowNode(n *sdk.WorkflowNode, refs map[int64]string) (*WorkflowNode, error) {
+	node := &WorkflowNode{
+		Name:     n.Name,
+		Ref:      refs[n.ID],
+		Pipeline: n.Pipeline.Name,
+	}
+
+	if n.Context != nil {
+		if n.Context.Application != nil {
+			node.Application = n.Context.Application.Name
+		}
+		if n.Context.Environment != nil && n.Context.Environment.ID != sdk.DefaultEnv.ID {
+			node.Environment = n.Context.Environment.Name
+		}
+		if n.Context.DefaultPayload != nil {
+			btes, err := json.Marshal(n.Context.DefaultPayload)
+			if err != nil {
+				return nil, err
+			}
+			node.Payload = string(btes)
+		}
+		if len(n.Context.DefaultPipelineParameters) > 0 {
+			node.Parameters = make(map[string]VariableValue, len(n.Context.DefaultPipelineParameters))
+			for _, p := range n.Context.DefaultPipelineParameters {
+				node.Parameters[p.Name] = VariableValue{
+					Type:  p.Type,
+					Value: p.Value,
+				}
+			}
+		}
+		if a := n.Context.Approval; a != nil {
+			node.Approval = &WorkflowApproval{
+				Groups:       a.Groups,
+				MinApprovals: a.MinApprovals,
+				Timeout:      a.Timeout,
+			}
+		}
+	}
+
+	for _, h := range n.Hooks {
+		node.Hooks = append(node.Hooks, WorkflowHook{
+			Model:      h.WorkflowHookModel.Name,
+			Config:     h.Config,
+			Conditions: newWorkflowConditions(h.Conditions),
+		})
+	}
+
+	for i := range n.Triggers {
+		t := &n.Triggers[i]
+		dest, err := newWorkflowNode(&t.WorkflowDestNode, refs)
+		if err != nil {
+			return nil, err
+		}
+		node.Triggers = append(node.Triggers, WorkflowTrigger{
+			Conditions: newWorkflowConditions(t.Conditions),
+			Node:       *dest,
+		})
+	}
+
+	return node, nil
+}
+
+func newWorkflowConditions(conditions []sdk.WorkflowTriggerCondition) []WorkflowCondition {
+	var res []WorkflowCondition
+	for _, c := range conditions {
+		res = append(res, WorkflowCondition{
+			Variable: c.Variable,
+			Operator: c.Operator,
+			Value:    c.Value,
+		})
+	}
+	return res
+}
+
+//Workflow returns a sdk.Workflow entity. Pipelines, applications, environments and hook models only have names
+func (w *Workflow) Workflow() (*sdk.Workflow, error) {
+	wf := &sdk.Workflow{
+		Name:        w.Name,
+		Description: w.Description,
+	}
+
+	root, err := w.Root.node()
+	if err != nil {
+		return nil, err
+	}
+	wf.Root = root
+
+	for _, j := range w.Joins {
+		join := sdk.WorkflowNodeJoin{
+			SourceNodeRefs: j.Sources,
+		}
+		for _, t := range j.Triggers {
+			n, err := t.Node.node()
+			if err != nil {
+				return nil, err
+			}
+			join.Triggers = append(join.Triggers, sdk.WorkflowNodeJoinTrigger{
+				WorkflowDestNode: *n,
+				Conditions:       workflowConditions(t.Conditions),
+			})
+		}
+		wf.Joins = append(wf.Joins, join)
+	}
+
+	return wf, nil
+}
+
+func (n *WorkflowNode) node() (*sdk.WorkflowNode, error) {
+	node := &sdk.WorkflowNode{
+		Name:     n.Name,
+		Ref:      n.Ref,
+		Pipeline: sdk.Pipeline{Name: n.Pipeline},
+		Context:  &sdk.WorkflowNodeContext{},
+	}
+
+	if n.Application != "" {
+		node.Context.Application = &sdk.Application{Name: n.Application}
+	}
+	if n.Environment != "" {
+		node.Context.Environment = &sdk.Environment{Name: n.Environment}
+	}
+	if n.Payload != "" {
+		if err := json.Unmarshal([]byte(n.Payload), &node.Context.DefaultPayload); err != nil {
+			return nil, fmt.Errorf("invalid payload on node %s: %s", n.Name, err)
+		}
+	}
+	for k, v := range n.Parameters {
+		node.Context.DefaultPipelineParameters = append(node.Context.DefaultPipelineParameters, sdk.Parameter{
+			Name:  k,
+			Type:  v.Type,
+			Value: v.Value,
+		})
+	}
+	if n.Approval != nil {
+		node.Context.Approval = &sdk.WorkflowNodeApproval{
+			Groups:       n.Approval.Groups,
+			MinApprovals: n.Approval.MinApprovals,
+			Timeout:      n.Approval.Timeout,
+		}
+	}
+
+	for _, h := range n.Hooks {
+		node.Hooks = append(node.Hooks, sdk.WorkflowNodeHook{
+			WorkflowHookModel: sdk.WorkflowHookModel{Name: h.Model},
+			Config:            h.Config,
+			Conditions:        workflowConditions(h.Conditions),
+		})
+	}
+
+	for _, t := range n.Triggers {
+		dest, err := t.Node.node()
+		if err != nil {
+			return nil, err
+		}
+		node.Triggers = append(node.Triggers, sdk.WorkflowNodeTrigger{
+			WorkflowDestNode: *dest,
+			Conditions:       workflowConditions(t.Conditions),
+		})
+	}
+
+	return node, nil
+}
+
+func workflowConditions(conditions []WorkflowCondition) []sdk.WorkflowTriggerCondition {
+	var res []sdk.WorkflowTriggerCondition
+	for _, c := range conditions {
+		res = append(res, sdk.WorkflowTriggerCondition{
+			Variable: c.Variable,
+			Operator: c.Operator,
+			Value:    c.Value,
+		})
+	}
+	return res
+}
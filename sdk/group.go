@@ -20,32 +20,37 @@ type Group struct {
 
 // GroupPermission represent a group and his role in the project
 type GroupPermission struct {
-	Group      Group `json:"group"`
-	Permission int   `json:"permission"`
+	Group      Group  `json:"group"`
+	Permission int    `json:"permission"`
+	Role       string `json:"role,omitempty"`
 }
 
 // EnvironmentGroup represent a link with a pipeline
 type EnvironmentGroup struct {
 	Environment Environment `json:"environment"`
 	Permission  int         `json:"permission"`
+	Role        string      `json:"role,omitempty"`
 }
 
 // ApplicationGroup represent a link with a pipeline
 type ApplicationGroup struct {
 	Application Application `json:"application"`
 	Permission  int         `json:"permission"`
+	Role        string      `json:"role,omitempty"`
 }
 
 // PipelineGroup represent a link with a pipeline
 type PipelineGroup struct {
 	Pipeline   Pipeline `json:"pipeline"`
 	Permission int      `json:"permission"`
+	Role       string   `json:"role,omitempty"`
 }
 
 // ProjectGroup represent a link with a project
 type ProjectGroup struct {
 	Project    Project `json:"project"`
 	Permission int     `json:"permission"`
+	Role       string  `json:"role,omitempty"`
 }
 
 // AddGroup creates a new group
@@ -0,0 +1,443 @@
+// Code generated by protoc-gen-go.
+// source: actionplugin.proto
+// DO NOT EDIT!
+
+/*
+Package grpcplugin is a generated protocol buffer package.
+
+It is generated from these files:
+	actionplugin.proto
+
+It has these top-level messages:
+	ActionPluginManifest
+	ActionParameter
+	ActionQuery
+	ActionEvent
+	ActionVariable
+	ActionResult
+*/
+package grpcplugin
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/empty"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// ActionPluginManifest describes an action plugin and its parameters
+type ActionPluginManifest struct {
+	Name        string             `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Version     string             `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	Description string             `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+	Author      string             `protobuf:"bytes,4,opt,name=author" json:"author,omitempty"`
+	Parameters  []*ActionParameter `protobuf:"bytes,5,rep,name=parameters" json:"parameters,omitempty"`
+}
+
+func (m *ActionPluginManifest) Reset()                    { *m = ActionPluginManifest{} }
+func (m *ActionPluginManifest) String() string            { return proto.CompactTextString(m) }
+func (*ActionPluginManifest) ProtoMessage()               {}
+func (*ActionPluginManifest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ActionPluginManifest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ActionPluginManifest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ActionPluginManifest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *ActionPluginManifest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *ActionPluginManifest) GetParameters() []*ActionParameter {
+	if m != nil {
+		return m.Parameters
+	}
+	return nil
+}
+
+// ActionParameter is a parameter of an action plugin, its type is a CDS parameter type
+type ActionParameter struct {
+	Name        string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Type        string `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	Value       string `protobuf:"bytes,3,opt,name=value" json:"value,omitempty"`
+	Description string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+}
+
+func (m *ActionParameter) Reset()                    { *m = ActionParameter{} }
+func (m *ActionParameter) String() string            { return proto.CompactTextString(m) }
+func (*ActionParameter) ProtoMessage()               {}
+func (*ActionParameter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ActionParameter) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ActionParameter) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ActionParameter) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *ActionParameter) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+// ActionQuery is sent by the worker to run an action plugin
+type ActionQuery struct {
+	Options   map[string]string `protobuf:"bytes,1,rep,name=options" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Secrets   map[string]string `protobuf:"bytes,2,rep,name=secrets" json:"secrets,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	JobId     int64             `protobuf:"varint,3,opt,name=job_id,json=jobId" json:"job_id,omitempty"`
+	StepOrder int64             `protobuf:"varint,4,opt,name=step_order,json=stepOrder" json:"step_order,omitempty"`
+}
+
+func (m *ActionQuery) Reset()                    { *m = ActionQuery{} }
+func (m *ActionQuery) String() string            { return proto.CompactTextString(m) }
+func (*ActionQuery) ProtoMessage()               {}
+func (*ActionQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ActionQuery) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *ActionQuery) GetSecrets() map[string]string {
+	if m != nil {
+		return m.Secrets
+	}
+	return nil
+}
+
+func (m *ActionQuery) GetJobId() int64 {
+	if m != nil {
+		return m.JobId
+	}
+	return 0
+}
+
+func (m *ActionQuery) GetStepOrder() int64 {
+	if m != nil {
+		return m.StepOrder
+	}
+	return 0
+}
+
+// ActionEvent is streamed by an action plugin while it runs: a log line, an output variable,
+// the path of an artifact to upload or the final result
+type ActionEvent struct {
+	Log      string          `protobuf:"bytes,1,opt,name=log" json:"log,omitempty"`
+	Variable *ActionVariable `protobuf:"bytes,2,opt,name=variable" json:"variable,omitempty"`
+	Artifact string          `protobuf:"bytes,3,opt,name=artifact" json:"artifact,omitempty"`
+	Result   *ActionResult   `protobuf:"bytes,4,opt,name=result" json:"result,omitempty"`
+}
+
+func (m *ActionEvent) Reset()                    { *m = ActionEvent{} }
+func (m *ActionEvent) String() string            { return proto.CompactTextString(m) }
+func (*ActionEvent) ProtoMessage()               {}
+func (*ActionEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *ActionEvent) GetLog() string {
+	if m != nil {
+		return m.Log
+	}
+	return ""
+}
+
+func (m *ActionEvent) GetVariable() *ActionVariable {
+	if m != nil {
+		return m.Variable
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetArtifact() string {
+	if m != nil {
+		return m.Artifact
+	}
+	return ""
+}
+
+func (m *ActionEvent) GetResult() *ActionResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// ActionVariable is an output variable of an action plugin
+type ActionVariable struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *ActionVariable) Reset()                    { *m = ActionVariable{} }
+func (m *ActionVariable) String() string            { return proto.CompactTextString(m) }
+func (*ActionVariable) ProtoMessage()               {}
+func (*ActionVariable) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *ActionVariable) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ActionVariable) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// ActionResult is the result of an action plugin run
+type ActionResult struct {
+	Success bool   `protobuf:"varint,1,opt,name=success" json:"success,omitempty"`
+	Reason  string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *ActionResult) Reset()                    { *m = ActionResult{} }
+func (m *ActionResult) String() string            { return proto.CompactTextString(m) }
+func (*ActionResult) ProtoMessage()               {}
+func (*ActionResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *ActionResult) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *ActionResult) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ActionPluginManifest)(nil), "grpcplugin.ActionPluginManifest")
+	proto.RegisterType((*ActionParameter)(nil), "grpcplugin.ActionParameter")
+	proto.RegisterType((*ActionQuery)(nil), "grpcplugin.ActionQuery")
+	proto.RegisterType((*ActionEvent)(nil), "grpcplugin.ActionEvent")
+	proto.RegisterType((*ActionVariable)(nil), "grpcplugin.ActionVariable")
+	proto.RegisterType((*ActionResult)(nil), "grpcplugin.ActionResult")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for ActionPlugin service
+
+type ActionPluginClient interface {
+	Manifest(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*ActionPluginManifest, error)
+	Run(ctx context.Context, in *ActionQuery, opts ...grpc.CallOption) (ActionPlugin_RunClient, error)
+}
+
+type actionPluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewActionPluginClient(cc *grpc.ClientConn) ActionPluginClient {
+	return &actionPluginClient{cc}
+}
+
+func (c *actionPluginClient) Manifest(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*ActionPluginManifest, error) {
+	out := new(ActionPluginManifest)
+	err := grpc.Invoke(ctx, "/grpcplugin.ActionPlugin/Manifest", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actionPluginClient) Run(ctx context.Context, in *ActionQuery, opts ...grpc.CallOption) (ActionPlugin_RunClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ActionPlugin_serviceDesc.Streams[0], c.cc, "/grpcplugin.ActionPlugin/Run", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actionPluginRunClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActionPlugin_RunClient interface {
+	Recv() (*ActionEvent, error)
+	grpc.ClientStream
+}
+
+type actionPluginRunClient struct {
+	grpc.ClientStream
+}
+
+func (x *actionPluginRunClient) Recv() (*ActionEvent, error) {
+	m := new(ActionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for ActionPlugin service
+
+type ActionPluginServer interface {
+	Manifest(context.Context, *google_protobuf.Empty) (*ActionPluginManifest, error)
+	Run(*ActionQuery, ActionPlugin_RunServer) error
+}
+
+func RegisterActionPluginServer(s *grpc.Server, srv ActionPluginServer) {
+	s.RegisterService(&_ActionPlugin_serviceDesc, srv)
+}
+
+func _ActionPlugin_Manifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActionPluginServer).Manifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcplugin.ActionPlugin/Manifest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionPluginServer).Manifest(ctx, req.(*google_protobuf.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActionPlugin_Run_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ActionQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActionPluginServer).Run(m, &actionPluginRunServer{stream})
+}
+
+type ActionPlugin_RunServer interface {
+	Send(*ActionEvent) error
+	grpc.ServerStream
+}
+
+type actionPluginRunServer struct {
+	grpc.ServerStream
+}
+
+func (x *actionPluginRunServer) Send(m *ActionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _ActionPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "grpcplugin.ActionPlugin",
+	HandlerType: (*ActionPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Manifest",
+			Handler:    _ActionPlugin_Manifest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Run",
+			Handler:       _ActionPlugin_Run_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "actionplugin.proto",
+}
+
+func init() { proto.RegisterFile("actionplugin.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 512 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0xad, 0xe3, 0x24, 0x4d, 0x27, 0x11, 0xa0, 0x51, 0x29, 0x96, 0x2b, 0xa4, 0xc8, 0xe2, 0xd0,
+	0x93, 0x5b, 0x05, 0x09, 0xa1, 0x54, 0x42, 0x70, 0x08, 0x12, 0x07, 0x54, 0x58, 0x24, 0xae, 0xd5,
+	0xc6, 0x99, 0x18, 0x17, 0xc7, 0x6b, 0x76, 0xd7, 0x91, 0xf2, 0x1d, 0x7c, 0x03, 0x5f, 0xd1, 0x9f,
+	0x43, 0xbb, 0x5e, 0xa7, 0x6e, 0x13, 0x90, 0x7a, 0xdb, 0x99, 0x79, 0x6f, 0xf7, 0xcd, 0xb3, 0x1f,
+	0x20, 0x4f, 0x74, 0x26, 0x8a, 0x32, 0xaf, 0xd2, 0xac, 0x88, 0x4b, 0x29, 0xb4, 0x40, 0x48, 0x65,
+	0x99, 0xd4, 0x9d, 0xf0, 0x34, 0x15, 0x22, 0xcd, 0xe9, 0xdc, 0x4e, 0xe6, 0xd5, 0xf2, 0x9c, 0x56,
+	0xa5, 0xde, 0xd4, 0xc0, 0xe8, 0xd6, 0x83, 0xe3, 0x0f, 0x96, 0xff, 0xc5, 0xa2, 0x3f, 0xf3, 0x22,
+	0x5b, 0x92, 0xd2, 0x88, 0xd0, 0x2d, 0xf8, 0x8a, 0x02, 0x6f, 0xec, 0x9d, 0x1d, 0x31, 0x7b, 0xc6,
+	0x00, 0x0e, 0xd7, 0x24, 0x55, 0x26, 0x8a, 0xa0, 0x63, 0xdb, 0x4d, 0x89, 0x63, 0x18, 0x2e, 0x48,
+	0x25, 0x32, 0x2b, 0xcd, 0x55, 0x81, 0x6f, 0xa7, 0xed, 0x16, 0x9e, 0x40, 0x9f, 0x57, 0xfa, 0x87,
+	0x90, 0x41, 0xd7, 0x0e, 0x5d, 0x85, 0x97, 0x00, 0x25, 0x97, 0x7c, 0x45, 0x9a, 0xa4, 0x0a, 0x7a,
+	0x63, 0xff, 0x6c, 0x38, 0x39, 0x8d, 0xef, 0xe4, 0xc7, 0x4e, 0x5d, 0x83, 0x61, 0x2d, 0x78, 0xf4,
+	0x0b, 0x9e, 0x3e, 0x18, 0xef, 0xd5, 0x8d, 0xd0, 0xd5, 0x9b, 0x92, 0x9c, 0x68, 0x7b, 0xc6, 0x63,
+	0xe8, 0xad, 0x79, 0x5e, 0x91, 0xd3, 0x5a, 0x17, 0x0f, 0xf7, 0xe8, 0xee, 0xec, 0x11, 0xdd, 0x76,
+	0x60, 0x58, 0xbf, 0xf9, 0xb5, 0x22, 0xb9, 0xc1, 0x77, 0x70, 0x28, 0xec, 0x44, 0x05, 0x9e, 0x15,
+	0xff, 0x6a, 0x57, 0xbc, 0x45, 0xc6, 0x57, 0x35, 0x6c, 0x56, 0x68, 0xb9, 0x61, 0x0d, 0xc9, 0xf0,
+	0x15, 0x25, 0x92, 0xb4, 0x0a, 0x3a, 0xff, 0xe7, 0x7f, 0xab, 0x61, 0x8e, 0xef, 0x48, 0xf8, 0x1c,
+	0xfa, 0x37, 0x62, 0x7e, 0x9d, 0x2d, 0xec, 0x22, 0x3e, 0xeb, 0xdd, 0x88, 0xf9, 0xa7, 0x05, 0xbe,
+	0x04, 0x50, 0x9a, 0xca, 0x6b, 0x21, 0x17, 0x54, 0x5b, 0xee, 0xb3, 0x23, 0xd3, 0xb9, 0x32, 0x8d,
+	0x70, 0x0a, 0xa3, 0xb6, 0x1c, 0x7c, 0x06, 0xfe, 0x4f, 0xda, 0x38, 0xd3, 0xcc, 0xf1, 0xce, 0x9f,
+	0x4e, 0xcb, 0x9f, 0x69, 0xe7, 0xad, 0x67, 0xb8, 0x6d, 0x29, 0x8f, 0xe1, 0x46, 0x7f, 0xbc, 0xc6,
+	0xbd, 0xd9, 0x9a, 0x0a, 0x6d, 0xb8, 0xb9, 0x48, 0x1b, 0x6e, 0x2e, 0x52, 0x7c, 0x03, 0x83, 0x35,
+	0x97, 0x19, 0x9f, 0xe7, 0x35, 0x7d, 0x38, 0x09, 0x77, 0x0d, 0xf9, 0xee, 0x10, 0x6c, 0x8b, 0xc5,
+	0x10, 0x06, 0x5c, 0xea, 0x6c, 0xc9, 0x13, 0xed, 0x3e, 0xe9, 0xb6, 0xc6, 0x0b, 0xe8, 0x4b, 0x52,
+	0x55, 0xae, 0xad, 0x11, 0xc3, 0x49, 0xb0, 0x7b, 0x23, 0xb3, 0x73, 0xe6, 0x70, 0xd1, 0x14, 0x9e,
+	0xdc, 0x7f, 0x69, 0xef, 0x7f, 0xb5, 0x77, 0xcf, 0xe8, 0x3d, 0x8c, 0xda, 0x77, 0x9a, 0xd4, 0xa8,
+	0x2a, 0x49, 0x48, 0x29, 0x4b, 0x1e, 0xb0, 0xa6, 0x34, 0x99, 0x90, 0xc4, 0xd5, 0x36, 0x4e, 0xae,
+	0x9a, 0xfc, 0xf6, 0x60, 0xd4, 0x0e, 0x25, 0x7e, 0x84, 0xc1, 0x36, 0x98, 0x27, 0x71, 0x9d, 0xe7,
+	0xb8, 0xc9, 0x73, 0x3c, 0x33, 0x79, 0x0e, 0xc7, 0x7b, 0x42, 0x73, 0x2f, 0xd2, 0xd1, 0x01, 0x5e,
+	0x82, 0xcf, 0xaa, 0x02, 0x5f, 0xfc, 0xe3, 0x17, 0x0b, 0xf7, 0x0c, 0xec, 0x77, 0x8a, 0x0e, 0x2e,
+	0xbc, 0x79, 0xdf, 0x3e, 0xf8, 0xfa, 0xef, 0x00, 0xcd, 0x11, 0x1f, 0x54, 0x70, 0x04, 0x00, 0x00,
+}
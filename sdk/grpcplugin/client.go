@@ -0,0 +1,147 @@
+package grpcplugin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// ErrNotGRPCPlugin is returned when a binary doesn't implement the GRPC plugin protocol, it may be a net/rpc plugin
+var ErrNotGRPCPlugin = errors.New("not a GRPC plugin")
+
+// handshakeTimeout is the time let to a plugin to start
+const handshakeTimeout = 10 * time.Second
+
+// Client is a running GRPC action plugin
+type Client struct {
+	ActionPluginClient
+	cmd  *exec.Cmd
+	conn *grpc.ClientConn
+}
+
+// Start starts a plugin binary and connects to it. The plugin process is killed when the context is done.
+// ErrNotGRPCPlugin is returned if the binary doesn't complete the handshake
+func Start(ctx context.Context, binary string) (*Client, error) {
+	cmd := exec.CommandContext(ctx, binary)
+	cmd.Env = append(environ(), MagicCookieKey+"="+MagicCookieValue)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	lines := make(chan string, 1)
+	go func() {
+		r := bufio.NewReader(stdout)
+		line, err := r.ReadString('\n')
+		if err != nil {
+			close(lines)
+			return
+		}
+		lines <- line
+		io.Copy(os.Stdout, r)
+	}()
+
+	var line string
+	select {
+	case l, ok := <-lines:
+		line = l
+		if !ok {
+			cmd.Wait()
+			return nil, ErrNotGRPCPlugin
+		}
+	case <-time.After(handshakeTimeout):
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, ErrNotGRPCPlugin
+	}
+
+	t := strings.Split(strings.TrimSpace(line), "|")
+	if len(t) != 4 || t[0] != handshakePrefix || t[2] != "tcp" {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, ErrNotGRPCPlugin
+	}
+	if t[1] != fmt.Sprintf("%d", ProtocolVersion) {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("unsupported GRPC plugin protocol version %s", t[1])
+	}
+
+	conn, err := grpc.Dial(t[3], grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(handshakeTimeout))
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+
+	return &Client{ActionPluginClient: NewActionPluginClient(conn), cmd: cmd, conn: conn}, nil
+}
+
+// environ returns the environment of the plugin: the environment of the worker without its technical variables
+func environ() []string {
+	env := []string{}
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, "CDS_MODEL=") ||
+			strings.HasPrefix(e, "CDS_TTL=") ||
+			strings.HasPrefix(e, "CDS_SINGLE_USE=") ||
+			strings.HasPrefix(e, "CDS_NAME=") ||
+			strings.HasPrefix(e, "CDS_TOKEN=") ||
+			strings.HasPrefix(e, "CDS_API=") ||
+			strings.HasPrefix(e, "CDS_HATCHERY=") {
+			continue
+		}
+		env = append(env, e)
+	}
+	return env
+}
+
+// GetManifest returns the manifest of the plugin
+func (c *Client) GetManifest(ctx context.Context) (*ActionPluginManifest, error) {
+	return c.ActionPluginClient.Manifest(ctx, &empty.Empty{})
+}
+
+// Exec runs the plugin and calls handle for each log, variable and artifact event until the final result.
+// Canceling the context cancels the run on the plugin side
+func (c *Client) Exec(ctx context.Context, q *ActionQuery, handle func(*ActionEvent) error) (*ActionResult, error) {
+	stream, err := c.ActionPluginClient.Run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("plugin stopped without result")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if e.Result != nil {
+			return e.Result, nil
+		}
+		if err := handle(e); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Kill closes the connection to the plugin and stops the plugin process
+func (c *Client) Kill() {
+	c.conn.Close()
+	if c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+	c.cmd.Wait()
+}
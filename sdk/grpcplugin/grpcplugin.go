@@ -0,0 +1,84 @@
+package grpcplugin
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Handshake between the worker and the plugins: the worker starts the plugin with the magic cookie in its environment,
+// the plugin writes on its standard output the line "CDS_GRPC_PLUGIN|<protocol version>|tcp|<address>" and serves on this address
+const (
+	MagicCookieKey   = "CDS_GRPC_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "Q0RTX0dSUENfUExVR0lOX01BR0lDX0NPT0tJRQ=="
+	ProtocolVersion  = 1
+	handshakePrefix  = "CDS_GRPC_PLUGIN"
+)
+
+// Action is implemented by the action plugins using the GRPC protocol
+type Action interface {
+	// Manifest describes the plugin and its parameters
+	Manifest() *ActionPluginManifest
+	// Run runs the plugin. The context is canceled when the job is canceled,
+	// logs, output variables and artifacts are sent to the worker while the plugin runs
+	Run(ctx context.Context, q *ActionQuery, out *Output) error
+}
+
+// Output sends the events of a plugin run to the worker
+type Output struct {
+	stream ActionPlugin_RunServer
+}
+
+// Log sends a log line to the worker
+func (o *Output) Log(format string, args ...interface{}) error {
+	return o.stream.Send(&ActionEvent{Log: fmt.Sprintf(format, args...)})
+}
+
+// SetVariable exports a variable to the next steps of the job, available as {{.cds.build.<name>}}
+func (o *Output) SetVariable(name, value string) error {
+	return o.stream.Send(&ActionEvent{Variable: &ActionVariable{Name: name, Value: value}})
+}
+
+// UploadArtifact asks the worker to upload a file as an artifact of the job
+func (o *Output) UploadArtifact(path string) error {
+	return o.stream.Send(&ActionEvent{Artifact: path})
+}
+
+// Serve has to be called in main func of every GRPC action plugin
+func Serve(a Action) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		fmt.Fprintln(os.Stderr, "This binary is a CDS action plugin, it's not meant to be executed directly")
+		os.Exit(1)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	s := grpc.NewServer()
+	RegisterActionPluginServer(s, &server{impl: a})
+
+	fmt.Printf("%s|%d|tcp|%s\n", handshakePrefix, ProtocolVersion, lis.Addr())
+	return s.Serve(lis)
+}
+
+type server struct {
+	impl Action
+}
+
+func (s *server) Manifest(ctx context.Context, _ *empty.Empty) (*ActionPluginManifest, error) {
+	return s.impl.Manifest(), nil
+}
+
+func (s *server) Run(q *ActionQuery, stream ActionPlugin_RunServer) error {
+	res := &ActionResult{Success: true}
+	if err := s.impl.Run(stream.Context(), q, &Output{stream: stream}); err != nil {
+		res = &ActionResult{Success: false, Reason: err.Error()}
+	}
+	return stream.Send(&ActionEvent{Result: res})
+}
@@ -0,0 +1,78 @@
+package grpcplugin
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type testAction struct{}
+
+func (testAction) Manifest() *ActionPluginManifest {
+	return &ActionPluginManifest{
+		Name:       "plugin-test",
+		Version:    "1.0.0",
+		Parameters: []*ActionParameter{{Name: "message", Type: "string", Value: "hello"}},
+	}
+}
+
+func (testAction) Run(ctx context.Context, q *ActionQuery, out *Output) error {
+	if q.Options["fail"] != "" {
+		return errors.New(q.Options["fail"])
+	}
+	out.Log("%s %s", q.Options["message"], q.Secrets["name"])
+	out.SetVariable("version", "1.2.3")
+	out.UploadArtifact("report.xml")
+	return nil
+}
+
+// The test binary is the plugin when it's started by the tests
+func TestMain(m *testing.M) {
+	if os.Getenv(MagicCookieKey) == MagicCookieValue {
+		Serve(testAction{})
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func TestExec(t *testing.T) {
+	c, err := Start(context.Background(), os.Args[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Kill()
+
+	m, err := c.GetManifest(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "plugin-test", m.Name)
+	assert.Equal(t, "hello", m.Parameters[0].Value)
+
+	events := []*ActionEvent{}
+	res, err := c.Exec(context.Background(), &ActionQuery{
+		Options: map[string]string{"message": "hello"},
+		Secrets: map[string]string{"name": "world"},
+	}, func(e *ActionEvent) error {
+		events = append(events, e)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, res.Success)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, "hello world", events[0].Log)
+		assert.Equal(t, "version", events[1].Variable.Name)
+		assert.Equal(t, "report.xml", events[2].Artifact)
+	}
+
+	res, err = c.Exec(context.Background(), &ActionQuery{Options: map[string]string{"fail": "boom"}}, func(e *ActionEvent) error { return nil })
+	assert.NoError(t, err)
+	assert.False(t, res.Success)
+	assert.Equal(t, "boom", res.Reason)
+}
+
+func TestStartNotGRPCPlugin(t *testing.T) {
+	_, err := Start(context.Background(), "false")
+	assert.Equal(t, ErrNotGRPCPlugin, err)
+}
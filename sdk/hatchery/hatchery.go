@@ -1,6 +1,7 @@
 package hatchery
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"sync/atomic"
@@ -11,6 +12,7 @@ import (
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // Interface describe an interface for each hatchery mode (mesos, local)
@@ -47,7 +49,7 @@ func CheckRequirement(r sdk.Requirement) (bool, error) {
 	}
 }
 
-func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID int64, jobQueuedSeconds int64, jobBookedBy sdk.Hatchery, requirements []sdk.Requirement, models []sdk.Model, nRoutines *int64, spawnIDs *cache.Cache, warningSeconds, criticalSeconds, graceSeconds int, hostname string) bool {
+func receiveJob(ctx context.Context, h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID int64, jobQueuedSeconds int64, jobBookedBy sdk.Hatchery, requirements []sdk.Requirement, models []sdk.Model, nRoutines *int64, spawnIDs *cache.Cache, warningSeconds, criticalSeconds, graceSeconds int, hostname string) bool {
 	if jobID == 0 {
 		return false
 	}
@@ -80,14 +82,14 @@ func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID i
 
 	atomic.AddInt64(nRoutines, 1)
 	defer atomic.AddInt64(nRoutines, -1)
-	if errR := routine(h, isWorkflowJob, models, execGroups, jobID, requirements, hostname, time.Now().Unix(), warningSeconds, criticalSeconds, graceSeconds); errR != nil {
+	if errR := routine(ctx, h, isWorkflowJob, models, execGroups, jobID, requirements, hostname, time.Now().Unix(), warningSeconds, criticalSeconds, graceSeconds); errR != nil {
 		log.Warning("Error on routine: %s", errR)
 		return false
 	}
 	return true
 }
 
-func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, hostname string, timestamp int64, warningSeconds, criticalSeconds, graceSeconds int) error {
+func routine(ctx context.Context, h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, hostname string, timestamp int64, warningSeconds, criticalSeconds, graceSeconds int) error {
 	defer logTime(fmt.Sprintf("routine> %d", timestamp), time.Now(), warningSeconds, criticalSeconds)
 	log.Debug("routine> %d enter", timestamp)
 
@@ -101,9 +103,16 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 	}
 	log.Debug("routine> %d - models received: %d", timestamp, len(models))
 
+	//The spawn is traced in the trace of the job
+	ctx, span := tracing.Start(ctx, "hatchery.SpawnWorker")
+	span.SetAttribute("hatchery", h.Hatchery().Name)
+	span.SetAttribute("job_id", jobID)
+	defer span.Finish()
+	client := h.Client().WithContext(ctx)
+
 	for _, model := range models {
 		if canRunJob(h, timestamp, execGroups, jobID, requirements, &model, hostname) {
-			if err := h.Client().QueueJobBook(isWorkflowJob, jobID); err != nil {
+			if err := client.QueueJobBook(isWorkflowJob, jobID); err != nil {
 				// perhaps already booked by another hatchery
 				log.Debug("routine> %d - cannot book job %d %s: %s", timestamp, jobID, model.Name, err)
 				break // go to next job
@@ -113,21 +122,24 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 			start := time.Now()
 			infos := []sdk.SpawnInfo{
 				{
-					RemoteTime: start,
-					Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryStarts.ID, Args: []interface{}{fmt.Sprintf("%s", h.Hatchery().Name), fmt.Sprintf("%d", h.Hatchery().ID), model.Name}},
+					RemoteTime:  start,
+					TraceParent: span.TraceParent(),
+					Message:     sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryStarts.ID, Args: []interface{}{fmt.Sprintf("%s", h.Hatchery().Name), fmt.Sprintf("%d", h.Hatchery().ID), model.Name}},
 				},
 			}
-			workerName, errSpawn := h.SpawnWorker(&model, jobID, requirements, false, "spawn for job")
+			span.SetAttribute("model", model.Name)
+			workerName, errSpawn := spawnWorker(h, &model, jobID, requirements, false, "spawn for job")
 			if errSpawn != nil {
+				span.SetError(errSpawn)
 				log.Warning("routine> %d - cannot spawn worker %s for job %d: %s", timestamp, model.Name, jobID, errSpawn)
 				infos = append(infos, sdk.SpawnInfo{
 					RemoteTime: time.Now(),
 					Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryErrorSpawn.ID, Args: []interface{}{fmt.Sprintf("%s", h.Hatchery().Name), fmt.Sprintf("%d", h.Hatchery().ID), model.Name, sdk.Round(time.Since(start), time.Second).String(), errSpawn.Error()}},
 				})
-				if err := h.Client().QueueJobSendSpawnInfo(isWorkflowJob, jobID, infos); err != nil {
+				if err := client.QueueJobSendSpawnInfo(isWorkflowJob, jobID, infos); err != nil {
 					log.Warning("routine> %d - cannot client.QueueJobSendSpawnInfo for job (err spawn)%d: %s", timestamp, jobID, err)
 				}
-				if err := h.Client().WorkerModelSpawnError(model.ID, fmt.Sprintf("routine> cannot spawn worker %s for job %d: %s", model.Name, jobID, errSpawn)); err != nil {
+				if err := client.WorkerModelSpawnError(model.ID, fmt.Sprintf("routine> cannot spawn worker %s for job %d: %s", model.Name, jobID, errSpawn)); err != nil {
 					log.Error("routine> error on call client.WorkerModelSpawnError on worker model %s for register: %s", model.Name, errSpawn)
 				}
 				continue // try another model
@@ -144,7 +156,7 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 				},
 			})
 
-			if err := h.Client().QueueJobSendSpawnInfo(isWorkflowJob, jobID, infos); err != nil {
+			if err := client.QueueJobSendSpawnInfo(isWorkflowJob, jobID, infos); err != nil {
 				log.Warning("routine> %d - cannot client.QueueJobSendSpawnInfo for job %d: %s", timestamp, jobID, err)
 			}
 			break // ok for this job
@@ -165,7 +177,7 @@ func provisioning(h Interface, provisionDisabled bool, models []sdk.Model) {
 			existing := h.WorkersStartedByModel(&models[k])
 			for i := existing; i < int(models[k].Provision); i++ {
 				go func(m sdk.Model) {
-					if name, errSpawn := h.SpawnWorker(&m, 0, nil, false, "spawn for provision"); errSpawn != nil {
+					if name, errSpawn := spawnWorker(h, &m, 0, nil, false, "spawn for provision"); errSpawn != nil {
 						log.Warning("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
 						if err := h.Client().WorkerModelSpawnError(m.ID, fmt.Sprintf("routine> cannot spawn worker %s for provisioning: %s", m.Name, errSpawn)); err != nil {
 							log.Error("provisioning> cannot client.WorkerModelSpawnError for worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
@@ -0,0 +1,26 @@
+package hatchery
+
+import (
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/metrics"
+)
+
+var (
+	spawnTotal    = metrics.NewCounterVec("cds_hatchery_spawns_total", "Number of workers spawned by model and status", "model", "status")
+	spawnDuration = metrics.NewHistogramVec("cds_hatchery_spawn_duration_seconds", "Duration of the spawn of the workers by model", metrics.LongBuckets, "model")
+)
+
+// spawnWorker spawns a worker and records the status and the duration of the spawn
+func spawnWorker(h Interface, model *sdk.Model, jobID int64, requirements []sdk.Requirement, registerOnly bool, logInfo string) (string, error) {
+	start := time.Now()
+	name, err := h.SpawnWorker(model, jobID, requirements, registerOnly, logInfo)
+	spawnDuration.Observe(time.Since(start).Seconds(), model.Name)
+	if err != nil {
+		spawnTotal.Inc(model.Name, "failure")
+		return name, err
+	}
+	spawnTotal.Inc(model.Name, "success")
+	return name, nil
+}
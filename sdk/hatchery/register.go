@@ -14,6 +14,7 @@ import (
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // Create creates hatchery
@@ -106,7 +107,7 @@ func Create(h Interface, name, api, token string, maxWorkers int64, provisionDis
 				continue
 			}
 			go func(job sdk.PipelineBuildJob) {
-				if isRun := receiveJob(h, false, job.ExecGroups, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, warningSeconds, criticalSeconds, graceSeconds, hostname); isRun {
+				if isRun := receiveJob(context.Background(), h, false, job.ExecGroups, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, warningSeconds, criticalSeconds, graceSeconds, hostname); isRun {
 					atomic.AddInt64(&workersStarted, 1)
 					spawnIDs.SetDefault(string(job.ID), job.ID)
 				}
@@ -117,7 +118,8 @@ func Create(h Interface, name, api, token string, maxWorkers int64, provisionDis
 				continue
 			}
 			go func(job sdk.WorkflowNodeJobRun) {
-				if isRun := receiveJob(h, true, nil, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, warningSeconds, criticalSeconds, graceSeconds, hostname); isRun {
+				ctx := tracing.ContextWithTraceParent(context.Background(), job.TraceParent)
+				if isRun := receiveJob(ctx, h, true, nil, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, warningSeconds, criticalSeconds, graceSeconds, hostname); isRun {
 					atomic.AddInt64(&workersStarted, 1)
 					spawnIDs.SetDefault(string(job.ID), job.ID)
 				}
@@ -231,7 +233,7 @@ func workerRegister(h Interface, models []sdk.Model) error {
 		}
 		if h.NeedRegistration(&m) {
 			log.Info("workerRegister> spawn a worker for register worker model %s (%d)", m.Name, m.ID)
-			if _, errSpawn := h.SpawnWorker(&m, 0, nil, true, "spawn for register"); errSpawn != nil {
+			if _, errSpawn := spawnWorker(h, &m, 0, nil, true, "spawn for register"); errSpawn != nil {
 				log.Warning("workerRegister> cannot spawn worker for register: %s", m.Name, errSpawn)
 				if err := h.Client().WorkerModelSpawnError(m.ID, fmt.Sprintf("workerRegister> cannot spawn worker for register: %s", errSpawn)); err != nil {
 					log.Error("workerRegister> error on call client.WorkerModelSpawnError on worker model %s for register: %s", m.Name, errSpawn)
@@ -1,11 +1,9 @@
 package sdk
 
 import (
-	"bytes"
-	"fmt"
-	"html/template"
-	"regexp"
 	"strings"
+
+	"github.com/ovh/cds/sdk/interpolate"
 )
 
 // InterpolateFilterFunc is the type of a filter func
@@ -37,39 +35,24 @@ var InterpolateFilters = struct {
 	},
 }
 
-// Interpolate returns interpolated input with vars
+// Interpolate returns interpolated input with vars. The expressions referencing unknown variables are kept as is,
+// see the package github.com/ovh/cds/sdk/interpolate for the syntax and the helpers
 func Interpolate(input string, vars map[string]string, filters ...InterpolateFilterFunc) (string, error) {
-	data := map[string]string{}
+	return InterpolateWithOptions(input, vars, interpolate.Options{}, filters...)
+}
 
-	for k, v := range vars {
-		kb := strings.Replace(k, ".", "__", -1)
-		data[kb] = v
-		re := regexp.MustCompile("{{." + k + "(.*)}}")
-		for {
-			sm := re.FindStringSubmatch(input)
-			if len(sm) > 0 {
-				input = strings.Replace(input, sm[0], "{{."+kb+sm[1]+"}}", -1)
-			} else {
-				break
-			}
+// InterpolateWithOptions returns interpolated input with vars, with the mode of the interpolation for the unknown variables
+func InterpolateWithOptions(input string, vars map[string]string, opts interpolate.Options, filters ...InterpolateFilterFunc) (string, error) {
+	if len(filters) > 0 {
+		funcs := map[string]interface{}{}
+		for k, f := range opts.Funcs {
+			funcs[k] = f
 		}
+		for i := range filters {
+			s, fun := filters[i]()
+			funcs[s] = fun
+		}
+		opts.Funcs = funcs
 	}
-
-	funcMap := template.FuncMap{}
-	for i := range filters {
-		s, fun := filters[i]()
-		funcMap[s] = fun
-	}
-
-	t, err := template.New("input").Funcs(funcMap).Parse(input)
-	if err != nil {
-		return "", fmt.Errorf("Invalid template format: %s", err.Error())
-	}
-
-	var buff bytes.Buffer
-	if err := t.Execute(&buff, data); err != nil {
-		return "", fmt.Errorf("Failed to execute template: %s", err.Error())
-	}
-
-	return buff.String(), nil
+	return interpolate.DoWithOptions(input, vars, opts)
 }
@@ -0,0 +1,184 @@
+package interpolate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/blang/semver"
+)
+
+// defaultFuncs are the helpers available in the expressions. The piped value is the last argument of the helpers:
+// {{.x | replace "a" "b"}} calls replace("a", "b", x)
+var defaultFuncs = template.FuncMap{
+	"default": defaultValue,
+
+	// strings
+	"title":      strings.Title,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"escape":     escape,
+	"trim":       strings.TrimSpace,
+	"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+	"truncate":   truncate,
+	"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+
+	// base64
+	"b64enc": func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+	"b64dec": b64dec,
+
+	// regex
+	"regexReplace": regexReplace,
+	"regexFind":    regexFind,
+
+	// semver
+	"semverMajor":      func(s string) (uint64, error) { v, err := parseSemver(s); return v.Major, err },
+	"semverMinor":      func(s string) (uint64, error) { v, err := parseSemver(s); return v.Minor, err },
+	"semverPatch":      func(s string) (uint64, error) { v, err := parseSemver(s); return v.Patch, err },
+	"semverPrerelease": semverPrerelease,
+	"semverBumpMajor":  func(s string) (string, error) { return semverBump(s, "major") },
+	"semverBumpMinor":  func(s string) (string, error) { return semverBump(s, "minor") },
+	"semverBumpPatch":  func(s string) (string, error) { return semverBump(s, "patch") },
+
+	// json
+	"jsonPath": jsonPath,
+}
+
+// defaultValue returns def if the value is empty or if the variable is unknown
+func defaultValue(def string, value string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// escape replaces the characters _ / and . by -
+func escape(s string) string {
+	s = strings.Replace(s, "_", "-", -1)
+	s = strings.Replace(s, "/", "-", -1)
+	return strings.Replace(s, ".", "-", -1)
+}
+
+func truncate(n int, s string) string {
+	if n < 0 || len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+func b64dec(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 value: %v", err)
+	}
+	return string(b), nil
+}
+
+func regexReplace(expr, repl, s string) (string, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(s, repl), nil
+}
+
+func regexFind(expr, s string) (string, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	return re.FindString(s), nil
+}
+
+func parseSemver(s string) (semver.Version, error) {
+	v, err := semver.ParseTolerant(s)
+	if err != nil {
+		return v, fmt.Errorf("invalid semantic version %s: %v", s, err)
+	}
+	return v, nil
+}
+
+func semverPrerelease(s string) (string, error) {
+	v, err := parseSemver(s)
+	if err != nil {
+		return "", err
+	}
+	pre := make([]string, len(v.Pre))
+	for i := range v.Pre {
+		pre[i] = v.Pre[i].String()
+	}
+	return strings.Join(pre, "."), nil
+}
+
+// semverBump increments a part of a version, the prerelease and the build metadata are dropped. The v prefix is kept
+func semverBump(s, part string) (string, error) {
+	v, err := parseSemver(s)
+	if err != nil {
+		return "", err
+	}
+	switch part {
+	case "major":
+		v.Major, v.Minor, v.Patch = v.Major+1, 0, 0
+	case "minor":
+		v.Minor, v.Patch = v.Minor+1, 0
+	case "patch":
+		v.Patch++
+	}
+	v.Pre, v.Build = nil, nil
+	if strings.HasPrefix(strings.TrimSpace(s), "v") {
+		return "v" + v.String(), nil
+	}
+	return v.String(), nil
+}
+
+// jsonPath returns the value at path in a JSON document. The path is a list of keys and indexes: .a.b[0].c
+// Strings are returned as is, other values are returned as JSON
+func jsonPath(path, s string) (string, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(s), &doc); err != nil {
+		return "", fmt.Errorf("invalid JSON value: %v", err)
+	}
+
+	path = strings.TrimPrefix(path, "$")
+	path = strings.Replace(path, "[", ".", -1)
+	path = strings.Replace(path, "]", "", -1)
+	for _, k := range strings.Split(path, ".") {
+		if k == "" {
+			continue
+		}
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			v, ok := d[k]
+			if !ok {
+				return "", fmt.Errorf("key %s not found", k)
+			}
+			doc = v
+		case []interface{}:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(d) {
+				return "", fmt.Errorf("invalid index %s", k)
+			}
+			doc = d[i]
+		default:
+			return "", fmt.Errorf("key %s not found", k)
+		}
+	}
+
+	if str, ok := doc.(string); ok {
+		return str, nil
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,276 @@
+// Package interpolate replaces the {{.var}} expressions of a text with the values of the CDS variables.
+//
+// Each expression is evaluated on its own with text/template, the variables are referenced with their full
+// name, dots and dashes included: {{.cds.build.my-var}}. The values are never escaped.
+// Expressions can be piped to helpers: {{.git.branch | default "master" | escape}}.
+//
+// Expressions which are not interpolation expressions (no variable, control structures like {{if}} or {{range}},
+// comments, unknown helpers) are kept as is: scripts can still contain docker or helm templates.
+package interpolate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Mode is the behaviour of the interpolation when an expression references an unknown variable
+type Mode int
+
+const (
+	// KeepMissing keeps the expressions referencing unknown variables as is, they can be interpolated later
+	KeepMissing Mode = iota
+	// ErrorOnMissing returns an error when an expression references an unknown variable
+	ErrorOnMissing
+)
+
+// Options of the interpolation
+type Options struct {
+	Missing Mode
+	// Funcs are additional helpers, they override the default helpers with the same name
+	Funcs template.FuncMap
+}
+
+// MissingVariableError is returned with the ErrorOnMissing mode
+type MissingVariableError struct {
+	Expression string
+	Variables  []string
+}
+
+func (e *MissingVariableError) Error() string {
+	return fmt.Sprintf("unknown variable %s in %s", strings.Join(e.Variables, ", "), e.Expression)
+}
+
+// Do interpolates the input with the variables, the expressions referencing unknown variables are kept as is
+func Do(input string, vars map[string]string) (string, error) {
+	return DoWithOptions(input, vars, Options{})
+}
+
+// DoWithOptions interpolates the input with the variables
+func DoWithOptions(input string, vars map[string]string, opts Options) (string, error) {
+	return opts.do(input, func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+}
+
+func (o Options) do(input string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(input, "{{") {
+		return input, nil
+	}
+
+	var out bytes.Buffer
+	for {
+		start := strings.Index(input, "{{")
+		if start < 0 {
+			break
+		}
+		end := actionEnd(input[start+2:])
+		if end < 0 {
+			break
+		}
+		action := input[start : start+2+end+2]
+		s, err := o.eval(action, input[start+2:start+2+end], lookup)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(input[:start])
+		out.WriteString(s)
+		input = input[start+2+end+2:]
+	}
+	out.WriteString(input)
+
+	return out.String(), nil
+}
+
+// Resolve returns the variables with their values interpolated: the variables can reference other variables.
+// The variables referencing themselves are not interpolated
+func Resolve(vars map[string]string) map[string]string {
+	r := resolver{vars: vars, res: make(map[string]string, len(vars)), visiting: map[string]bool{}}
+	for k := range vars {
+		r.resolve(k)
+	}
+	return r.res
+}
+
+type resolver struct {
+	vars     map[string]string
+	res      map[string]string
+	visiting map[string]bool
+}
+
+func (r *resolver) lookup(name string) (string, bool) {
+	if _, ok := r.vars[name]; !ok {
+		return "", false
+	}
+	return r.resolve(name), true
+}
+
+func (r *resolver) resolve(name string) string {
+	if v, ok := r.res[name]; ok {
+		return v
+	}
+	v := r.vars[name]
+	if r.visiting[name] {
+		return v
+	}
+	r.visiting[name] = true
+	s, err := Options{}.do(v, r.lookup)
+	if err == nil {
+		v = s
+	}
+	delete(r.visiting, name)
+	r.res[name] = v
+	return v
+}
+
+// actionEnd returns the index of the }} closing an action, string literals are skipped
+func actionEnd(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case '`':
+			for i++; i < len(s) && s[i] != '`'; i++ {
+			}
+		case '}':
+			if i+1 < len(s) && s[i+1] == '}' {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var controlKeywords = map[string]bool{
+	"if": true, "else": true, "end": true, "range": true, "with": true,
+	"define": true, "template": true, "block": true, "break": true, "continue": true,
+}
+
+// eval evaluates an action, body is the action without the delimiters
+func (o Options) eval(action, body string, lookup func(string) (string, bool)) (string, error) {
+	expr := strings.TrimSpace(body)
+	expr = strings.TrimPrefix(expr, "- ")
+	expr = strings.TrimSuffix(expr, " -")
+	if expr == "" || strings.HasPrefix(expr, "/*") {
+		return action, nil
+	}
+
+	rewritten, refs, idents := rewrite(expr)
+	if len(refs) == 0 || (len(idents) > 0 && controlKeywords[idents[0]] && !strings.HasPrefix(expr, ".")) {
+		return action, nil
+	}
+
+	values := map[string]string{}
+	var missing []string
+	for _, r := range refs {
+		v, ok := lookup(r)
+		if !ok {
+			missing = append(missing, r)
+		}
+		values[r] = v
+	}
+	if len(missing) > 0 && !contains(idents, "default") {
+		if o.Missing == ErrorOnMissing {
+			return "", &MissingVariableError{Expression: action, Variables: missing}
+		}
+		return action, nil
+	}
+
+	funcs := template.FuncMap{}
+	for k, f := range defaultFuncs {
+		funcs[k] = f
+	}
+	for k, f := range o.Funcs {
+		funcs[k] = f
+	}
+	funcs[varFunc] = func(name string) string {
+		return values[name]
+	}
+
+	t, err := template.New("").Funcs(funcs).Parse("{{" + rewritten + "}}")
+	if err != nil {
+		if o.Missing == ErrorOnMissing {
+			return "", fmt.Errorf("invalid expression %s: %v", action, err)
+		}
+		return action, nil
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, nil); err != nil {
+		if e, ok := err.(template.ExecError); ok {
+			err = e.Err
+		}
+		return "", fmt.Errorf("unable to interpolate %s: %v", action, err)
+	}
+	return buf.String(), nil
+}
+
+// varFunc is the helper the variable references are rewritten to
+const varFunc = "_var"
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// rewrite rewrites the variable references .a.b-c of an expression into (_var "a.b-c").
+// It returns the rewritten expression, the referenced variables and the identifiers (helpers, keywords)
+func rewrite(expr string) (string, []string, []string) {
+	var out bytes.Buffer
+	var refs, idents []string
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == '"' || c == '`':
+			j := i + 1
+			for ; j < len(expr) && expr[j] != c; j++ {
+				if c == '"' && expr[j] == '\\' {
+					j++
+				}
+			}
+			if j < len(expr) {
+				j++
+			}
+			out.WriteString(expr[i:j])
+			i = j
+		case c == '.' && (i == 0 || strings.IndexByte(" \t\n(|,", expr[i-1]) >= 0) && i+1 < len(expr) && isNameChar(expr[i+1]):
+			j := i + 1
+			for ; j < len(expr) && isNameChar(expr[j]); j++ {
+			}
+			name := strings.TrimRight(expr[i+1:j], ".")
+			j = i + 1 + len(name)
+			refs = append(refs, name)
+			fmt.Fprintf(&out, "(%s %q)", varFunc, name)
+			i = j
+		case isIdentChar(c) && (c < '0' || c > '9') && (i == 0 || !isNameChar(expr[i-1])):
+			j := i
+			for ; j < len(expr) && isIdentChar(expr[j]); j++ {
+			}
+			idents = append(idents, expr[i:j])
+			out.WriteString(expr[i:j])
+			i = j
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String(), refs, idents
+}
+
+func contains(a []string, s string) bool {
+	for _, x := range a {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
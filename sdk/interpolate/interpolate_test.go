@@ -0,0 +1,112 @@
+package interpolate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo(t *testing.T) {
+	vars := map[string]string{
+		"cds.app.name":        "my-app",
+		"cds.build.my-var":    "<b>value</b>",
+		"git.branch":          "feat/my_branch",
+		"cds.version":         "12",
+		"cds.build.tag":       "v1.2.3-rc.1",
+		"cds.build.payload":   `{"image":{"tags":["latest","1.0"],"size":42}}`,
+		"cds.build.b64":       "Y2Rz",
+		"cds.env.description": "  padded  ",
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{{.cds.app.name}}", "my-app"},
+		{"name={{.cds.app.name}} version={{ .cds.version }}", "name=my-app version=12"},
+		{"{{.cds.build.my-var}}", "<b>value</b>"},
+		{"{{.git.branch | escape}}", "feat-my-branch"},
+		{"{{.git.branch | upper}}", "FEAT/MY_BRANCH"},
+		{"{{.cds.env.description | trim}}", "padded"},
+		{`{{.git.branch | replace "feat/" "" | title}}`, "My_branch"},
+		{`{{.git.branch | regexReplace "[^a-z]" ""}}`, "featmybranch"},
+		{`{{.cds.build.tag | trimPrefix "v"}}`, "1.2.3-rc.1"},
+		{"{{.cds.build.tag | semverMajor}}.{{.cds.build.tag | semverMinor}}", "1.2"},
+		{"{{.cds.build.tag | semverPrerelease}}", "rc.1"},
+		{"{{.cds.build.tag | semverBumpMinor}}", "v1.3.0"},
+		{"{{.cds.build.b64 | b64dec}}", "cds"},
+		{"{{.cds.app.name | b64enc}}", "bXktYXBw"},
+		{`{{.cds.build.payload | jsonPath ".image.tags[1]"}}`, "1.0"},
+		{`{{.cds.build.payload | jsonPath "image.size"}}`, "42"},
+		{`{{.cds.build.unknown | default "master"}}`, "master"},
+		{`{{.git.branch | default "master"}}`, "feat/my_branch"},
+		// not interpolated
+		{"{{.cds.build.unknown}}", "{{.cds.build.unknown}}"},
+		{"{{.cds.build.unknown | upper}} {{.cds.version}}", "{{.cds.build.unknown | upper}} 12"},
+		{"docker inspect --format '{{.State.Running}}'", "docker inspect --format '{{.State.Running}}'"},
+		{"{{range .Items}}{{.Name}}{{end}}", "{{range .Items}}{{.Name}}{{end}}"},
+		{"{{ json . }}", "{{ json . }}"},
+		{`{{.cds.version | unknownHelper}}`, `{{.cds.version | unknownHelper}}`},
+		{"{{.cds.version", "{{.cds.version"},
+		{`{{ "}}" }}`, `{{ "}}" }}`},
+	}
+
+	for _, tt := range tests {
+		res, err := Do(tt.input, vars)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, res, tt.input)
+	}
+}
+
+func TestDoErrorOnMissing(t *testing.T) {
+	vars := map[string]string{"cds.version": "12"}
+
+	_, err := DoWithOptions("{{.cds.version}}-{{.cds.build.unknown}}", vars, Options{Missing: ErrorOnMissing})
+	assert.Error(t, err)
+	merr, ok := err.(*MissingVariableError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"cds.build.unknown"}, merr.Variables)
+
+	res, err := DoWithOptions(`{{.cds.version}}-{{.cds.build.unknown | default "0"}}`, vars, Options{Missing: ErrorOnMissing})
+	assert.NoError(t, err)
+	assert.Equal(t, "12-0", res)
+
+	_, err = DoWithOptions("{{.cds.version | unknownHelper}}", vars, Options{Missing: ErrorOnMissing})
+	assert.Error(t, err)
+}
+
+func TestDoHelperErrors(t *testing.T) {
+	vars := map[string]string{"v": "not a version", "b": "%%%", "j": "{}"}
+	for _, input := range []string{"{{.v | semverMajor}}", "{{.b | b64dec}}", `{{.j | jsonPath ".a"}}`, `{{.v | regexReplace "(" ""}}`} {
+		_, err := Do(input, vars)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestDoFuncs(t *testing.T) {
+	res, err := DoWithOptions("{{.a | reverse}}", map[string]string{"a": "abc"}, Options{
+		Funcs: map[string]interface{}{
+			"reverse": func(s string) string {
+				r := []rune(s)
+				for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+					r[i], r[j] = r[j], r[i]
+				}
+				return string(r)
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "cba", res)
+}
+
+func TestResolve(t *testing.T) {
+	vars := Resolve(map[string]string{
+		"a":     "{{.b | upper}}-{{.c}}",
+		"b":     "{{.c}}",
+		"c":     "value",
+		"cycle": "{{.cycle}}",
+	})
+	assert.Equal(t, "VALUE-value", vars["a"])
+	assert.Equal(t, "value", vars["b"])
+	assert.Equal(t, "{{.cycle}}", vars["cycle"])
+}
@@ -60,6 +60,28 @@ var (
 	MsgSpawnInfoJobError                   = &Message{"MsgSpawnInfoJobError", trad{FR: "Impossible de lancer ce job : %s", EN: "Unable to run this job: %s"}, nil}
 	MsgWorkflowStarting                    = &Message{"MsgWorkflowStarting", trad{FR: "Le workflow %s#%s a été démarré", EN: "Workflow %s#%s has been started"}, nil}
 	MsgWorkflowError                       = &Message{"MsgWorkflowError", trad{FR: "Une erreur est survenue: %v", EN: "An error has occured: %v"}, nil}
+	MsgWorkflowNodeRunWaitingApproval      = &Message{"MsgWorkflowNodeRunWaitingApproval", trad{FR: "Le pipeline %s attend %d approbation(s) des groupes %s", EN: "Pipeline %s is waiting for %d approval(s) from groups %s"}, nil}
+	MsgWorkflowNodeRunApproved             = &Message{"MsgWorkflowNodeRunApproved", trad{FR: "Le pipeline %s a été approuvé par %s", EN: "Pipeline %s has been approved by %s"}, nil}
+	MsgWorkflowNodeRunRejected             = &Message{"MsgWorkflowNodeRunRejected", trad{FR: "Le pipeline %s a été rejeté par %s", EN: "Pipeline %s has been rejected by %s"}, nil}
+	MsgWorkflowNodeRunApprovalTimeout      = &Message{"MsgWorkflowNodeRunApprovalTimeout", trad{FR: "Le pipeline %s a été rejeté: délai d'approbation dépassé", EN: "Pipeline %s has been rejected: approval timeout exceeded"}, nil}
+	MsgProjectCreated                      = &Message{"MsgProjectCreated", trad{FR: "Le projet %s a été créé avec succès", EN: "Project %s successfully created"}, nil}
+	MsgProjectExists                       = &Message{"MsgProjectExists", trad{FR: "Le projet %s existe déjà, seuls les éléments manquants sont importés", EN: "Project %s already exists, only the missing entities are imported"}, nil}
+	MsgProjectGroupCreated                 = &Message{"MsgProjectGroupCreated", trad{FR: "Le groupe %s a été créé", EN: "Group %s has been created"}, nil}
+	MsgProjectGroupAdded                   = &Message{"MsgProjectGroupAdded", trad{FR: "Permission accordée au groupe %s sur le projet %s", EN: "Permission applied to group %s on project %s"}, nil}
+	MsgProjectVariableCreated              = &Message{"MsgProjectVariableCreated", trad{FR: "La variable %s du projet %s a été ajoutée", EN: "Variable %s on project %s has been added"}, nil}
+	MsgProjectKeyCreated                   = &Message{"MsgProjectKeyCreated", trad{FR: "La clé %s du projet %s a été ajoutée", EN: "Key %s on project %s has been added"}, nil}
+	MsgAppKeyCreated                       = &Message{"MsgAppKeyCreated", trad{FR: "La clé %s de l'application %s a été ajoutée", EN: "Key %s on application %s has been added"}, nil}
+	MsgEnvironmentKeyCreated               = &Message{"MsgEnvironmentKeyCreated", trad{FR: "La clé %s de l'environnement %s a été ajoutée", EN: "Key %s on environment %s has been added"}, nil}
+	MsgWorkflowCreated                     = &Message{"MsgWorkflowCreated", trad{FR: "Le workflow %s a été créé avec succès", EN: "Workflow %s successfully created"}, nil}
+	MsgImportEntityUnchanged               = &Message{"MsgImportEntityUnchanged", trad{FR: "%s %s existe déjà et est identique à l'archive", EN: "%s %s already exists and is identical to the archive"}, nil}
+	MsgImportEntityDiffers                 = &Message{"MsgImportEntityDiffers", trad{FR: "%s %s existe déjà et diffère de l'archive : il n'a pas été modifié", EN: "%s %s already exists and differs from the archive: it has not been modified"}, nil}
+	MsgImportAppOptionsSkipped             = &Message{"MsgImportAppOptionsSkipped", trad{FR: "Les hooks, pollers, notifications et schedulers de l'application %s ne sont pas importés", EN: "Hooks, pollers, notifications and schedulers of application %s are not imported"}, nil}
+	MsgImportTriggerSkipped                = &Message{"MsgImportTriggerSkipped", trad{FR: "Le trigger du pipeline %s de l'application %s vers le projet %s n'est pas importé", EN: "Trigger from pipeline %s of application %s to project %s is not imported"}, nil}
+	MsgImportRepositoriesManagerLinked     = &Message{"MsgImportRepositoriesManagerLinked", trad{FR: "Le gestionnaire de dépôts %s a été lié au projet %s : son autorisation doit être refaite", EN: "Repositories manager %s has been linked to project %s: it must be authorized again"}, nil}
+	MsgImportRepositoriesManagerNotFound   = &Message{"MsgImportRepositoriesManagerNotFound", trad{FR: "Le gestionnaire de dépôts %s n'existe pas sur cette instance : les dépôts des applications ne sont pas liés", EN: "Repositories manager %s does not exist on this instance: the repositories of the applications are not linked"}, nil}
+	MsgImportAppRepositoryLinked           = &Message{"MsgImportAppRepositoryLinked", trad{FR: "L'application %s a été liée au dépôt %s de %s", EN: "Application %s has been linked to repository %s on %s"}, nil}
+	MsgImportDryRun                        = &Message{"MsgImportDryRun", trad{FR: "Simulation : rien n'a été importé", EN: "Dry run: nothing has been imported"}, nil}
+	MsgImportGroupSkipped                  = &Message{"MsgImportGroupSkipped", trad{FR: "Le groupe %s existe déjà et vous n'en êtes pas administrateur : ses permissions ne sont pas importées", EN: "Group %s already exists and you are not one of its administrators: its permissions are not imported"}, nil}
 )
 
 // Messages contains all sdk Messages
@@ -103,6 +125,28 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoWorkerForJob.ID:               MsgSpawnInfoWorkerForJob,
 	MsgSpawnInfoWorkerForJobError.ID:          MsgSpawnInfoWorkerForJobError,
 	MsgWorkflowStarting.ID:                    MsgWorkflowStarting,
+	MsgWorkflowNodeRunWaitingApproval.ID:      MsgWorkflowNodeRunWaitingApproval,
+	MsgWorkflowNodeRunApproved.ID:             MsgWorkflowNodeRunApproved,
+	MsgWorkflowNodeRunRejected.ID:             MsgWorkflowNodeRunRejected,
+	MsgWorkflowNodeRunApprovalTimeout.ID:      MsgWorkflowNodeRunApprovalTimeout,
+	MsgProjectCreated.ID:                      MsgProjectCreated,
+	MsgProjectExists.ID:                       MsgProjectExists,
+	MsgProjectGroupCreated.ID:                 MsgProjectGroupCreated,
+	MsgProjectGroupAdded.ID:                   MsgProjectGroupAdded,
+	MsgProjectVariableCreated.ID:              MsgProjectVariableCreated,
+	MsgProjectKeyCreated.ID:                   MsgProjectKeyCreated,
+	MsgAppKeyCreated.ID:                       MsgAppKeyCreated,
+	MsgEnvironmentKeyCreated.ID:               MsgEnvironmentKeyCreated,
+	MsgWorkflowCreated.ID:                     MsgWorkflowCreated,
+	MsgImportEntityUnchanged.ID:               MsgImportEntityUnchanged,
+	MsgImportEntityDiffers.ID:                 MsgImportEntityDiffers,
+	MsgImportAppOptionsSkipped.ID:             MsgImportAppOptionsSkipped,
+	MsgImportTriggerSkipped.ID:                MsgImportTriggerSkipped,
+	MsgImportRepositoriesManagerLinked.ID:     MsgImportRepositoriesManagerLinked,
+	MsgImportRepositoriesManagerNotFound.ID:   MsgImportRepositoriesManagerNotFound,
+	MsgImportAppRepositoryLinked.ID:           MsgImportAppRepositoryLinked,
+	MsgImportDryRun.ID:                        MsgImportDryRun,
+	MsgImportGroupSkipped.ID:                  MsgImportGroupSkipped,
 }
 
 //Message represent a struc format translated messages
@@ -0,0 +1,408 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets are the default buckets of the histograms, in seconds, suitable for HTTP requests
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// LongBuckets are buckets in seconds suitable for long operations, like spawning a worker or running a step
+var LongBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+// Collector is a metric family which can be written in the Prometheus text format
+type Collector interface {
+	Name() string
+	Write(w io.Writer)
+}
+
+// Registry holds the collectors exposed on an endpoint
+type Registry struct {
+	mutex        sync.Mutex
+	collectors   map[string]Collector
+	hooks        []func()
+	collectMutex sync.Mutex
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{collectors: map[string]Collector{}}
+}
+
+// DefaultRegistry is the registry used by the package level functions
+var DefaultRegistry = NewRegistry()
+
+// Register adds a collector to the registry, a collector with the same name is replaced
+func (r *Registry) Register(c Collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.collectors[c.Name()] = c
+}
+
+// OnCollect adds a function called once at the beginning of each collection, before the collectors are written.
+// It computes the values shared by several collectors, e.g. with a single database query
+func (r *Registry) OnCollect(f func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.hooks = append(r.hooks, f)
+}
+
+// WriteTo writes all the collectors of the registry in the Prometheus text format, sorted by name.
+// The collections are serialized so that the values computed by the OnCollect functions are consistent
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.collectMutex.Lock()
+	defer r.collectMutex.Unlock()
+
+	r.mutex.Lock()
+	hooks := append([]func(){}, r.hooks...)
+	names := make([]string, 0, len(r.collectors))
+	for n := range r.collectors {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	collectors := make([]Collector, len(names))
+	for i, n := range names {
+		collectors[i] = r.collectors[n]
+	}
+	r.mutex.Unlock()
+
+	for _, f := range hooks {
+		f()
+	}
+
+	buf := new(bytes.Buffer)
+	for _, c := range collectors {
+		c.Write(buf)
+	}
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP writes the metrics of the registry
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if _, err := r.WriteTo(w); err != nil {
+		log.Warning("metrics> Unable to write metrics: %s", err)
+	}
+}
+
+// Serve exposes the default registry on /mon/metrics at the given address. Nothing is done if the address is empty
+func Serve(addr string) {
+	if addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/mon/metrics", DefaultRegistry)
+	log.Info("Starting metrics HTTP server on %s", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Error("metrics> Unable to serve metrics on %s: %s", addr, err)
+		}
+	}()
+}
+
+// family is the common part of the metrics: a name, a help text, a type and label names
+type family struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (f *family) Name() string { return f.name }
+
+func (f *family) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, strings.Replace(f.help, "\n", " ", -1))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+}
+
+func (f *family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelValueEscaper escapes a label value as the Prometheus text format expects: only the backslash, the double quote
+// and the line feed are escaped, other characters are written as is
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels returns the labels of a sample: {name="value",...}
+func formatLabels(names, values []string, extra ...string) string {
+	pairs := []string{}
+	for i := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, names[i], labelValueEscaper.Replace(values[i])))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[i], labelValueEscaper.Replace(extra[i+1])))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sample is the value of a counter or a gauge for a set of label values
+type sample struct {
+	values []string
+	value  float64
+}
+
+// valueVec holds the samples of a counter or a gauge
+type valueVec struct {
+	family
+	mutex   sync.Mutex
+	samples map[string]*sample
+}
+
+func newValueVec(kind, name, help string, labels []string) valueVec {
+	return valueVec{
+		family:  family{name: name, help: help, kind: kind, labels: labels},
+		samples: map[string]*sample{},
+	}
+}
+
+func (v *valueVec) update(values []string, f func(float64) float64) {
+	k := v.key(values)
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	s, ok := v.samples[k]
+	if !ok {
+		s = &sample{values: append([]string{}, values...)}
+		v.samples[k] = s
+	}
+	s.value = f(s.value)
+}
+
+// Value returns the current value for a set of label values
+func (v *valueVec) Value(values ...string) float64 {
+	k := v.key(values)
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if s, ok := v.samples[k]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (v *valueVec) Write(w io.Writer) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.writeHeader(w)
+	keys := make([]string, 0, len(v.samples))
+	for k := range v.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := v.samples[k]
+		fmt.Fprintf(w, "%s%s %s\n", v.name, formatLabels(v.labels, s.values), formatValue(s.value))
+	}
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct {
+	valueVec
+}
+
+// NewCounterVec creates a counter and registers it in the default registry
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{newValueVec("counter", name, help, labels)}
+	DefaultRegistry.Register(c)
+	return c
+}
+
+// Inc increments the counter by 1
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add increments the counter by a positive value
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		return
+	}
+	c.update(values, func(v float64) float64 { return v + delta })
+}
+
+// GaugeVec is a gauge partitioned by labels
+type GaugeVec struct {
+	valueVec
+}
+
+// NewGaugeVec creates a gauge and registers it in the default registry
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{newValueVec("gauge", name, help, labels)}
+	DefaultRegistry.Register(g)
+	return g
+}
+
+// Set sets the value of the gauge
+func (g *GaugeVec) Set(value float64, values ...string) {
+	g.update(values, func(float64) float64 { return value })
+}
+
+// Add adds a value, positive or negative, to the gauge
+func (g *GaugeVec) Add(delta float64, values ...string) {
+	g.update(values, func(v float64) float64 { return v + delta })
+}
+
+// GaugeFunc is a gauge whose values are computed each time the metrics are collected
+type GaugeFunc struct {
+	family
+	f func() map[string]float64
+}
+
+// NewGaugeFunc creates a gauge without labels computed by f, and registers it in the default registry
+func NewGaugeFunc(name, help string, f func() float64) *GaugeFunc {
+	return NewGaugeVecFunc(name, help, "", func() map[string]float64 {
+		return map[string]float64{"": f()}
+	})
+}
+
+// NewGaugeVecFunc creates a gauge computed by f and registers it in the default registry.
+// f returns the values of the gauge indexed by the value of the label
+func NewGaugeVecFunc(name, help, label string, f func() map[string]float64) *GaugeFunc {
+	g := &GaugeFunc{family: family{name: name, help: help, kind: "gauge"}, f: f}
+	if label != "" {
+		g.labels = []string{label}
+	}
+	DefaultRegistry.Register(g)
+	return g
+}
+
+// CounterFunc is a counter whose value is computed each time the metrics are collected, for the counters maintained
+// outside of the registry
+type CounterFunc struct {
+	GaugeFunc
+}
+
+// NewCounterFunc creates a counter without labels computed by f, and registers it in the default registry.
+// f must return a value that never decreases
+func NewCounterFunc(name, help string, f func() float64) *CounterFunc {
+	c := &CounterFunc{GaugeFunc{family: family{name: name, help: help, kind: "counter"}, f: func() map[string]float64 {
+		return map[string]float64{"": f()}
+	}}}
+	DefaultRegistry.Register(c)
+	return c
+}
+
+func (g *GaugeFunc) Write(w io.Writer) {
+	values := g.f()
+	g.writeHeader(w)
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var labels string
+		if len(g.labels) > 0 {
+			labels = formatLabels(g.labels, []string{k})
+		}
+		fmt.Fprintf(w, "%s%s %s\n", g.name, labels, formatValue(values[k]))
+	}
+}
+
+// histogram is the state of an histogram for a set of label values
+type histogram struct {
+	values []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is an histogram partitioned by labels
+type HistogramVec struct {
+	family
+	buckets []float64
+	mutex   sync.Mutex
+	samples map[string]*histogram
+}
+
+// NewHistogramVec creates an histogram and registers it in the default registry
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	b := append([]float64{}, buckets...)
+	sort.Float64s(b)
+	h := &HistogramVec{
+		family:  family{name: name, help: help, kind: "histogram", labels: labels},
+		buckets: b,
+		samples: map[string]*histogram{},
+	}
+	DefaultRegistry.Register(h)
+	return h
+}
+
+// Observe adds an observation to the histogram
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	k := h.key(values)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	s, ok := h.samples[k]
+	if !ok {
+		s = &histogram{values: append([]string{}, values...), counts: make([]uint64, len(h.buckets))}
+		h.samples[k] = s
+	}
+	for i, b := range h.buckets {
+		if value <= b {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+// Count returns the number of observations for a set of label values
+func (h *HistogramVec) Count(values ...string) uint64 {
+	k := h.key(values)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if s, ok := h.samples[k]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) Write(w io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.writeHeader(w)
+	keys := make([]string, 0, len(h.samples))
+	for k := range h.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := h.samples[k]
+		for i, b := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, s.values, "le", formatValue(b)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, s.values), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, s.values), s.count)
+	}
+}
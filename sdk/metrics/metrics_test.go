@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterVec(t *testing.T) {
+	c := NewCounterVec("test_counter_total", "A counter", "model")
+	c.Inc("docker")
+	c.Add(2, "docker")
+	c.Inc("openstack")
+	c.Add(-1, "openstack")
+
+	assert.Equal(t, float64(3), c.Value("docker"))
+	assert.Equal(t, float64(1), c.Value("openstack"))
+
+	buf := new(bytes.Buffer)
+	c.Write(buf)
+	assert.Equal(t, `# HELP test_counter_total A counter
+# TYPE test_counter_total counter
+test_counter_total{model="docker"} 3
+test_counter_total{model="openstack"} 1
+`, buf.String())
+}
+
+func TestGaugeFunc(t *testing.T) {
+	g := NewGaugeVecFunc("test_gauge", "A gauge", "status", func() map[string]float64 {
+		return map[string]float64{"Waiting": 2, "Building": 1}
+	})
+
+	buf := new(bytes.Buffer)
+	g.Write(buf)
+	assert.Equal(t, `# HELP test_gauge A gauge
+# TYPE test_gauge gauge
+test_gauge{status="Building"} 1
+test_gauge{status="Waiting"} 2
+`, buf.String())
+
+	g = NewGaugeFunc("test_gauge_nolabel", "A gauge", func() float64 { return 0.5 })
+	buf.Reset()
+	g.Write(buf)
+	assert.Contains(t, buf.String(), "test_gauge_nolabel 0.5\n")
+}
+
+func TestCounterFunc(t *testing.T) {
+	c := NewCounterFunc("test_func_total", "A counter", func() float64 { return 3 })
+
+	buf := new(bytes.Buffer)
+	c.Write(buf)
+	assert.Equal(t, `# HELP test_func_total A counter
+# TYPE test_func_total counter
+test_func_total 3
+`, buf.String())
+}
+
+func TestHistogramVec(t *testing.T) {
+	h := NewHistogramVec("test_duration_seconds", "An histogram", []float64{1, 0.1}, "route")
+	h.Observe(0.05, "/project")
+	h.Observe(0.5, "/project")
+	h.Observe(5, "/project")
+
+	assert.Equal(t, uint64(3), h.Count("/project"))
+
+	buf := new(bytes.Buffer)
+	h.Write(buf)
+	assert.Equal(t, `# HELP test_duration_seconds An histogram
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{route="/project",le="0.1"} 1
+test_duration_seconds_bucket{route="/project",le="1"} 2
+test_duration_seconds_bucket{route="/project",le="+Inf"} 3
+test_duration_seconds_sum{route="/project"} 5.55
+test_duration_seconds_count{route="/project"} 3
+`, buf.String())
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	NewCounterVec("test_served_total", "A counter").Inc()
+
+	rec := httptest.NewRecorder()
+	DefaultRegistry.ServeHTTP(rec, httptest.NewRequest("GET", "/mon/metrics", nil))
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "test_served_total 1\n")
+}
+
+func TestLabelEscaping(t *testing.T) {
+	c := NewCounterVec("test_escaped_total", "A counter", "route")
+	c.Inc("/project/é\\\"\n")
+
+	buf := new(bytes.Buffer)
+	c.Write(buf)
+	assert.Contains(t, buf.String(), `test_escaped_total{route="/project/é\\\"\n"} 1`+"\n")
+}
+
+func TestRegistryOnCollect(t *testing.T) {
+	r := NewRegistry()
+	var calls, value float64
+	r.OnCollect(func() {
+		calls++
+		value = calls * 10
+	})
+	for _, n := range []string{"test_first", "test_second"} {
+		r.Register(&GaugeFunc{family: family{name: n, help: "A gauge", kind: "gauge"}, f: func() map[string]float64 {
+			return map[string]float64{"": value}
+		}})
+	}
+
+	buf := new(bytes.Buffer)
+	_, err := r.WriteTo(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), calls)
+	assert.Contains(t, buf.String(), "test_first 10\n")
+	assert.Contains(t, buf.String(), "test_second 10\n")
+
+	buf.Reset()
+	_, err = r.WriteTo(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), calls)
+	assert.Contains(t, buf.String(), "test_second 20\n")
+}
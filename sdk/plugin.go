@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -50,6 +51,26 @@ func DownloadPlugin(name string, destdir string) error {
 
 //UploadPlugin uploads binary file to perform a new action
 func UploadPlugin(filePath string, update bool) ([]byte, error) {
+	return UploadPluginVersion(filePath, "", update)
+}
+
+//UploadPluginVersion uploads a version of a plugin. Without version, the first upload is the version 1.0.0
+//and an update is the next patch of the highest version
+func UploadPluginVersion(filePath, version string, update bool) ([]byte, error) {
+	method := "POST"
+	if update {
+		method = "PUT"
+	}
+	return uploadPluginFile(method, "/plugin", filePath, map[string]string{"version": version})
+}
+
+//UploadPluginBinary uploads the binary of an existing plugin version for another OS and architecture
+func UploadPluginBinary(name, version, goos, goarch, filePath string) ([]byte, error) {
+	uri := fmt.Sprintf("/plugin/%s/version/%s/binary", name, version)
+	return uploadPluginFile("POST", uri, filePath, map[string]string{"os": goos, "arch": goarch})
+}
+
+func uploadPluginFile(method, uri, filePath string, fields map[string]string) ([]byte, error) {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return nil, err
 	}
@@ -60,8 +81,6 @@ func UploadPlugin(filePath string, update bool) ([]byte, error) {
 	}
 	defer file.Close()
 
-	//_, name := filepath.Split(filePath)
-
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	part, errc := writer.CreateFormFile("UploadFile", filepath.Base(filePath))
@@ -73,14 +92,19 @@ func UploadPlugin(filePath string, update bool) ([]byte, error) {
 		return nil, err
 	}
 
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := writer.WriteField(k, v); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := writer.Close(); err != nil {
 		return nil, err
 	}
-	method := "POST"
-	if update {
-		method = "PUT"
-	}
-	btes, code, err := UploadMultiPart(method, "/plugin", body, SetHeader("uploadfile", filePath), SetHeader("Content-Type", writer.FormDataContentType()))
+	btes, code, err := UploadMultiPart(method, uri, body, SetHeader("uploadfile", filePath), SetHeader("Content-Type", writer.FormDataContentType()))
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +116,39 @@ func UploadPlugin(filePath string, update bool) ([]byte, error) {
 	return btes, nil
 }
 
+//ListPluginVersions returns all the versions of a plugin, highest version first
+func ListPluginVersions(name string) ([]ActionPluginVersion, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/plugin/%s/version", name), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Error %d", code)
+	}
+
+	var versions []ActionPluginVersion
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+//SetPluginCurrentVersion makes a version the current version of a plugin, it's used to rollback a plugin
+func SetPluginCurrentVersion(name, version string) error {
+	data, err := json.Marshal(ActionPluginVersion{Version: version})
+	if err != nil {
+		return err
+	}
+	_, code, err := Request("PUT", fmt.Sprintf("/plugin/%s/current", name), data)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP Error %d", code)
+	}
+	return nil
+}
+
 //DeletePlugin delete plugin
 func DeletePlugin(name string) error {
 	path := fmt.Sprintf("/plugin/%s", name)
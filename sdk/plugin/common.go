@@ -232,3 +232,40 @@ func SendLog(j IJob, format string, i ...interface{}) error {
 	}
 	return nil
 }
+
+//SetBuildVariable exports a variable from the plugin. The variable is available as {{.cds.build.<name>}}
+//in the next steps of the job, in the next stages and in the conditions of the workflow triggers
+func SetBuildVariable(j IJob, name, value string) error {
+	s, ok := j.(BuildVariableSetter)
+	if !ok {
+		return errors.New("the job can't export variables, is the plugin running inside a CDS worker job?")
+	}
+	return s.SetBuildVariable(name, value)
+}
+
+// setBuildVariable sends a variable to the HTTP server of the worker running the plugin, like the worker export command does
+func setBuildVariable(port int, name, value string) error {
+	if port == 0 {
+		return errors.New("worker HTTP server not found, is the plugin running inside a CDS worker job?")
+	}
+
+	data, err := json.Marshal(sdk.Variable{
+		Name:  name,
+		Type:  sdk.StringVariable,
+		Value: value,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(fmt.Sprintf("http://127.0.0.1:%d/var", port), "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("cannot set variable %s: HTTP %d", name, resp.StatusCode)
+	}
+	return nil
+}
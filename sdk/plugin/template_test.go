@@ -28,6 +28,18 @@ func TestApplyArguments(t *testing.T) {
 			want:    "a valbar here, Mytitle, TOUPPER, tolower, a-b-c-d",
 			wantErr: false,
 		},
+		{
+			name: "test helpers and unknown variables",
+			args: args{
+				variables: map[string]string{
+					"cds.app.my-app": "my-app",
+					"cds.version":    "1.2.3",
+				},
+				input: `{"id": "{{.cds.app.my-app}}", "version": "{{.cds.version | semverBumpMinor}}", "branch": "{{.git.branch | default "master"}}", "env": "{{.cds.env.name}}"}`,
+			},
+			want:    `{"id": "my-app", "version": "1.3.0", "branch": "master", "env": "{{.cds.env.name}}"}`,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -32,6 +32,11 @@ type IJob interface {
 	Secrets() Secrets
 }
 
+//BuildVariableSetter is implemented by the jobs able to export build variables, use SetBuildVariable
+type BuildVariableSetter interface {
+	SetBuildVariable(name, value string) error
+}
+
 //Job is the input of the plugin run function
 type Job struct {
 	IDPipelineJobBuild int64
@@ -39,6 +44,7 @@ type Job struct {
 	Args               Arguments
 	Secrts             Secrets
 	OrderStep          int
+	WorkerHTTPPort     int
 }
 
 func (j Job) ID() int64              { return j.IDPipelineJobBuild }
@@ -47,6 +53,11 @@ func (j Job) Secrets() Secrets       { return j.Secrts }
 func (j Job) PipelineBuildID() int64 { return j.IDPipelineBuild }
 func (j Job) StepOrder() int         { return j.OrderStep }
 
+//SetBuildVariable sends the variable to the worker running the job
+func (j Job) SetBuildVariable(name, value string) error {
+	return setBuildVariable(j.WorkerHTTPPort, name, value)
+}
+
 //IOptions is
 type IOptions interface {
 	Hash() string
@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestJobSetBuildVariable(t *testing.T) {
+	var v sdk.Variable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/var", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&v))
+	}))
+	defer srv.Close()
+
+	_, portS, err := net.SplitHostPort(srv.Listener.Addr().String())
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(portS)
+	assert.NoError(t, err)
+
+	j := Job{WorkerHTTPPort: port}
+	assert.NoError(t, SetBuildVariable(j, "version", "1.2.3"))
+	assert.Equal(t, "version", v.Name)
+	assert.Equal(t, "1.2.3", v.Value)
+	assert.Equal(t, sdk.StringVariable, v.Type)
+
+	assert.Error(t, SetBuildVariable(Job{}, "version", "1.2.3"))
+}
+
+//legacyJob is an IJob of a plugin built before build variables
+type legacyJob struct{}
+
+func (legacyJob) ID() int64              { return 0 }
+func (legacyJob) PipelineBuildID() int64 { return 0 }
+func (legacyJob) StepOrder() int         { return 0 }
+func (legacyJob) Arguments() Arguments   { return Arguments{} }
+func (legacyJob) Secrets() Secrets       { return Secrets{} }
+
+func TestSetBuildVariableLegacyJob(t *testing.T) {
+	var j IJob = legacyJob{}
+	assert.Error(t, SetBuildVariable(j, "version", "1.2.3"))
+}
@@ -0,0 +1,167 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+// Provenance attestations types, see https://github.com/in-toto/attestation and https://slsa.dev/provenance
+const (
+	ProvenanceStatementType   = "https://in-toto.io/Statement/v0.1"
+	ProvenancePredicateType   = "https://slsa.dev/provenance/v0.1"
+	ProvenancePayloadType     = "application/vnd.in-toto+json"
+	ProvenanceRecipeType      = "https://github.com/ovh/cds/workflow@v1"
+	ProvenanceBuilderIDPrefix = "https://github.com/ovh/cds/engine/api@"
+)
+
+//ProvenanceStatement is an in-toto statement describing how the artifacts of a workflow node run have been built
+type ProvenanceStatement struct {
+	Type          string              `json:"_type"`
+	Subject       []ProvenanceSubject `json:"subject"`
+	PredicateType string              `json:"predicateType"`
+	Predicate     ProvenancePredicate `json:"predicate"`
+}
+
+//ProvenanceSubject is an artifact described by a provenance statement
+type ProvenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+//ProvenancePredicate is the SLSA provenance predicate of a statement
+type ProvenancePredicate struct {
+	Builder   ProvenanceBuilder    `json:"builder"`
+	Recipe    ProvenanceRecipe     `json:"recipe"`
+	Metadata  ProvenanceMetadata   `json:"metadata"`
+	Materials []ProvenanceMaterial `json:"materials"`
+}
+
+//ProvenanceBuilder identifies the platform which built the artifacts
+type ProvenanceBuilder struct {
+	ID string `json:"id"`
+}
+
+//ProvenanceRecipe describes the workflow node which built the artifacts. Arguments never contain secrets
+type ProvenanceRecipe struct {
+	Type         string      `json:"type"`
+	EntryPoint   string      `json:"entryPoint"`
+	Arguments    []Parameter `json:"arguments"`
+	WorkerModels []string    `json:"workerModels"`
+}
+
+//ProvenanceMetadata contains the timestamps of the workflow node run
+type ProvenanceMetadata struct {
+	BuildStartedOn  time.Time `json:"buildStartedOn"`
+	BuildFinishedOn time.Time `json:"buildFinishedOn"`
+}
+
+//ProvenanceMaterial is a source used by the workflow node run
+type ProvenanceMaterial struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+//ProvenanceAttestation is a provenance statement signed with a project PGP key
+type ProvenanceAttestation struct {
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	PayloadType       string    `json:"payload_type" db:"payload_type"`
+	Payload           string    `json:"payload" db:"payload"`
+	KeyName           string    `json:"key_name" db:"key_name"`
+	KeyID             string    `json:"key_id" db:"key_id"`
+	Signature         string    `json:"signature" db:"signature"`
+	Created           time.Time `json:"created" db:"created"`
+}
+
+//ProvenanceVerification is the result of the verification of a provenance attestation
+type ProvenanceVerification struct {
+	Verified bool   `json:"verified" cli:"verified"`
+	KeyName  string `json:"key_name" cli:"key_name"`
+	KeyID    string `json:"key_id" cli:"key_id"`
+	Error    string `json:"error,omitempty" cli:"error"`
+}
+
+//NewProvenanceStatement returns a statement for a node run and its artifacts, password and key parameters are excluded
+func NewProvenanceStatement(entryPoint string, nodeRun WorkflowNodeRun, models []string) ProvenanceStatement {
+	s := ProvenanceStatement{
+		Type:          ProvenanceStatementType,
+		PredicateType: ProvenancePredicateType,
+		Subject:       []ProvenanceSubject{},
+		Predicate: ProvenancePredicate{
+			Builder: ProvenanceBuilder{ID: ProvenanceBuilderIDPrefix + VERSION},
+			Recipe: ProvenanceRecipe{
+				Type:         ProvenanceRecipeType,
+				EntryPoint:   entryPoint,
+				Arguments:    []Parameter{},
+				WorkerModels: models,
+			},
+			Metadata: ProvenanceMetadata{
+				BuildStartedOn:  nodeRun.Start,
+				BuildFinishedOn: nodeRun.Done,
+			},
+			Materials: []ProvenanceMaterial{},
+		},
+	}
+
+	for _, a := range nodeRun.Artifacts {
+		digest := map[string]string{"md5": a.MD5sum}
+		if a.SHA256sum != "" {
+			digest["sha256"] = a.SHA256sum
+		}
+		s.Subject = append(s.Subject, ProvenanceSubject{Name: a.Name, Digest: digest})
+	}
+
+	for _, p := range nodeRun.BuildParameters {
+		if NeedPlaceholder(p.Type) || strings.HasSuffix(p.Name, ".priv") {
+			continue
+		}
+		s.Predicate.Recipe.Arguments = append(s.Predicate.Recipe.Arguments, p)
+	}
+
+	if hash := ParameterValue(nodeRun.BuildParameters, "git.hash"); hash != "" {
+		uri := ParameterValue(nodeRun.BuildParameters, "git.http_url")
+		if uri == "" {
+			uri = ParameterValue(nodeRun.BuildParameters, "git.url")
+		}
+		s.Predicate.Materials = append(s.Predicate.Materials, ProvenanceMaterial{
+			URI:    uri,
+			Digest: map[string]string{"sha1": hash},
+		})
+	}
+
+	return s
+}
+
+//Statement decodes the payload of the attestation
+func (a *ProvenanceAttestation) Statement() (*ProvenanceStatement, error) {
+	b, err := base64.StdEncoding.DecodeString(a.Payload)
+	if err != nil {
+		return nil, err
+	}
+	s := &ProvenanceStatement{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+//Verify checks the signature of the attestation with an armored PGP public key
+func (a *ProvenanceAttestation) Verify(publicKey string) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(publicKey))
+	if err != nil {
+		return fmt.Errorf("invalid public key %s: %v", a.KeyName, err)
+	}
+	payload, err := base64.StdEncoding.DecodeString(a.Payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %v", err)
+	}
+	if _, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(payload), strings.NewReader(a.Signature)); err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	return nil
+}
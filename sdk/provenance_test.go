@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+func TestNewProvenanceStatement(t *testing.T) {
+	nr := WorkflowNodeRun{
+		BuildParameters: []Parameter{
+			{Name: "git.hash", Type: StringParameter, Value: "abcdef"},
+			{Name: "git.url", Type: StringParameter, Value: "git@github.com:ovh/cds.git"},
+			{Name: "cds.proj.password", Type: SecretVariable, Value: "secret"},
+			{Name: "cds.key.mykey.priv", Type: StringParameter, Value: "private"},
+		},
+		Artifacts: []WorkflowNodeRunArtifact{
+			{Name: "foo.tar.gz", MD5sum: "md5", SHA256sum: "sha256"},
+		},
+	}
+
+	s := NewProvenanceStatement("PRJ/wf/build", nr, []string{"golang"})
+	assert.Equal(t, ProvenanceStatementType, s.Type)
+	assert.Equal(t, []ProvenanceSubject{{Name: "foo.tar.gz", Digest: map[string]string{"md5": "md5", "sha256": "sha256"}}}, s.Subject)
+	assert.Len(t, s.Predicate.Recipe.Arguments, 2)
+	assert.Equal(t, []string{"golang"}, s.Predicate.Recipe.WorkerModels)
+	assert.Equal(t, []ProvenanceMaterial{{URI: "git@github.com:ovh/cds.git", Digest: map[string]string{"sha1": "abcdef"}}}, s.Predicate.Materials)
+}
+
+func TestProvenanceAttestationVerify(t *testing.T) {
+	e, err := openpgp.NewEntity("mykey", "mykey", "cds@localhost", nil)
+	assert.NoError(t, err)
+
+	//SerializePrivate self-signs the identities of the entity
+	assert.NoError(t, e.SerializePrivate(new(bytes.Buffer), nil))
+	pub := new(bytes.Buffer)
+	w, err := armor.Encode(pub, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Serialize(w))
+	w.Close()
+
+	payload, _ := json.Marshal(NewProvenanceStatement("PRJ/wf/build", WorkflowNodeRun{}, nil))
+	sig := new(bytes.Buffer)
+	assert.NoError(t, openpgp.ArmoredDetachSign(sig, e, bytes.NewReader(payload), nil))
+
+	att := ProvenanceAttestation{
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: sig.String(),
+	}
+	assert.NoError(t, att.Verify(pub.String()))
+
+	s, err := att.Statement()
+	assert.NoError(t, err)
+	assert.Equal(t, "PRJ/wf/build", s.Predicate.Recipe.EntryPoint)
+
+	att.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type":"tampered"}`))
+	assert.Error(t, att.Verify(pub.String()))
+}
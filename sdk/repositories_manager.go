@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -15,6 +16,10 @@ const (
 	Stash RepositoriesManagerType = "STASH"
 	//Github is valued to "GITHUB"
 	Github RepositoriesManagerType = "GITHUB"
+	//Gitlab is valued to "GITLAB"
+	Gitlab RepositoriesManagerType = "GITLAB"
+	//Gitea is valued to "GITEA", it also supports Gogs
+	Gitea RepositoriesManagerType = "GITEA"
 )
 
 //RepositoriesManager is the struct for every repositories manager.
@@ -306,6 +311,10 @@ type RepositoriesManagerClient interface {
 	DeleteEvents(string, []interface{}) ([]VCSDeleteEvent, error)
 	PullRequestEvents(string, []interface{}) ([]VCSPullRequestEvent, error)
 
+	//Pull requests
+	PullRequests(repo string) ([]VCSPullRequest, error)
+	PullRequestComment(repo string, id int, text string) error
+
 	// Set build status on repository
 	SetStatus(event Event) error
 }
@@ -364,9 +373,48 @@ type VCSDeleteEvent struct {
 //VCSPullRequestEvent represents a push events for polling
 type VCSPullRequestEvent struct {
 	Action string       `json:"action"` // opened | closed
+	ID     int          `json:"id"`
 	URL    string       `json:"url"`
 	User   VCSAuthor    `json:"user"`
 	Head   VCSPushEvent `json:"head"`
 	Base   VCSPushEvent `json:"base"`
 	Branch VCSBranch    `json:"branch"`
+	Ref    string       `json:"ref"`  //Git reference of the pull request on the base repository: refs/pull/1/merge on Github
+	Fork   bool         `json:"fork"` //True if the head branch is on another repository
+	//MergeBase is the base branch to merge into Ref when the repositories manager does not provide a merge ref (Gitlab, Gitea)
+	MergeBase string `json:"merge_base,omitempty"`
+}
+
+//PullRequestParameters returns the git parameters of a build triggered by a pull request
+func PullRequestParameters(e VCSPullRequestEvent) []Parameter {
+	params := []Parameter{}
+	AddParameter(&params, "git.branch", StringParameter, e.Head.Branch.DisplayID)
+	AddParameter(&params, "git.hash", StringParameter, e.Head.Commit.Hash)
+	AddParameter(&params, "git.author", StringParameter, e.User.Name)
+	AddParameter(&params, "git.pr.id", StringParameter, strconv.Itoa(e.ID))
+	AddParameter(&params, "git.pr.url", StringParameter, e.URL)
+	AddParameter(&params, "git.pr.base", StringParameter, e.Base.Branch.DisplayID)
+	AddParameter(&params, "git.pr.head", StringParameter, e.Head.Branch.DisplayID)
+	AddParameter(&params, "git.pr.author", StringParameter, e.User.Name)
+	AddParameter(&params, "git.pr.ref", StringParameter, e.Ref)
+	AddParameter(&params, "git.pr.fork", BooleanParameter, strconv.FormatBool(e.Fork))
+	if e.MergeBase != "" {
+		AddParameter(&params, "git.pr.merge_base", StringParameter, e.MergeBase)
+	}
+	return params
+}
+
+//IsForkPullRequest returns true if the parameters are the one of a build triggered by a pull request from a fork
+func IsForkPullRequest(params []Parameter) bool {
+	return ParameterValue(params, "git.pr.fork") == "true"
+}
+
+//VCSPullRequest represents an opened pull request on a repository
+type VCSPullRequest struct {
+	ID    int          `json:"id"`
+	Title string       `json:"title"`
+	URL   string       `json:"url"`
+	User  VCSAuthor    `json:"user"`
+	Head  VCSPushEvent `json:"head"`
+	Base  VCSPushEvent `json:"base"`
 }
@@ -34,6 +34,10 @@ var (
 	RequestedWithValue = "X-CDS-SDK"
 	//SessionTokenHeader is user as HTTP header
 	SessionTokenHeader = "Session-Token"
+	//AccessTokenHeader is used as HTTP header to authenticate with a personal access token or a service account token
+	AccessTokenHeader = "Access-Token"
+	//PassphraseHeader is used as HTTP header to send the passphrase protecting the secrets of an exported project
+	PassphraseHeader = "Passphrase"
 	// HTTP client
 	client HTTPClient
 	// current agent calling
@@ -0,0 +1,63 @@
+package sdk
+
+// Capability is an action a group is allowed to do on a project, an application, a pipeline or an environment
+type Capability string
+
+// Capabilities granted by roles
+const (
+	CapabilityRead               Capability = "read"
+	CapabilityExecute            Capability = "execute"
+	CapabilityWrite              Capability = "write"
+	CapabilityManageVariables    Capability = "manage-variables"
+	CapabilityManageKeys         Capability = "manage-keys"
+	CapabilityApproveDeployments Capability = "approve-deployments"
+)
+
+// Roles names
+const (
+	RoleRead               = "read"
+	RoleReadExecute        = "read-execute"
+	RoleReadWriteExecute   = "read-write-execute"
+	RoleDeployer           = "deployer"
+	RoleKeyManager         = "key-manager"
+	RoleVariableManager    = "variable-manager"
+	RoleDeploymentApprover = "deployment-approver"
+)
+
+// Role is a named set of capabilities which can be given to a group
+type Role struct {
+	Name         string       `json:"name" cli:"name,key"`
+	Capabilities []Capability `json:"capabilities" cli:"capabilities"`
+}
+
+// Has returns true if the role grants the capability
+func (r Role) Has(c Capability) bool {
+	for _, rc := range r.Capabilities {
+		if rc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles are all the roles which can be given to a group.
+// read, read-execute and read-write-execute are the roles matching the legacy numeric permissions
+var Roles = []Role{
+	{Name: RoleRead, Capabilities: []Capability{CapabilityRead}},
+	{Name: RoleReadExecute, Capabilities: []Capability{CapabilityRead, CapabilityExecute}},
+	{Name: RoleReadWriteExecute, Capabilities: []Capability{CapabilityRead, CapabilityExecute, CapabilityWrite, CapabilityManageVariables, CapabilityManageKeys, CapabilityApproveDeployments}},
+	{Name: RoleDeployer, Capabilities: []Capability{CapabilityRead, CapabilityExecute, CapabilityApproveDeployments}},
+	{Name: RoleKeyManager, Capabilities: []Capability{CapabilityRead, CapabilityManageKeys}},
+	{Name: RoleVariableManager, Capabilities: []Capability{CapabilityRead, CapabilityManageVariables}},
+	{Name: RoleDeploymentApprover, Capabilities: []Capability{CapabilityRead, CapabilityApproveDeployments}},
+}
+
+// RoleByName returns the role with the given name
+func RoleByName(name string) (Role, bool) {
+	for _, r := range Roles {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Role{}, false
+}
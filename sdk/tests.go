@@ -3,10 +3,72 @@ package sdk
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ovh/venom"
 )
 
+// Test case statuses recorded in the tests history
+const (
+	TestCaseStatusSuccess = "Success"
+	TestCaseStatusFail    = "Fail"
+)
+
+//TestCaseResult is the result of a test case for a run of an application on a branch
+type TestCaseResult struct {
+	ID            int64     `json:"id" db:"id"`
+	ApplicationID int64     `json:"application_id" db:"application_id"`
+	Branch        string    `json:"branch" db:"branch"`
+	Hash          string    `json:"hash" db:"hash"`
+	Num           int64     `json:"num" db:"num"`
+	TestSuite     string    `json:"test_suite" db:"test_suite"`
+	TestName      string    `json:"test_name" db:"test_name"`
+	Status        string    `json:"status" db:"status"`
+	Created       time.Time `json:"created" db:"created"`
+}
+
+//FlakyTest is a test case whose status flipped between two runs of the same commit
+type FlakyTest struct {
+	ApplicationID int64     `json:"application_id" db:"application_id" cli:"-"`
+	Branch        string    `json:"branch" db:"branch" cli:"branch"`
+	TestSuite     string    `json:"test_suite" db:"test_suite" cli:"test_suite"`
+	TestName      string    `json:"test_name" db:"test_name" cli:"test_name"`
+	Flips         int64     `json:"flips" db:"flips" cli:"flips"`
+	LastHash      string    `json:"last_hash" db:"last_hash" cli:"last_hash"`
+	LastFlip      time.Time `json:"last_flip" db:"last_flip" cli:"last_flip"`
+	Quarantined   bool      `json:"quarantined" db:"-" cli:"quarantined"`
+}
+
+//TestQuarantine is a known flaky test: its failures don't fail the job
+type TestQuarantine struct {
+	ApplicationID int64     `json:"application_id" db:"application_id" cli:"-"`
+	TestSuite     string    `json:"test_suite" db:"test_suite" cli:"test_suite"`
+	TestName      string    `json:"test_name" db:"test_name" cli:"test_name"`
+	Author        string    `json:"author" db:"author" cli:"author"`
+	Created       time.Time `json:"created" db:"created" cli:"created"`
+}
+
+//IsTestQuarantined returns true if the test case is in the quarantine list
+func IsTestQuarantined(quarantine []TestQuarantine, testSuite, testName string) bool {
+	for _, q := range quarantine {
+		if q.TestSuite == testSuite && q.TestName == testName {
+			return true
+		}
+	}
+	return false
+}
+
+//TestCaseStatus returns the status of a test case for the tests history, an empty string if the test case has been skipped
+func TestCaseStatus(tc venom.TestCase) string {
+	switch {
+	case len(tc.Failures) > 0 || len(tc.Errors) > 0:
+		return TestCaseStatusFail
+	case tc.Skipped > 0:
+		return ""
+	}
+	return TestCaseStatusSuccess
+}
+
 // GetTestResults retrieves tests results for a specific build
 func GetTestResults(proj, app, pip, env string, bn int) (venom.Tests, error) {
 	if env == "" {
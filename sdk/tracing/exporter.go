@@ -0,0 +1,249 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Exporters
+const (
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Config is the configuration of the tracing
+type Config struct {
+	// Exporter is stdout, file or otlp. Tracing is disabled if empty
+	Exporter string
+	// File is the file the spans are written to with the file exporter
+	File string
+	// Endpoint is the URL of the OpenTelemetry collector with the otlp exporter, e.g. http://localhost:4318
+	Endpoint string
+	// ServiceName identifies the component in the traces: cds-api, cds-hatchery, cds-worker
+	ServiceName string
+}
+
+var (
+	providerMutex sync.RWMutex
+	provider      *sdktrace.TracerProvider
+)
+
+func currentProvider() *sdktrace.TracerProvider {
+	providerMutex.RLock()
+	defer providerMutex.RUnlock()
+	return provider
+}
+
+// SetExporter sets the exporter of the spans of the service, tracing is disabled with a nil exporter.
+// The spans of the previous exporter are flushed
+func SetExporter(e sdktrace.SpanExporter, service string) {
+	var p *sdktrace.TracerProvider
+	if e != nil {
+		p = sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(e),
+			sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(service))),
+		)
+	}
+
+	providerMutex.Lock()
+	old := provider
+	provider = p
+	providerMutex.Unlock()
+
+	if old != nil {
+		if err := old.Shutdown(context.Background()); err != nil {
+			log.Warning("tracing> Unable to flush spans: %s", err)
+		}
+	}
+}
+
+// Init configures the exporter of the spans
+func Init(cfg Config) error {
+	switch cfg.Exporter {
+	case "":
+		SetExporter(nil, cfg.ServiceName)
+		return nil
+	case ExporterStdout:
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return fmt.Errorf("tracing: unable to create the stdout exporter: %s", err)
+		}
+		SetExporter(e, cfg.ServiceName)
+	case ExporterFile:
+		if cfg.File == "" {
+			return fmt.Errorf("tracing: a file is mandatory with the file exporter")
+		}
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("tracing: unable to open %s: %s", cfg.File, err)
+		}
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("tracing: unable to create the file exporter: %s", err)
+		}
+		SetExporter(&fileExporter{SpanExporter: e, f: f}, cfg.ServiceName)
+	case ExporterOTLP:
+		if cfg.Endpoint == "" {
+			return fmt.Errorf("tracing: an endpoint is mandatory with the otlp exporter")
+		}
+		SetExporter(NewOTLPExporter(cfg.Endpoint), cfg.ServiceName)
+	default:
+		return fmt.Errorf("tracing: unknown exporter %s", cfg.Exporter)
+	}
+	log.Info("Tracing enabled with the %s exporter", cfg.Exporter)
+	return nil
+}
+
+// Close flushes the spans and disables the tracing
+func Close() {
+	SetExporter(nil, "")
+}
+
+// fileExporter closes the file the spans are written to on shutdown
+type fileExporter struct {
+	sdktrace.SpanExporter
+	f *os.File
+}
+
+// Shutdown closes the file
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	if err := e.SpanExporter.Shutdown(ctx); err != nil {
+		return err
+	}
+	return e.f.Close()
+}
+
+// OTLPExporter sends the spans to an OpenTelemetry collector, with the OTLP/HTTP JSON protocol
+type OTLPExporter struct {
+	url    string
+	client *http.Client
+}
+
+// NewOTLPExporter returns an exporter sending the spans to the collector at endpoint
+func NewOTLPExporter(endpoint string) *OTLPExporter {
+	return &OTLPExporter{
+		url:    strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ExportSpans sends a batch of spans to the collector
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(otlpRequest(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", e.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("tracing: unable to export %d spans: HTTP %d", len(spans), resp.StatusCode)
+	}
+	return nil
+}
+
+// Shutdown does nothing, the spans are sent synchronously
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+func otlpAttributes(kvs []attribute.KeyValue) []otlpAttribute {
+	attrs := make([]otlpAttribute, len(kvs))
+	for i, kv := range kvs {
+		attrs[i] = otlpAttribute{Key: string(kv.Key), Value: otlpValue{StringValue: kv.Value.Emit()}}
+	}
+	return attrs
+}
+
+// otlpRequest returns the body of an OTLP/HTTP JSON export request, the spans of a batch come from the same provider and share their resource
+func otlpRequest(batch []sdktrace.ReadOnlySpan) interface{} {
+	spans := make([]otlpSpan, len(batch))
+	for i, s := range batch {
+		spans[i] = otlpSpan{
+			TraceID:           s.SpanContext().TraceID().String(),
+			SpanID:            s.SpanContext().SpanID().String(),
+			Name:              s.Name(),
+			Kind:              int(s.SpanKind()),
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime().UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime().UnixNano(), 10),
+			Attributes:        otlpAttributes(s.Attributes()),
+		}
+		if s.Parent().HasSpanID() {
+			spans[i].ParentSpanID = s.Parent().SpanID().String()
+		}
+		if s.Status().Code == codes.Error {
+			spans[i].Status = otlpStatus{Code: 2, Message: s.Status().Description}
+		}
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": otlpAttributes(batch[0].Resource().Attributes()),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": instrumentationName},
+						"spans": spans,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header is the W3C trace context header used to propagate the traces between the API, the hatcheries and the workers
+const Header = "traceparent"
+
+// instrumentationName is the name of the tracer of all the CDS components
+const instrumentationName = "github.com/ovh/cds"
+
+var propagator = propagation.TraceContext{}
+
+// ContextWithTraceParent returns a context carrying the span context of a W3C traceparent. The context is unchanged if the traceparent is invalid
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return propagator.Extract(ctx, propagation.MapCarrier{Header: traceParent})
+}
+
+// TraceParent returns the W3C traceparent of the span context carried by a context, or an empty string
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get(Header)
+}
+
+// InjectHTTP sets the traceparent header of a request from the span context carried by a context
+func InjectHTTP(ctx context.Context, req *http.Request) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+}
+
+// ExtractHTTP returns the context of a request carrying the span context of its traceparent header
+func ExtractHTTP(req *http.Request) context.Context {
+	return propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+}
+
+// Span is an operation of a trace
+type Span struct {
+	span trace.Span
+}
+
+// Start starts a span, child of the span carried by the context. The returned context carries the new span.
+// When tracing is disabled, the span is nil and the context is unchanged: the trace context is still propagated
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	p := currentProvider()
+	if p == nil {
+		return ctx, nil
+	}
+	ctx, s := p.Tracer(instrumentationName).Start(ctx, name)
+	return ctx, &Span{span: s}
+}
+
+// StartFromTraceParent starts a span, child of the span of a W3C traceparent
+func StartFromTraceParent(ctx context.Context, name, traceParent string) (context.Context, *Span) {
+	return Start(ContextWithTraceParent(ctx, traceParent), name)
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attribute.String(key, fmt.Sprintf("%v", value)))
+}
+
+// SetError marks the span as failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// TraceParent returns the W3C traceparent of the span
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+	return TraceParent(trace.ContextWithSpan(context.Background(), s.span))
+}
+
+// Finish ends the span, it's exported by the exporter of the tracing
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+)
+
+func TestContextWithTraceParent(t *testing.T) {
+	tp := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	assert.Equal(t, tp, TraceParent(ContextWithTraceParent(context.Background(), tp)))
+
+	for _, s := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-zzad6b7169203331-01",
+	} {
+		assert.Equal(t, "", TraceParent(ContextWithTraceParent(context.Background(), s)), s)
+	}
+}
+
+func TestStartDisabled(t *testing.T) {
+	Close()
+	ctx := ContextWithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	ctx2, span := Start(ctx, "disabled")
+	assert.Nil(t, span)
+	assert.Equal(t, ctx, ctx2)
+
+	//Nil spans are usable
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("error"))
+	span.Finish()
+}
+
+type stdoutSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+	Attributes []struct {
+		Key   string
+		Value struct {
+			Value interface{}
+		}
+	}
+	Status struct {
+		Code        string
+		Description string
+	}
+	Resource []struct {
+		Key   string
+		Value struct {
+			Value interface{}
+		}
+	}
+}
+
+func TestStart(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e, err := stdouttrace.New(stdouttrace.WithWriter(buf))
+	assert.NoError(t, err)
+	SetExporter(e, "cds-test")
+
+	ctx := ContextWithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	ctx, parent := Start(ctx, "parent")
+	_, child := Start(ctx, "child")
+	child.SetAttribute("job_id", 42)
+	child.SetError(errors.New("spawn failed"))
+	child.Finish()
+	child.Finish()
+	parent.Finish()
+	Close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var c, p stdoutSpan
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &c))
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &p))
+
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", p.SpanContext.TraceID)
+	assert.Equal(t, "b7ad6b7169203331", p.Parent.SpanID)
+	assert.Equal(t, p.SpanContext.TraceID, c.SpanContext.TraceID)
+	assert.Equal(t, p.SpanContext.SpanID, c.Parent.SpanID)
+	assert.Equal(t, "child", c.Name)
+	assert.Equal(t, "Error", c.Status.Code)
+	assert.Equal(t, "spawn failed", c.Status.Description)
+
+	attrs := map[string]interface{}{}
+	for _, a := range c.Attributes {
+		attrs[a.Key] = a.Value.Value
+	}
+	assert.Equal(t, "42", attrs["job_id"])
+
+	resource := map[string]interface{}{}
+	for _, a := range c.Resource {
+		resource[a.Key] = a.Value.Value
+	}
+	assert.Equal(t, "cds-test", resource["service.name"])
+}
+
+func TestHTTPPropagation(t *testing.T) {
+	e, err := stdouttrace.New(stdouttrace.WithWriter(ioutil.Discard))
+	assert.NoError(t, err)
+	SetExporter(e, "cds-test")
+	defer Close()
+
+	ctx, span := Start(context.Background(), "request")
+	req := httptest.NewRequest("GET", "/mon/status", nil)
+	InjectHTTP(ctx, req)
+	assert.NotEmpty(t, span.TraceParent())
+	assert.Equal(t, span.TraceParent(), req.Header.Get(Header))
+	assert.Equal(t, span.TraceParent(), TraceParent(ExtractHTTP(req)))
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer srv.Close()
+
+	assert.NoError(t, Init(Config{Exporter: ExporterOTLP, Endpoint: srv.URL, ServiceName: "cds-test"}))
+	_, span := Start(context.Background(), "spawn")
+	span.SetError(errors.New("spawn failed"))
+	span.Finish()
+	Close()
+
+	b, err := json.Marshal(body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"name":"spawn"`)
+	assert.Contains(t, string(b), `"stringValue":"cds-test"`)
+	assert.Contains(t, string(b), `"status":{"code":2,"message":"spawn failed"}`)
+}
@@ -21,6 +21,8 @@ type CloneOpts struct {
 	Verbose                 bool
 	Quiet                   bool
 	CheckoutCommit          string
+	CheckoutRef             string
+	MergeBranch             string
 	NoStrictHostKeyChecking bool
 }
 
@@ -31,6 +33,12 @@ func Clone(repo string, path string, auth *AuthOpts, opts *CloneOpts, output *Ou
 		if opts != nil && opts.CheckoutCommit != "" {
 			defer LogFunc("Checkout commit %s", opts.CheckoutCommit)
 		}
+		if opts != nil && opts.CheckoutRef != "" {
+			defer LogFunc("Checkout ref %s", opts.CheckoutRef)
+		}
+		if opts != nil && opts.MergeBranch != "" {
+			defer LogFunc("Merge branch %s", opts.MergeBranch)
+		}
 		defer LogFunc("Git clone %s (%v s)", path, int(time.Since(t1).Seconds()))
 	}
 
@@ -106,7 +114,7 @@ func gitCloneCommand(repo string, path string, opts *CloneOpts) cmds {
 			gitcmd.args = append(gitcmd.args, "--verbose")
 		}
 
-		if opts.CheckoutCommit == "" {
+		if opts.CheckoutCommit == "" && opts.CheckoutRef == "" {
 			if opts.Depth != 0 {
 				gitcmd.args = append(gitcmd.args, "--depth", fmt.Sprintf("%d", opts.Depth))
 			}
@@ -131,19 +139,46 @@ func gitCloneCommand(repo string, path string, opts *CloneOpts) cmds {
 
 	allCmd = append(allCmd, gitcmd)
 
-	if opts != nil && opts.CheckoutCommit != "" {
+	//Locate the git commands run after the clone to the right directory
+	dir := path
+	if dir == "" {
+		t := strings.Split(repo, "/")
+		dir = strings.TrimSuffix(t[len(t)-1], ".git")
+	}
+
+	if opts != nil && opts.CheckoutRef != "" {
+		fetchCmd := cmd{
+			cmd:  "git",
+			args: []string{"fetch", "origin", opts.CheckoutRef},
+			dir:  dir,
+		}
+		checkoutCmd := cmd{
+			cmd:  "git",
+			args: []string{"checkout", "--detach", "FETCH_HEAD"},
+			dir:  dir,
+		}
+		allCmd = append(allCmd, fetchCmd, checkoutCmd)
+
+		//Merge the base branch when the ref is only the head of the pull request
+		if opts.MergeBranch != "" {
+			fetchBaseCmd := cmd{
+				cmd:  "git",
+				args: []string{"fetch", "origin", opts.MergeBranch},
+				dir:  dir,
+			}
+			mergeCmd := cmd{
+				cmd:  "git",
+				args: []string{"-c", "user.name=cds", "-c", "user.email=cds@localhost", "merge", "--no-edit", "FETCH_HEAD"},
+				dir:  dir,
+			}
+			allCmd = append(allCmd, fetchBaseCmd, mergeCmd)
+		}
+	} else if opts != nil && opts.CheckoutCommit != "" {
 		resetCmd := cmd{
 			cmd:  "git",
 			args: []string{"reset", "--hard", opts.CheckoutCommit},
+			dir:  dir,
 		}
-		//Locate the git reset cmd to the right directory
-		if path == "" {
-			t := strings.Split(repo, "/")
-			resetCmd.dir = strings.TrimSuffix(t[len(t)-1], ".git")
-		} else {
-			resetCmd.dir = path
-		}
-
 		allCmd = append(allCmd, resetCmd)
 	}
 
@@ -210,6 +210,43 @@ func Test_gitCommand(t *testing.T) {
 				"git reset --hard eb8b87a",
 			},
 		},
+		{
+			name: "Clone public repo over http and checkout pull request ref",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "/tmp/Test_gitCommand-4",
+				opts: &CloneOpts{
+					Depth:          1,
+					Quiet:          true,
+					CheckoutCommit: "eb8b87a",
+					CheckoutRef:    "refs/pull/42/merge",
+				},
+			},
+			want: []string{
+				"git clone --quiet https://github.com/ovh/cds.git /tmp/Test_gitCommand-4",
+				"git fetch origin refs/pull/42/merge",
+				"git checkout --detach FETCH_HEAD",
+			},
+		},
+		{
+			name: "Clone public repo over http and merge the base branch into the pull request ref",
+			args: args{
+				repo: "https://gitlab.com/ovh/cds.git",
+				path: "/tmp/Test_gitCommand-5",
+				opts: &CloneOpts{
+					Quiet:       true,
+					CheckoutRef: "refs/merge-requests/42/head",
+					MergeBranch: "master",
+				},
+			},
+			want: []string{
+				"git clone --quiet https://gitlab.com/ovh/cds.git /tmp/Test_gitCommand-5",
+				"git fetch origin refs/merge-requests/42/head",
+				"git checkout --detach FETCH_HEAD",
+				"git fetch origin master",
+				"git -c user.name=cds -c user.email=cds@localhost merge --no-edit FETCH_HEAD",
+			},
+		},
 	}
 	for _, tt := range tests {
 		os.RemoveAll(tt.args.path)
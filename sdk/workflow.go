@@ -338,14 +338,35 @@ type WorkflowTriggerCondition struct {
 
 //WorkflowNodeContext represents a context attached on a node
 type WorkflowNodeContext struct {
-	ID                        int64        `json:"id" db:"id"`
-	WorkflowNodeID            int64        `json:"workflow_node_id" db:"workflow_node_id"`
-	ApplicationID             int64        `json:"application_id" db:"application_id"`
-	Application               *Application `json:"application,omitempty" db:"-"`
-	Environment               *Environment `json:"environment,omitempty" db:"-"`
-	EnvironmentID             int64        `json:"environment_id" db:"environment_id"`
-	DefaultPayload            interface{}  `json:"default_payload,omitempty" db:"-"`
-	DefaultPipelineParameters []Parameter  `json:"default_pipeline_parameters,omitempty" db:"-"`
+	ID                        int64                 `json:"id" db:"id"`
+	WorkflowNodeID            int64                 `json:"workflow_node_id" db:"workflow_node_id"`
+	ApplicationID             int64                 `json:"application_id" db:"application_id"`
+	Application               *Application          `json:"application,omitempty" db:"-"`
+	Environment               *Environment          `json:"environment,omitempty" db:"-"`
+	EnvironmentID             int64                 `json:"environment_id" db:"environment_id"`
+	DefaultPayload            interface{}           `json:"default_payload,omitempty" db:"-"`
+	DefaultPipelineParameters []Parameter           `json:"default_pipeline_parameters,omitempty" db:"-"`
+	Approval                  *WorkflowNodeApproval `json:"approval,omitempty" db:"-"`
+}
+
+//WorkflowNodeApproval is an approval gate on a workflow node: the node run waits until enough members of the groups approve it
+type WorkflowNodeApproval struct {
+	Groups       []string `json:"groups"`
+	MinApprovals int      `json:"min_approvals"`
+	//Timeout is the number of seconds after which the node run is rejected, 0 means no timeout
+	Timeout int64 `json:"timeout,omitempty"`
+}
+
+//IsApprover returns true if the user is member of one of the groups of the approval gate
+func (a *WorkflowNodeApproval) IsApprover(u *User) bool {
+	for _, g := range u.Groups {
+		for _, name := range a.Groups {
+			if g.Name == name {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 //WorkflowNodeHook represents a hook which cann trigger the workflow from a given node
@@ -3,7 +3,10 @@ package sdk
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/ovh/cds/sdk/interpolate"
 )
 
 // Workflow conditions operator
@@ -15,31 +18,33 @@ const (
 	WorkflowConditionsOperatorGreaterThan        = "gt"
 	WorkflowConditionsOperatorGreaterOrEqualThan = "ge"
 	WorkflowConditionsOperatorRegex              = "regex"
+	// The numeric operators compare the values as numbers, the condition is false if one of them is not a number
+	WorkflowConditionsOperatorNumberLessThan           = "num_lt"
+	WorkflowConditionsOperatorNumberLessOrEqualThan    = "num_le"
+	WorkflowConditionsOperatorNumberGreaterThan        = "num_gt"
+	WorkflowConditionsOperatorNumberGreaterOrEqualThan = "num_ge"
 )
 
 // Workflow conditions operator
 var (
 	WorkflowConditionsOperators = map[string]string{
-		WorkflowConditionsOperatorEquals:             "=",
-		WorkflowConditionsOperatorNotEquals:          "!=",
-		WorkflowConditionsOperatorLessThan:           "<",
-		WorkflowConditionsOperatorLessOrEqualThan:    "<=",
-		WorkflowConditionsOperatorGreaterThan:        ">",
-		WorkflowConditionsOperatorGreaterOrEqualThan: ">=",
-		WorkflowConditionsOperatorRegex:              "match",
+		WorkflowConditionsOperatorEquals:                   "=",
+		WorkflowConditionsOperatorNotEquals:                "!=",
+		WorkflowConditionsOperatorLessThan:                 "<",
+		WorkflowConditionsOperatorLessOrEqualThan:          "<=",
+		WorkflowConditionsOperatorGreaterThan:              ">",
+		WorkflowConditionsOperatorGreaterOrEqualThan:       ">=",
+		WorkflowConditionsOperatorRegex:                    "match",
+		WorkflowConditionsOperatorNumberLessThan:           "< (number)",
+		WorkflowConditionsOperatorNumberLessOrEqualThan:    "<= (number)",
+		WorkflowConditionsOperatorNumberGreaterThan:        "> (number)",
+		WorkflowConditionsOperatorNumberGreaterOrEqualThan: ">= (number)",
 	}
 )
 
 //WorkflowCheckConditions checks conditions given a list of parameters
 func WorkflowCheckConditions(conditions []WorkflowTriggerCondition, params []Parameter) (bool, error) {
-	mapParams := ParametersToMap(params)
-	for k, v := range mapParams {
-		var err error
-		mapParams[k], err = Interpolate(v, mapParams)
-		if err != nil {
-			return false, fmt.Errorf("Unable to interpolate %s (%v)", v, err)
-		}
-	}
+	mapParams := interpolate.Resolve(ParametersToMap(params))
 
 	var conditionsOK = true
 	for _, cond := range conditions {
@@ -68,6 +73,22 @@ func WorkflowCheckConditions(conditions []WorkflowTriggerCondition, params []Par
 		case WorkflowConditionsOperatorGreaterOrEqualThan:
 			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) >= 0
 
+		case WorkflowConditionsOperatorNumberLessThan:
+			c, ok := compareConditionNumbers(mapParams[cond.Variable], cond.Value)
+			conditionsOK = conditionsOK && ok && c < 0
+
+		case WorkflowConditionsOperatorNumberLessOrEqualThan:
+			c, ok := compareConditionNumbers(mapParams[cond.Variable], cond.Value)
+			conditionsOK = conditionsOK && ok && c <= 0
+
+		case WorkflowConditionsOperatorNumberGreaterThan:
+			c, ok := compareConditionNumbers(mapParams[cond.Variable], cond.Value)
+			conditionsOK = conditionsOK && ok && c > 0
+
+		case WorkflowConditionsOperatorNumberGreaterOrEqualThan:
+			c, ok := compareConditionNumbers(mapParams[cond.Variable], cond.Value)
+			conditionsOK = conditionsOK && ok && c >= 0
+
 		case WorkflowConditionsOperatorRegex:
 			match, err := regexp.MatchString(cond.Value, mapParams[cond.Variable])
 			if err != nil {
@@ -79,3 +100,19 @@ func WorkflowCheckConditions(conditions []WorkflowTriggerCondition, params []Par
 
 	return conditionsOK, nil
 }
+
+//compareConditionNumbers compares two numbers. It returns false if one of the values is not a number
+func compareConditionNumbers(a, b string) (int, bool) {
+	fa, erra := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errb := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if erra != nil || errb != nil {
+		return 0, false
+	}
+	switch {
+	case fa < fb:
+		return -1, true
+	case fa > fb:
+		return 1, true
+	}
+	return 0, true
+}
@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowCheckConditionsNumbers(t *testing.T) {
+	params := []Parameter{
+		{Name: "cds.coverage.lines", Value: "9.5"},
+		{Name: "cds.coverage.lines.delta", Value: "-0.25"},
+		{Name: "git.branch", Value: "master"},
+	}
+
+	ok, err := WorkflowCheckConditions([]WorkflowTriggerCondition{
+		{Variable: "cds.coverage.lines", Operator: WorkflowConditionsOperatorNumberLessThan, Value: "80"},
+		{Variable: "cds.coverage.lines.delta", Operator: WorkflowConditionsOperatorNumberGreaterOrEqualThan, Value: "-1"},
+		{Variable: "git.branch", Operator: WorkflowConditionsOperatorEquals, Value: "master"},
+	}, params)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = WorkflowCheckConditions([]WorkflowTriggerCondition{
+		{Variable: "cds.coverage.lines.delta", Operator: WorkflowConditionsOperatorNumberGreaterOrEqualThan, Value: "0"},
+	}, params)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	//A value which is not a number never matches a numeric operator
+	ok, err = WorkflowCheckConditions([]WorkflowTriggerCondition{
+		{Variable: "git.branch", Operator: WorkflowConditionsOperatorNumberLessThan, Value: "80"},
+	}, params)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	//The lt, le, gt and ge operators still compare strings
+	ok, err = WorkflowCheckConditions([]WorkflowTriggerCondition{
+		{Variable: "cds.coverage.lines", Operator: WorkflowConditionsOperatorGreaterThan, Value: "80"},
+	}, params)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
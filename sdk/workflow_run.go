@@ -21,6 +21,7 @@ type WorkflowRun struct {
 	WorkflowNodeRuns map[int64][]WorkflowNodeRun `json:"nodes" db:"-"`
 	Infos            []WorkflowRunInfo           `json:"infos" db:"-"`
 	Tags             []WorkflowRunTag            `json:"tags" db:"-"`
+	TraceParent      string                      `json:"trace_parent,omitempty" db:"trace_parent"`
 }
 
 // Translate translates messages in WorkflowNodeRun
@@ -94,6 +95,17 @@ func (nr *WorkflowNodeRun) Translate(lang string) {
 	}
 }
 
+//WorkflowNodeRunApproval is the decision of a user on a workflow node run waiting for approval
+type WorkflowNodeRunApproval struct {
+	ID                int64     `json:"id" db:"id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	UserID            int64     `json:"user_id,omitempty" db:"user_id"`
+	Username          string    `json:"username" db:"username"`
+	Approved          bool      `json:"approved" db:"approved"`
+	Comment           string    `json:"comment,omitempty" db:"comment"`
+	Created           time.Time `json:"created" db:"created"`
+}
+
 //WorkflowNodeRunArtifact represents tests list
 type WorkflowNodeRunArtifact struct {
 	WorkflowID        int64     `json:"workflow_id" db:"workflow_run_id"`
@@ -105,6 +117,7 @@ type WorkflowNodeRunArtifact struct {
 	Size              int64     `json:"size,omitempty" db:"size"`
 	Perm              uint32    `json:"perm,omitempty" db:"perm"`
 	MD5sum            string    `json:"md5sum,omitempty" db:"md5sum"`
+	SHA256sum         string    `json:"sha256sum,omitempty" db:"sha256sum"`
 	ObjectPath        string    `json:"object_path,omitempty" db:"object_path"`
 	Created           time.Time `json:"created,omitempty" db:"created"`
 }
@@ -123,6 +136,7 @@ type WorkflowNodeJobRun struct {
 	Model             string      `json:"model,omitempty" db:"model"`
 	BookedBy          Hatchery    `json:"bookedby" db:"-"`
 	SpawnInfos        []SpawnInfo `json:"spawninfos" db:"-"`
+	TraceParent       string      `json:"trace_parent,omitempty" db:"trace_parent"`
 }
 
 // Translate translates messages in WorkflowNodeJobRun
@@ -139,6 +153,8 @@ type WorkflowNodeRunHookEvent struct {
 	Payload            interface{} `json:"payload" db:"-"`
 	PipelineParameters []Parameter `json:"pipeline_parameter" db:"-"`
 	WorkflowNodeHookID int64       `json:"workflow_node_hook_id" db:"-"`
+	//PullRequest is set when the hook has been triggered by a pull request, its parameters are added to the payload
+	PullRequest *VCSPullRequestEvent `json:"pull_request,omitempty" db:"-"`
 }
 
 //WorkflowNodeRunManual is an instanc of event received on a hook